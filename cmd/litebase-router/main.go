@@ -4,6 +4,7 @@ import (
 	"log"
 
 	"github.com/joho/godotenv"
+	"github.com/litebase/litebase/pkg/config"
 	"github.com/litebase/litebase/pkg/router"
 )
 
@@ -16,6 +17,6 @@ func main() {
 		log.Fatal("Error loading .env file")
 	}
 
-	r := router.NewRouter()
+	r := router.NewRouter(config.NewConfig())
 	r.Start()
 }
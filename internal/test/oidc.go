@@ -0,0 +1,176 @@
+package test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+/*
+MockOIDCProvider is a local OpenID Connect identity provider for tests. It
+serves a discovery document, its signing keys, and the device authorization
+grant. Device codes are approved on the first poll and yield an identity token
+for the Identity of the provider.
+*/
+type MockOIDCProvider struct {
+	ClientID string
+	Identity map[string]any
+	Server   *httptest.Server
+	URL      string
+
+	deviceCodes map[string]bool
+	key         *rsa.PrivateKey
+	mutex       sync.Mutex
+}
+
+const mockOIDCKeyID = "test-key"
+
+// Create and start a new mock identity provider. The provider is closed when
+// the test finishes.
+func NewMockOIDCProvider(t testing.TB) *MockOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := &MockOIDCProvider{
+		ClientID: "litebase-cli",
+		Identity: map[string]any{
+			"sub":    "user-1",
+			"email":  "jane@example.com",
+			"groups": []string{"engineering"},
+		},
+		deviceCodes: map[string]bool{},
+		key:         key,
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeMockOIDCResponse(w, http.StatusOK, map[string]any{
+			"issuer":                        provider.URL,
+			"authorization_endpoint":        provider.URL + "/authorize",
+			"device_authorization_endpoint": provider.URL + "/device/code",
+			"jwks_uri":                      provider.URL + "/jwks",
+			"token_endpoint":                provider.URL + "/token",
+		})
+	})
+
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeMockOIDCResponse(w, http.StatusOK, map[string]any{
+			"keys": []map[string]any{{
+				"kty": "RSA",
+				"kid": mockOIDCKeyID,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+
+	mux.HandleFunc("POST /device/code", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("client_id") != provider.ClientID {
+			writeMockOIDCResponse(w, http.StatusBadRequest, map[string]any{"error": "invalid_client"})
+			return
+		}
+
+		deviceCode := CreateHash(32)
+
+		provider.mutex.Lock()
+		provider.deviceCodes[deviceCode] = true
+		provider.mutex.Unlock()
+
+		writeMockOIDCResponse(w, http.StatusOK, map[string]any{
+			"device_code":               deviceCode,
+			"user_code":                 "ABCD-EFGH",
+			"verification_uri":          provider.URL + "/device",
+			"verification_uri_complete": provider.URL + "/device?user_code=ABCD-EFGH",
+			"expires_in":                600,
+			"interval":                  1,
+		})
+	})
+
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:device_code" {
+			writeMockOIDCResponse(w, http.StatusBadRequest, map[string]any{"error": "unsupported_grant_type"})
+			return
+		}
+
+		provider.mutex.Lock()
+		approved := provider.deviceCodes[r.FormValue("device_code")]
+		delete(provider.deviceCodes, r.FormValue("device_code"))
+		provider.mutex.Unlock()
+
+		if !approved {
+			writeMockOIDCResponse(w, http.StatusBadRequest, map[string]any{"error": "expired_token"})
+			return
+		}
+
+		writeMockOIDCResponse(w, http.StatusOK, map[string]any{
+			"access_token": CreateHash(32),
+			"expires_in":   3600,
+			"id_token":     provider.IDToken(nil),
+			"token_type":   "Bearer",
+		})
+	})
+
+	provider.Server = httptest.NewServer(mux)
+	provider.URL = provider.Server.URL
+
+	t.Cleanup(provider.Server.Close)
+
+	return provider
+}
+
+// Return a signed identity token for the identity of the provider. The claims
+// are added to the token and take precedence over the claims of the identity.
+func (p *MockOIDCProvider) IDToken(claims map[string]any) string {
+	now := time.Now()
+
+	payload := map[string]any{
+		"iss": p.URL,
+		"aud": p.ClientID,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+
+	for key, value := range p.Identity {
+		payload[key] = value
+	}
+
+	for key, value := range claims {
+		payload[key] = value
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": mockOIDCKeyID, "typ": "JWT"})
+	body, _ := json.Marshal(payload)
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+	digest := sha256.Sum256([]byte(signed))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+
+	if err != nil {
+		panic(err)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func writeMockOIDCResponse(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -7,27 +7,49 @@ import (
 	"log"
 	"log/slog"
 	"os"
+	"slices"
 	"time"
 )
 
-type AccessKey struct {
-	AccessKeyID      string `json:"access_key_id"`
-	AccessKeySecret  string `json:"access_key_secret"`
-	Description      string `json:"description"`
-	accessKeyManager *AccessKeyManager
-	CreatedAt        time.Time            `json:"created_at"`
-	UpdatedAt        time.Time            `json:"updated_at"`
-	Statements       []AccessKeyStatement `json:"statements"`
+// The default period during which the previous secret of a rotated access key
+// remains valid.
+const DefaultAccessKeyRotationGracePeriod = 24 * time.Hour
 
-	hash [32]byte
+type AccessKey struct {
+	AccessKeyID             string `json:"access_key_id"`
+	AccessKeySecret         string `json:"access_key_secret"`
+	Description             string `json:"description"`
+	accessKeyManager        *AccessKeyManager
+	Claims                  map[string]string    `json:"claims,omitempty"`
+	CreatedAt               time.Time            `json:"created_at"`
+	ExpiresAt               *time.Time           `json:"expires_at,omitempty"`
+	PreviousAccessKeySecret string               `json:"previous_access_key_secret,omitempty"`
+	PreviousSecretExpiresAt *time.Time           `json:"previous_secret_expires_at,omitempty"`
+	Roles                   []string             `json:"roles,omitempty"`
+	RotatedAt               *time.Time           `json:"rotated_at,omitempty"`
+	RowPolicies             []AccessKeyRowPolicy `json:"row_policies,omitempty"`
+	UpdatedAt               time.Time            `json:"updated_at"`
+	Statements              []AccessKeyStatement `json:"statements"`
+
+	authorizationContext *AuthorizationContext
+	hash                 [32]byte
+	parentStatements     []AccessKeyStatement
+	token                string
+	tracer               func(AuthorizationDecision)
 }
 
 type AccessKeyResponse struct {
-	AccessKeyID string               `json:"access_key_id"`
-	Description string               `json:"description"`
-	CreatedAt   time.Time            `json:"created_at"`
-	UpdatedAt   time.Time            `json:"updated_at"`
-	Statements  []AccessKeyStatement `json:"statements"`
+	AccessKeyID             string               `json:"access_key_id"`
+	Description             string               `json:"description"`
+	Claims                  map[string]string    `json:"claims,omitempty"`
+	CreatedAt               time.Time            `json:"created_at"`
+	ExpiresAt               *time.Time           `json:"expires_at"`
+	PreviousSecretExpiresAt *time.Time           `json:"previous_secret_expires_at,omitempty"`
+	Roles                   []string             `json:"roles,omitempty"`
+	RotatedAt               *time.Time           `json:"rotated_at,omitempty"`
+	RowPolicies             []AccessKeyRowPolicy `json:"row_policies,omitempty"`
+	UpdatedAt               time.Time            `json:"updated_at"`
+	Statements              []AccessKeyStatement `json:"statements"`
 }
 
 // Create a new AccessKey instance.
@@ -49,13 +71,21 @@ func NewAccessKey(
 	}
 }
 
+// Return the context of the request the AccessKey is used for, or nil when the
+// AccessKey is not used for a request.
+func (accessKey *AccessKey) AuthorizationContext() *AuthorizationContext {
+	return accessKey.authorizationContext
+}
+
 // Determine if the AccessKey has authorization for the given resources and actions.
 func (accessKey *AccessKey) AuthorizeForResource(resources []string, actions []Privilege) bool {
 	hasAuthorization := false
 
+	statements := accessKey.effectiveStatements()
+
 	for _, action := range actions {
 		for _, resource := range resources {
-			if Authorized(accessKey.Statements, resource, action) {
+			if accessKey.authorized(statements, resource, action) {
 				hasAuthorization = true
 				break // No need to check further if one action is authorized
 			}
@@ -65,6 +95,33 @@ func (accessKey *AccessKey) AuthorizeForResource(resources []string, actions []P
 	return hasAuthorization
 }
 
+// Determine if the statements authorize the action on the resource in the
+// authorization context of the AccessKey. Access keys resolved from an access
+// token are also limited by the conditional statements of the issuing key.
+func (accessKey *AccessKey) authorized(statements []AccessKeyStatement, resource string, privilege Privilege) bool {
+	decision := accessKey.evaluate(statements, resource, privilege)
+
+	if accessKey.tracer != nil {
+		accessKey.tracer(decision)
+	}
+
+	return decision.Allowed
+}
+
+// Evaluate the statements for the action on the resource in the authorization
+// context of the AccessKey, returning the decision and the deciding statement.
+func (accessKey *AccessKey) evaluate(statements []AccessKeyStatement, resource string, privilege Privilege) AuthorizationDecision {
+	if accessKey.parentStatements != nil {
+		decision := Evaluate(accessKey.parentStatements, resource, privilege, accessKey.authorizationContext)
+
+		if !decision.Allowed {
+			return decision
+		}
+	}
+
+	return Evaluate(statements, resource, privilege, accessKey.authorizationContext)
+}
+
 // Delete the AccessKey from the filesystem.
 func (accessKey *AccessKey) Delete() error {
 	keys := AllKeys(
@@ -94,6 +151,26 @@ func (accessKey *AccessKey) Delete() error {
 	return nil
 }
 
+// Return the statements of the AccessKey together with the statements of the
+// roles assigned to it. Roles are resolved each time so that changes to a role
+// apply to cached access keys.
+func (accessKey *AccessKey) effectiveStatements() []AccessKeyStatement {
+	if len(accessKey.Roles) == 0 || accessKey.accessKeyManager == nil {
+		return accessKey.Statements
+	}
+
+	return append(
+		append([]AccessKeyStatement{}, accessKey.Statements...),
+		accessKey.accessKeyManager.auth.RoleManager().Statements(accessKey.Roles)...,
+	)
+}
+
+// Explain the decision for the action on the resource, including the
+// statement of the AccessKey or its roles that decided it.
+func (accessKey *AccessKey) Explain(resource string, privilege Privilege) AuthorizationDecision {
+	return accessKey.evaluate(accessKey.effectiveStatements(), resource, privilege)
+}
+
 // Return the hash of the AccessKey.
 func (accessKey *AccessKey) Hash() [32]byte {
 	if accessKey.hash != [32]byte{} {
@@ -105,25 +182,56 @@ func (accessKey *AccessKey) Hash() [32]byte {
 	return accessKey.hash
 }
 
-func (accessKey *AccessKey) ToResponse() *AccessKeyResponse {
-	return &AccessKeyResponse{
-		AccessKeyID: accessKey.AccessKeyID,
-		Description: accessKey.Description,
-		CreatedAt:   accessKey.CreatedAt,
-		UpdatedAt:   accessKey.UpdatedAt,
-		Statements:  accessKey.Statements,
+// Determine if the AccessKey has expired.
+func (accessKey *AccessKey) IsExpired() bool {
+	return accessKey.ExpiresAt != nil && !time.Now().UTC().Before(*accessKey.ExpiresAt)
+}
+
+// Return the previous secret of the AccessKey if it is still within the grace
+// period of the last rotation, otherwise an empty string.
+func (accessKey *AccessKey) PreviousSecret() string {
+	if accessKey.PreviousAccessKeySecret == "" || accessKey.PreviousSecretExpiresAt == nil {
+		return ""
 	}
+
+	if !time.Now().UTC().Before(*accessKey.PreviousSecretExpiresAt) {
+		return ""
+	}
+
+	return accessKey.PreviousAccessKeySecret
 }
 
-// Update the AccessKey statements.
-func (accessKey *AccessKey) Update(
-	description string,
-	statements []AccessKeyStatement,
-) error {
-	accessKey.Description = description
-	accessKey.Statements = statements
-	accessKey.UpdatedAt = time.Now().UTC()
+// Set the context of the request the AccessKey is used for. The conditions of
+// statements are evaluated against this context.
+func (accessKey *AccessKey) SetAuthorizationContext(context *AuthorizationContext) {
+	accessKey.authorizationContext = context
+	accessKey.hash = [32]byte{}
+}
+
+// Issue a new secret for the AccessKey. The previous secret remains valid for
+// the grace period so that clients can be updated without downtime.
+func (accessKey *AccessKey) Rotate(gracePeriod time.Duration) error {
+	now := time.Now().UTC()
+
+	accessKey.PreviousAccessKeySecret = ""
+	accessKey.PreviousSecretExpiresAt = nil
+
+	if gracePeriod > 0 {
+		previousSecretExpiresAt := now.Add(gracePeriod)
+
+		accessKey.PreviousAccessKeySecret = accessKey.AccessKeySecret
+		accessKey.PreviousSecretExpiresAt = &previousSecretExpiresAt
+	}
+
+	accessKey.AccessKeySecret = accessKey.accessKeyManager.GenerateAccessKeySecret()
+	accessKey.RotatedAt = &now
+	accessKey.UpdatedAt = now
+
+	return accessKey.save()
+}
 
+// Write the AccessKey to storage and purge it from the cache.
+func (accessKey *AccessKey) save() error {
 	jsonValue, err := json.Marshal(accessKey)
 
 	if err != nil {
@@ -160,12 +268,108 @@ func (accessKey *AccessKey) Update(
 	return accessKey.accessKeyManager.Purge(accessKey.AccessKeyID)
 }
 
-// Update the internal hash of the access key.
+// Return the access token the AccessKey was resolved from, or an empty string
+// if the request was signed with the access key secret.
+func (accessKey *AccessKey) Token() string {
+	return accessKey.token
+}
+
+// Trace the decisions made while authorizing the AccessKey. The tracer is
+// called with every decision until it is cleared by passing nil.
+func (accessKey *AccessKey) Trace(tracer func(AuthorizationDecision)) {
+	accessKey.tracer = tracer
+}
+
+func (accessKey *AccessKey) ToResponse() *AccessKeyResponse {
+	return &AccessKeyResponse{
+		AccessKeyID:             accessKey.AccessKeyID,
+		Description:             accessKey.Description,
+		Claims:                  accessKey.Claims,
+		CreatedAt:               accessKey.CreatedAt,
+		ExpiresAt:               accessKey.ExpiresAt,
+		PreviousSecretExpiresAt: accessKey.PreviousSecretExpiresAt,
+		Roles:                   accessKey.Roles,
+		RotatedAt:               accessKey.RotatedAt,
+		RowPolicies:             accessKey.RowPolicies,
+		UpdatedAt:               accessKey.UpdatedAt,
+		Statements:              accessKey.Statements,
+	}
+}
+
+// Update the AccessKey statements.
+func (accessKey *AccessKey) Update(
+	description string,
+	statements []AccessKeyStatement,
+) error {
+	accessKey.Description = description
+	accessKey.Statements = statements
+	accessKey.UpdatedAt = time.Now().UTC()
+
+	return accessKey.save()
+}
+
+// Update the roles assigned to the AccessKey.
+func (accessKey *AccessKey) UpdateRoles(roles []string) error {
+	accessKey.Roles = roles
+	accessKey.UpdatedAt = time.Now().UTC()
+
+	return accessKey.save()
+}
+
+// Update the claims and row policies of the AccessKey.
+func (accessKey *AccessKey) UpdateRowPolicies(
+	claims map[string]string,
+	rowPolicies []AccessKeyRowPolicy,
+) error {
+	accessKey.Claims = claims
+	accessKey.RowPolicies = rowPolicies
+	accessKey.UpdatedAt = time.Now().UTC()
+
+	return accessKey.save()
+}
+
+// Update the internal hash of the access key. The statements of assigned roles
+// are part of the hash so that a role change is reflected in the hash, as are
+// the statements whose conditions are met in the authorization context.
 func (accessKey *AccessKey) updateHash() {
 	jsonBytes, err := json.Marshal(accessKey)
 	if err != nil {
 		return
 	}
 
+	if len(accessKey.Roles) > 0 {
+		roleBytes, err := json.Marshal(accessKey.effectiveStatements())
+
+		if err != nil {
+			return
+		}
+
+		jsonBytes = append(jsonBytes, roleBytes...)
+	}
+
+	// The statements of the access key a token was issued for limit the
+	// token, so a change to them is reflected in the hash of the token.
+	if accessKey.parentStatements != nil {
+		parentBytes, err := json.Marshal(accessKey.parentStatements)
+
+		if err != nil {
+			return
+		}
+
+		jsonBytes = append(jsonBytes, parentBytes...)
+	}
+
+	for _, statement := range slices.Concat(accessKey.effectiveStatements(), accessKey.parentStatements) {
+		if statement.Conditions == nil {
+			continue
+		}
+
+		if statement.appliesTo(accessKey.authorizationContext) {
+			jsonBytes = append(jsonBytes, 1)
+		} else {
+			jsonBytes = append(jsonBytes, 0)
+		}
+	}
+
 	accessKey.hash = sha256.Sum256(jsonBytes)
 }
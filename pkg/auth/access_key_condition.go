@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+)
+
+/*
+Conditions limit when a statement applies. A statement with conditions only
+applies to a request when all of its conditions are met:
+
+	{
+		"effect": "allow",
+		"resource": "database:*",
+		"actions": ["database:query"],
+		"conditions": {
+			"source_ips": ["10.0.0.0/8", "203.0.113.7"],
+			"time_windows": [{"days": ["mon", "tue", "wed", "thu", "fri"], "start": "08:00", "end": "18:00", "timezone": "Europe/Berlin"}],
+			"require_tls": true,
+			"replica_only": true
+		}
+	}
+
+| Condition      | Met when                                                    |
+|----------------|-------------------------------------------------------------|
+| `source_ips`   | The client address is within one of the CIDR ranges or IPs |
+| `time_windows` | The request is made within one of the time windows         |
+| `require_tls`  | The request was made over TLS                               |
+| `replica_only` | The request is handled by a replica node                    |
+
+Time windows that end before they start span midnight, and the days of such a
+window refer to the day the window starts. When a request cannot be evaluated,
+allow statements with conditions do not apply and deny statements with
+conditions do, so conditions never grant more access than intended.
+*/
+type AccessKeyConditions struct {
+	ReplicaOnly bool                  `json:"replica_only,omitempty"`
+	RequireTLS  bool                  `json:"require_tls,omitempty"`
+	SourceIPs   []string              `json:"source_ips,omitempty"`
+	TimeWindows []AccessKeyTimeWindow `json:"time_windows,omitempty"`
+}
+
+type AccessKeyTimeWindow struct {
+	Days     []string `json:"days,omitempty"`
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+	Timezone string   `json:"timezone,omitempty"`
+}
+
+var accessKeyTimeWindowDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Determine if all of the conditions are well formed.
+func (c *AccessKeyConditions) IsValid() bool {
+	if len(c.SourceIPs) > 100 || len(c.TimeWindows) > 20 {
+		return false
+	}
+
+	for _, sourceIP := range c.SourceIPs {
+		if _, ok := parseSourceIP(sourceIP); !ok {
+			return false
+		}
+	}
+
+	for _, window := range c.TimeWindows {
+		if !window.IsValid() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Determine if the conditions are met in the authorization context.
+func (c *AccessKeyConditions) Satisfied(context *AuthorizationContext) bool {
+	if c.ReplicaOnly && !context.Replica {
+		return false
+	}
+
+	if c.RequireTLS && !context.TLS {
+		return false
+	}
+
+	if len(c.SourceIPs) > 0 && !c.sourceIPAllowed(context.SourceIP) {
+		return false
+	}
+
+	if len(c.TimeWindows) > 0 && !slices.ContainsFunc(c.TimeWindows, func(window AccessKeyTimeWindow) bool {
+		return window.Contains(context.Time)
+	}) {
+		return false
+	}
+
+	return true
+}
+
+// Determine if the address is within one of the source IP ranges.
+func (c *AccessKeyConditions) sourceIPAllowed(address netip.Addr) bool {
+	if !address.IsValid() {
+		return false
+	}
+
+	address = address.Unmap()
+
+	for _, sourceIP := range c.SourceIPs {
+		if prefix, ok := parseSourceIP(sourceIP); ok && prefix.Contains(address) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parse a CIDR range, or a single IP address as a range of one address.
+func parseSourceIP(value string) (netip.Prefix, bool) {
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+
+		if err != nil {
+			return netip.Prefix{}, false
+		}
+
+		return prefix.Masked(), true
+	}
+
+	address, err := netip.ParseAddr(value)
+
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+
+	address = address.Unmap()
+
+	return netip.PrefixFrom(address, address.BitLen()), true
+}
+
+// Determine if the time is within the window.
+func (w AccessKeyTimeWindow) Contains(t time.Time) bool {
+	location, err := w.location()
+
+	if err != nil {
+		return false
+	}
+
+	start, startOk := parseTimeOfDay(w.Start)
+	end, endOk := parseTimeOfDay(w.End)
+
+	if !startOk || !endOk {
+		return false
+	}
+
+	t = t.In(location)
+	minutes := t.Hour()*60 + t.Minute()
+	weekday := t.Weekday()
+
+	if start <= end {
+		if minutes < start || minutes >= end {
+			return false
+		}
+	} else {
+		if minutes < start && minutes >= end {
+			return false
+		}
+
+		// After midnight the window belongs to the day it started on.
+		if minutes < end {
+			weekday = (weekday + 6) % 7
+		}
+	}
+
+	return len(w.Days) == 0 || slices.Contains(w.Days, accessKeyTimeWindowDays[weekday])
+}
+
+// Determine if the window has valid days, times, and timezone.
+func (w AccessKeyTimeWindow) IsValid() bool {
+	if _, ok := parseTimeOfDay(w.Start); !ok {
+		return false
+	}
+
+	if _, ok := parseTimeOfDay(w.End); !ok || w.Start == w.End {
+		return false
+	}
+
+	for _, day := range w.Days {
+		if !slices.Contains(accessKeyTimeWindowDays, day) {
+			return false
+		}
+	}
+
+	_, err := w.location()
+
+	return err == nil
+}
+
+// Return the location of the window, UTC by default.
+func (w AccessKeyTimeWindow) location() (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.UTC, nil
+	}
+
+	return time.LoadLocation(w.Timezone)
+}
+
+// Parse a time of day in the HH:MM format as minutes since midnight.
+func parseTimeOfDay(value string) (int, bool) {
+	parsed, err := time.Parse("15:04", value)
+
+	if err != nil {
+		return 0, false
+	}
+
+	return parsed.Hour()*60 + parsed.Minute(), true
+}
+
+// Determine if the statement applies in the authorization context. Statements
+// without conditions always apply. Without a context the conditions cannot be
+// evaluated, so only deny statements apply.
+func (aks AccessKeyStatement) appliesTo(context *AuthorizationContext) bool {
+	if aks.Conditions == nil {
+		return true
+	}
+
+	if context == nil {
+		return strings.ToLower(string(aks.Effect)) == string(AccessKeyEffectDeny)
+	}
+
+	return aks.Conditions.Satisfied(context)
+}
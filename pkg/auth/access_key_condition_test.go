@@ -0,0 +1,124 @@
+package auth_test
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestAccessKeyConditions_IsValid(t *testing.T) {
+	testCases := []struct {
+		name       string
+		conditions auth.AccessKeyConditions
+		expected   bool
+	}{
+		{"Empty", auth.AccessKeyConditions{}, true},
+		{"CIDR and IP", auth.AccessKeyConditions{SourceIPs: []string{"10.0.0.0/8", "203.0.113.7", "2001:db8::/32"}}, true},
+		{"Invalid IP", auth.AccessKeyConditions{SourceIPs: []string{"10.0.0.300"}}, false},
+		{"Time window", auth.AccessKeyConditions{TimeWindows: []auth.AccessKeyTimeWindow{{Days: []string{"mon"}, Start: "09:00", End: "17:00", Timezone: "Europe/Berlin"}}}, true},
+		{"Invalid time", auth.AccessKeyConditions{TimeWindows: []auth.AccessKeyTimeWindow{{Start: "25:00", End: "17:00"}}}, false},
+		{"Empty window", auth.AccessKeyConditions{TimeWindows: []auth.AccessKeyTimeWindow{{Start: "09:00", End: "09:00"}}}, false},
+		{"Invalid day", auth.AccessKeyConditions{TimeWindows: []auth.AccessKeyTimeWindow{{Days: []string{"monday"}, Start: "09:00", End: "17:00"}}}, false},
+		{"Invalid timezone", auth.AccessKeyConditions{TimeWindows: []auth.AccessKeyTimeWindow{{Start: "09:00", End: "17:00", Timezone: "Mars/Olympus"}}}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.conditions.IsValid() != tc.expected {
+				t.Errorf("Expected IsValid() to be %v", tc.expected)
+			}
+		})
+	}
+}
+
+func TestAccessKeyConditions_Satisfied(t *testing.T) {
+	conditions := auth.AccessKeyConditions{SourceIPs: []string{"10.0.0.0/8", "203.0.113.7"}}
+
+	for address, expected := range map[string]bool{
+		"10.1.2.3":        true,
+		"::ffff:10.1.2.3": true,
+		"203.0.113.7":     true,
+		"203.0.113.8":     false,
+	} {
+		if conditions.Satisfied(&auth.AuthorizationContext{SourceIP: netip.MustParseAddr(address)}) != expected {
+			t.Errorf("Expected %s to be satisfied: %v", address, expected)
+		}
+	}
+
+	if conditions.Satisfied(&auth.AuthorizationContext{}) {
+		t.Error("Expected a missing source address to not satisfy the conditions")
+	}
+
+	conditions = auth.AccessKeyConditions{RequireTLS: true, ReplicaOnly: true}
+
+	if conditions.Satisfied(&auth.AuthorizationContext{TLS: true}) {
+		t.Error("Expected replica only conditions to not be satisfied on the primary")
+	}
+
+	if !conditions.Satisfied(&auth.AuthorizationContext{TLS: true, Replica: true}) {
+		t.Error("Expected the conditions to be satisfied over TLS on a replica")
+	}
+}
+
+func TestAccessKeyTimeWindow_Contains(t *testing.T) {
+	// 22:00 to 06:00 starting on Mondays, in Berlin (UTC+1 in January)
+	window := auth.AccessKeyTimeWindow{Days: []string{"mon"}, Start: "22:00", End: "06:00", Timezone: "Europe/Berlin"}
+
+	testCases := []struct {
+		time     time.Time
+		expected bool
+	}{
+		{time.Date(2026, 1, 5, 22, 0, 0, 0, time.UTC), true},  // Monday 23:00
+		{time.Date(2026, 1, 6, 4, 0, 0, 0, time.UTC), true},   // Tuesday 05:00
+		{time.Date(2026, 1, 6, 6, 0, 0, 0, time.UTC), false},  // Tuesday 07:00
+		{time.Date(2026, 1, 6, 22, 0, 0, 0, time.UTC), false}, // Tuesday 23:00
+		{time.Date(2026, 1, 5, 20, 0, 0, 0, time.UTC), false}, // Monday 21:00
+	}
+
+	for _, tc := range testCases {
+		if window.Contains(tc.time) != tc.expected {
+			t.Errorf("Expected %s to be within the window: %v", tc.time, tc.expected)
+		}
+	}
+}
+
+func TestAuthorizedWithContext(t *testing.T) {
+	conditions := &auth.AccessKeyConditions{SourceIPs: []string{"10.0.0.0/8"}}
+	inside := &auth.AuthorizationContext{SourceIP: netip.MustParseAddr("10.0.0.1")}
+	outside := &auth.AuthorizationContext{SourceIP: netip.MustParseAddr("192.0.2.1")}
+
+	allow := []auth.AccessKeyStatement{
+		{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}, Conditions: conditions},
+	}
+
+	if !auth.AuthorizedWithContext(allow, "database:*", auth.DatabasePrivilegeRead, inside) {
+		t.Error("Expected the allow statement to apply when its conditions are met")
+	}
+
+	if auth.AuthorizedWithContext(allow, "database:*", auth.DatabasePrivilegeRead, outside) {
+		t.Error("Expected the allow statement to not apply when its conditions are not met")
+	}
+
+	if auth.Authorized(allow, "database:*", auth.DatabasePrivilegeRead) {
+		t.Error("Expected the allow statement to not apply without a context")
+	}
+
+	deny := []auth.AccessKeyStatement{
+		{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}},
+		{Effect: "Deny", Resource: "database:*", Actions: []auth.Privilege{"*"}, Conditions: conditions},
+	}
+
+	if auth.AuthorizedWithContext(deny, "database:*", auth.DatabasePrivilegeRead, inside) {
+		t.Error("Expected the deny statement to apply when its conditions are met")
+	}
+
+	if !auth.AuthorizedWithContext(deny, "database:*", auth.DatabasePrivilegeRead, outside) {
+		t.Error("Expected the deny statement to not apply when its conditions are not met")
+	}
+
+	if auth.Authorized(deny, "database:*", auth.DatabasePrivilegeRead) {
+		t.Error("Expected the deny statement to apply without a context")
+	}
+}
@@ -68,28 +68,63 @@ func (akm *AccessKeyManager) AllAccessKeyIds() ([]string, error) {
 
 // Create a new access key
 func (akm *AccessKeyManager) Create(description string, statements []AccessKeyStatement) (*AccessKey, error) {
+	return akm.CreateWithExpiration(description, statements, nil)
+}
+
+// Create a new access key that is no longer valid after the expiration time.
+// The access key does not expire if the expiration time is nil.
+func (akm *AccessKeyManager) CreateWithExpiration(
+	description string,
+	statements []AccessKeyStatement,
+	expiresAt *time.Time,
+) (*AccessKey, error) {
+	accessKey, err := akm.New(description, statements)
+
+	if err != nil {
+		return nil, err
+	}
+
+	accessKey.ExpiresAt = expiresAt
+
+	err = akm.Store(accessKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return accessKey, nil
+}
+
+// Return a new access key with a generated id and secret. The access key is
+// not stored until Store is called, so the rest of its fields can be set first.
+func (akm *AccessKeyManager) New(description string, statements []AccessKeyStatement) (*AccessKey, error) {
 	accessKeyId, err := akm.GenerateAccessKeyId()
 
 	if err != nil {
 		return nil, err
 	}
 
-	accessKey := NewAccessKey(
+	return NewAccessKey(
 		akm,
 		accessKeyId,
 		akm.GenerateAccessKeySecret(),
 		description,
 		statements,
-	)
+	), nil
+}
 
-	err = akm.auth.SecretsManager.StoreAccessKey(accessKey)
+// Store a new access key.
+func (akm *AccessKeyManager) Store(accessKey *AccessKey) error {
+	err := akm.auth.SecretsManager.StoreAccessKey(accessKey)
 
 	if err != nil {
 		log.Println(err)
-		return nil, err
+		return err
 	}
 
-	return accessKey, nil
+	accessKey.updateHash()
+
+	return nil
 }
 
 // Generate an access key id
@@ -212,6 +247,8 @@ func (akm *AccessKeyManager) Get(accessKeyId string) (*AccessKey, error) {
 func (akm *AccessKeyManager) Purge(accessKeyId string) error {
 	akm.auth.SecretsManager.cache("map").Forget(akm.accessKeyCacheKey(accessKeyId))
 	akm.auth.SecretsManager.cache("transient").Forget(akm.accessKeyCacheKey(accessKeyId))
+	akm.auth.SecretsManager.cache("transient").Forget(fmt.Sprintf("%s:access_key_secret", accessKeyId))
+	akm.auth.SecretsManager.cache("transient").Forget(fmt.Sprintf("%s:server_secret", accessKeyId))
 	akm.auth.Broadcast("access-key:purge", accessKeyId)
 
 	return nil
@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+A mask transforms the values of a column before they are returned to the
+access key. Masks are set on allow statements for column resources:
+
+	{
+		"effect": "allow",
+		"resource": "database:DATABASE_ID:branch:BRANCH_ID:table:users:column:email",
+		"actions": ["database:read"],
+		"mask": "hash"
+	}
+
+The mask of the most specific allow statement that grants read access to a
+column applies, so a broad mask can be lifted for a single column by a more
+specific statement without a mask.
+
+| Mask     | Example input      | Example output                     |
+|----------|--------------------|------------------------------------|
+| `redact` | `jane@example.com` | `[REDACTED]`                       |
+| `hash`   | `jane@example.com` | `5f0c7d0b4e3e8f1a9c2b6d4e8a1f3c5b` |
+| `last-4` | `4111111111111111` | `************1111`                 |
+*/
+type AccessKeyMask string
+
+const (
+	AccessKeyMaskHash   AccessKeyMask = "hash"
+	AccessKeyMaskLast4  AccessKeyMask = "last-4"
+	AccessKeyMaskRedact AccessKeyMask = "redact"
+)
+
+// Determine if the AccessKeyMask is valid.
+func (m AccessKeyMask) IsValid() bool {
+	switch m {
+	case AccessKeyMaskHash, AccessKeyMaskLast4, AccessKeyMaskRedact:
+		return true
+	default:
+		return false
+	}
+}
+
+// Apply the mask to a value. Hashes are keyed with the secret so that the
+// original values can not be recovered by hashing candidate values.
+func (m AccessKeyMask) Apply(value string, secret []byte) string {
+	switch m {
+	case AccessKeyMaskHash:
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(value))
+
+		return hex.EncodeToString(mac.Sum(nil)[:16])
+	case AccessKeyMaskLast4:
+		length := utf8.RuneCountInString(value)
+
+		if length <= 4 {
+			return strings.Repeat("*", length)
+		}
+
+		runes := []rune(value)
+
+		return strings.Repeat("*", length-4) + string(runes[length-4:])
+	default:
+		return "[REDACTED]"
+	}
+}
+
+// Return the mask that applies to a column for the access key, or an empty
+// mask if the values of the column are returned as is. The masks of the
+// access key a token was issued for also apply to the token, and the
+// strictest of the two masks wins.
+func (accessKey *AccessKey) ColumnMask(databaseId, branchId, table, column string) AccessKeyMask {
+	if !accessKey.HasColumnMasks() {
+		return ""
+	}
+
+	resource := accessKey.authorizationKey("database", databaseId, "branch", branchId, "table", table, "column", column)
+	mask := accessKey.columnMask(accessKey.effectiveStatements(), resource)
+
+	if accessKey.parentStatements != nil {
+		if parentMask := accessKey.columnMask(accessKey.parentStatements, resource); parentMask.strictness() > mask.strictness() {
+			mask = parentMask
+		}
+	}
+
+	return mask
+}
+
+// Return the mask of the most specific allow statement that grants read
+// access to the column resource.
+func (accessKey *AccessKey) columnMask(statements []AccessKeyStatement, resource string) AccessKeyMask {
+	specificity := -1
+	mask := AccessKeyMask("")
+
+	for _, statement := range statements {
+		if strings.ToLower(string(statement.Effect)) != string(AccessKeyEffectAllow) ||
+			!statement.appliesTo(accessKey.authorizationContext) {
+			continue
+		}
+
+		if !slices.Contains(statement.Actions, "*") && !slices.Contains(statement.Actions, DatabasePrivilegeRead) {
+			continue
+		}
+
+		if !resourceMatches(string(statement.Resource), resource) {
+			continue
+		}
+
+		// Resources with more segments are more specific, and an exact
+		// resource is more specific than a wildcard with the same segments.
+		count := 2 * strings.Count(string(statement.Resource), ":")
+
+		if !strings.HasSuffix(string(statement.Resource), "*") {
+			count++
+		}
+
+		// Masks take precedence over statements of the same specificity.
+		if count > specificity || (count == specificity && statement.Mask != "") {
+			specificity = count
+			mask = statement.Mask
+		}
+	}
+
+	return mask
+}
+
+// Determine if any statement of the access key, or of the access key a token
+// was issued for, masks columns.
+func (accessKey *AccessKey) HasColumnMasks() bool {
+	for _, statement := range slices.Concat(accessKey.effectiveStatements(), accessKey.parentStatements) {
+		if statement.Mask != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Return how much of a value the mask hides. A redacted value hides
+// everything, a hash still allows values to be compared, and the last 4
+// characters reveal part of the value.
+func (m AccessKeyMask) strictness() int {
+	switch m {
+	case AccessKeyMaskRedact:
+		return 3
+	case AccessKeyMaskHash:
+		return 2
+	case AccessKeyMaskLast4:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,100 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestAccessKeyMask(t *testing.T) {
+	t.Run("Apply", func(t *testing.T) {
+		secret := []byte("secret")
+
+		if masked := auth.AccessKeyMaskRedact.Apply("jane@example.com", secret); masked != "[REDACTED]" {
+			t.Errorf("Expected the value to be redacted, got %q", masked)
+		}
+
+		if masked := auth.AccessKeyMaskLast4.Apply("4111111111111111", secret); masked != "************1111" {
+			t.Errorf("Expected the last 4 characters to be kept, got %q", masked)
+		}
+
+		if masked := auth.AccessKeyMaskLast4.Apply("123", secret); masked != "***" {
+			t.Errorf("Expected short values to be fully masked, got %q", masked)
+		}
+
+		hash := auth.AccessKeyMaskHash.Apply("jane@example.com", secret)
+
+		if len(hash) != 32 || strings.Contains(hash, "jane") {
+			t.Errorf("Expected a hash of the value, got %q", hash)
+		}
+
+		if auth.AccessKeyMaskHash.Apply("jane@example.com", secret) != hash {
+			t.Error("Expected the hash to be deterministic")
+		}
+
+		if auth.AccessKeyMaskHash.Apply("jane@example.com", []byte("other")) == hash {
+			t.Error("Expected the hash to depend on the secret")
+		}
+	})
+
+	t.Run("ColumnMask", func(t *testing.T) {
+		accessKey := &auth.AccessKey{
+			Statements: []auth.AccessKeyStatement{
+				{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}},
+				{Effect: "Allow", Resource: "database:db:branch:main:table:users:column:*", Actions: []auth.Privilege{auth.DatabasePrivilegeRead}, Mask: auth.AccessKeyMaskRedact},
+				{Effect: "Allow", Resource: "database:db:branch:main:table:users:column:email", Actions: []auth.Privilege{auth.DatabasePrivilegeRead}, Mask: auth.AccessKeyMaskHash},
+				{Effect: "Allow", Resource: "database:db:branch:main:table:users:column:id", Actions: []auth.Privilege{auth.DatabasePrivilegeRead}},
+			},
+		}
+
+		tc := []struct {
+			table  string
+			column string
+			mask   auth.AccessKeyMask
+		}{
+			{"users", "email", auth.AccessKeyMaskHash},
+			{"users", "name", auth.AccessKeyMaskRedact},
+			{"users", "id", ""},
+			{"orders", "total", ""},
+		}
+
+		for _, testCase := range tc {
+			if mask := accessKey.ColumnMask("db", "main", testCase.table, testCase.column); mask != testCase.mask {
+				t.Errorf("Expected mask %q for %s.%s, got %q", testCase.mask, testCase.table, testCase.column, mask)
+			}
+		}
+	})
+
+	t.Run("IsValid", func(t *testing.T) {
+		tc := []struct {
+			effect   auth.AccessKeyEffect
+			resource auth.AccessKeyResource
+			mask     auth.AccessKeyMask
+			valid    bool
+		}{
+			{auth.AccessKeyEffectAllow, "database:*:branch:*:table:users:column:email", auth.AccessKeyMaskHash, true},
+			{auth.AccessKeyEffectAllow, "database:*:branch:*:table:users:column:*", auth.AccessKeyMaskLast4, true},
+			{auth.AccessKeyEffectDeny, "database:*:branch:*:table:users:column:email", auth.AccessKeyMaskRedact, false},
+			{auth.AccessKeyEffectAllow, "database:*:branch:*:table:users", auth.AccessKeyMaskRedact, false},
+			{auth.AccessKeyEffectAllow, "*", auth.AccessKeyMaskRedact, false},
+		}
+
+		for _, testCase := range tc {
+			statement := auth.AccessKeyStatement{
+				Effect:   testCase.effect,
+				Resource: testCase.resource,
+				Actions:  []auth.Privilege{auth.DatabasePrivilegeRead},
+				Mask:     testCase.mask,
+			}
+
+			if statement.IsValid() != testCase.valid {
+				t.Errorf("Expected statement with mask on %s to have validity %v", testCase.resource, testCase.valid)
+			}
+		}
+
+		if auth.AccessKeyMask("reverse").IsValid() {
+			t.Error("Expected unknown masks to be invalid")
+		}
+	})
+}
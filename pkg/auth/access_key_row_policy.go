@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/litebase/litebase/pkg/sqlite3"
+)
+
+/*
+A row policy limits the rows of a table that an access key can read and write
+to the rows that satisfy a condition. The condition is an SQL expression that
+is evaluated against each row of the table and may reference the claims of the
+access key, or of the access token used for the request, as `:claim.NAME`.
+
+| Field       | Example                                                   |
+|-------------|-----------------------------------------------------------|
+| `resource`  | `database:DATABASE_ID:branch:BRANCH_ID:table:TABLE_NAME`  |
+| `resource`  | `database:*:branch:*:table:TABLE_NAME`                    |
+| `condition` | `tenant_id = :claim.tenant`                               |
+
+When more than one policy applies to a table, a row must satisfy all of them.
+A claim that is not defined evaluates to NULL, so no rows match.
+*/
+type AccessKeyRowPolicy struct {
+	Resource  string `json:"resource" validate:"required"`
+	Condition string `json:"condition" validate:"required,max=1024"`
+}
+
+// Return the condition of the policy with the claims substituted as SQL
+// literals.
+func (p AccessKeyRowPolicy) Expression(claims map[string]string) string {
+	var builder strings.Builder
+
+	tokens := sqlite3.Tokenize(p.Condition)
+
+	for i := 0; i < len(tokens); i++ {
+		if name, ok := claimReference(tokens, i); ok {
+			if value, exists := claims[name]; exists {
+				builder.WriteString("'" + strings.ReplaceAll(value, "'", "''") + "'")
+			} else {
+				builder.WriteString("NULL")
+			}
+
+			i += 2
+
+			continue
+		}
+
+		builder.WriteString(tokens[i].Text)
+	}
+
+	return builder.String()
+}
+
+// Determine if the policy has a well formed resource and a condition that is a
+// single expression. Conditions may not contain comments, statement
+// separators, or parameters other than claim references.
+func (p AccessKeyRowPolicy) IsValid() bool {
+	if _, _, table, ok := p.parseResource(); !ok || table == "" {
+		return false
+	}
+
+	tokens := sqlite3.Tokenize(p.Condition)
+	depth := 0
+	empty := true
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+
+		switch {
+		case token.Type == sqlite3.TokenIllegal, token.Type == sqlite3.TokenComment:
+			return false
+		case token.Type == sqlite3.TokenParameter:
+			if _, ok := claimReference(tokens, i); !ok {
+				return false
+			}
+
+			i += 2
+		case token.IsPunctuation(";"):
+			return false
+		case token.IsPunctuation("("):
+			depth++
+		case token.IsPunctuation(")"):
+			depth--
+
+			if depth < 0 {
+				return false
+			}
+		}
+
+		if !token.IsSpace() {
+			empty = false
+		}
+	}
+
+	return depth == 0 && !empty
+}
+
+// Determine if the policy applies to the table of the given database branch.
+func (p AccessKeyRowPolicy) Matches(databaseId, branchId, table string) bool {
+	policyDatabaseId, policyBranchId, policyTable, ok := p.parseResource()
+
+	if !ok {
+		return false
+	}
+
+	return (policyDatabaseId == "*" || policyDatabaseId == databaseId) &&
+		(policyBranchId == "*" || policyBranchId == branchId) &&
+		strings.EqualFold(policyTable, table)
+}
+
+func (p AccessKeyRowPolicy) parseResource() (string, string, string, bool) {
+	parts := strings.SplitN(p.Resource, ":", 6)
+
+	if len(parts) != 6 || parts[0] != "database" || parts[2] != "branch" || parts[4] != "table" {
+		return "", "", "", false
+	}
+
+	if parts[1] == "" || parts[3] == "" || strings.Contains(parts[5], "*") {
+		return "", "", "", false
+	}
+
+	return parts[1], parts[3], parts[5], true
+}
+
+// Return the conditions of the row policies that apply to each table of the
+// database branch, keyed by the lower case table name. Multiple policies for
+// the same table are combined so that all of them must be satisfied.
+func (accessKey *AccessKey) RowPolicyConditions(databaseId, branchId string) map[string]string {
+	if len(accessKey.RowPolicies) == 0 {
+		return nil
+	}
+
+	conditions := map[string][]string{}
+
+	for _, policy := range accessKey.RowPolicies {
+		_, _, table, ok := policy.parseResource()
+
+		if !ok || !policy.Matches(databaseId, branchId, table) {
+			continue
+		}
+
+		table = strings.ToLower(table)
+
+		conditions[table] = append(conditions[table], "("+policy.Expression(accessKey.Claims)+")")
+	}
+
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	policies := make(map[string]string, len(conditions))
+
+	for table, expressions := range conditions {
+		slices.Sort(expressions)
+		expressions = slices.Compact(expressions)
+
+		policies[table] = strings.Join(expressions, " AND ")
+	}
+
+	return policies
+}
+
+// Return the claim name if the tokens at the position form a claim reference.
+func claimReference(tokens []sqlite3.Token, i int) (string, bool) {
+	if i+2 >= len(tokens) || tokens[i].Type != sqlite3.TokenParameter || tokens[i].Text != ":claim" {
+		return "", false
+	}
+
+	if !tokens[i+1].IsPunctuation(".") || tokens[i+2].Type != sqlite3.TokenIdentifier {
+		return "", false
+	}
+
+	return tokens[i+2].Text, true
+}
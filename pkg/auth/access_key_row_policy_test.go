@@ -0,0 +1,102 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestAccessKeyRowPolicy(t *testing.T) {
+	t.Run("IsValid", func(t *testing.T) {
+		tc := []struct {
+			resource  string
+			condition string
+			valid     bool
+		}{
+			{"database:*:branch:*:table:orders", "tenant_id = :claim.tenant", true},
+			{"database:db:branch:main:table:orders", "tenant_id IN (SELECT id FROM tenants WHERE owner = :claim.user)", true},
+			{"database:*:branch:*:table:orders", "status = 'open'", true},
+			{"database:*:branch:*:table:*", "tenant_id = :claim.tenant", false},
+			{"database:*:branch:*", "tenant_id = :claim.tenant", false},
+			{"access-key:*", "tenant_id = :claim.tenant", false},
+			{"database:*:branch:*:table:orders", "", false},
+			{"database:*:branch:*:table:orders", "tenant_id = ?", false},
+			{"database:*:branch:*:table:orders", "tenant_id = :tenant", false},
+			{"database:*:branch:*:table:orders", "1) OR (1", false},
+			{"database:*:branch:*:table:orders", "1; DROP TABLE orders", false},
+			{"database:*:branch:*:table:orders", "1 -- comment", false},
+			{"database:*:branch:*:table:orders", "name = 'unterminated", false},
+		}
+
+		for _, testCase := range tc {
+			policy := auth.AccessKeyRowPolicy{
+				Resource:  testCase.resource,
+				Condition: testCase.condition,
+			}
+
+			if policy.IsValid() != testCase.valid {
+				t.Errorf("Expected row policy %q on %q to have validity %v", testCase.condition, testCase.resource, testCase.valid)
+			}
+		}
+	})
+
+	t.Run("Expression", func(t *testing.T) {
+		policy := auth.AccessKeyRowPolicy{
+			Resource:  "database:*:branch:*:table:orders",
+			Condition: "tenant_id = :claim.tenant AND region = :claim.region AND note != ':claim.tenant'",
+		}
+
+		expression := policy.Expression(map[string]string{"tenant": "o'brien"})
+		expected := "tenant_id = 'o''brien' AND region = NULL AND note != ':claim.tenant'"
+
+		if expression != expected {
+			t.Errorf("Expected %q, got %q", expected, expression)
+		}
+	})
+
+	t.Run("Matches", func(t *testing.T) {
+		policy := auth.AccessKeyRowPolicy{
+			Resource:  "database:db:branch:*:table:Orders",
+			Condition: "1",
+		}
+
+		if !policy.Matches("db", "main", "orders") {
+			t.Error("Expected the policy to match the table on any branch")
+		}
+
+		if policy.Matches("other", "main", "orders") {
+			t.Error("Expected the policy to not match other databases")
+		}
+
+		if policy.Matches("db", "main", "customers") {
+			t.Error("Expected the policy to not match other tables")
+		}
+	})
+
+	t.Run("RowPolicyConditions", func(t *testing.T) {
+		accessKey := &auth.AccessKey{
+			Claims: map[string]string{"tenant": "a"},
+			RowPolicies: []auth.AccessKeyRowPolicy{
+				{Resource: "database:db:branch:main:table:orders", Condition: "tenant_id = :claim.tenant"},
+				{Resource: "database:*:branch:*:table:ORDERS", Condition: "deleted_at IS NULL"},
+				{Resource: "database:other:branch:main:table:customers", Condition: "tenant_id = :claim.tenant"},
+			},
+		}
+
+		conditions := accessKey.RowPolicyConditions("db", "main")
+
+		if len(conditions) != 1 {
+			t.Fatalf("Expected conditions for 1 table, got %d", len(conditions))
+		}
+
+		expected := "(deleted_at IS NULL) AND (tenant_id = 'a')"
+
+		if conditions["orders"] != expected {
+			t.Errorf("Expected %q, got %q", expected, conditions["orders"])
+		}
+
+		if (&auth.AccessKey{}).RowPolicyConditions("db", "main") != nil {
+			t.Error("Expected no conditions for an access key without row policies")
+		}
+	})
+}
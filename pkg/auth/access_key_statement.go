@@ -2,17 +2,42 @@ package auth
 
 import (
 	"slices"
+	"strings"
 )
 
 type AccessKeyStatement struct {
-	Effect   AccessKeyEffect   `json:"effect" validate:"required,validateFn=IsValid"`
-	Resource AccessKeyResource `json:"resource" validate:"required,validateFn=IsValid"`
-	Actions  []Privilege       `json:"actions" validate:"required,min=1,max=100"`
+	Effect     AccessKeyEffect      `json:"effect" validate:"required,validateFn=IsValid"`
+	Resource   AccessKeyResource    `json:"resource" validate:"required,validateFn=IsValid"`
+	Actions    []Privilege          `json:"actions" validate:"required,min=1,max=100"`
+	Conditions *AccessKeyConditions `json:"conditions,omitempty"`
+	Mask       AccessKeyMask        `json:"mask,omitempty" validate:"omitempty,validateFn=IsValid"`
 }
 
 // This method validates if all of the actions in the statement align with the
 // selected resource.
 func (aks AccessKeyStatement) IsValid() bool {
+	if aks.Conditions != nil && !aks.Conditions.IsValid() {
+		return false
+	}
+
+	// Masks can only be applied by allow statements that grant read access
+	// to column resources.
+	if aks.Mask != "" {
+		if strings.ToLower(string(aks.Effect)) != string(AccessKeyEffectAllow) ||
+			!aks.Resource.HasPrefix("database:") ||
+			!strings.Contains(string(aks.Resource), ":column:") {
+			return false
+		}
+
+		for _, action := range aks.Actions {
+			if action != "*" && action != DatabasePrivilegeRead {
+				return false
+			}
+		}
+
+		return true
+	}
+
 	if aks.Resource == "*" {
 		return true
 	}
@@ -39,154 +39,164 @@ func (accessKey *AccessKey) authorizationKey(strs ...string) string {
 }
 
 func (accessKey *AccessKey) authorizedForBranch(databaseId, branchId string, privilege Privilege) bool {
+	statements := accessKey.effectiveStatements()
+
 	// Any resource
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("*"), privilege) {
 		return true
 	}
 
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database:*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database:*"), privilege) {
 		return true
 	}
 
 	// Any resource of the database
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "*"), privilege) {
 		return true
 	}
 
 	// Any branch resource of the database
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", "*"), privilege) {
 		return true
 	}
 
 	// Any resource of the specific branch of the database
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "*"), privilege) {
 		return true
 	}
 
 	// A specific branch of a specific database
-	return Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", branchId), privilege)
+	return accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", branchId), privilege)
 }
 
 func (accessKey *AccessKey) authorizedForColumn(databaseId, branchId, table, column string, privilege Privilege) bool {
+	statements := accessKey.effectiveStatements()
+
 	// Any resource
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("*"), privilege) {
 		return true
 	}
 
 	// Any resource of the database
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "*"), privilege) {
 		return true
 	}
 
 	// Any resources of the branch
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "*"), privilege) {
 		return true
 	}
 
 	// Any resource of the table
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "table", table, "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "table", table, "*"), privilege) {
 		return true
 	}
 
 	// Any column resource of the table
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "table", table, "column", "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "table", table, "column", "*"), privilege) {
 		return true
 	}
 
 	// A specific column of a specific table of a specific database
-	return Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "table", table, "column", column), privilege)
+	return accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "table", table, "column", column), privilege)
 }
 
 // Determine if an Access Key is authorized to perform an action on a database.
 func (accessKey *AccessKey) authorizedForDatabase(databaseId string, privilege Privilege) bool {
+	statements := accessKey.effectiveStatements()
+
 	// Any resource
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("*"), privilege) {
 		return true
 	}
 
 	// Any database resource
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", "*"), privilege) {
 		return true
 	}
 
 	// A specific database
-	return Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId), privilege)
+	return accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId), privilege)
 }
 
 // Determine if an Access Key is authorized to perform an action on a table.
 func (accessKey *AccessKey) authorizedForTable(databaseId, branchId, table string, privilege Privilege) bool {
+	statements := accessKey.effectiveStatements()
+
 	// Any resource
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("*"), privilege) {
 		return true
 	}
 
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", "*"), privilege) {
 		return true
 	}
 
 	// Any resource of the specific database
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "*"), privilege) {
 		return true
 	}
 
 	// Any resource of the specific database and branch
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", "*"), privilege) {
 		return true
 	}
 
 	// Any resource of the specific database and branch
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", branchId), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", branchId), privilege) {
 		return true
 	}
 
 	// Any resource of the specific database and branch
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "*"), privilege) {
 		return true
 	}
 
 	// Any table resource of the specific branch
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "table", "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "table", "*"), privilege) {
 		return true
 	}
 
 	// A specific table of a specific database
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "table", table), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "table", table), privilege) {
 		return true
 	}
 
 	// Any resource of the specific table
-	return Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "table", table, "*"), privilege)
+	return accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "table", table, "*"), privilege)
 }
 
 // Determine if an Access Key is authorized to perform an action on a module.
 func (accessKey *AccessKey) authorizedForVTable(databaseId, branchId, module, vtable string, privilege Privilege) bool {
+	statements := accessKey.effectiveStatements()
+
 	// Any resource
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("*"), privilege) {
 		return true
 	}
 
 	// Any resource of the specific database
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "*"), privilege) {
 		return true
 	}
 
 	// Any resource of the specific database and branch
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "*"), privilege) {
 		return true
 	}
 
 	// Any module resource of the specific branch
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "module", "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "module", "*"), privilege) {
 		return true
 	}
 
 	// Any vtable resource of the specific module
-	if Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "module", module, "vtable", "*"), privilege) {
+	if accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "module", module, "vtable", "*"), privilege) {
 		return true
 	}
 
 	// A specific vtable of a specific module of a specific database
-	return Authorized(accessKey.Statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "module", module, "vtable", vtable), privilege)
+	return accessKey.authorized(statements, accessKey.authorizationKey("database", databaseId, "branch", branchId, "module", module, "vtable", vtable), privilege)
 }
 
 // Determine if an Access Key is authorized to perform an action on a branch.
@@ -195,7 +205,7 @@ func (accessKey *AccessKey) CanAccessDatabase(databaseId, branchId string) error
 		return NewDatabaseAccessError()
 	}
 
-	for _, statement := range accessKey.Statements {
+	for _, statement := range accessKey.effectiveStatements() {
 		if statement.Resource == "*" {
 			return nil
 		}
@@ -3,6 +3,7 @@ package auth_test
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/litebase/litebase/internal/test"
 	"github.com/litebase/litebase/pkg/auth"
@@ -124,6 +125,94 @@ func TestAccessKey(t *testing.T) {
 			}
 		})
 
+		t.Run("IsExpired", func(t *testing.T) {
+			accessKey := auth.NewAccessKey(
+				app.Auth.AccessKeyManager,
+				"accessKeyId",
+				"accessKeySecret",
+				"Description",
+				[]auth.AccessKeyStatement{},
+			)
+
+			if accessKey.IsExpired() {
+				t.Error("Expected an access key without an expiration to not be expired")
+			}
+
+			expiresAt := time.Now().UTC().Add(time.Hour)
+			accessKey.ExpiresAt = &expiresAt
+
+			if accessKey.IsExpired() {
+				t.Error("Expected the access key to not be expired")
+			}
+
+			expiresAt = time.Now().UTC().Add(-time.Second)
+
+			if !accessKey.IsExpired() {
+				t.Error("Expected the access key to be expired")
+			}
+		})
+
+		t.Run("RotateAccessKey", func(t *testing.T) {
+			accessKey := auth.NewAccessKey(
+				app.Auth.AccessKeyManager,
+				"accessKeyId",
+				"accessSecret",
+				"Description",
+				[]auth.AccessKeyStatement{},
+			)
+
+			err := app.Auth.SecretsManager.StoreAccessKey(accessKey)
+
+			if err != nil {
+				t.Error(err)
+			}
+
+			if err := accessKey.Rotate(time.Hour); err != nil {
+				t.Fatal(err)
+			}
+
+			accessKey, err = app.Auth.AccessKeyManager.Get("accessKeyId")
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if accessKey.AccessKeySecret == "accessSecret" {
+				t.Error("Expected a new access key secret")
+			}
+
+			if accessKey.PreviousSecret() != "accessSecret" {
+				t.Errorf("Expected the previous secret to be 'accessSecret', got %q", accessKey.PreviousSecret())
+			}
+
+			if accessKey.RotatedAt == nil {
+				t.Error("Expected RotatedAt to be set")
+			}
+
+			// Rotating without a grace period invalidates the previous secret
+			if err := accessKey.Rotate(0); err != nil {
+				t.Fatal(err)
+			}
+
+			if accessKey.PreviousSecret() != "" {
+				t.Errorf("Expected no previous secret, got %q", accessKey.PreviousSecret())
+			}
+		})
+
+		t.Run("PreviousSecretExpires", func(t *testing.T) {
+			previousSecretExpiresAt := time.Now().UTC().Add(-time.Second)
+
+			accessKey := &auth.AccessKey{
+				AccessKeySecret:         "accessKeySecret",
+				PreviousAccessKeySecret: "previousSecret",
+				PreviousSecretExpiresAt: &previousSecretExpiresAt,
+			}
+
+			if accessKey.PreviousSecret() != "" {
+				t.Errorf("Expected the previous secret to have expired, got %q", accessKey.PreviousSecret())
+			}
+		})
+
 		t.Run("UpdateAccessKey", func(t *testing.T) {
 			accessKey := auth.NewAccessKey(
 				app.Auth.AccessKeyManager,
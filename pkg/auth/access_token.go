@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"strings"
+	"time"
+)
+
+// The maximum lifetime of an access token.
+const AccessTokenMaxTTL = time.Hour
+
+// The default lifetime of an access token.
+const AccessTokenDefaultTTL = 15 * time.Minute
+
+var (
+	ErrAccessTokenExpired  = errors.New("the access token has expired")
+	ErrAccessTokenInvalid  = errors.New("the access token is invalid")
+	ErrAccessTokenScope    = errors.New("the access token statements must be a subset of the access key statements")
+	ErrAccessTokenTTLRange = errors.New("the access token ttl must be between 1 second and 1 hour")
+)
+
+// An AccessToken is a short-lived bearer token issued for an access key. The
+// token carries a subset of the statements of the access key and is signed
+// with the private key of the cluster, so clients can use it without ever
+// holding the access key secret.
+type AccessToken struct {
+	AccessKeyID string               `json:"access_key_id"`
+	Claims      map[string]string    `json:"claims,omitempty"`
+	ExpiresAt   int64                `json:"exp"`
+	IssuedAt    int64                `json:"iat"`
+	RowPolicies []AccessKeyRowPolicy `json:"row_policies,omitempty"`
+	Statements  []AccessKeyStatement `json:"statements"`
+}
+
+// Issue a new access token for the access key that is valid for the ttl. Each
+// allow statement of the token must be authorized by the access key.
+func (akm *AccessKeyManager) IssueAccessToken(
+	accessKey *AccessKey,
+	statements []AccessKeyStatement,
+	ttl time.Duration,
+) (string, *AccessToken, error) {
+	return akm.IssueAccessTokenWithRowPolicies(accessKey, statements, nil, nil, ttl)
+}
+
+// Issue a new access token for the access key that carries claims and row
+// policies in addition to its statements. The row policies of the access key
+// still apply to the token, and claims of the access key take precedence over
+// the claims of the token.
+func (akm *AccessKeyManager) IssueAccessTokenWithRowPolicies(
+	accessKey *AccessKey,
+	statements []AccessKeyStatement,
+	claims map[string]string,
+	rowPolicies []AccessKeyRowPolicy,
+	ttl time.Duration,
+) (string, *AccessToken, error) {
+	if ttl < time.Second || ttl > AccessTokenMaxTTL {
+		return "", nil, ErrAccessTokenTTLRange
+	}
+
+	accessKeyStatements := accessKey.effectiveStatements()
+
+	for _, statement := range statements {
+		if strings.ToLower(string(statement.Effect)) != "allow" {
+			continue
+		}
+
+		for _, action := range statement.Actions {
+			if !accessKey.authorized(accessKeyStatements, string(statement.Resource), action) {
+				return "", nil, ErrAccessTokenScope
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+
+	accessToken := &AccessToken{
+		AccessKeyID: accessKey.AccessKeyID,
+		Claims:      claims,
+		ExpiresAt:   now.Add(ttl).Unix(),
+		IssuedAt:    now.Unix(),
+		RowPolicies: rowPolicies,
+		Statements:  statements,
+	}
+
+	payload, err := json.Marshal(accessToken)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	token, err := akm.auth.signToken(payload)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, accessToken, nil
+}
+
+// Verify an access token and return an access key that is limited to the
+// statements of the token. The current statements of the access key the token
+// was issued for still limit the token, and the token is rejected once that
+// access key has been deleted or has expired.
+func (akm *AccessKeyManager) VerifyAccessToken(token string) (*AccessKey, error) {
+	payload, err := akm.auth.verifyToken(token)
+
+	if err != nil {
+		if errors.Is(err, errSignedTokenInvalid) {
+			return nil, ErrAccessTokenInvalid
+		}
+
+		return nil, err
+	}
+
+	accessToken := AccessToken{}
+
+	if err := json.Unmarshal(payload, &accessToken); err != nil {
+		return nil, ErrAccessTokenInvalid
+	}
+
+	expiresAt := time.Unix(accessToken.ExpiresAt, 0).UTC()
+
+	if !time.Now().UTC().Before(expiresAt) {
+		return nil, ErrAccessTokenExpired
+	}
+
+	accessKey, err := akm.Get(accessToken.AccessKeyID)
+
+	if err != nil {
+		return nil, ErrAccessTokenInvalid
+	}
+
+	if accessKey.IsExpired() {
+		return nil, ErrAccessTokenExpired
+	}
+
+	statements := append([]AccessKeyStatement{}, accessToken.Statements...)
+	accessKeyStatements := accessKey.effectiveStatements()
+
+	for _, statement := range accessKeyStatements {
+		if strings.ToLower(string(statement.Effect)) == "deny" {
+			statements = append(statements, statement)
+		}
+	}
+
+	// The current statements of the access key also apply to its tokens, so
+	// a token is limited to what the access key can do now, is not used where
+	// the access key itself could not be, and cannot read columns the access
+	// key reads masked.
+	parentStatements := accessKeyStatements
+
+	if parentStatements == nil {
+		parentStatements = []AccessKeyStatement{}
+	}
+
+	var claims map[string]string
+
+	if len(accessToken.Claims) > 0 || len(accessKey.Claims) > 0 {
+		claims = make(map[string]string, len(accessToken.Claims)+len(accessKey.Claims))
+
+		maps.Copy(claims, accessToken.Claims)
+		maps.Copy(claims, accessKey.Claims)
+	}
+
+	return &AccessKey{
+		accessKeyManager: akm,
+		AccessKeyID:      accessKey.AccessKeyID,
+		Claims:           claims,
+		Description:      accessKey.Description,
+		CreatedAt:        time.Unix(accessToken.IssuedAt, 0).UTC(),
+		ExpiresAt:        &expiresAt,
+		RowPolicies:      append(append([]AccessKeyRowPolicy{}, accessKey.RowPolicies...), accessToken.RowPolicies...),
+		UpdatedAt:        time.Unix(accessToken.IssuedAt, 0).UTC(),
+		Statements:       statements,
+		parentStatements: parentStatements,
+		token:            token,
+	}, nil
+}
@@ -0,0 +1,301 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/server"
+)
+
+func TestAccessToken(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		accessKey, err := app.Auth.AccessKeyManager.Create("Test", []auth.AccessKeyStatement{
+			{Effect: "Allow", Resource: "database:*", Actions: []auth.Privilege{"*"}},
+			{Effect: "Deny", Resource: "database:secret:*", Actions: []auth.Privilege{"*"}},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run("IssueAndVerify", func(t *testing.T) {
+			token, accessToken, err := app.Auth.AccessKeyManager.IssueAccessToken(
+				accessKey,
+				[]auth.AccessKeyStatement{
+					{Effect: "Allow", Resource: "database:test:branch:main", Actions: []auth.Privilege{auth.DatabasePrivilegeQuery}},
+				},
+				time.Minute,
+			)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if accessToken.AccessKeyID != accessKey.AccessKeyID {
+				t.Errorf("Expected the token to be issued for %s, got %s", accessKey.AccessKeyID, accessToken.AccessKeyID)
+			}
+
+			scopedAccessKey, err := app.Auth.AccessKeyManager.VerifyAccessToken(token)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if scopedAccessKey.Token() != token {
+				t.Error("Expected the scoped access key to reference the token")
+			}
+
+			if !scopedAccessKey.AuthorizeForResource([]string{"database:test:branch:main"}, []auth.Privilege{auth.DatabasePrivilegeQuery}) {
+				t.Error("Expected the token to authorize queries on the branch")
+			}
+
+			if scopedAccessKey.AuthorizeForResource([]string{"database:test:branch:dev"}, []auth.Privilege{auth.DatabasePrivilegeQuery}) {
+				t.Error("Expected the token to not authorize queries on other branches")
+			}
+
+			if scopedAccessKey.AuthorizeForResource([]string{"database:*"}, []auth.Privilege{auth.DatabasePrivilegeCreate}) {
+				t.Error("Expected the token to not authorize other actions")
+			}
+		})
+
+		t.Run("KeepsDenyStatements", func(t *testing.T) {
+			token, _, err := app.Auth.AccessKeyManager.IssueAccessToken(
+				accessKey,
+				[]auth.AccessKeyStatement{
+					{Effect: "Allow", Resource: "database:*", Actions: []auth.Privilege{auth.DatabasePrivilegeQuery}},
+				},
+				time.Minute,
+			)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			scopedAccessKey, err := app.Auth.AccessKeyManager.VerifyAccessToken(token)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if scopedAccessKey.AuthorizeForResource([]string{"database:secret:branch:main"}, []auth.Privilege{auth.DatabasePrivilegeQuery}) {
+				t.Error("Expected the deny statements of the access key to apply to the token")
+			}
+		})
+
+		t.Run("NarrowedWithAccessKey", func(t *testing.T) {
+			revokedAccessKey, err := app.Auth.AccessKeyManager.Create("Revoked", []auth.AccessKeyStatement{
+				{Effect: "Allow", Resource: "database:*", Actions: []auth.Privilege{"*"}},
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			token, _, err := app.Auth.AccessKeyManager.IssueAccessToken(
+				revokedAccessKey,
+				[]auth.AccessKeyStatement{
+					{Effect: "Allow", Resource: "database:test:branch:main", Actions: []auth.Privilege{auth.DatabasePrivilegeQuery}},
+				},
+				time.Minute,
+			)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Revoking the allow statement of the access key revokes it from
+			// the tokens that were already issued.
+			err = revokedAccessKey.Update("Revoked", []auth.AccessKeyStatement{
+				{Effect: "Allow", Resource: "database:other:*", Actions: []auth.Privilege{"*"}},
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			scopedAccessKey, err := app.Auth.AccessKeyManager.VerifyAccessToken(token)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if scopedAccessKey.AuthorizeForResource([]string{"database:test:branch:main"}, []auth.Privilege{auth.DatabasePrivilegeQuery}) {
+				t.Error("Expected the token to lose the statements revoked from the access key")
+			}
+		})
+
+		t.Run("RejectsBroaderScope", func(t *testing.T) {
+			_, _, err := app.Auth.AccessKeyManager.IssueAccessToken(
+				accessKey,
+				[]auth.AccessKeyStatement{
+					{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}},
+				},
+				time.Minute,
+			)
+
+			if err != auth.ErrAccessTokenScope {
+				t.Errorf("Expected %v, got %v", auth.ErrAccessTokenScope, err)
+			}
+		})
+
+		t.Run("RejectsInvalidTTL", func(t *testing.T) {
+			for _, ttl := range []time.Duration{0, 2 * time.Hour} {
+				_, _, err := app.Auth.AccessKeyManager.IssueAccessToken(accessKey, []auth.AccessKeyStatement{}, ttl)
+
+				if err != auth.ErrAccessTokenTTLRange {
+					t.Errorf("Expected %v, got %v", auth.ErrAccessTokenTTLRange, err)
+				}
+			}
+		})
+
+		t.Run("RejectsTamperedToken", func(t *testing.T) {
+			token, _, err := app.Auth.AccessKeyManager.IssueAccessToken(accessKey, []auth.AccessKeyStatement{}, time.Minute)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			payload, signature, _ := strings.Cut(token, ".")
+
+			for _, tampered := range []string{
+				payload + "x." + signature,
+				payload,
+				"",
+			} {
+				if _, err := app.Auth.AccessKeyManager.VerifyAccessToken(tampered); err != auth.ErrAccessTokenInvalid {
+					t.Errorf("Expected %v, got %v", auth.ErrAccessTokenInvalid, err)
+				}
+			}
+		})
+
+		t.Run("RejectsExpiredToken", func(t *testing.T) {
+			token, _, err := app.Auth.AccessKeyManager.IssueAccessToken(accessKey, []auth.AccessKeyStatement{}, time.Second)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			time.Sleep(time.Second)
+
+			if _, err := app.Auth.AccessKeyManager.VerifyAccessToken(token); err != auth.ErrAccessTokenExpired {
+				t.Errorf("Expected %v, got %v", auth.ErrAccessTokenExpired, err)
+			}
+		})
+
+		t.Run("WithRowPolicies", func(t *testing.T) {
+			policyAccessKey, err := app.Auth.AccessKeyManager.Create("Policies", []auth.AccessKeyStatement{
+				{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}},
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = policyAccessKey.UpdateRowPolicies(
+				map[string]string{"tenant": "a"},
+				[]auth.AccessKeyRowPolicy{{Resource: "database:*:branch:*:table:orders", Condition: "tenant_id = :claim.tenant"}},
+			)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			token, _, err := app.Auth.AccessKeyManager.IssueAccessTokenWithRowPolicies(
+				policyAccessKey,
+				[]auth.AccessKeyStatement{},
+				map[string]string{"tenant": "b", "user": "1"},
+				[]auth.AccessKeyRowPolicy{{Resource: "database:*:branch:*:table:orders", Condition: "user_id = :claim.user"}},
+				time.Minute,
+			)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			scopedAccessKey, err := app.Auth.AccessKeyManager.VerifyAccessToken(token)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if scopedAccessKey.Claims["tenant"] != "a" || scopedAccessKey.Claims["user"] != "1" {
+				t.Errorf("Expected the claims of the access key to take precedence, got %v", scopedAccessKey.Claims)
+			}
+
+			if len(scopedAccessKey.RowPolicies) != 2 {
+				t.Errorf("Expected the row policies of the access key and the token, got %v", scopedAccessKey.RowPolicies)
+			}
+		})
+
+		t.Run("KeepsColumnMasks", func(t *testing.T) {
+			maskedAccessKey, err := app.Auth.AccessKeyManager.Create("Masked", []auth.AccessKeyStatement{
+				{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}},
+				{Effect: "Allow", Resource: "database:db:branch:main:table:users:column:email", Actions: []auth.Privilege{auth.DatabasePrivilegeRead}, Mask: auth.AccessKeyMaskHash},
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The token grants read access to the column without a mask,
+			// which must not lift the mask of the access key.
+			token, _, err := app.Auth.AccessKeyManager.IssueAccessToken(
+				maskedAccessKey,
+				[]auth.AccessKeyStatement{
+					{Effect: "Allow", Resource: "database:db:branch:main:table:users:column:email", Actions: []auth.Privilege{auth.DatabasePrivilegeRead}},
+					{Effect: "Allow", Resource: "database:db:branch:main:table:users:column:name", Actions: []auth.Privilege{auth.DatabasePrivilegeRead}, Mask: auth.AccessKeyMaskRedact},
+				},
+				time.Minute,
+			)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			scopedAccessKey, err := app.Auth.AccessKeyManager.VerifyAccessToken(token)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !scopedAccessKey.HasColumnMasks() {
+				t.Error("Expected the token to mask columns")
+			}
+
+			if mask := scopedAccessKey.ColumnMask("db", "main", "users", "email"); mask != auth.AccessKeyMaskHash {
+				t.Errorf("Expected the mask of the access key to apply, got %q", mask)
+			}
+
+			if mask := scopedAccessKey.ColumnMask("db", "main", "users", "name"); mask != auth.AccessKeyMaskRedact {
+				t.Errorf("Expected the mask of the token to apply, got %q", mask)
+			}
+		})
+
+		t.Run("RejectsDeletedAccessKey", func(t *testing.T) {
+			deletedAccessKey, err := app.Auth.AccessKeyManager.Create("Deleted", []auth.AccessKeyStatement{
+				{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}},
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			token, _, err := app.Auth.AccessKeyManager.IssueAccessToken(deletedAccessKey, []auth.AccessKeyStatement{}, time.Minute)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := deletedAccessKey.Delete(); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := app.Auth.AccessKeyManager.VerifyAccessToken(token); err != auth.ErrAccessTokenInvalid {
+				t.Errorf("Expected %v, got %v", auth.ErrAccessTokenInvalid, err)
+			}
+		})
+	})
+}
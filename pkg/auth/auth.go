@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"sync"
+
 	"github.com/litebase/litebase/pkg/config"
 	"github.com/litebase/litebase/pkg/storage"
 )
@@ -12,8 +14,11 @@ type Auth struct {
 	SecretsManager   *SecretsManager
 	TmpFS            *storage.FileSystem
 
-	broadcaster func(key string, value string)
-	userManager *UserManager
+	broadcaster  func(key string, value string)
+	oidcMutex    sync.Mutex
+	oidcProvider *OIDCProvider
+	roleManager  *RoleManager
+	userManager  *UserManager
 }
 
 func NewAuth(
@@ -107,3 +107,47 @@ func TestAuthorized(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluate(t *testing.T) {
+	statements := []auth.AccessKeyStatement{
+		{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}},
+		{Effect: "Allow", Resource: "database:db1:*", Actions: []auth.Privilege{"read"}},
+		{Effect: "Deny", Resource: "database:db1:table:secrets", Actions: []auth.Privilege{"read"}},
+	}
+
+	t.Run("most specific allow decides", func(t *testing.T) {
+		decision := auth.Evaluate(statements, "database:db1:table:users", "read", nil)
+
+		if !decision.Allowed {
+			t.Fatal("expected the action to be allowed")
+		}
+
+		if decision.Statement == nil || decision.Statement.Resource != "database:db1:*" {
+			t.Fatalf("expected the database statement to decide, got %v", decision.Statement)
+		}
+
+		if decision.Action != "read" || decision.Resource != "database:db1:table:users" {
+			t.Errorf("unexpected action or resource: %v", decision)
+		}
+	})
+
+	t.Run("deny decides", func(t *testing.T) {
+		decision := auth.Evaluate(statements, "database:db1:table:secrets", "read", nil)
+
+		if decision.Allowed {
+			t.Fatal("expected the action to be denied")
+		}
+
+		if decision.Statement == nil || decision.Statement.Effect != "Deny" {
+			t.Fatalf("expected the deny statement to decide, got %v", decision.Statement)
+		}
+	})
+
+	t.Run("implicit deny has no statement", func(t *testing.T) {
+		decision := auth.Evaluate(statements[1:2], "database:db2:table:users", "read", nil)
+
+		if decision.Allowed || decision.Statement != nil {
+			t.Errorf("expected an implicit deny, got %v", decision)
+		}
+	})
+}
@@ -0,0 +1,15 @@
+package auth
+
+import (
+	"net/netip"
+	"time"
+)
+
+// The context of a request that the conditions of statements are evaluated
+// against.
+type AuthorizationContext struct {
+	Replica  bool
+	SourceIP netip.Addr
+	Time     time.Time
+	TLS      bool
+}
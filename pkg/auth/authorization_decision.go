@@ -0,0 +1,11 @@
+package auth
+
+// An AuthorizationDecision is the outcome of evaluating statements for an
+// action on a resource. Statement is the statement that decided the outcome,
+// or nil when the action was denied because no statement matched.
+type AuthorizationDecision struct {
+	Action    Privilege           `json:"action"`
+	Allowed   bool                `json:"allowed"`
+	Resource  string              `json:"resource"`
+	Statement *AccessKeyStatement `json:"statement"`
+}
@@ -27,14 +27,45 @@ func resourceMatches(pattern, resource string) bool {
 }
 
 // Determine if an Access Key is authorized to perform an action on a resource.
+// Statements with conditions are evaluated as if there is no request context.
 func Authorized(statements []AccessKeyStatement, resource string, permission Privilege) bool {
+	return AuthorizedWithContext(statements, resource, permission, nil)
+}
+
+// Determine if an Access Key is authorized to perform an action on a resource
+// for a request. Statements only apply when their conditions are met in the
+// context of the request.
+func AuthorizedWithContext(
+	statements []AccessKeyStatement,
+	resource string,
+	permission Privilege,
+	context *AuthorizationContext,
+) bool {
+	return Evaluate(statements, resource, permission, context).Allowed
+}
+
+// Evaluate the statements for an action on a resource and return the decision
+// along with the statement that decided it. A matching deny statement always
+// takes precedence, otherwise the most specific matching allow statement
+// decides. When no statement matches the action is denied without a statement.
+func Evaluate(
+	statements []AccessKeyStatement,
+	resource string,
+	permission Privilege,
+	context *AuthorizationContext,
+) AuthorizationDecision {
+	decision := AuthorizationDecision{
+		Action:   permission,
+		Resource: resource,
+	}
+
 	// Order the statements in descending order based on the number of
 	// segmentations in the resource (most specific first)
 	slices.SortFunc(statements, func(a, b AccessKeyStatement) int {
 		return strings.Count(string(b.Resource), ":") - strings.Count(string(a.Resource), ":")
 	})
 
-	var allowFound bool
+	var allowStatement *AccessKeyStatement
 
 	for _, statement := range statements {
 		// Check if the statement resource matches the requested resource, or is a wildcard
@@ -42,35 +73,33 @@ func Authorized(statements []AccessKeyStatement, resource string, permission Pri
 			continue
 		}
 
-		// Check if the statement allows all actions
-		if len(statement.Actions) == 1 && statement.Actions[0] == "*" {
-			if strings.ToLower(string(statement.Effect)) == "deny" {
-				return false // Deny always takes precedence
-			}
-
-			if strings.ToLower(string(statement.Effect)) == "allow" {
-				allowFound = true
-			}
+		if !statement.appliesTo(context) {
+			continue
+		}
 
+		// Check if the statement allows all actions, or the specific permission
+		if !(len(statement.Actions) == 1 && statement.Actions[0] == "*") &&
+			!slices.Contains(statement.Actions, permission) {
 			continue
 		}
 
-		// Check if the statement allows the specific permission
-		if slices.Contains(statement.Actions, permission) {
-			if strings.ToLower(string(statement.Effect)) == "deny" {
-				return false // Deny always takes precedence
-			}
+		if strings.ToLower(string(statement.Effect)) == "deny" {
+			// Deny always takes precedence
+			decision.Statement = &statement
+
+			return decision
+		}
 
-			if strings.ToLower(string(statement.Effect)) == "allow" {
-				allowFound = true
-			}
+		if strings.ToLower(string(statement.Effect)) == "allow" && allowStatement == nil {
+			allowStatement = &statement
 		}
 	}
 
-	if allowFound {
-		return true
+	if allowStatement != nil {
+		decision.Allowed = true
+		decision.Statement = allowStatement
 	}
 
 	// Deny by default if no statement matches
-	return false
+	return decision
 }
@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+// The lockout after the first failed attempt over the allowed attempts. Each
+// further failed attempt doubles the lockout up to the maximum lockout.
+const loginLockoutBase = time.Second
+
+// The number of tracked keys after which keys that are no longer locked out
+// are removed.
+const loginThrottlePruneSize = 1024
+
+// A LoginLockedError is returned when a login is refused because of too many
+// failed attempts.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+/*
+A LoginThrottle tracks failed logins by key, such as a username or a source
+address, and locks a key out with exponential backoff once it has failed more
+than the allowed attempts. Failures are forgotten once the maximum lockout has
+passed since the last failure. The throttle is held in memory, so each node
+tracks the attempts it received.
+*/
+type LoginThrottle struct {
+	attempts    map[string]*loginAttempts
+	maxAttempts int64
+	maxLockout  time.Duration
+	mutex       *sync.Mutex
+	now         func() time.Time
+}
+
+type loginAttempts struct {
+	failures    int64
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// Create a new LoginThrottle.
+func NewLoginThrottle(maxAttempts int64, maxLockout time.Duration) *LoginThrottle {
+	return &LoginThrottle{
+		attempts:    map[string]*loginAttempts{},
+		maxAttempts: maxAttempts,
+		maxLockout:  maxLockout,
+		mutex:       &sync.Mutex{},
+		now:         time.Now,
+	}
+}
+
+// Return the time until the longest lockout of the keys ends, or zero when
+// none of the keys are locked out.
+func (t *LoginThrottle) Check(keys ...string) time.Duration {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var retryAfter time.Duration
+
+	now := t.now()
+
+	for _, key := range keys {
+		if attempts, ok := t.attempts[key]; ok && attempts.lockedUntil.After(now) {
+			retryAfter = max(retryAfter, attempts.lockedUntil.Sub(now))
+		}
+	}
+
+	return retryAfter
+}
+
+// Record a failed login for the keys.
+func (t *LoginThrottle) Fail(keys ...string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.now()
+
+	if len(t.attempts) >= loginThrottlePruneSize {
+		t.prune(now)
+	}
+
+	for _, key := range keys {
+		attempts, ok := t.attempts[key]
+
+		if !ok || now.Sub(attempts.lastFailure) > t.maxLockout {
+			attempts = &loginAttempts{}
+			t.attempts[key] = attempts
+		}
+
+		attempts.failures++
+		attempts.lastFailure = now
+
+		if t.maxAttempts > 0 && attempts.failures > t.maxAttempts {
+			attempts.lockedUntil = now.Add(t.lockout(attempts.failures - t.maxAttempts))
+		}
+	}
+}
+
+// Set the clock the throttle measures lockouts with.
+func (t *LoginThrottle) SetNow(now func() time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.now = now
+}
+
+// Forget the failed logins of the keys after a successful login.
+func (t *LoginThrottle) Succeed(keys ...string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, key := range keys {
+		delete(t.attempts, key)
+	}
+}
+
+// Return the lockout for the number of failed attempts over the allowed
+// attempts.
+func (t *LoginThrottle) lockout(excess int64) time.Duration {
+	if excess > 32 {
+		return t.maxLockout
+	}
+
+	return min(loginLockoutBase<<(excess-1), t.maxLockout)
+}
+
+func (t *LoginThrottle) prune(now time.Time) {
+	for key, attempts := range t.attempts {
+		if !attempts.lockedUntil.After(now) && now.Sub(attempts.lastFailure) > t.maxLockout {
+			delete(t.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestLoginThrottle(t *testing.T) {
+	throttle := auth.NewLoginThrottle(3, time.Minute)
+
+	for range 3 {
+		throttle.Fail("user:jane", "ip:10.0.0.1")
+	}
+
+	if retryAfter := throttle.Check("user:jane", "ip:10.0.0.1"); retryAfter != 0 {
+		t.Fatalf("Expected no lockout within the allowed attempts, got %s", retryAfter)
+	}
+
+	throttle.Fail("user:jane", "ip:10.0.0.1")
+
+	retryAfter := throttle.Check("user:jane")
+
+	if retryAfter <= 0 || retryAfter > time.Second {
+		t.Errorf("Expected a lockout of at most one second, got %s", retryAfter)
+	}
+
+	throttle.Fail("user:jane", "ip:10.0.0.1")
+	throttle.Fail("user:jane", "ip:10.0.0.1")
+
+	retryAfter = throttle.Check("user:jane")
+
+	if retryAfter <= 2*time.Second || retryAfter > 4*time.Second {
+		t.Errorf("Expected the lockout to double with each failed attempt, got %s", retryAfter)
+	}
+
+	if throttle.Check("user:john") != 0 {
+		t.Error("Expected other users not to be locked out")
+	}
+
+	// A successful login only forgets the failures of the user, so the
+	// source address stays locked out.
+	throttle.Succeed("user:jane")
+
+	if throttle.Check("user:jane") != 0 {
+		t.Error("Expected the lockout of the user to be forgotten")
+	}
+
+	if throttle.Check("user:john", "ip:10.0.0.1") == 0 {
+		t.Error("Expected the source address to stay locked out")
+	}
+}
+
+func TestLoginThrottleMaximumLockout(t *testing.T) {
+	throttle := auth.NewLoginThrottle(1, 5*time.Second)
+
+	for range 100 {
+		throttle.Fail("user:jane")
+	}
+
+	if retryAfter := throttle.Check("user:jane"); retryAfter <= 0 || retryAfter > 5*time.Second {
+		t.Errorf("Expected the lockout to be capped at the maximum lockout, got %s", retryAfter)
+	}
+}
+
+func TestLoginLockedError(t *testing.T) {
+	err := &auth.LoginLockedError{RetryAfter: 1500 * time.Millisecond}
+
+	if err.Error() != "too many failed login attempts, try again in 2s" {
+		t.Errorf("Unexpected error message: %s", err.Error())
+	}
+}
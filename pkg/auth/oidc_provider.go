@@ -0,0 +1,462 @@
+package auth
+
+import (
+	"cmp"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/litebase/litebase/pkg/config"
+)
+
+var (
+	ErrOIDCNotConfigured = errors.New("single sign-on is not configured")
+	ErrOIDCTokenInvalid  = errors.New("the identity token is invalid")
+	ErrOIDCTokenExpired  = errors.New("the identity token has expired")
+)
+
+// The clock skew allowed when checking the times of an identity token.
+const oidcClockSkew = time.Minute
+
+// The minimum time between fetching the signing keys of the provider when a
+// token is signed with an unknown key.
+const oidcKeysRefreshInterval = time.Minute
+
+// An OIDCProvider verifies identity tokens issued by an OpenID Connect
+// provider for the client of the cluster. The discovery document and signing
+// keys of the provider are fetched when first needed, and the keys are fetched
+// again when a token is signed with a key that is not known yet.
+type OIDCProvider struct {
+	ClientID      string
+	GroupsClaim   string
+	Issuer        string
+	RoleMappings  map[string][]string
+	Scopes        []string
+	UsernameClaim string
+
+	httpClient    *http.Client
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+	metadata      *OIDCProviderMetadata
+	mutex         *sync.Mutex
+}
+
+// The parts of the discovery document of a provider that are used.
+type OIDCProviderMetadata struct {
+	AuthorizationEndpoint       string `json:"authorization_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	Issuer                      string `json:"issuer"`
+	JWKSURI                     string `json:"jwks_uri"`
+	TokenEndpoint               string `json:"token_endpoint"`
+}
+
+// An OIDCIdentity is the verified identity of an identity token.
+type OIDCIdentity struct {
+	ExpiresAt time.Time
+	Groups    []string
+	Subject   string
+	Username  string
+}
+
+// Return the OIDCProvider of the cluster, or nil when single sign-on is not
+// configured.
+func (auth *Auth) OIDCProvider() *OIDCProvider {
+	auth.oidcMutex.Lock()
+	defer auth.oidcMutex.Unlock()
+
+	if auth.Config.OIDCIssuer == "" || auth.Config.OIDCClientID == "" {
+		return nil
+	}
+
+	if auth.oidcProvider != nil &&
+		auth.oidcProvider.Issuer == strings.TrimRight(auth.Config.OIDCIssuer, "/") &&
+		auth.oidcProvider.ClientID == auth.Config.OIDCClientID {
+		return auth.oidcProvider
+	}
+
+	auth.oidcProvider = NewOIDCProvider(auth.Config)
+
+	return auth.oidcProvider
+}
+
+// Create a new OIDCProvider from the configuration. Role mappings are given
+// as group=role pairs.
+func NewOIDCProvider(c *config.Config) *OIDCProvider {
+	mappings := map[string][]string{}
+
+	for _, mapping := range c.OIDCRoleMappings {
+		group, role, ok := strings.Cut(mapping, "=")
+
+		if !ok || group == "" || role == "" {
+			continue
+		}
+
+		mappings[group] = append(mappings[group], role)
+	}
+
+	provider := &OIDCProvider{
+		ClientID:      c.OIDCClientID,
+		GroupsClaim:   cmp.Or(c.OIDCGroupsClaim, "groups"),
+		Issuer:        strings.TrimRight(c.OIDCIssuer, "/"),
+		RoleMappings:  mappings,
+		Scopes:        c.OIDCScopes,
+		UsernameClaim: cmp.Or(c.OIDCUsernameClaim, "email"),
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
+		keys:          map[string]crypto.PublicKey{},
+		mutex:         &sync.Mutex{},
+	}
+
+	if len(provider.Scopes) == 0 {
+		provider.Scopes = []string{"openid", "profile", "email"}
+	}
+
+	return provider
+}
+
+// Return the discovery document of the provider.
+func (p *OIDCProvider) Metadata() (*OIDCProviderMetadata, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.loadMetadata()
+}
+
+func (p *OIDCProvider) loadMetadata() (*OIDCProviderMetadata, error) {
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	metadata := &OIDCProviderMetadata{}
+
+	if err := p.getJSON(p.Issuer+"/.well-known/openid-configuration", metadata); err != nil {
+		return nil, fmt.Errorf("failed to discover the identity provider: %w", err)
+	}
+
+	if strings.TrimRight(metadata.Issuer, "/") != p.Issuer {
+		return nil, fmt.Errorf("the identity provider issuer %q does not match %q", metadata.Issuer, p.Issuer)
+	}
+
+	p.metadata = metadata
+
+	return p.metadata, nil
+}
+
+// Return the roles mapped to the groups.
+func (p *OIDCProvider) Roles(groups []string) []string {
+	roles := []string{}
+
+	for _, group := range groups {
+		for _, role := range p.RoleMappings[group] {
+			if !slices.Contains(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+	}
+
+	slices.Sort(roles)
+
+	return roles
+}
+
+// Verify an identity token issued by the provider for the client and return
+// the identity it holds.
+func (p *OIDCProvider) Verify(token string) (*OIDCIdentity, error) {
+	parts := strings.Split(token, ".")
+
+	if len(parts) != 3 {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}
+
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+
+	if err != nil {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	key, err := p.key(header.KeyID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !verifyJWTSignature(header.Algorithm, key, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	claims := map[string]any{}
+
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	if issuer, _ := claims["iss"].(string); strings.TrimRight(issuer, "/") != p.Issuer {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	if !slices.Contains(claimStrings(claims["aud"]), p.ClientID) {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	now := time.Now()
+	expiresAt, ok := claimTime(claims["exp"])
+
+	if !ok {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	if now.After(expiresAt.Add(oidcClockSkew)) {
+		return nil, ErrOIDCTokenExpired
+	}
+
+	if notBefore, ok := claimTime(claims["nbf"]); ok && now.Add(oidcClockSkew).Before(notBefore) {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	subject, _ := claims["sub"].(string)
+	username, _ := claims[p.UsernameClaim].(string)
+
+	if subject == "" || username == "" {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	return &OIDCIdentity{
+		ExpiresAt: expiresAt,
+		Groups:    claimStrings(claims[p.GroupsClaim]),
+		Subject:   subject,
+		Username:  username,
+	}, nil
+}
+
+func (p *OIDCProvider) getJSON(url string, value any) error {
+	response, err := p.httpClient.Get(url)
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s from %s", response.Status, url)
+	}
+
+	return json.NewDecoder(response.Body).Decode(value)
+}
+
+// Return the signing key with the id, fetching the keys of the provider when
+// the key is not known.
+func (p *OIDCProvider) key(keyID string) (crypto.PublicKey, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if key, ok := p.keys[keyID]; ok {
+		return key, nil
+	}
+
+	if time.Since(p.keysFetchedAt) < oidcKeysRefreshInterval {
+		return nil, ErrOIDCTokenInvalid
+	}
+
+	metadata, err := p.loadMetadata()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var keySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := p.getJSON(metadata.JWKSURI, &keySet); err != nil {
+		return nil, fmt.Errorf("failed to fetch the identity provider keys: %w", err)
+	}
+
+	p.keys = map[string]crypto.PublicKey{}
+	p.keysFetchedAt = time.Now()
+
+	for _, jwk := range keySet.Keys {
+		if key, ok := jwk.publicKey(); ok {
+			p.keys[jwk.KeyID] = key
+		}
+	}
+
+	if key, ok := p.keys[keyID]; ok {
+		return key, nil
+	}
+
+	return nil, ErrOIDCTokenInvalid
+}
+
+type jsonWebKey struct {
+	Curve   string `json:"crv"`
+	E       string `json:"e"`
+	KeyID   string `json:"kid"`
+	KeyType string `json:"kty"`
+	N       string `json:"n"`
+	Use     string `json:"use"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+// Return the public key of an RSA or EC signing key.
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, bool) {
+	if jwk.Use != "" && jwk.Use != "sig" {
+		return nil, false
+	}
+
+	switch jwk.KeyType {
+	case "RSA":
+		n, nOk := decodeBigInt(jwk.N)
+		e, eOk := decodeBigInt(jwk.E)
+
+		if !nOk || !eOk || !e.IsInt64() {
+			return nil, false
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, true
+	case "EC":
+		var curve elliptic.Curve
+
+		switch jwk.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, false
+		}
+
+		x, xOk := decodeBigInt(jwk.X)
+		y, yOk := decodeBigInt(jwk.Y)
+
+		if !xOk || !yOk || !curve.IsOnCurve(x, y) {
+			return nil, false
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, true
+	}
+
+	return nil, false
+}
+
+// Verify the signature of a token with one of the supported algorithms.
+func verifyJWTSignature(algorithm string, key crypto.PublicKey, signed, signature []byte) bool {
+	var hash crypto.Hash
+
+	switch algorithm {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512":
+		hash = crypto.SHA512
+	default:
+		return false
+	}
+
+	var digest []byte
+
+	switch hash {
+	case crypto.SHA256:
+		sum := sha256.Sum256(signed)
+		digest = sum[:]
+	case crypto.SHA384:
+		sum := sha512.Sum384(signed)
+		digest = sum[:]
+	case crypto.SHA512:
+		sum := sha512.Sum512(signed)
+		digest = sum[:]
+	}
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return strings.HasPrefix(algorithm, "RS") &&
+			rsa.VerifyPKCS1v15(key, hash, digest, signature) == nil
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+
+		if !strings.HasPrefix(algorithm, "ES") || len(signature) != 2*size {
+			return false
+		}
+
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+
+		return ecdsa.Verify(key, digest, r, s)
+	}
+
+	return false
+}
+
+func decodeBigInt(value string) (*big.Int, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+
+	if err != nil || len(data) == 0 {
+		return nil, false
+	}
+
+	return new(big.Int).SetBytes(data), true
+}
+
+func decodeJWTSegment(segment string, value any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, value)
+}
+
+// Return a claim that is either a string or a list of strings as a list.
+func claimStrings(claim any) []string {
+	switch claim := claim.(type) {
+	case string:
+		return []string{claim}
+	case []any:
+		values := make([]string, 0, len(claim))
+
+		for _, value := range claim {
+			if value, ok := value.(string); ok {
+				values = append(values, value)
+			}
+		}
+
+		return values
+	}
+
+	return nil
+}
+
+// Return a claim of seconds since the epoch as a time.
+func claimTime(claim any) (time.Time, bool) {
+	seconds, ok := claim.(float64)
+
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(seconds), 0), true
+}
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/litebase/litebase/pkg/config"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// The argon2id parameters new password hashes are created with. Hashes
+// created with other parameters, or with bcrypt, are upgraded on login.
+const (
+	passwordHashMemory  = 19 * 1024
+	passwordHashTime    = 2
+	passwordHashThreads = 1
+	passwordHashKeySize = 32
+	passwordSaltSize    = 16
+)
+
+// The limits of the argon2id parameters of hashes that are verified, so that a
+// stored hash can not make a login exhaust the memory or time of the node.
+const (
+	passwordHashMaxMemory  = 1024 * 1024
+	passwordHashMaxTime    = 16
+	passwordHashMaxKeySize = 128
+)
+
+var ErrPasswordHashInvalid = errors.New("the password hash is invalid")
+
+type argon2idParams struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+}
+
+/*
+Hash a password with argon2id. The hash is encoded in the PHC string format,
+$argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>, so the
+parameters of a hash are known when it is verified.
+*/
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltSize)
+
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, passwordHashTime, passwordHashMemory, passwordHashThreads, passwordHashKeySize)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		passwordHashMemory,
+		passwordHashTime,
+		passwordHashThreads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify a password against an argon2id or bcrypt hash. The second return
+// value reports whether the hash should be replaced with a hash created with
+// the current parameters.
+func verifyPassword(hash, password string) (bool, bool) {
+	if !strings.HasPrefix(hash, "$argon2id$") {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+			return false, false
+		}
+
+		return true, true
+	}
+
+	params, salt, key, err := decodeArgon2idHash(hash)
+
+	if err != nil {
+		return false, false
+	}
+
+	computed := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(key)))
+
+	if subtle.ConstantTimeCompare(key, computed) != 1 {
+		return false, false
+	}
+
+	return true, params != argon2idParams{passwordHashMemory, passwordHashTime, passwordHashThreads} ||
+		len(key) != passwordHashKeySize
+}
+
+func decodeArgon2idHash(hash string) (argon2idParams, []byte, []byte, error) {
+	var (
+		params  argon2idParams
+		version int
+	)
+
+	parts := strings.Split(hash, "$")
+
+	if len(parts) != 6 {
+		return params, nil, nil, ErrPasswordHashInvalid
+	}
+
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrPasswordHashInvalid
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return params, nil, nil, ErrPasswordHashInvalid
+	}
+
+	// Argon2 panics when the time or the threads are zero, and requires at
+	// least 8 KiB of memory for each thread.
+	if params.time < 1 || params.time > passwordHashMaxTime ||
+		params.threads < 1 ||
+		params.memory < 8*uint32(params.threads) || params.memory > passwordHashMaxMemory {
+		return params, nil, nil, ErrPasswordHashInvalid
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+
+	if err != nil {
+		return params, nil, nil, ErrPasswordHashInvalid
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+
+	if err != nil || len(key) == 0 || len(key) > passwordHashMaxKeySize {
+		return params, nil, nil, ErrPasswordHashInvalid
+	}
+
+	return params, salt, key, nil
+}
+
+// Check a password against the password policy of the cluster and return a
+// message for each requirement the password does not meet.
+func ValidatePassword(c *config.Config, username, password string) []string {
+	var (
+		messages                                 []string
+		hasLower, hasUpper, hasNumber, hasSymbol bool
+	)
+
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasNumber = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if int64(len([]rune(password))) < c.PasswordMinLength {
+		messages = append(messages, fmt.Sprintf("The password must be at least %d characters.", c.PasswordMinLength))
+	}
+
+	if c.PasswordRequireLowercase && !hasLower {
+		messages = append(messages, "The password must contain a lowercase letter.")
+	}
+
+	if c.PasswordRequireUppercase && !hasUpper {
+		messages = append(messages, "The password must contain an uppercase letter.")
+	}
+
+	if c.PasswordRequireNumber && !hasNumber {
+		messages = append(messages, "The password must contain a number.")
+	}
+
+	if c.PasswordRequireSymbol && !hasSymbol {
+		messages = append(messages, "The password must contain a symbol.")
+	}
+
+	if username != "" && strings.EqualFold(password, username) {
+		messages = append(messages, "The password must not be the same as the username.")
+	}
+
+	return messages
+}
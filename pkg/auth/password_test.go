@@ -0,0 +1,177 @@
+package auth_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/config"
+	"github.com/litebase/litebase/pkg/server"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestUserManagerHashesPasswordsWithArgon2id(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		um := app.Auth.UserManager()
+
+		user, err := um.Add("testuser", "testpassword", []auth.AccessKeyStatement{
+			{Effect: auth.AccessKeyEffectAllow, Resource: "*", Actions: []auth.Privilege{"*"}},
+		})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if !strings.HasPrefix(user.Password, "$argon2id$v=19$") {
+			t.Errorf("Expected an argon2id hash, got %s", user.Password)
+		}
+
+		if !um.Authenticate("testuser", "testpassword") {
+			t.Error("Expected the password to be verified")
+		}
+
+		if um.Authenticate("testuser", "wrongpassword") {
+			t.Error("Expected the wrong password to be rejected")
+		}
+	})
+}
+
+func TestUserManagerRehashesBcryptPasswords(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		hash, err := bcrypt.GenerateFromPassword([]byte("legacypassword"), bcrypt.MinCost)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := json.Marshal(map[string]*auth.User{
+			"legacy": {Username: "legacy", Password: string(hash)},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := app.Auth.ObjectFS.WriteFile("users.json", data, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		um := app.Auth.UserManager()
+
+		if err := um.Init(); err != nil {
+			t.Fatal(err)
+		}
+
+		if um.Authenticate("legacy", "wrongpassword") {
+			t.Fatal("Expected the wrong password to be rejected")
+		}
+
+		if um.Get("legacy").Password != string(hash) {
+			t.Error("Expected the hash to be kept after a failed login")
+		}
+
+		if !um.Authenticate("legacy", "legacypassword") {
+			t.Fatal("Expected the bcrypt password to be verified")
+		}
+
+		if !strings.HasPrefix(um.Get("legacy").Password, "$argon2id$") {
+			t.Errorf("Expected the password to be rehashed with argon2id, got %s", um.Get("legacy").Password)
+		}
+
+		if !um.Authenticate("legacy", "legacypassword") {
+			t.Error("Expected the rehashed password to be verified")
+		}
+
+		stored, err := app.Auth.ObjectFS.ReadFile("users.json")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(string(stored), "$argon2id$") {
+			t.Error("Expected the rehashed password to be stored")
+		}
+	})
+}
+
+func TestUserManagerRejectsInvalidArgon2Params(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		salt := "c2FsdHNhbHRzYWx0c2FsdA"
+		key := "a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U"
+
+		hashes := map[string]string{
+			"zero-time":       "$argon2id$v=19$m=19456,t=0,p=1$" + salt + "$" + key,
+			"zero-threads":    "$argon2id$v=19$m=19456,t=2,p=0$" + salt + "$" + key,
+			"zero-memory":     "$argon2id$v=19$m=0,t=2,p=1$" + salt + "$" + key,
+			"too-much-time":   "$argon2id$v=19$m=19456,t=100000,p=1$" + salt + "$" + key,
+			"too-much-memory": "$argon2id$v=19$m=4294967295,t=2,p=1$" + salt + "$" + key,
+		}
+
+		users := map[string]*auth.User{}
+
+		for username, hash := range hashes {
+			users[username] = &auth.User{Username: username, Password: hash}
+		}
+
+		data, err := json.Marshal(users)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := app.Auth.ObjectFS.WriteFile("users.json", data, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		um := app.Auth.UserManager()
+
+		if err := um.Init(); err != nil {
+			t.Fatal(err)
+		}
+
+		for username := range hashes {
+			if um.Authenticate(username, "password") {
+				t.Errorf("%s: expected the hash to be rejected", username)
+			}
+		}
+	})
+}
+
+func TestValidatePassword(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   config.Config
+		password string
+		messages int
+	}{
+		{"long enough", config.Config{PasswordMinLength: 8}, "password123", 0},
+		{"too short", config.Config{PasswordMinLength: 8}, "abc213", 1},
+		{"multibyte characters", config.Config{PasswordMinLength: 4}, "ééé", 1},
+		{"same as username", config.Config{PasswordMinLength: 4}, "TestUser", 1},
+		{"all classes", config.Config{
+			PasswordMinLength:        8,
+			PasswordRequireLowercase: true,
+			PasswordRequireNumber:    true,
+			PasswordRequireSymbol:    true,
+			PasswordRequireUppercase: true,
+		}, "Correct-Horse-1", 0},
+		{"missing classes", config.Config{
+			PasswordMinLength:        8,
+			PasswordRequireLowercase: true,
+			PasswordRequireNumber:    true,
+			PasswordRequireSymbol:    true,
+			PasswordRequireUppercase: true,
+		}, "correcthorse", 3},
+	}
+
+	for _, tc := range testCases {
+		messages := auth.ValidatePassword(&tc.config, "testuser", tc.password)
+
+		if len(messages) != tc.messages {
+			t.Errorf("%s: expected %d messages, got %v", tc.name, tc.messages, messages)
+		}
+	}
+}
@@ -12,6 +12,8 @@ type RequestToken struct {
 	AccessKeyID      string   `json:"access_key_id"`
 	SignedHeaders    []string `json:"signed_headers"`
 	Signature        string   `json:"signature"`
+
+	bearer bool
 }
 
 func CaptureRequestToken(accessKeyManager *AccessKeyManager, authorizationHeader string) RequestToken {
@@ -19,6 +21,26 @@ func CaptureRequestToken(accessKeyManager *AccessKeyManager, authorizationHeader
 		return RequestToken{}
 	}
 
+	// Bearer tokens are short-lived access tokens issued for an access key
+	if token, ok := strings.CutPrefix(authorizationHeader, "Bearer "); ok {
+		if accessKeyManager == nil {
+			return RequestToken{}
+		}
+
+		accessKey, err := accessKeyManager.VerifyAccessToken(token)
+
+		if err != nil {
+			return RequestToken{}
+		}
+
+		return RequestToken{
+			accessKey:        accessKey,
+			accessKeyManager: accessKeyManager,
+			AccessKeyID:      accessKey.AccessKeyID,
+			bearer:           true,
+		}
+	}
+
 	// base64_decode the authorization header
 	rawDecodedText, err := base64.StdEncoding.DecodeString(authorizationHeader)
 
@@ -51,12 +73,25 @@ func CaptureRequestToken(accessKeyManager *AccessKeyManager, authorizationHeader
 		return RequestToken{}
 	}
 
-	return RequestToken{
+	requestToken := RequestToken{
 		AccessKeyID:      token["credential"],
 		accessKeyManager: accessKeyManager,
 		SignedHeaders:    strings.Split(token["signed_headers"], ","),
 		Signature:        token["signature"],
 	}
+
+	// Reject tokens of access keys that have expired
+	if accessKeyManager != nil {
+		if accessKey := requestToken.AccessKey(); accessKey != nil {
+			if accessKey.IsExpired() {
+				return RequestToken{}
+			}
+
+			requestToken.accessKey = accessKey
+		}
+	}
+
+	return requestToken
 }
 
 func (requestToken RequestToken) AccessKey() *AccessKey {
@@ -75,6 +110,11 @@ func (requestToken RequestToken) AccessKey() *AccessKey {
 	return requestToken.accessKey
 }
 
+// Determine if the request was authenticated with a bearer access token.
+func (requestToken RequestToken) IsBearer() bool {
+	return requestToken.bearer
+}
+
 func (requestToken RequestToken) ToMap() map[string]any {
 	return map[string]interface{}{
 		"access_key_id":  requestToken.AccessKeyID,
@@ -94,5 +134,9 @@ func (requestToken RequestToken) ToJson() string {
 }
 
 func (requestToken RequestToken) Valid() bool {
+	if requestToken.bearer {
+		return requestToken.AccessKeyID != "" && requestToken.accessKey != nil
+	}
+
 	return requestToken.AccessKeyID != "" && len(requestToken.SignedHeaders) > 0 && requestToken.Signature != ""
 }
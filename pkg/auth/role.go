@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"regexp"
+	"time"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// A Role is a named list of statements that can be assigned to users and
+// access keys. Changes to a role apply to everything it is assigned to.
+type Role struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Statements  []AccessKeyStatement `json:"statements"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+// Determine if the name is a valid role name. Role names may contain letters,
+// numbers, dashes, and underscores.
+func IsValidRoleName(name string) bool {
+	return roleNamePattern.MatchString(name)
+}
@@ -0,0 +1,284 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type RoleManager struct {
+	auth   *Auth
+	loaded bool
+	mutex  *sync.Mutex
+	path   string
+	roles  map[string]*Role
+}
+
+// Get the RoleManager instance
+func (auth *Auth) RoleManager() *RoleManager {
+	if auth.roleManager == nil {
+		auth.roleManager = &RoleManager{
+			auth:  auth,
+			mutex: &sync.Mutex{},
+			path:  "roles.json",
+			roles: map[string]*Role{},
+		}
+	}
+
+	return auth.roleManager
+}
+
+// Add a new role
+func (r *RoleManager) Add(name, description string, statements []AccessKeyStatement) (*Role, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	if _, exists := r.roles[name]; exists {
+		return nil, fmt.Errorf("the role already exists")
+	}
+
+	r.roles[name] = &Role{
+		Name:        name,
+		Description: description,
+		Statements:  statements,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	if err := r.writeFile(); err != nil {
+		return nil, err
+	}
+
+	r.auth.Broadcast("role:purge", name)
+
+	return r.roles[name], nil
+}
+
+// Return all roles sorted by name
+func (r *RoleManager) All() ([]Role, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	roles := make([]Role, 0, len(r.roles))
+
+	for _, role := range r.roles {
+		roles = append(roles, *role)
+	}
+
+	slices.SortFunc(roles, func(a, b Role) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return roles, nil
+}
+
+// Get a role by name
+func (r *RoleManager) Get(name string) *Role {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.load(); err != nil {
+		return nil
+	}
+
+	return r.roles[name]
+}
+
+// Load the roles from storage if they have not been loaded yet.
+func (r *RoleManager) load() error {
+	if r.loaded {
+		return nil
+	}
+
+	var roles map[string]*Role
+
+	file, err := r.auth.ObjectFS.ReadFile(r.path)
+
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if len(file) > 0 {
+		if err := json.Unmarshal(file, &roles); err != nil {
+			return err
+		}
+	}
+
+	if roles == nil {
+		roles = map[string]*Role{}
+	}
+
+	r.roles = roles
+	r.loaded = true
+
+	return nil
+}
+
+// Purge the roles from memory so they are read from storage again when they
+// are next used. This is called when a role is changed on another node.
+func (r *RoleManager) Purge(name string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.roles, name)
+	r.loaded = false
+
+	return nil
+}
+
+// Remove a role by name. The role is unassigned from the access keys and
+// users it was assigned to first, so a role that is later created with the
+// same name is not granted to them.
+func (r *RoleManager) Remove(name string) error {
+	if r.Get(name) == nil {
+		return fmt.Errorf("the role was not found")
+	}
+
+	if err := r.unassign(name); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.load(); err != nil {
+		return err
+	}
+
+	if _, exists := r.roles[name]; !exists {
+		return fmt.Errorf("the role was not found")
+	}
+
+	delete(r.roles, name)
+
+	if err := r.writeFile(); err != nil {
+		return err
+	}
+
+	r.auth.Broadcast("role:purge", name)
+
+	return nil
+}
+
+// Return the statements of the given roles. Roles that do not exist are
+// ignored, so removing a role revokes its statements from everything it was
+// assigned to.
+func (r *RoleManager) Statements(names []string) []AccessKeyStatement {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.load(); err != nil {
+		return nil
+	}
+
+	var statements []AccessKeyStatement
+
+	for _, name := range names {
+		if role, ok := r.roles[name]; ok {
+			statements = append(statements, role.Statements...)
+		}
+	}
+
+	return statements
+}
+
+// Remove the role from the access keys and users it is assigned to. The mutex
+// must not be held, as saving an access key reads the statements of its roles.
+func (r *RoleManager) unassign(name string) error {
+	accessKeyIds, err := r.auth.AccessKeyManager.AllAccessKeyIds()
+
+	if err != nil {
+		return err
+	}
+
+	for _, accessKeyId := range accessKeyIds {
+		accessKey, err := r.auth.AccessKeyManager.Get(accessKeyId)
+
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(accessKey.Roles, name) {
+			continue
+		}
+
+		roles := slices.DeleteFunc(slices.Clone(accessKey.Roles), func(role string) bool {
+			return role == name
+		})
+
+		if err := accessKey.UpdateRoles(roles); err != nil {
+			return err
+		}
+	}
+
+	userManager := r.auth.UserManager()
+
+	for _, user := range userManager.All() {
+		if !slices.Contains(user.Roles, name) {
+			continue
+		}
+
+		user.Roles = slices.DeleteFunc(slices.Clone(user.Roles), func(role string) bool {
+			return role == name
+		})
+
+		if err := userManager.Update(&user); err != nil {
+			return err
+		}
+
+		r.auth.Broadcast("user:purge", user.Username)
+	}
+
+	return nil
+}
+
+// Update the description and statements of an existing role
+func (r *RoleManager) Update(name, description string, statements []AccessKeyStatement) (*Role, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	role, exists := r.roles[name]
+
+	if !exists {
+		return nil, fmt.Errorf("the role was not found")
+	}
+
+	role.Description = description
+	role.Statements = statements
+	role.UpdatedAt = time.Now().UTC()
+
+	if err := r.writeFile(); err != nil {
+		return nil, err
+	}
+
+	r.auth.Broadcast("role:purge", name)
+
+	return role, nil
+}
+
+// Write the roles to storage
+func (r *RoleManager) writeFile() error {
+	data, err := json.MarshalIndent(r.roles, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return r.auth.ObjectFS.WriteFile(r.path, data, 0600)
+}
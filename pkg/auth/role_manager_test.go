@@ -0,0 +1,166 @@
+package auth_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/server"
+)
+
+func TestRoleManager(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		rm := app.Auth.RoleManager()
+
+		t.Run("Add", func(t *testing.T) {
+			role, err := rm.Add("readers", "Read only", []auth.AccessKeyStatement{
+				{Effect: auth.AccessKeyEffectAllow, Resource: "database:*", Actions: []auth.Privilege{auth.DatabasePrivilegeRead}},
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if role.Name != "readers" || len(role.Statements) != 1 {
+				t.Errorf("Unexpected role: %v", role)
+			}
+
+			if _, err := rm.Add("readers", "", nil); err == nil {
+				t.Error("Expected an error when adding a role that already exists")
+			}
+
+			if rm.Get("readers") == nil {
+				t.Error("Expected the role to be found")
+			}
+
+			roles, err := rm.All()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(roles) != 1 {
+				t.Errorf("Expected 1 role, got %d", len(roles))
+			}
+		})
+
+		t.Run("AccessKeyRoles", func(t *testing.T) {
+			accessKey, err := app.Auth.AccessKeyManager.Create("test", []auth.AccessKeyStatement{})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if accessKey.AuthorizeForResource([]string{"access-key:*"}, []auth.Privilege{auth.AccessKeyPrivilegeCreate}) {
+				t.Fatal("Expected the access key to not be authorized without roles")
+			}
+
+			_, err = rm.Add("key-admins", "", []auth.AccessKeyStatement{
+				{Effect: auth.AccessKeyEffectAllow, Resource: "access-key:*", Actions: []auth.Privilege{auth.AccessKeyPrivilegeCreate}},
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := accessKey.UpdateRoles([]string{"key-admins"}); err != nil {
+				t.Fatal(err)
+			}
+
+			accessKey, err = app.Auth.AccessKeyManager.Get(accessKey.AccessKeyID)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			hash := accessKey.Hash()
+
+			if !accessKey.AuthorizeForResource([]string{"access-key:*"}, []auth.Privilege{auth.AccessKeyPrivilegeCreate}) {
+				t.Error("Expected the access key to be authorized by its role")
+			}
+
+			// Changes to the role apply to the cached access key.
+			_, err = rm.Update("key-admins", "", []auth.AccessKeyStatement{
+				{Effect: auth.AccessKeyEffectAllow, Resource: "access-key:*", Actions: []auth.Privilege{auth.AccessKeyPrivilegeRead}},
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			accessKey, err = app.Auth.AccessKeyManager.Get(accessKey.AccessKeyID)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if accessKey.AuthorizeForResource([]string{"access-key:*"}, []auth.Privilege{auth.AccessKeyPrivilegeCreate}) {
+				t.Error("Expected the access key to lose the statements removed from its role")
+			}
+
+			if accessKey.Hash() == hash {
+				t.Error("Expected the hash of the access key to change with its role")
+			}
+		})
+
+		t.Run("UserRoles", func(t *testing.T) {
+			user, err := app.Auth.UserManager().AddWithRoles("role-user", "password", nil, []string{"readers"})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !user.AuthorizeForResource([]string{"database:*"}, []auth.Privilege{auth.DatabasePrivilegeRead}) {
+				t.Error("Expected the user to be authorized by its role")
+			}
+		})
+
+		t.Run("Purge", func(t *testing.T) {
+			if err := rm.Purge("readers"); err != nil {
+				t.Fatal(err)
+			}
+
+			// The role is read from storage again.
+			if rm.Get("readers") == nil {
+				t.Error("Expected the role to be reloaded after a purge")
+			}
+		})
+
+		t.Run("Remove", func(t *testing.T) {
+			if err := rm.Remove("readers"); err != nil {
+				t.Fatal(err)
+			}
+
+			if rm.Get("readers") != nil {
+				t.Error("Expected the role to be removed")
+			}
+
+			if err := rm.Remove("readers"); err == nil {
+				t.Error("Expected an error when removing a missing role")
+			}
+
+			if len(rm.Statements([]string{"readers"})) != 0 {
+				t.Error("Expected a removed role to have no statements")
+			}
+
+			if slices.Contains(app.Auth.UserManager().Get("role-user").Roles, "readers") {
+				t.Error("Expected the removed role to be unassigned from the user")
+			}
+
+			// A role created with the name of a removed role is not granted
+			// to the users the removed role was assigned to.
+			_, err := rm.Add("readers", "", []auth.AccessKeyStatement{
+				{Effect: auth.AccessKeyEffectAllow, Resource: "*", Actions: []auth.Privilege{"*"}},
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if app.Auth.UserManager().Get("role-user").AuthorizeForResource([]string{"database:*"}, []auth.Privilege{auth.DatabasePrivilegeRead}) {
+				t.Error("Expected the new role not to be granted to the user")
+			}
+		})
+	})
+}
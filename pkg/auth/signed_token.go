@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+var errSignedTokenInvalid = errors.New("the token signature is invalid")
+
+// Sign the payload with the private key of the cluster. The token is the
+// base64 encoded payload followed by its signature, separated by a dot.
+func (auth *Auth) signToken(payload []byte) (string, error) {
+	privateKey, err := GetPrivateKey(auth.Config.EncryptionKey, auth.SecretsManager.ObjectFS)
+
+	if err != nil {
+		return "", err
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(encodedPayload))
+
+	signature, err := rsa.SignPSS(rand.Reader, privateKey, crypto.SHA256, digest[:], nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	return encodedPayload + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Verify the signature of a token signed by the cluster and return its
+// payload. A token that is malformed or not signed by the cluster returns
+// errSignedTokenInvalid.
+func (auth *Auth) verifyToken(token string) ([]byte, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+
+	if !ok {
+		return nil, errSignedTokenInvalid
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+
+	if err != nil {
+		return nil, errSignedTokenInvalid
+	}
+
+	privateKey, err := GetPrivateKey(auth.Config.EncryptionKey, auth.SecretsManager.ObjectFS)
+
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256([]byte(encodedPayload))
+
+	if err := rsa.VerifyPSS(&privateKey.PublicKey, crypto.SHA256, digest[:], signature, nil); err != nil {
+		return nil, errSignedTokenInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+
+	if err != nil {
+		return nil, errSignedTokenInvalid
+	}
+
+	return payload, nil
+}
@@ -5,13 +5,17 @@ import "time"
 type User struct {
 	Username   string               `json:"username"`
 	Password   string               `json:"password"`
+	Roles      []string             `json:"roles,omitempty"`
 	Statements []AccessKeyStatement `json:"statements"`
 	CreatedAt  time.Time            `json:"created_at"`
 	UpdatedAt  time.Time            `json:"updated_at"`
+
+	userManager *UserManager
 }
 
 type UserResponse struct {
 	Username   string               `json:"username"`
+	Roles      []string             `json:"roles,omitempty"`
 	Statements []AccessKeyStatement `json:"statements"`
 	CreatedAt  time.Time            `json:"created_at"`
 	UpdatedAt  time.Time            `json:"updated_at"`
@@ -19,11 +23,22 @@ type UserResponse struct {
 
 // Check if the user has authorization for the given resources and actions
 func (u *User) AuthorizeForResource(resources []string, actions []Privilege) bool {
+	return u.AuthorizeForResourceWithContext(resources, actions, nil)
+}
+
+// Check if the user has authorization for the given resources and actions in
+// the context of a request.
+func (u *User) AuthorizeForResourceWithContext(
+	resources []string,
+	actions []Privilege,
+	context *AuthorizationContext,
+) bool {
 	hasAuthorization := false
+	statements := u.effectiveStatements()
 
 	for _, action := range actions {
 		for _, resource := range resources {
-			if Authorized(u.Statements, resource, action) {
+			if AuthorizedWithContext(statements, resource, action, context) {
 				hasAuthorization = true
 				break // No need to check further if one action is authorized
 			}
@@ -32,3 +47,22 @@ func (u *User) AuthorizeForResource(resources []string, actions []Privilege) boo
 
 	return hasAuthorization
 }
+
+// Return the statements of the user together with the statements of the roles
+// assigned to the user.
+func (u *User) effectiveStatements() []AccessKeyStatement {
+	if len(u.Roles) == 0 || u.userManager == nil {
+		return u.Statements
+	}
+
+	return append(
+		append([]AccessKeyStatement{}, u.Statements...),
+		u.userManager.auth.RoleManager().Statements(u.Roles)...,
+	)
+}
+
+// Explain the decision for the action on the resource in the context of a
+// request, including the statement of the user or its roles that decided it.
+func (u *User) Explain(resource string, privilege Privilege, context *AuthorizationContext) AuthorizationDecision {
+	return Evaluate(u.effectiveStatements(), resource, privilege, context)
+}
@@ -3,21 +3,22 @@ package auth
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/netip"
 	"os"
 	"sync"
 	"time"
 
 	"github.com/litebase/litebase/pkg/config"
-
-	"golang.org/x/crypto/bcrypt"
 )
 
 type UserManager struct {
-	auth   *Auth
-	config *config.Config
-	mutex  *sync.Mutex
-	path   string
-	users  map[string]*User
+	auth     *Auth
+	config   *config.Config
+	mutex    *sync.Mutex
+	path     string
+	throttle *LoginThrottle
+	users    map[string]*User
 }
 
 // Get the UserManager instance
@@ -28,7 +29,11 @@ func (auth *Auth) UserManager() *UserManager {
 			config: auth.Config,
 			mutex:  &sync.Mutex{},
 			path:   "users.json",
-			users:  map[string]*User{},
+			throttle: NewLoginThrottle(
+				auth.Config.LoginMaxAttempts,
+				time.Duration(auth.Config.LoginLockoutMaxSeconds)*time.Second,
+			),
+			users: map[string]*User{},
 		}
 	}
 
@@ -37,11 +42,15 @@ func (auth *Auth) UserManager() *UserManager {
 
 // Add a new user
 func (u *UserManager) Add(username, password string, statements []AccessKeyStatement) (*User, error) {
+	return u.AddWithRoles(username, password, statements, nil)
+}
+
+// Add a new user with roles assigned
+func (u *UserManager) AddWithRoles(username, password string, statements []AccessKeyStatement, roles []string) (*User, error) {
 	u.mutex.Lock()
 	defer u.mutex.Unlock()
 
-	// Bcrypt the password
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hash, err := hashPassword(password)
 
 	if err != nil {
 		return nil, err
@@ -52,11 +61,13 @@ func (u *UserManager) Add(username, password string, statements []AccessKeyState
 	}
 
 	u.users[username] = &User{
-		Username:   username,
-		Password:   string(bytes),
-		Statements: statements,
-		CreatedAt:  time.Now().UTC(),
-		UpdatedAt:  time.Now().UTC(),
+		Username:    username,
+		Password:    hash,
+		Roles:       roles,
+		Statements:  statements,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+		userManager: u,
 	}
 
 	return u.users[username], u.writeFile()
@@ -73,6 +84,7 @@ func (u *UserManager) All() []User {
 	for _, user := range u.users {
 		users = append(users, User{
 			Username:   user.Username,
+			Roles:      user.Roles,
 			Statements: user.Statements,
 			CreatedAt:  user.CreatedAt,
 			UpdatedAt:  user.UpdatedAt,
@@ -107,27 +119,76 @@ func (u *UserManager) allUsers() (map[string]*User, error) {
 		return nil, err
 	}
 
+	for _, user := range users {
+		user.userManager = u
+	}
+
 	return users, err
 }
 
-// Authenticate a user with username and password
+// Authenticate a user with username and password. Password hashes created
+// with bcrypt or outdated argon2id parameters are replaced once the password
+// has been verified.
 func (u *UserManager) Authenticate(username, password string) bool {
+	var hash string
+
 	u.mutex.Lock()
-	defer u.mutex.Unlock()
 
 	for _, user := range u.users {
 		if user.Username == username {
-			err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+			hash = user.Password
+			break
+		}
+	}
 
-			if err != nil {
-				return false
-			}
+	u.mutex.Unlock()
 
-			return true // Password matches
-		}
+	if hash == "" {
+		return false
 	}
 
-	return false
+	// Verify the password without holding the lock, hashing is slow by design
+	ok, rehash := verifyPassword(hash, password)
+
+	if !ok {
+		return false
+	}
+
+	if rehash {
+		u.rehashPassword(username, hash, password)
+	}
+
+	return true // Password matches
+}
+
+/*
+Authenticate a user with username and password sent from the source address.
+Failed logins are tracked for both the username and the source address, and
+either is locked out with exponential backoff after too many failed attempts,
+in which case a LoginLockedError is returned without checking the password.
+*/
+func (u *UserManager) AuthenticateFrom(username, password string, sourceIP netip.Addr) error {
+	keys := []string{"user:" + username}
+
+	if sourceIP.IsValid() {
+		keys = append(keys, "ip:"+sourceIP.String())
+	}
+
+	if retryAfter := u.throttle.Check(keys...); retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+
+	if !u.Authenticate(username, password) {
+		u.throttle.Fail(keys...)
+
+		return ErrInvalidCredentials
+	}
+
+	// Only the failures of the user are forgotten, so a source address that
+	// guesses passwords for many users is not unlocked by a single login.
+	u.throttle.Succeed(keys[0])
+
+	return nil
 }
 
 // Get a user by username
@@ -213,6 +274,41 @@ func (u *UserManager) Remove(username string) error {
 	return u.writeFile()
 }
 
+// Replace the password hash of a user with a hash created with the current
+// parameters. The hash is not replaced if the password was changed in the
+// meantime. The login succeeds even when the new hash cannot be stored.
+func (u *UserManager) rehashPassword(username, previousHash, password string) {
+	hash, err := hashPassword(password)
+
+	if err != nil {
+		slog.Error("Error rehashing user password", "username", username, "error", err)
+		return
+	}
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	user, ok := u.users[username]
+
+	if !ok || user.Password != previousHash {
+		return
+	}
+
+	user.Password = hash
+
+	if err := u.writeFile(); err != nil {
+		slog.Error("Error storing rehashed user password", "username", username, "error", err)
+		return
+	}
+
+	u.auth.Broadcast("user:purge", username)
+}
+
+// Set the clock that lockouts after failed logins are measured with.
+func (u *UserManager) SetClock(now func() time.Time) {
+	u.throttle.SetNow(now)
+}
+
 // Update an existing user
 func (u *UserManager) Update(user *User) error {
 	u.mutex.Lock()
@@ -224,6 +320,7 @@ func (u *UserManager) Update(user *User) error {
 		return fmt.Errorf("the user was not found")
 	}
 
+	existingUser.Roles = user.Roles
 	existingUser.Statements = user.Statements
 	existingUser.UpdatedAt = time.Now().UTC()
 
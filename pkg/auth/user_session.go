@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// The lifetime of a user session issued for a single sign-on login.
+const UserSessionTTL = 8 * time.Hour
+
+// The type of the signed payload of a user session, which distinguishes
+// sessions from access tokens signed with the same key.
+const userSessionType = "user_session"
+
+var (
+	ErrUserSessionExpired = errors.New("the session has expired")
+	ErrUserSessionInvalid = errors.New("the session is invalid")
+	ErrUserSessionNoRoles = errors.New("none of the groups of the identity are mapped to a role")
+)
+
+// A UserSession is a bearer token issued after a user signed in with the
+// identity provider. The session holds the groups of the identity, which are
+// mapped to roles each time the session is used so that changes to the role
+// mappings apply to existing sessions.
+type UserSession struct {
+	ExpiresAt int64    `json:"exp"`
+	Groups    []string `json:"groups,omitempty"`
+	IssuedAt  int64    `json:"iat"`
+	Subject   string   `json:"sub"`
+	Type      string   `json:"typ"`
+	Username  string   `json:"username"`
+}
+
+// Issue a session for an identity verified by the identity provider. A
+// session is only issued when at least one group of the identity is mapped to
+// a role.
+func (auth *Auth) IssueUserSession(identity *OIDCIdentity) (string, *UserSession, error) {
+	provider := auth.OIDCProvider()
+
+	if provider == nil {
+		return "", nil, ErrOIDCNotConfigured
+	}
+
+	if len(provider.Roles(identity.Groups)) == 0 {
+		return "", nil, ErrUserSessionNoRoles
+	}
+
+	now := time.Now().UTC()
+
+	session := &UserSession{
+		ExpiresAt: now.Add(UserSessionTTL).Unix(),
+		Groups:    identity.Groups,
+		IssuedAt:  now.Unix(),
+		Subject:   identity.Subject,
+		Type:      userSessionType,
+		Username:  identity.Username,
+	}
+
+	payload, err := json.Marshal(session)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	token, err := auth.signToken(payload)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, session, nil
+}
+
+// Verify a session token and return the session.
+func (auth *Auth) VerifyUserSession(token string) (*UserSession, error) {
+	payload, err := auth.verifyToken(token)
+
+	if err != nil {
+		if errors.Is(err, errSignedTokenInvalid) {
+			return nil, ErrUserSessionInvalid
+		}
+
+		return nil, err
+	}
+
+	session := &UserSession{}
+
+	if err := json.Unmarshal(payload, session); err != nil || session.Type != userSessionType {
+		return nil, ErrUserSessionInvalid
+	}
+
+	if !time.Now().UTC().Before(time.Unix(session.ExpiresAt, 0)) {
+		return nil, ErrUserSessionExpired
+	}
+
+	return session, nil
+}
+
+// Return a user for the session holding the roles mapped to the groups of the
+// session. The user is not stored and has no password or statements of its
+// own.
+func (auth *Auth) SessionUser(session *UserSession) *User {
+	var roles []string
+
+	if provider := auth.OIDCProvider(); provider != nil {
+		roles = provider.Roles(session.Groups)
+	}
+
+	return &User{
+		Username:    session.Username,
+		Roles:       roles,
+		CreatedAt:   time.Unix(session.IssuedAt, 0).UTC(),
+		UpdatedAt:   time.Unix(session.IssuedAt, 0).UTC(),
+		userManager: auth.UserManager(),
+	}
+}
@@ -77,6 +77,14 @@ func (c *Client) Request(method, path string, data map[string]any) (map[string]a
 		)
 	} else if c.shouldUseBasicAuth() {
 		c.defaultHeaders["Authorization"] = c.basicAuthHeader()
+	} else if c.shouldUseSession() {
+		header, err := c.sessionHeader()
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		c.defaultHeaders["Authorization"] = header
 	}
 
 	req, err := http.NewRequest(method, url, strings.NewReader(string(jsonData)))
@@ -178,6 +186,21 @@ func (c *Client) basicAuthHeader() string {
 	)
 }
 
+func (c *Client) sessionHeader() (string, error) {
+	profile, err := c.Config.GetCurrentProfile()
+
+	if err != nil {
+		return "", err
+	}
+
+	if profile.Credentials.SessionToken == "" ||
+		(profile.Credentials.SessionExpiresAt != nil && !time.Now().Before(*profile.Credentials.SessionExpiresAt)) {
+		return "", config.ErrSessionExpired
+	}
+
+	return fmt.Sprintf("Bearer %s", profile.Credentials.SessionToken), nil
+}
+
 func (c *Client) clusterURL() (*url.URL, error) {
 	if c.Config.GetUrl() == "" && (c.Config.GetAccessKeyId() != "" || c.Config.GetUsername() != "") {
 		return nil, config.ErrMissingClusterURL
@@ -227,3 +250,13 @@ func (c *Client) shouldUseBasicAuth() bool {
 
 	return profile.Type == string(config.ProfileTypeBasicAuth)
 }
+
+func (c *Client) shouldUseSession() bool {
+	profile, err := c.Config.GetCurrentProfile()
+
+	if err != nil {
+		return false
+	}
+
+	return profile.Type == string(config.ProfileTypeSSO)
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+var ErrSSOAuthorizationDenied = errors.New("the sign in request was denied or has expired")
+
+// A Session is issued by the cluster after a user signed in with the identity
+// provider of the cluster.
+type Session struct {
+	ExpiresAt time.Time `json:"expires_at"`
+	Roles     []string  `json:"roles"`
+	Token     string    `json:"token"`
+	Username  string    `json:"username"`
+}
+
+type ssoConfiguration struct {
+	ClientID                    string   `json:"client_id"`
+	DeviceAuthorizationEndpoint string   `json:"device_authorization_endpoint"`
+	Scopes                      []string `json:"scopes"`
+	TokenEndpoint               string   `json:"token_endpoint"`
+}
+
+type deviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+}
+
+type deviceTokenResponse struct {
+	Error   string `json:"error"`
+	IDToken string `json:"id_token"`
+}
+
+/*
+Sign in to a cluster with the device authorization grant of its identity
+provider. The user is asked to open the verification url in a browser while
+the token endpoint of the identity provider is polled. The identity token is
+then exchanged for a session issued by the cluster.
+*/
+func SignIn(cluster string, out io.Writer) (*Session, error) {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+
+	configuration := ssoConfiguration{}
+
+	err := clusterRequest(httpClient, "GET", cluster, "/v1/auth/oidc", nil, &configuration)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if configuration.DeviceAuthorizationEndpoint == "" {
+		return nil, errors.New("the identity provider of the cluster does not support device authorization")
+	}
+
+	authorization := deviceAuthorization{}
+
+	err = formRequest(httpClient, configuration.DeviceAuthorizationEndpoint, url.Values{
+		"client_id": {configuration.ClientID},
+		"scope":     {strings.Join(configuration.Scopes, " ")},
+	}, &authorization)
+
+	if err != nil {
+		return nil, err
+	}
+
+	verificationURI := authorization.VerificationURIComplete
+
+	if verificationURI == "" {
+		verificationURI = authorization.VerificationURI
+	}
+
+	fmt.Fprintf(out, "Open %s in your browser and confirm the code %s to sign in.\n", verificationURI, authorization.UserCode)
+
+	interval := time.Duration(authorization.Interval) * time.Second
+
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	expiresAt := time.Now().Add(time.Duration(authorization.ExpiresIn) * time.Second)
+
+	if authorization.ExpiresIn <= 0 {
+		expiresAt = time.Now().Add(10 * time.Minute)
+	}
+
+	var idToken string
+
+	for idToken == "" {
+		if time.Now().After(expiresAt) {
+			return nil, ErrSSOAuthorizationDenied
+		}
+
+		response := deviceTokenResponse{}
+
+		err := formRequest(httpClient, configuration.TokenEndpoint, url.Values{
+			"client_id":   {configuration.ClientID},
+			"device_code": {authorization.DeviceCode},
+			"grant_type":  {deviceCodeGrantType},
+		}, &response)
+
+		switch {
+		case response.IDToken != "":
+			idToken = response.IDToken
+		case response.Error == "authorization_pending":
+			time.Sleep(interval)
+		case response.Error == "slow_down":
+			interval += 5 * time.Second
+			time.Sleep(interval)
+		case response.Error == "access_denied" || response.Error == "expired_token":
+			return nil, ErrSSOAuthorizationDenied
+		case response.Error != "":
+			return nil, fmt.Errorf("sign in failed: %s", response.Error)
+		case err != nil:
+			return nil, err
+		default:
+			return nil, errors.New("the identity provider did not return an identity token")
+		}
+	}
+
+	session := Session{}
+
+	err = clusterRequest(httpClient, "POST", cluster, "/v1/auth/oidc/token", map[string]any{
+		"id_token": idToken,
+	}, &session)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Send an unauthenticated request to the cluster and decode the data of the
+// response.
+func clusterRequest(httpClient *http.Client, method, cluster, path string, body map[string]any, data any) error {
+	var reader io.Reader
+
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+
+		if err != nil {
+			return err
+		}
+
+		reader = strings.NewReader(string(jsonData))
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("%s/%s", strings.TrimRight(cluster, "/"), strings.TrimLeft(path, "/")), reader)
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := httpClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	response := struct {
+		Data    json.RawMessage `json:"data"`
+		Message string          `json:"message"`
+	}{}
+
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return fmt.Errorf("Request Error: %s", res.Status)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		if response.Message != "" {
+			return errors.New(response.Message)
+		}
+
+		return fmt.Errorf("Request Error: %s", res.Status)
+	}
+
+	return json.Unmarshal(response.Data, data)
+}
+
+// Send a form encoded request to the identity provider and decode the
+// response. Error responses are decoded as well, since the device grant
+// reports its state through the error of the response.
+func formRequest(httpClient *http.Client, endpoint string, values url.Values, data any) error {
+	res, err := httpClient.PostForm(endpoint, values)
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if err := json.NewDecoder(res.Body).Decode(data); err != nil {
+		return fmt.Errorf("Request Error: %s", res.Status)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("Request Error: %s", res.Status)
+	}
+
+	return nil
+}
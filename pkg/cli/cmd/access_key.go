@@ -25,6 +25,7 @@ func NewAccessKeyCmd(config *config.Configuration) *cobra.Command {
 	cmd.AddCommand(NewAccessKeyShowCmd(config))
 	cmd.AddCommand(NewAccessKeyDeleteCmd(config))
 	cmd.AddCommand(NewAccessKeyUpdateCmd(config))
+	cmd.AddCommand(NewAccessKeyRotateCmd(config))
 
 	return cmd
 }
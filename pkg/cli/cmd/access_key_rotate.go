@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/litebase/litebase/pkg/cli/api"
+	"github.com/litebase/litebase/pkg/cli/components"
+	"github.com/litebase/litebase/pkg/cli/config"
+
+	"github.com/spf13/cobra"
+)
+
+func NewAccessKeyRotateCmd(config *config.Configuration) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate <id>",
+		Short: "Issue a new secret for an access key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			body := map[string]any{}
+
+			if cmd.Flags().Changed("grace-period") {
+				gracePeriod, err := cmd.Flags().GetInt64("grace-period")
+
+				if err != nil {
+					return err
+				}
+
+				body["grace_period"] = gracePeriod
+			}
+
+			res, _, err := api.Post(config, fmt.Sprintf("/v1/access-keys/%s/rotate", args[0]), body)
+
+			if err != nil {
+				return err
+			}
+
+			rows := []components.CardRow{
+				{
+					Key:   "Access Key ID",
+					Value: res["data"].(map[string]any)["access_key_id"].(string),
+				},
+				{
+					Key:   "Access Key Secret",
+					Value: res["data"].(map[string]any)["access_key_secret"].(string),
+				},
+			}
+
+			for _, field := range []struct {
+				key   string
+				label string
+			}{
+				{"rotated_at", "Rotated At"},
+				{"previous_secret_expires_at", "Previous Secret Expires At"},
+				{"expires_at", "Expires At"},
+			} {
+				if res["data"].(map[string]any)[field.key] == nil {
+					continue
+				}
+
+				parsedDate, err := time.Parse(time.RFC3339, res["data"].(map[string]any)[field.key].(string))
+
+				if err != nil {
+					return err
+				}
+
+				rows = append(rows, components.CardRow{
+					Key:   field.label,
+					Value: parsedDate.Format(time.RFC3339),
+				})
+			}
+
+			lipgloss.Fprint(
+				cmd.OutOrStdout(),
+				components.Container(
+					components.SuccessAlert(res["message"].(string)),
+					components.NewCard(
+						components.WithCardTitle("Access Key"),
+						components.WithCardDescription("Copy and securely store the new Access Key Secret now. You won't be able to retrieve the secret later."),
+						components.WithCardRows(rows),
+					).Render(),
+				),
+			)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64("grace-period", 86400, "Seconds the previous secret remains valid")
+
+	return cmd
+}
@@ -0,0 +1,51 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestAccessKeyRotate(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		cli := test.NewTestCLI(server.App).
+			WithServer(server).
+			WithAccessKey([]auth.AccessKeyStatement{
+				{Effect: auth.AccessKeyEffectAllow, Resource: "*", Actions: []auth.Privilege{"*"}},
+			})
+
+		accessKey, err := server.App.Auth.AccessKeyManager.Create("Test access key", []auth.AccessKeyStatement{
+			{Effect: auth.AccessKeyEffectAllow, Resource: "*", Actions: []auth.Privilege{"*"}},
+		})
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		err = cli.Run("access-key", "rotate", accessKey.AccessKeyID, "--grace-period", "60")
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("Access Key Secret") {
+			t.Error("expected output to contain 'Access Key Secret'")
+		}
+
+		if cli.Sees(accessKey.AccessKeySecret) {
+			t.Error("expected output to not contain the previous secret")
+		}
+
+		if cli.DoesntSee("Rotated At") {
+			t.Error("expected output to contain 'Rotated At'")
+		}
+
+		if cli.DoesntSee("Previous Secret Expires At") {
+			t.Error("expected output to contain 'Previous Secret Expires At'")
+		}
+	})
+}
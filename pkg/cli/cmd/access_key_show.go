@@ -3,6 +3,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss/v2"
@@ -35,6 +36,19 @@ func accessKeyShow(cmd *cobra.Command, config *config.Configuration, accessKeyId
 		})
 	}
 
+	if roles, ok := res["data"].(map[string]any)["roles"].([]any); ok && len(roles) > 0 {
+		names := make([]string, 0, len(roles))
+
+		for _, role := range roles {
+			names = append(names, fmt.Sprint(role))
+		}
+
+		rows = append(rows, components.CardRow{
+			Key:   "Roles",
+			Value: strings.Join(names, ", "),
+		})
+	}
+
 	if res["data"].(map[string]any)["created_at"] != nil {
 		parsedDate, err := time.Parse(time.RFC3339, res["data"].(map[string]any)["created_at"].(string))
 
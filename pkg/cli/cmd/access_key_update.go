@@ -42,6 +42,24 @@ func NewAccessKeyUpdateCmd(config *config.Configuration) *cobra.Command {
 				return err
 			}
 
+			expiresAt, err := cmd.Flags().GetString("expires-at")
+
+			if err != nil {
+				return err
+			}
+
+			roles, err := cmd.Flags().GetStringSlice("roles")
+
+			if err != nil {
+				return err
+			}
+
+			if expiresAt != "" {
+				if _, err := time.Parse(time.RFC3339, expiresAt); err != nil {
+					return errors.New("invalid RFC3339 format for expires-at")
+				}
+			}
+
 			// Check if we're in non-interactive mode (flags provided)
 			nonInteractive := !config.GetInteractive() || (description != "" && statements != "")
 
@@ -49,7 +67,10 @@ func NewAccessKeyUpdateCmd(config *config.Configuration) *cobra.Command {
 				// Non-interactive mode: use provided flags
 				input.Description = description
 
-				if err := json.Unmarshal([]byte(statements), &input.Statements); err != nil {
+				// Access keys with roles do not need statements of their own
+				if statements == "" && len(roles) > 0 {
+					input.Statements = nil
+				} else if err := json.Unmarshal([]byte(statements), &input.Statements); err != nil {
 					return errors.New("invalid JSON format for statements")
 				}
 
@@ -153,10 +174,23 @@ func NewAccessKeyUpdateCmd(config *config.Configuration) *cobra.Command {
 				return nil
 			}
 
-			res, _, err := api.Put(config, fmt.Sprintf("/v1/access-keys/%s", accessKeyId), map[string]any{
+			body := map[string]any{
 				"description": input.Description,
-				"statements":  input.Statements,
-			})
+			}
+
+			if input.Statements != nil {
+				body["statements"] = input.Statements
+			}
+
+			if roles != nil {
+				body["roles"] = roles
+			}
+
+			if expiresAt != "" {
+				body["expires_at"] = expiresAt
+			}
+
+			res, _, err := api.Put(config, fmt.Sprintf("/v1/access-keys/%s", accessKeyId), body)
 
 			if err != nil {
 				return err
@@ -188,6 +222,19 @@ func NewAccessKeyUpdateCmd(config *config.Configuration) *cobra.Command {
 				})
 			}
 
+			if res["data"].(map[string]any)["expires_at"] != nil {
+				parsedDate, err := time.Parse(time.RFC3339, res["data"].(map[string]any)["expires_at"].(string))
+
+				if err != nil {
+					return err
+				}
+
+				rows = append(rows, components.CardRow{
+					Key:   "Expires At",
+					Value: parsedDate.Format(time.RFC3339),
+				})
+			}
+
 			if res["data"].(map[string]any)["updated_at"] != nil {
 				parsedDate, err := time.Parse(time.RFC3339, res["data"].(map[string]any)["updated_at"].(string))
 
@@ -231,6 +278,8 @@ func NewAccessKeyUpdateCmd(config *config.Configuration) *cobra.Command {
 	// Add flags
 	cmd.Flags().String("description", "", "Description for the access key")
 	cmd.Flags().String("statements", "", "JSON array of statements")
+	cmd.Flags().String("expires-at", "", "Expiration date of the access key in RFC3339 format")
+	cmd.Flags().StringSlice("roles", nil, "Names of the roles to assign to the access key")
 
 	return cmd
 }
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	neturl "net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/litebase/litebase/pkg/cli/api"
+	"github.com/litebase/litebase/pkg/cli/components"
+	"github.com/litebase/litebase/pkg/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewAuditCmd(config *config.Configuration) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "View the audit log",
+		Long:  "List the entries of the audit log, which records administrative and data-changing operations with the user or access key that performed them.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := neturl.Values{}
+
+			for _, flag := range []string{"action", "actor"} {
+				value, err := cmd.Flags().GetString(flag)
+
+				if err != nil {
+					return err
+				}
+
+				if value != "" {
+					query.Set(flag, value)
+				}
+			}
+
+			since, err := cmd.Flags().GetDuration("since")
+
+			if err != nil {
+				return err
+			}
+
+			if since > 0 {
+				query.Set("start", strconv.FormatInt(time.Now().Add(-since).Unix(), 10))
+			}
+
+			for _, flag := range []string{"limit", "offset"} {
+				value, err := cmd.Flags().GetInt(flag)
+
+				if err != nil {
+					return err
+				}
+
+				if value > 0 {
+					query.Set(flag, strconv.Itoa(value))
+				}
+			}
+
+			path := "/v1/audit"
+
+			if len(query) > 0 {
+				path += "?" + query.Encode()
+			}
+
+			res, err := api.Get(config, path)
+
+			if err != nil {
+				return err
+			}
+
+			entries, ok := res["data"].([]any)
+
+			if !ok || len(entries) == 0 {
+				lipgloss.Fprint(
+					cmd.OutOrStdout(),
+					components.Container(components.WarningAlert("No audit log entries found")),
+				)
+
+				return nil
+			}
+
+			rows := [][]string{}
+
+			for _, e := range entries {
+				entry, ok := e.(map[string]any)
+
+				if !ok {
+					continue
+				}
+
+				timestamp := "-"
+
+				if t, ok := entry["timestamp"].(float64); ok {
+					timestamp = time.Unix(int64(t), 0).UTC().Format(time.RFC3339)
+				}
+
+				details := []string{}
+
+				if d, ok := entry["details"].(map[string]any); ok {
+					for key, value := range d {
+						details = append(details, fmt.Sprintf("%s=%v", key, value))
+					}
+				}
+
+				slices.Sort(details)
+
+				rows = append(rows, []string{
+					timestamp,
+					fmt.Sprintf("%v:%v", entry["actor_type"], entry["actor"]),
+					fmt.Sprintf("%v", entry["action"]),
+					fmt.Sprintf("%v", entry["resource"]),
+					strings.Join(details, " "),
+				})
+			}
+
+			lipgloss.Fprint(
+				cmd.OutOrStdout(),
+				components.Container(
+					components.NewTable(
+						[]string{"Time", "Actor", "Action", "Resource", "Details"},
+						rows,
+					).Render(false),
+				),
+			)
+
+			if next, ok := res["next_offset"].(float64); ok {
+				lipgloss.Fprint(
+					cmd.OutOrStdout(),
+					components.Container(components.InfoAlert(fmt.Sprintf("More entries are available, use --offset %d to see them", int(next)))),
+				)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("action", "", "Only show entries with this action, e.g. database:create")
+	cmd.Flags().String("actor", "", "Only show entries performed by this username or access key ID")
+	cmd.Flags().Int("limit", 0, "The maximum number of entries to show")
+	cmd.Flags().Int("offset", 0, "The number of entries to skip")
+	cmd.Flags().Duration("since", 0, "Only show entries from this long ago, e.g. 24h")
+
+	cmd.AddCommand(NewAuditVerifyCmd(config))
+
+	return cmd
+}
@@ -0,0 +1,63 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/logs"
+)
+
+func TestAuditCmd(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		cli := test.NewTestCLI(server.App).
+			WithServer(server).
+			WithAccessKey([]auth.AccessKeyStatement{
+				{Effect: auth.AccessKeyEffectAllow, Resource: "*", Actions: []auth.Privilege{"*"}},
+			})
+
+		err := server.App.LogManager.Audit(server.App.Cluster, logs.AuditLogEntry{
+			ActorType: logs.AuditActorUser,
+			Actor:     "root",
+			Action:    logs.AuditActionDatabaseCreate,
+			Resource:  "database:audited",
+		})
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		err = cli.Run("audit")
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("database:audited") {
+			t.Errorf("expected output to contain the audited resource, got %q", cli.GetOutput())
+		}
+
+		err = cli.Run("audit", "--action", logs.AuditActionUserDelete)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("No audit log entries found") {
+			t.Errorf("expected no matching entries, got %q", cli.GetOutput())
+		}
+
+		err = cli.Run("audit", "verify")
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("intact") {
+			t.Errorf("expected output to confirm the audit log is intact, got %q", cli.GetOutput())
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/litebase/litebase/pkg/cli/api"
+	"github.com/litebase/litebase/pkg/cli/components"
+	"github.com/litebase/litebase/pkg/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewAuditVerifyCmd(config *config.Configuration) *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the integrity of the audit log",
+		Long:  "Verify that no audit log entry has been modified, removed, or reordered by checking the hash chain of the entries written by each node.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			res, err := api.Get(config, "/v1/audit/verify")
+
+			if err != nil {
+				return err
+			}
+
+			chains, _ := res["data"].([]any)
+			rows := [][]string{}
+
+			for _, c := range chains {
+				chain, ok := c.(map[string]any)
+
+				if !ok {
+					continue
+				}
+
+				rows = append(rows, []string{
+					fmt.Sprintf("%v", chain["node_id"]),
+					fmt.Sprintf("%v", chain["entries"]),
+					fmt.Sprintf("%v", chain["hash"]),
+				})
+			}
+
+			output := []string{
+				components.SuccessAlert(res["message"].(string)),
+			}
+
+			if len(rows) > 0 {
+				output = append(output, components.NewTable(
+					[]string{"Node", "Entries", "Last Hash"},
+					rows,
+				).Render(false))
+			}
+
+			lipgloss.Fprint(
+				cmd.OutOrStdout(),
+				components.Container(output...),
+			)
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/litebase/litebase/pkg/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewClusterCmd(config *config.Configuration) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "Manage the cluster",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := cmd.Help()
+
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.AddCommand(NewClusterHandoffCmd(config))
+	cmd.AddCommand(NewClusterMembersCmd(config))
+	cmd.AddCommand(NewClusterRemoveCmd(config))
+
+	return cmd
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/litebase/litebase/pkg/cli/api"
+	"github.com/litebase/litebase/pkg/cli/components"
+	"github.com/litebase/litebase/pkg/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewClusterHandoffCmd(config *config.Configuration) *cobra.Command {
+	return &cobra.Command{
+		Use:   "handoff <address>",
+		Short: "Hand off the primary role to a replica",
+		Long:  "Transfer the primary role to the replica with the given address. Writes are paused while pending writes are drained and flushed to storage, then the lease is handed over to the replica.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			res, apiErrors, err := api.Post(config, "/v1/cluster/handoff", map[string]any{
+				"address": args[0],
+			})
+
+			if err != nil {
+				return err
+			}
+
+			if len(apiErrors) > 0 {
+				return fmt.Errorf("failed to hand off the primary role: %v", apiErrors)
+			}
+
+			data, ok := res["data"].(map[string]any)
+
+			if !ok {
+				return fmt.Errorf("invalid data format for cluster handoff")
+			}
+
+			lipgloss.Fprint(
+				cmd.OutOrStdout(),
+				components.Container(
+					components.SuccessAlert(res["message"].(string)),
+					components.NewCard(
+						components.WithCardTitle("Primary Handoff"),
+						components.WithCardRows([]components.CardRow{
+							{
+								Key:   "Previous Primary",
+								Value: fmt.Sprintf("%v", data["previous_primary"]),
+							},
+							{
+								Key:   "Primary",
+								Value: fmt.Sprintf("%v", data["primary"]),
+							},
+						}),
+					).Render(),
+				),
+			)
+
+			return nil
+		},
+	}
+}
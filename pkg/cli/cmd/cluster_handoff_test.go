@@ -0,0 +1,44 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestClusterHandoffCmd(t *testing.T) {
+	test.Run(t, func() {
+		server1 := test.NewTestServer(t)
+		defer server1.Shutdown()
+
+		server2 := test.NewTestServer(t)
+		defer server2.Shutdown()
+
+		address, err := server2.App.Cluster.Node().Address()
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		cli := test.NewTestCLI(server1.App).
+			WithServer(server1).
+			WithAccessKey([]auth.AccessKeyStatement{
+				{Effect: auth.AccessKeyEffectAllow, Resource: "*", Actions: []auth.Privilege{"*"}},
+			})
+
+		err = cli.Run("cluster", "handoff", address)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("Primary Handoff") {
+			t.Errorf("expected output to contain 'Primary Handoff', got %q", cli.GetOutput())
+		}
+
+		if cli.DoesntSee(address) {
+			t.Errorf("expected output to contain the new primary, got %q", cli.GetOutput())
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/litebase/litebase/pkg/cli/api"
+	"github.com/litebase/litebase/pkg/cli/components"
+	"github.com/litebase/litebase/pkg/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewClusterMembersCmd(config *config.Configuration) *cobra.Command {
+	return &cobra.Command{
+		Use:   "members",
+		Short: "List the members of the cluster",
+		Long:  "List the members of the cluster with their role, last heartbeat, lease expiry, open connections and WAL replication lag per database.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			res, err := api.Get(config, "/v1/cluster/nodes")
+
+			if err != nil {
+				return err
+			}
+
+			members, ok := res["data"].([]any)
+
+			if !ok || len(members) == 0 {
+				lipgloss.Fprint(
+					cmd.OutOrStdout(),
+					components.Container(components.WarningAlert("No cluster members found")),
+				)
+
+				return nil
+			}
+
+			memberRows := [][]string{}
+			lagRows := [][]string{}
+
+			for _, m := range members {
+				member, ok := m.(map[string]any)
+
+				if !ok {
+					continue
+				}
+
+				lastHeartbeat := "-"
+
+				if t, err := time.Parse(time.RFC3339Nano, fmt.Sprintf("%v", member["last_heartbeat_at"])); err == nil && !t.IsZero() {
+					lastHeartbeat = fmt.Sprintf("%s ago", time.Since(t).Round(time.Second))
+				}
+
+				leaseExpiresAt := "-"
+
+				if expiresAt, ok := member["lease_expires_at"].(float64); ok && expiresAt > 0 {
+					leaseExpiresAt = time.Unix(int64(expiresAt), 0).UTC().Format(time.RFC3339)
+				}
+
+				status := "Reachable"
+
+				if reachable, _ := member["reachable"].(bool); !reachable {
+					status = fmt.Sprintf("Unreachable: %v", member["error"])
+				}
+
+				memberRows = append(memberRows, []string{
+					fmt.Sprintf("%v", member["address"]),
+					fmt.Sprintf("%v", member["role"]),
+					lastHeartbeat,
+					leaseExpiresAt,
+					fmt.Sprintf("%v", member["connections"]),
+					status,
+				})
+
+				if member["role"] == "PRIMARY" {
+					continue
+				}
+
+				databases, _ := member["databases"].([]any)
+
+				for _, d := range databases {
+					database, ok := d.(map[string]any)
+
+					if !ok {
+						continue
+					}
+
+					lagRows = append(lagRows, []string{
+						fmt.Sprintf("%v", member["address"]),
+						fmt.Sprintf("%v", database["database_id"]),
+						fmt.Sprintf("%v", database["branch_id"]),
+						fmt.Sprintf("%vms", database["replication_lag_ms"]),
+					})
+				}
+			}
+
+			output := []string{
+				components.NewTable(
+					[]string{"Address", "Role", "Last Heartbeat", "Lease Expires", "Connections", "Status"},
+					memberRows,
+				).Render(false),
+			}
+
+			if len(lagRows) > 0 {
+				output = append(output, components.NewTable(
+					[]string{"Replica", "Database", "Branch", "Replication Lag"},
+					lagRows,
+				).Render(false))
+			}
+
+			lipgloss.Fprint(
+				cmd.OutOrStdout(),
+				components.Container(output...),
+			)
+
+			return nil
+		},
+	}
+}
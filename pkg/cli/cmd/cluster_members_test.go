@@ -0,0 +1,59 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestClusterMembersCmd(t *testing.T) {
+	test.Run(t, func() {
+		server1 := test.NewTestServer(t)
+		defer server1.Shutdown()
+
+		server2 := test.NewTestServer(t)
+		defer server2.Shutdown()
+
+		address, _ := server2.App.Cluster.Node().Address()
+
+		cli := test.NewTestCLI(server1.App).
+			WithServer(server1).
+			WithAccessKey([]auth.AccessKeyStatement{
+				{Effect: auth.AccessKeyEffectAllow, Resource: "*", Actions: []auth.Privilege{"*"}},
+			})
+
+		err := cli.Run("cluster", "members")
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("PRIMARY") {
+			t.Errorf("expected output to contain 'PRIMARY', got %q", cli.GetOutput())
+		}
+
+		if cli.DoesntSee(address) {
+			t.Errorf("expected output to contain the replica address, got %q", cli.GetOutput())
+		}
+	})
+}
+
+func TestClusterRemoveCmd(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		cli := test.NewTestCLI(server.App).
+			WithServer(server).
+			WithAccessKey([]auth.AccessKeyStatement{
+				{Effect: auth.AccessKeyEffectAllow, Resource: "*", Actions: []auth.Privilege{"*"}},
+			})
+
+		err := cli.Run("cluster", "remove", "10.0.0.0:9876")
+
+		if err == nil {
+			t.Fatal("expected an error when removing an unknown member")
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/litebase/litebase/pkg/cli/api"
+	"github.com/litebase/litebase/pkg/cli/components"
+	"github.com/litebase/litebase/pkg/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewClusterRemoveCmd(config *config.Configuration) *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <address>",
+		Short: "Remove a dead member from the cluster",
+		Long:  "Remove a member that has stopped sending heartbeats from the cluster. Members that are still active cannot be removed.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			res, apiErrors, err := api.Delete(config, fmt.Sprintf("/v1/cluster/nodes/%s", args[0]))
+
+			if err != nil {
+				return err
+			}
+
+			if len(apiErrors) > 0 {
+				return fmt.Errorf("failed to remove the cluster member: %v", apiErrors)
+			}
+
+			lipgloss.Fprint(
+				cmd.OutOrStdout(),
+				components.Container(
+					components.SuccessAlert(res["message"].(string)),
+				),
+			)
+
+			return nil
+		},
+	}
+}
@@ -33,5 +33,9 @@ func TestDatabaseShow(t *testing.T) {
 		if !cli.Sees(database.DatabaseName) {
 			t.Errorf("expected output to contain database name %s", database.DatabaseName)
 		}
+
+		if !cli.Sees("Total Storage") {
+			t.Errorf("expected output to contain the storage usage of the database")
+		}
 	})
 }
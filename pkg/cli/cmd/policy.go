@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/litebase/litebase/pkg/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewPolicyCmd(config *config.Configuration) *cobra.Command {
+	PolicyCmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Inspect policies",
+		Long:  "Inspect the policies of access keys and users.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := cmd.Help()
+
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	PolicyCmd.AddCommand(NewPolicySimulateCmd(config))
+
+	return PolicyCmd
+}
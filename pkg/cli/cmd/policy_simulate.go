@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/litebase/litebase/pkg/cli/api"
+	"github.com/litebase/litebase/pkg/cli/components"
+	"github.com/litebase/litebase/pkg/cli/config"
+	"github.com/spf13/cobra"
+)
+
+// Describe the statement of a decision, or the implicit deny without one.
+func policyDecisionStatement(decision map[string]any) string {
+	statement, ok := decision["statement"].(map[string]any)
+
+	if !ok {
+		return "No matching statement"
+	}
+
+	return fmt.Sprintf("%v %v", statement["effect"], statement["resource"])
+}
+
+// Render the decision for an action on a resource.
+func policyDecisionOutput(data map[string]any) (string, error) {
+	var cardContent string
+
+	if data["statement"] != nil {
+		statementJSON, err := json.MarshalIndent(data["statement"], "", "  ")
+
+		if err != nil {
+			return "", err
+		}
+
+		cardContent = "```json\n" + string(statementJSON) + "\n```"
+	}
+
+	card := components.NewCard(
+		components.WithCardTitle("Decision"),
+		components.WithCardRows([]components.CardRow{
+			{Key: "Action", Value: fmt.Sprint(data["action"])},
+			{Key: "Resource", Value: fmt.Sprint(data["resource"])},
+			{Key: "Statement", Value: policyDecisionStatement(data)},
+		}),
+		components.WithCardContent("Matched Statement", cardContent),
+	).Render()
+
+	return components.Container(policyDecisionAlert(data["allowed"] == true), card), nil
+}
+
+func policyDecisionAlert(allowed bool) string {
+	if allowed {
+		return components.SuccessAlert("Allowed")
+	}
+
+	return components.WarningAlert("Denied")
+}
+
+// Render the checks of the authorizer for a simulated SQL statement.
+func policySimulationOutput(config *config.Configuration, data map[string]any) string {
+	rows := [][]string{}
+
+	checks, _ := data["checks"].([]any)
+
+	for i, value := range checks {
+		check, ok := value.(map[string]any)
+
+		if !ok {
+			continue
+		}
+
+		var arguments []string
+
+		if values, ok := check["arguments"].([]any); ok {
+			for _, argument := range values {
+				if argument != "" {
+					arguments = append(arguments, fmt.Sprint(argument))
+				}
+			}
+		}
+
+		result := "Allowed"
+
+		if check["allowed"] != true {
+			result = "Denied"
+		}
+
+		reason := fmt.Sprint(check["reason"])
+
+		if decision, ok := check["decision"].(map[string]any); ok {
+			reason = policyDecisionStatement(decision)
+		} else if check["reason"] == nil {
+			reason = ""
+		}
+
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", i+1),
+			fmt.Sprint(check["operation"]),
+			strings.Join(arguments, ", "),
+			result,
+			reason,
+		})
+	}
+
+	output := []string{policyDecisionAlert(data["allowed"] == true)}
+
+	if message, ok := data["error"].(string); ok && message != "" {
+		output = append(output, components.ErrorAlert(message))
+	}
+
+	columns := []string{
+		"#",
+		"Operation",
+		"Arguments",
+		"Result",
+		"Statement",
+	}
+
+	output = append(output, components.NewTable(columns, rows).Render(config.GetInteractive()))
+
+	return components.Container(output...)
+}
+
+func NewPolicySimulateCmd(config *config.Configuration) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Simulate the decision for an access key or user",
+		Long: `Simulate whether an access key or user may perform an action on a resource,
+or whether an access key may run a SQL statement against a branch. The
+statement is prepared but never executed.`,
+		Example: `  litebase policy simulate --access-key <id> --resource 'database:*' --action database:query
+  litebase policy simulate --access-key <id> --database mydb/main --sql 'SELECT * FROM users'
+  litebase policy simulate --user alice --resource '*' --action cluster:manage`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			accessKeyId, _ := cmd.Flags().GetString("access-key")
+			username, _ := cmd.Flags().GetString("user")
+			resource, _ := cmd.Flags().GetString("resource")
+			action, _ := cmd.Flags().GetString("action")
+			databasePath, _ := cmd.Flags().GetString("database")
+			statement, _ := cmd.Flags().GetString("sql")
+
+			if (accessKeyId == "") == (username == "") {
+				return errors.New("exactly one of --access-key or --user is required")
+			}
+
+			if (resource == "") == (statement == "") {
+				return errors.New("exactly one of --resource or --sql is required")
+			}
+
+			body := map[string]any{}
+
+			if accessKeyId != "" {
+				body["access_key_id"] = accessKeyId
+			} else {
+				body["username"] = username
+			}
+
+			if resource != "" {
+				if action == "" {
+					return errors.New("--action is required with --resource")
+				}
+
+				body["resource"] = resource
+				body["action"] = action
+			} else {
+				databaseName, branchName, err := splitDatabasePath(databasePath)
+
+				if err != nil {
+					return fmt.Errorf("invalid database path: %w", err)
+				}
+
+				body["database"] = databaseName
+				body["branch"] = branchName
+				body["statement"] = statement
+			}
+
+			context := map[string]any{}
+
+			if sourceIP, _ := cmd.Flags().GetString("source-ip"); sourceIP != "" {
+				context["source_ip"] = sourceIP
+			}
+
+			if at, _ := cmd.Flags().GetString("time"); at != "" {
+				context["time"] = at
+			}
+
+			for _, flag := range []string{"tls", "replica"} {
+				if cmd.Flags().Changed(flag) {
+					context[flag], _ = cmd.Flags().GetBool(flag)
+				}
+			}
+
+			if len(context) > 0 {
+				body["context"] = context
+			}
+
+			res, _, err := api.Post(config, "/v1/policy/simulate", body)
+
+			if err != nil {
+				return err
+			}
+
+			data, ok := res["data"].(map[string]any)
+
+			if !ok {
+				return errors.New("invalid response from the server")
+			}
+
+			if _, ok := data["checks"]; ok {
+				lipgloss.Fprint(cmd.OutOrStdout(), policySimulationOutput(config, data))
+
+				return nil
+			}
+
+			output, err := policyDecisionOutput(data)
+
+			if err != nil {
+				return err
+			}
+
+			lipgloss.Fprint(cmd.OutOrStdout(), output)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("access-key", "", "ID of the access key to simulate")
+	cmd.Flags().String("user", "", "Username of the user to simulate")
+	cmd.Flags().String("resource", "", "Resource to evaluate, e.g. database:*")
+	cmd.Flags().String("action", "", "Action to evaluate, e.g. database:query")
+	cmd.Flags().String("database", "", "Database and branch for --sql, e.g. mydb/main")
+	cmd.Flags().String("sql", "", "SQL statement to prepare with the access key")
+	cmd.Flags().String("source-ip", "", "Source IP address to evaluate conditions with")
+	cmd.Flags().String("time", "", "Time to evaluate conditions at, in RFC 3339 format")
+	cmd.Flags().Bool("tls", false, "Evaluate conditions as if the request was made over TLS")
+	cmd.Flags().Bool("replica", false, "Evaluate conditions as if the request was handled by a replica")
+
+	return cmd
+}
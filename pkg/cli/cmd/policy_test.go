@@ -0,0 +1,68 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestPolicySimulateCmd(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		accessKey, err := server.App.Auth.AccessKeyManager.Create(
+			"Simulated access key",
+			[]auth.AccessKeyStatement{
+				{Effect: auth.AccessKeyEffectAllow, Resource: "database:*", Actions: []auth.Privilege{auth.DatabasePrivilegeQuery}},
+			},
+		)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		cli := test.NewTestCLI(server.App).
+			WithServer(server).
+			WithAccessKey([]auth.AccessKeyStatement{
+				{Effect: auth.AccessKeyEffectAllow, Resource: "*", Actions: []auth.Privilege{"*"}},
+			})
+
+		err = cli.Run(
+			"policy", "simulate",
+			"--access-key", accessKey.AccessKeyID,
+			"--resource", "database:test",
+			"--action", "database:query",
+		)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("Allowed") || cli.DoesntSee("Allow database:*") {
+			t.Error("expected output to contain the allowing statement")
+		}
+
+		err = cli.Run(
+			"policy", "simulate",
+			"--access-key", accessKey.AccessKeyID,
+			"--resource", "access-key:*",
+			"--action", "access-key:list",
+		)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("Denied") || cli.DoesntSee("No matching statement") {
+			t.Error("expected output to contain the implicit deny")
+		}
+
+		err = cli.Run("policy", "simulate", "--resource", "*", "--action", "database:query")
+
+		if err == nil {
+			t.Error("expected an error without an access key or user")
+		}
+	})
+}
@@ -17,6 +17,7 @@ func NewProfileCmd(c *config.Configuration) *cobra.Command {
 	cmd.AddCommand(NewProfileCurrentCmd(c))
 	cmd.AddCommand(NewProfileDeleteCmd(c))
 	cmd.AddCommand(NewProfileListCmd(c))
+	cmd.AddCommand(NewProfileLoginCmd(c))
 	cmd.AddCommand(NewProfileSwitchCmd(c))
 
 	return cmd
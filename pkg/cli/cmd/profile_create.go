@@ -65,14 +65,15 @@ func NewProfileCreateCmd(c *config.Configuration) *cobra.Command {
 							Options(
 								huh.NewOption(string(config.ProfileTypeAccessKey), string(config.ProfileTypeAccessKey)),
 								huh.NewOption(string(config.ProfileTypeBasicAuth), string(config.ProfileTypeBasicAuth)),
+								huh.NewOption(string(config.ProfileTypeSSO), string(config.ProfileTypeSSO)),
 							).
 							Validate(func(str string) error {
 								if str == "" {
 									return errors.New("type cannot be empty")
 								}
 
-								if str != string(config.ProfileTypeAccessKey) && str != string(config.ProfileTypeBasicAuth) {
-									return errors.New("invalid type, must be either 'Access Key' or 'Basic Auth'")
+								if str != string(config.ProfileTypeAccessKey) && str != string(config.ProfileTypeBasicAuth) && str != string(config.ProfileTypeSSO) {
+									return errors.New("invalid type, must be either 'Access Key', 'Basic Auth' or 'SSO'")
 								}
 
 								return nil
@@ -148,8 +149,8 @@ func NewProfileCreateCmd(c *config.Configuration) *cobra.Command {
 					}
 				}
 
-				if profile.Type != string(config.ProfileTypeAccessKey) && profile.Type != string(config.ProfileTypeBasicAuth) {
-					return fmt.Errorf("invalid profile type, must be either '%s' or '%s', got '%s'", config.ProfileTypeAccessKey, config.ProfileTypeBasicAuth, profile.Type)
+				if profile.Type != string(config.ProfileTypeAccessKey) && profile.Type != string(config.ProfileTypeBasicAuth) && profile.Type != string(config.ProfileTypeSSO) {
+					return fmt.Errorf("invalid profile type, must be either '%s', '%s' or '%s', got '%s'", config.ProfileTypeAccessKey, config.ProfileTypeBasicAuth, config.ProfileTypeSSO, profile.Type)
 				}
 
 				if profile.Type == string(config.ProfileTypeBasicAuth) {
@@ -160,8 +161,16 @@ func NewProfileCreateCmd(c *config.Configuration) *cobra.Command {
 					if profile.Credentials.AccessKeyID == "" || profile.Credentials.AccessKeySecret == "" {
 						return errors.New("access key id and secret are required for Access Key type")
 					}
-				} else {
-					return errors.New("invalid profile type, must be either 'Access Key' or 'Basic Auth'")
+				} else if profile.Type != string(config.ProfileTypeSSO) {
+					return errors.New("invalid profile type, must be either 'Access Key', 'Basic Auth' or 'SSO'")
+				}
+			}
+
+			if profile.Type == string(config.ProfileTypeSSO) {
+				err := signIn(cmd, &profile)
+
+				if err != nil {
+					return err
 				}
 			}
 
@@ -185,7 +194,7 @@ func NewProfileCreateCmd(c *config.Configuration) *cobra.Command {
 	// Add flags
 	cmd.Flags().String("profile-name", "", "Name of the profile (required)")
 	cmd.Flags().String("profile-cluster", "", "Cluster URL (required)")
-	cmd.Flags().String("profile-type", "", "Type of profile (Access Key or Basic Auth) (required)")
+	cmd.Flags().String("profile-type", "", "Type of profile (access_key, basic_auth or sso) (required)")
 	cmd.Flags().String("profile-username", "", "Username for Basic Auth (required if type is Basic Auth)")
 	cmd.Flags().String("profile-password", "", "Password for Basic Auth (required if type is Basic Auth)")
 	cmd.Flags().String("profile-access-key-id", "", "Access Key ID (required if type is Access Key)")
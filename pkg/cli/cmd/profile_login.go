@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/litebase/litebase/pkg/cli/api"
+	"github.com/litebase/litebase/pkg/cli/components"
+	"github.com/litebase/litebase/pkg/cli/config"
+
+	"github.com/spf13/cobra"
+)
+
+func NewProfileLoginCmd(c *config.Configuration) *cobra.Command {
+	return &cobra.Command{
+		Use:   "login [name]",
+		Short: "Sign in again with the identity provider of a single sign-on profile",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				profile *config.Profile
+				err     error
+			)
+
+			if len(args) == 1 {
+				profile = c.GetProfile(args[0])
+			} else {
+				profile, err = c.GetCurrentProfile()
+
+				if err != nil {
+					return err
+				}
+			}
+
+			if profile == nil {
+				return config.ErrorProfileNotFound
+			}
+
+			if profile.Type != string(config.ProfileTypeSSO) {
+				return errors.New("only single sign-on profiles can sign in, the profile uses stored credentials")
+			}
+
+			err = signIn(cmd, profile)
+
+			if err != nil {
+				return err
+			}
+
+			err = c.UpdateProfile(*profile)
+
+			if err != nil {
+				return err
+			}
+
+			lipgloss.Fprint(
+				cmd.OutOrStdout(),
+				components.Container(
+					components.SuccessAlert(fmt.Sprintf("Signed in successfully to '%s'", profile.Name)),
+				),
+			)
+
+			return nil
+		},
+	}
+}
+
+// Sign in with the identity provider of the cluster of the profile and store
+// the session in the credentials of the profile.
+func signIn(cmd *cobra.Command, profile *config.Profile) error {
+	session, err := api.SignIn(profile.Cluster, cmd.OutOrStdout())
+
+	if err != nil {
+		return fmt.Errorf("failed to sign in: %w", err)
+	}
+
+	profile.Credentials.Username = session.Username
+	profile.Credentials.SessionToken = session.Token
+	profile.Credentials.SessionExpiresAt = &session.ExpiresAt
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestProfileLoginCmd(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		provider := test.NewMockOIDCProvider(t)
+
+		server.App.Config.OIDCIssuer = provider.URL
+		server.App.Config.OIDCClientID = provider.ClientID
+		server.App.Config.OIDCRoleMappings = []string{"engineering=readers"}
+
+		_, err := server.App.Auth.RoleManager().Add("readers", "", []auth.AccessKeyStatement{
+			{Effect: auth.AccessKeyEffectAllow, Resource: "access-key:*", Actions: []auth.Privilege{auth.AccessKeyPrivilegeList}},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cli := test.NewTestCLI(server.App)
+
+		err = cli.Run("profile", "create", "--profile-name", "sso", "--profile-cluster", server.Server.URL, "--profile-type", "sso")
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("ABCD-EFGH") {
+			t.Errorf("expected output to contain the user code, got %q", cli.GetOutput())
+		}
+
+		if cli.DoesntSee("Profile stored successfully") {
+			t.Errorf("expected output to contain 'Profile stored successfully', got %q", cli.GetOutput())
+		}
+
+		// The session of the profile authenticates requests
+		err = cli.Run("access-key", "list")
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		cli.ClearOutput()
+
+		err = cli.Run("profile", "login", "sso")
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("Signed in successfully to 'sso'") {
+			t.Errorf("expected output to contain 'Signed in successfully to 'sso'', got %q", cli.GetOutput())
+		}
+	})
+}
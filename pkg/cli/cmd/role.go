@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/litebase/litebase/pkg/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewRoleCmd(config *config.Configuration) *cobra.Command {
+	RoleCmd := &cobra.Command{
+		Use:   "role",
+		Short: "Manage roles",
+		Long:  "Manage named roles holding statements that can be assigned to users and access keys.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := cmd.Help()
+
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	RoleCmd.AddCommand(NewRoleListCmd(config))
+	RoleCmd.AddCommand(NewRoleCreateCmd(config))
+	RoleCmd.AddCommand(NewRoleDeleteCmd(config))
+	RoleCmd.AddCommand(NewRoleShowCmd(config))
+	RoleCmd.AddCommand(NewRoleUpdateCmd(config))
+
+	return RoleCmd
+}
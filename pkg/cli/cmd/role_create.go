@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/litebase/litebase/pkg/cli/api"
+	"github.com/litebase/litebase/pkg/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewRoleCreateCmd(config *config.Configuration) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a new role",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			description, err := cmd.Flags().GetString("description")
+
+			if err != nil {
+				return err
+			}
+
+			statementsString, err := cmd.Flags().GetString("statements")
+
+			if err != nil {
+				return err
+			}
+
+			var statements []AccessKeyInputStatement
+
+			if err := json.Unmarshal([]byte(statementsString), &statements); err != nil {
+				return errors.New("invalid JSON format for statements")
+			}
+
+			res, _, err := api.Post(config, "/v1/roles", map[string]any{
+				"name":        args[0],
+				"description": description,
+				"statements":  statements,
+			})
+
+			if err != nil {
+				return err
+			}
+
+			output, err := roleCard(res["data"].(map[string]any), res["message"].(string))
+
+			if err != nil {
+				return err
+			}
+
+			lipgloss.Fprint(cmd.OutOrStdout(), output)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("description", "", "Description for the role")
+	cmd.Flags().String("statements", "", "JSON array of statements")
+
+	_ = cmd.MarkFlagRequired("statements")
+
+	return cmd
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/litebase/litebase/pkg/cli/api"
+	"github.com/litebase/litebase/pkg/cli/components"
+	"github.com/litebase/litebase/pkg/cli/config"
+
+	"github.com/spf13/cobra"
+)
+
+func NewRoleDeleteCmd(config *config.Configuration) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a role",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, _, err := api.Delete(config, "/v1/roles/"+args[0])
+
+			if err != nil {
+				return err
+			}
+
+			lipgloss.Fprint(
+				cmd.OutOrStdout(),
+				components.Container(
+					components.SuccessAlert("Role deleted successfully"),
+				),
+			)
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/litebase/litebase/pkg/cli/api"
+	"github.com/litebase/litebase/pkg/cli/components"
+	"github.com/litebase/litebase/pkg/cli/config"
+
+	"github.com/spf13/cobra"
+)
+
+func NewRoleListCmd(config *config.Configuration) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List roles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := api.Get(config, "/v1/roles")
+
+			if err != nil {
+				return err
+			}
+
+			roles, ok := data["data"].([]any)
+
+			if !ok || len(roles) == 0 {
+				lipgloss.Fprint(
+					cmd.OutOrStdout(),
+					components.Container(components.WarningAlert("No roles found")),
+				)
+
+				return nil
+			}
+
+			rows := [][]string{}
+
+			for i, role := range roles {
+				var name, description string = "-", "-"
+
+				if a, ok := role.(map[string]any)["name"].(string); ok {
+					name = a
+				}
+
+				if a, ok := role.(map[string]any)["description"].(string); ok && a != "" {
+					description = a
+				}
+
+				statements, _ := role.(map[string]any)["statements"].([]any)
+
+				rows = append(rows, []string{
+					fmt.Sprintf("%d", i+1),
+					name,
+					description,
+					fmt.Sprintf("%d", len(statements)),
+				})
+			}
+
+			columns := []string{
+				"#",
+				"Name",
+				"Description",
+				"Statements",
+			}
+
+			lipgloss.Fprint(
+				cmd.OutOrStdout(),
+				components.Container(
+					components.NewTable(columns, rows).
+						SetHandler(func(row []string) {
+							roleShow(cmd, config, row[1])
+						}).Render(config.GetInteractive()),
+				),
+			)
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/litebase/litebase/pkg/cli/api"
+	"github.com/litebase/litebase/pkg/cli/components"
+	"github.com/litebase/litebase/pkg/cli/config"
+	"github.com/spf13/cobra"
+)
+
+// Render a role as a card, optionally preceded by a success message.
+func roleCard(data map[string]any, message string) (string, error) {
+	var cardContent string
+
+	rows := []components.CardRow{
+		{
+			Key:   "Name",
+			Value: fmt.Sprint(data["name"]),
+		},
+	}
+
+	if description, ok := data["description"].(string); ok && description != "" {
+		rows = append(rows, components.CardRow{
+			Key:   "Description",
+			Value: description,
+		})
+	}
+
+	for _, field := range []struct{ key, label string }{
+		{"created_at", "Created At"},
+		{"updated_at", "Updated At"},
+	} {
+		value, ok := data[field.key].(string)
+
+		if !ok {
+			continue
+		}
+
+		parsedDate, err := time.Parse(time.RFC3339, value)
+
+		if err != nil {
+			return "", err
+		}
+
+		rows = append(rows, components.CardRow{
+			Key:   field.label,
+			Value: parsedDate.Format(time.RFC3339),
+		})
+	}
+
+	if data["statements"] != nil {
+		statementsJSON, err := json.MarshalIndent(data["statements"], "", "  ")
+
+		if err != nil {
+			return "", err
+		}
+
+		cardContent = "```json\n" + string(statementsJSON) + "\n```"
+	}
+
+	card := components.NewCard(
+		components.WithCardTitle("Role"),
+		components.WithCardRows(rows),
+		components.WithCardContent("Statements", cardContent),
+	).Render()
+
+	if message != "" {
+		return components.Container(components.SuccessAlert(message), card), nil
+	}
+
+	return components.Container(card), nil
+}
+
+func roleShow(cmd *cobra.Command, config *config.Configuration, name string) error {
+	res, err := api.Get(config, fmt.Sprintf("/v1/roles/%s", name))
+
+	if err != nil {
+		return err
+	}
+
+	output, err := roleCard(res["data"].(map[string]any), "")
+
+	if err != nil {
+		return err
+	}
+
+	lipgloss.Fprint(cmd.OutOrStdout(), output)
+
+	return nil
+}
+
+func NewRoleShowCmd(config *config.Configuration) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show role details",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return roleShow(cmd, config, args[0])
+		},
+	}
+}
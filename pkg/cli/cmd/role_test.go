@@ -0,0 +1,78 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestRoleCmd(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		cli := test.NewTestCLI(server.App).
+			WithServer(server).
+			WithAccessKey([]auth.AccessKeyStatement{
+				{Effect: auth.AccessKeyEffectAllow, Resource: "*", Actions: []auth.Privilege{"*"}},
+			})
+
+		err := cli.Run(
+			"role", "create", "readers",
+			"--description", "Read only access",
+			"--statements", `[{"effect":"Allow","resource":"database:*","actions":["database:read"]}]`,
+		)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("Role created successfully") {
+			t.Error("expected output to contain 'Role created successfully'")
+		}
+
+		err = cli.Run("role", "list")
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("readers") {
+			t.Error("expected output to contain 'readers'")
+		}
+
+		err = cli.Run(
+			"role", "update", "readers",
+			"--statements", `[{"effect":"Allow","resource":"database:*","actions":["database:insert"]}]`,
+		)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("database:insert") {
+			t.Error("expected output to contain the updated statements")
+		}
+
+		err = cli.Run("role", "show", "readers")
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("Read only access") {
+			t.Error("expected output to contain the role description")
+		}
+
+		err = cli.Run("role", "delete", "readers")
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("Role deleted") {
+			t.Error("expected output to contain 'Role deleted'")
+		}
+	})
+}
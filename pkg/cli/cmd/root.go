@@ -25,11 +25,16 @@ var (
 
 func addCommands(cmd *cobra.Command, c *config.Configuration) {
 	cmd.AddCommand(NewAccessKeyCmd(c))
+	cmd.AddCommand(NewAuditCmd(c))
+	cmd.AddCommand(NewClusterCmd(c))
 	cmd.AddCommand(NewDatabaseCmd(c))
+	cmd.AddCommand(NewPolicyCmd(c))
 	cmd.AddCommand(NewProfileCmd(c))
+	cmd.AddCommand(NewRoleCmd(c))
 	cmd.AddCommand(NewServeCmd())
 	cmd.AddCommand(NewSQLCmd(c))
 	cmd.AddCommand(NewStatusCmd(c))
+	cmd.AddCommand(NewStorageCmd(c))
 	cmd.AddCommand(NewUserCmd(c))
 }
 
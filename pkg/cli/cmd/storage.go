@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/litebase/litebase/pkg/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewStorageCmd(config *config.Configuration) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "storage",
+		Short: "Manage cluster storage",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := cmd.Help()
+
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.AddCommand(NewStorageGarbageCollectionCmd(config))
+	cmd.AddCommand(NewStorageInspectCmd(config))
+
+	return cmd
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/litebase/litebase/pkg/cli/api"
+	"github.com/litebase/litebase/pkg/cli/components"
+	"github.com/litebase/litebase/pkg/cli/config"
+	"github.com/spf13/cobra"
+)
+
+func NewStorageGarbageCollectionCmd(config *config.Configuration) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Report and sweep orphaned objects in storage",
+		Long:  "Report the objects left behind in storage by deleted databases and branches, failed backups and abandoned range versions and page logs. Nothing is deleted unless the --delete flag is given.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			deleteObjects, err := cmd.Flags().GetBool("delete")
+
+			if err != nil {
+				return err
+			}
+
+			var res map[string]any
+
+			if deleteObjects {
+				var apiErrors api.Errors
+
+				res, apiErrors, err = api.Post(config, "/v1/storage/gc", map[string]any{
+					"dry_run": false,
+				})
+
+				if err == nil && len(apiErrors) > 0 {
+					err = fmt.Errorf("failed to sweep orphaned objects: %v", apiErrors)
+				}
+			} else {
+				res, err = api.Get(config, "/v1/storage/gc")
+			}
+
+			if err != nil {
+				return err
+			}
+
+			data, ok := res["data"].(map[string]any)
+
+			if !ok {
+				return fmt.Errorf("invalid data format for garbage collection report")
+			}
+
+			objects, _ := data["objects"].([]any)
+
+			totalSize, _ := data["total_size"].(float64)
+
+			rows := []components.CardRow{
+				{
+					Key:   "Orphaned Objects",
+					Value: fmt.Sprintf("%d", len(objects)),
+				},
+				{
+					Key:   "Orphaned Size",
+					Value: components.FormatBytes(int64(totalSize)),
+				},
+			}
+
+			if deleteObjects {
+				deletedSize, _ := data["deleted_size"].(float64)
+
+				rows = append(rows, components.CardRow{
+					Key:   "Deleted Size",
+					Value: components.FormatBytes(int64(deletedSize)),
+				})
+			}
+
+			output := []string{
+				components.SuccessAlert(res["message"].(string)),
+				components.NewCard(
+					components.WithCardTitle("Garbage Collection"),
+					components.WithCardRows(rows),
+				).Render(),
+			}
+
+			if len(objects) > 0 {
+				tableRows := [][]string{}
+
+				for i, object := range objects {
+					objectData, ok := object.(map[string]any)
+
+					if !ok {
+						continue
+					}
+
+					size, _ := objectData["size"].(float64)
+
+					tableRows = append(tableRows, []string{
+						fmt.Sprintf("%d", i+1),
+						fmt.Sprintf("%v", objectData["type"]),
+						fmt.Sprintf("%v", objectData["storage"]),
+						fmt.Sprintf("%v", objectData["path"]),
+						components.FormatBytes(int64(size)),
+						fmt.Sprintf("%v", objectData["reason"]),
+					})
+				}
+
+				output = append(output, components.NewTable(
+					[]string{"#", "Type", "Storage", "Path", "Size", "Reason"},
+					tableRows,
+				).Render(false))
+			}
+
+			if !deleteObjects && len(objects) > 0 {
+				output = append(output, components.InfoAlert("This was a dry run. Run the command again with --delete to remove the orphaned objects."))
+			}
+
+			lipgloss.Fprint(
+				cmd.OutOrStdout(),
+				components.Container(output...),
+			)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("delete", false, "Delete the orphaned objects instead of only reporting them")
+
+	return cmd
+}
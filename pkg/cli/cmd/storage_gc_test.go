@@ -0,0 +1,63 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestStorageGarbageCollectionCmd(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		cli := test.NewTestCLI(server.App).
+			WithServer(server).
+			WithAccessKey([]auth.AccessKeyStatement{
+				{Effect: auth.AccessKeyEffectAllow, Resource: "*", Actions: []auth.Privilege{"*"}},
+			})
+
+		err := server.App.Cluster.ObjectFS().WriteFile("_databases/orphaned/main/backups/1/backup-1.tar.gz", []byte("backup"), 0600)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		err = cli.Run("storage", "gc")
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("Garbage Collection") {
+			t.Errorf("expected output to contain 'Garbage Collection', got %q", cli.GetOutput())
+		}
+
+		if cli.DoesntSee("_databases/orphaned/") {
+			t.Errorf("expected output to contain the orphaned database, got %q", cli.GetOutput())
+		}
+
+		if cli.DoesntSee("dry run") {
+			t.Errorf("expected output to mention the dry run, got %q", cli.GetOutput())
+		}
+
+		if _, err := server.App.Cluster.ObjectFS().Stat("_databases/orphaned/main/backups/1/backup-1.tar.gz"); err != nil {
+			t.Errorf("expected the orphaned object to remain after a dry run, got %v", err)
+		}
+
+		err = cli.Run("storage", "gc", "--delete")
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("Deleted Size") {
+			t.Errorf("expected output to contain 'Deleted Size', got %q", cli.GetOutput())
+		}
+
+		if _, err := server.App.Cluster.ObjectFS().Stat("_databases/orphaned/main/backups/1/backup-1.tar.gz"); err == nil {
+			t.Error("expected the orphaned object to be deleted")
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/litebase/litebase/pkg/cli/components"
+	"github.com/litebase/litebase/pkg/cli/config"
+	"github.com/litebase/litebase/pkg/inspector"
+	"github.com/litebase/litebase/pkg/storage"
+	"github.com/spf13/cobra"
+)
+
+func NewStorageInspectCmd(config *config.Configuration) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect <directory>",
+		Short: "Inspect and repair storage files offline",
+		Long:  "Decode the ranges, page logs, WAL files and indexes, rollback logs and snapshot logs in a data directory and verify their consistency. The directory is read directly and no server is required. Indexes that can be derived from other files are rebuilt when the --rebuild flag is given.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pageSize, err := cmd.Flags().GetInt64("page-size")
+
+			if err != nil {
+				return err
+			}
+
+			if pageSize <= 0 {
+				return fmt.Errorf("the page size must be greater than 0")
+			}
+
+			rebuild, err := cmd.Flags().GetBool("rebuild")
+
+			if err != nil {
+				return err
+			}
+
+			info, err := os.Stat(args[0])
+
+			if err != nil {
+				return err
+			}
+
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", args[0])
+			}
+
+			i := inspector.NewInspector(
+				storage.NewFileSystem(storage.NewLocalFileSystemDriver(args[0])),
+				pageSize,
+			)
+
+			rebuilt := []string{}
+
+			if rebuild {
+				rebuilt, err = i.Rebuild("")
+
+				if err != nil {
+					return err
+				}
+			}
+
+			report, err := i.Inspect("")
+
+			if err != nil {
+				return err
+			}
+
+			report.Rebuilt = rebuilt
+
+			output := []string{
+				components.NewCard(
+					components.WithCardTitle("Storage Inspection"),
+					components.WithCardRows([]components.CardRow{
+						{Key: "Directory", Value: args[0]},
+						{Key: "Files", Value: fmt.Sprintf("%d", len(report.Files))},
+						{Key: "Errors", Value: fmt.Sprintf("%d", report.Errors)},
+						{Key: "Warnings", Value: fmt.Sprintf("%d", report.Warnings)},
+						{Key: "Rebuilt Indexes", Value: fmt.Sprintf("%d", len(report.Rebuilt))},
+					}),
+				).Render(),
+			}
+
+			findingRows := [][]string{}
+
+			for _, file := range report.Files {
+				for _, finding := range file.Findings {
+					findingRows = append(findingRows, []string{
+						finding.Severity,
+						file.Kind,
+						finding.Path,
+						finding.Message,
+					})
+				}
+			}
+
+			if len(findingRows) > 0 {
+				output = append(output, components.NewTable(
+					[]string{"Severity", "Kind", "Path", "Finding"},
+					findingRows,
+				).Render(false))
+			}
+
+			for _, path := range report.Rebuilt {
+				output = append(output, components.SuccessAlert(fmt.Sprintf("Rebuilt %s", path)))
+			}
+
+			switch {
+			case report.Errors > 0:
+				output = append(output, components.ErrorAlert(fmt.Sprintf("Found %d errors and %d warnings.", report.Errors, report.Warnings)))
+			case report.Warnings > 0:
+				output = append(output, components.WarningAlert(fmt.Sprintf("Found %d warnings.", report.Warnings)))
+			default:
+				output = append(output, components.SuccessAlert("No problems found."))
+			}
+
+			lipgloss.Fprint(
+				cmd.OutOrStdout(),
+				components.Container(output...),
+			)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64("page-size", storage.PageSize, "The page size of the databases in the directory")
+	cmd.Flags().Bool("rebuild", false, "Rebuild range and WAL indexes from the files they are derived from")
+
+	return cmd
+}
@@ -0,0 +1,66 @@
+package cmd_test
+
+import (
+	"encoding/binary"
+	"os"
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/server"
+)
+
+func TestStorageInspectCmd(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		directory := t.TempDir()
+
+		err := os.MkdirAll(directory+"/db/main/logs/wal", 0750)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		err = os.WriteFile(directory+"/db/main/logs/wal/WAL_10", []byte{}, 0600)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		err = os.WriteFile(directory+"/db/main/logs/wal/WAL_INDEX", binary.LittleEndian.AppendUint64(nil, 20), 0600)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		cli := test.NewTestCLI(app)
+
+		err = cli.Run("storage", "inspect", directory)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("Storage Inspection") {
+			t.Errorf("expected output to contain 'Storage Inspection', got %q", cli.GetOutput())
+		}
+
+		if cli.DoesntSee("WAL file WAL_20 does not exist") {
+			t.Errorf("expected output to report the missing WAL file, got %q", cli.GetOutput())
+		}
+
+		cli.ClearOutput()
+
+		err = cli.Run("storage", "inspect", directory, "--rebuild")
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if cli.DoesntSee("Rebuilt db/main/logs/wal/WAL_INDEX") {
+			t.Errorf("expected output to report the rebuilt index, got %q", cli.GetOutput())
+		}
+
+		if cli.DoesntSee("No problems found.") {
+			t.Errorf("expected no problems after rebuilding, got %q", cli.GetOutput())
+		}
+	})
+}
@@ -44,6 +44,33 @@ func DatabaseCard(data map[string]any) string {
 		Value: data["url"].(string),
 	})
 
+	if usage, ok := data["usage"].(map[string]any); ok {
+		rows = append(rows, CardRow{
+			Key:   "",
+			Value: "",
+		})
+
+		for _, field := range []struct {
+			key   string
+			label string
+		}{
+			{"ranges", "Ranges"},
+			{"page_logs", "Page Logs"},
+			{"wal", "WAL"},
+			{"rollback_logs", "Rollback Logs"},
+			{"snapshots", "Snapshots"},
+			{"backups", "Backups"},
+			{"total", "Total Storage"},
+		} {
+			if value, ok := usage[field.key].(float64); ok {
+				rows = append(rows, CardRow{
+					Key:   field.label,
+					Value: FormatBytes(int64(value)),
+				})
+			}
+		}
+	}
+
 	return NewCard(
 		WithCardTitle("Database"),
 		WithCardRows(rows),
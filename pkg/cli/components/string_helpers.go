@@ -1,5 +1,7 @@
 package components
 
+import "fmt"
+
 // truncateString truncates a string to maxLength and adds "..." if truncated
 func truncateString(s string, maxLength int) string {
 	if len(s) <= maxLength {
@@ -12,3 +14,21 @@ func truncateString(s string, maxLength int) string {
 
 	return s[:maxLength-3] + "..."
 }
+
+// FormatBytes formats a number of bytes using binary units
+func FormatBytes(bytes int64) string {
+	const unit = 1024
+
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
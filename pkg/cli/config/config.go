@@ -24,6 +24,7 @@ type Configuration struct {
 var ErrMissingClusterURL = errors.New("missing cluster URL")
 var ErrorCredentialsNotSet = errors.New("credentials were not set, please provide access credentials or a stored profile name")
 var ErrorProfileNotFound = errors.New("profile not found, provide a valid profile name")
+var ErrSessionExpired = errors.New("the session of the profile has expired, run `litebase profile login` to sign in again")
 
 // Create a new configuration instance.
 func NewConfiguration(path string) (*Configuration, error) {
@@ -206,6 +207,19 @@ func (c *Configuration) SetUsername(username string) {
 	c.username = username
 }
 
+// Replace a stored profile with the profile of the same name.
+func (c *Configuration) UpdateProfile(profile Profile) error {
+	for i := range c.Profiles {
+		if c.Profiles[i].Name == profile.Name {
+			c.Profiles[i] = profile
+
+			return c.Save()
+		}
+	}
+
+	return ErrorProfileNotFound
+}
+
 func (c *Configuration) SwitchProfile(name string) error {
 	profile := c.GetProfile(name)
 
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type Profile struct {
 	Name        string             `json:"name"`
 	Cluster     string             `json:"cluster"`
@@ -12,6 +14,7 @@ type ProfileType string
 const (
 	ProfileTypeBasicAuth ProfileType = "basic_auth"
 	ProfileTypeAccessKey ProfileType = "access_key"
+	ProfileTypeSSO       ProfileType = "sso"
 )
 
 type ProfileCredentials struct {
@@ -19,4 +22,8 @@ type ProfileCredentials struct {
 	Password        string `json:"password"`
 	AccessKeyID     string `json:"accessKeyId"`
 	AccessKeySecret string `json:"accessKeySecret"`
+
+	// The session of a single sign-on profile, renewed by signing in again.
+	SessionToken     string     `json:"sessionToken,omitempty"`
+	SessionExpiresAt *time.Time `json:"sessionExpiresAt,omitempty"`
 }
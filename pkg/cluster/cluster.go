@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
+	"net/http"
 	"os"
 	"path/filepath"
 	"slices"
@@ -23,8 +24,9 @@ const (
 	ClusterMembershipPrimary = "PRIMARY"
 	ClusterMembershipReplica = "REPLICA"
 
-	LeaseFile   = "LEASE"
-	PrimaryFile = "PRIMARY"
+	LeaseFile     = "LEASE"
+	LeaseTermFile = "LEASE_TERM"
+	PrimaryFile   = "PRIMARY"
 )
 
 var (
@@ -40,12 +42,14 @@ type Cluster struct {
 	fileSystemMutex    *sync.Mutex
 	Initialized        bool   `json:"-"`
 	Id                 string `json:"id"`
+	internalTransport  *http.Transport
 	QueryPrimary       string `json:"-"`
 	nodes              []*NodeIdentifier
 	MembersRetrievedAt time.Time `json:"-"`
 	mutex              *sync.Mutex
 	node               *Node
 	subscriptions      map[string][]EventHandler
+	tls                *ClusterTLS
 
 	localFileSystem     *storage.FileSystem
 	objectFileSystem    *storage.FileSystem
@@ -104,14 +108,47 @@ func (cluster *Cluster) createDirectoriesAndFiles() error {
 	return nil
 }
 
+// Check that the database prefetch mode, replica lag policy and replica mode
+// are supported.
+func validateConfig(c *config.Config) error {
+	if err := config.ValidateDatabasePrefetch(c.DatabasePrefetch); err != nil {
+		return err
+	}
+
+	if err := config.ValidateReplicaLagPolicy(c.ReplicaLagPolicy); err != nil {
+		return err
+	}
+
+	return config.ValidateReplicaMode(c.ReplicaMode)
+}
+
 // Create a new cluster instance.
 func NewCluster(config *config.Config) (*Cluster, error) {
+	clusterTLS, err := ClusterTLSFromConfig(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := storage.ValidateTieredFileSystemEvictionPolicy(config.TieredStoragePolicy); err != nil {
+		return nil, err
+	}
+
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+
 	cluster := &Cluster{
 		Config:          config,
 		eventsChannel:   make(chan *EventMessage, 1000),
 		fileSystemMutex: &sync.Mutex{},
 		mutex:           &sync.Mutex{},
 		subscriptions:   map[string][]EventHandler{},
+		tls:             clusterTLS,
+	}
+
+	cluster.internalTransport = &http.Transport{
+		TLSClientConfig: cluster.TLSClientConfig(),
 	}
 
 	cluster.runEventLoop()
@@ -356,6 +393,12 @@ func (cluster *Cluster) LeasePath() string {
 	return fmt.Sprintf("_cluster/%s", LeaseFile)
 }
 
+// Return the path to the lease term file for the cluster. The term is
+// incremented each time the primary role changes hands during a handoff.
+func (cluster *Cluster) LeaseTermPath() string {
+	return fmt.Sprintf("_cluster/%s", LeaseTermFile)
+}
+
 // Return the path to the current node in repsect to the node type.
 func (cluster *Cluster) NodePath() string {
 	return "_nodes/"
@@ -143,7 +143,7 @@ func (ce *ClusterElection) proposeLeadership() bool {
 			request, err := http.NewRequestWithContext(
 				requestCtx,
 				"POST",
-				fmt.Sprintf("http://%s/v1/cluster/election", nodeAddress),
+				fmt.Sprintf("%s://%s/v1/cluster/election", ce.node.Cluster.InternalScheme(), nodeAddress),
 				bytes.NewBuffer(jsonData),
 			)
 
@@ -163,7 +163,11 @@ func (ce *ClusterElection) proposeLeadership() bool {
 				return
 			}
 
-			resp, err := http.DefaultClient.Do(request)
+			client := &http.Client{
+				Transport: ce.node.Cluster.InternalTransport(),
+			}
+
+			resp, err := client.Do(request)
 
 			if err != nil {
 				slog.Debug(
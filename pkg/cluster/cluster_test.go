@@ -2,6 +2,7 @@ package cluster_test
 
 import (
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/litebase/litebase/pkg/cluster"
 	"github.com/litebase/litebase/pkg/config"
 	"github.com/litebase/litebase/pkg/server"
+	"github.com/litebase/litebase/pkg/storage"
 )
 
 func TestCluster(t *testing.T) {
@@ -56,6 +58,37 @@ func TestCluster(t *testing.T) {
 			}
 		})
 
+		t.Run("NewClusterWithInvalidEvictionPolicy", func(t *testing.T) {
+			t.Setenv("LITEBASE_CLUSTER_ID", "TEST_CLUSTER_000")
+			t.Setenv("LITEBASE_TIERED_STORAGE_EVICTION_POLICY", "fifo")
+
+			_, err := cluster.NewCluster(config.NewConfig())
+
+			if !errors.Is(err, storage.ErrTieredFileSystemEvictionPolicyInvalid) {
+				t.Fatalf("Expected an invalid eviction policy error, got %v", err)
+			}
+		})
+
+		t.Run("NewClusterWithInvalidReplicaConfig", func(t *testing.T) {
+			t.Setenv("LITEBASE_CLUSTER_ID", "TEST_CLUSTER_000")
+			t.Setenv("LITEBASE_REPLICA_LAG_POLICY", "drop")
+
+			_, err := cluster.NewCluster(config.NewConfig())
+
+			if !errors.Is(err, config.ErrReplicaLagPolicyInvalid) {
+				t.Fatalf("Expected an invalid replica lag policy error, got %v", err)
+			}
+
+			t.Setenv("LITEBASE_REPLICA_LAG_POLICY", config.ReplicaLagPolicyForward)
+			t.Setenv("LITEBASE_REPLICA_MODE", "local")
+
+			_, err = cluster.NewCluster(config.NewConfig())
+
+			if !errors.Is(err, config.ErrReplicaModeInvalid) {
+				t.Fatalf("Expected an invalid replica mode error, got %v", err)
+			}
+		})
+
 		t.Run("AddMember(t *testing.T)", func(t *testing.T) {
 			server1 := test.NewTestServer(t)
 			defer server1.Shutdown()
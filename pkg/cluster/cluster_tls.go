@@ -0,0 +1,214 @@
+package cluster
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/litebase/litebase/pkg/config"
+	"github.com/litebase/litebase/pkg/https"
+)
+
+// The server name that nodes send when connecting to each other. The server
+// presents the node certificate for this name and the public certificate, if
+// configured, for any other name.
+const ClusterTLSServerName = "litebase.cluster.internal"
+
+var (
+	clusterTLSInstances = &sync.Map{}
+
+	ErrClusterTLSClientCertificateRequired = errors.New("a client certificate signed by the cluster CA is required")
+	ErrClusterTLSIncomplete                = errors.New("the cluster CA, certificate and key paths must all be set to enable mTLS")
+)
+
+// ClusterTLS holds the certificates used for mutual TLS between the nodes of
+// the cluster. Each node presents a certificate signed by the cluster CA and
+// only accepts peers presenting a certificate signed by the same CA. The files
+// are checked for changes periodically so certificates can be rotated without
+// restarting the node.
+type ClusterTLS struct {
+	reloader *https.CertificateReloader
+}
+
+// Create a new instance of ClusterTLS from the configuration. Nil is returned
+// if mTLS has not been configured.
+func NewClusterTLS(c *config.Config) (*ClusterTLS, error) {
+	if c.ClusterTLSCAPath == "" && c.ClusterTLSCertPath == "" && c.ClusterTLSKeyPath == "" {
+		return nil, nil
+	}
+
+	if c.ClusterTLSCAPath == "" || c.ClusterTLSCertPath == "" || c.ClusterTLSKeyPath == "" {
+		return nil, ErrClusterTLSIncomplete
+	}
+
+	reloader, err := https.NewCertificateReloaderWithCA(
+		c.ClusterTLSCAPath,
+		c.ClusterTLSCertPath,
+		c.ClusterTLSKeyPath,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the cluster certificates: %w", err)
+	}
+
+	return &ClusterTLS{reloader: reloader}, nil
+}
+
+// Return the ClusterTLS for the configuration, creating it the first time it
+// is requested. The HTTP server and the cluster share the instance, so the
+// certificates are loaded and reloaded once per node.
+func ClusterTLSFromConfig(c *config.Config) (*ClusterTLS, error) {
+	if clusterTLS, ok := clusterTLSInstances.Load(c); ok {
+		return clusterTLS.(*ClusterTLS), nil
+	}
+
+	clusterTLS, err := NewClusterTLS(c)
+
+	if err != nil || clusterTLS == nil {
+		return clusterTLS, err
+	}
+
+	instance, _ := clusterTLSInstances.LoadOrStore(c, clusterTLS)
+
+	return instance.(*ClusterTLS), nil
+}
+
+// Return the TLS configuration used by nodes to connect to other nodes.
+// Server names are not verified since nodes are addressed by their IP
+// address, instead the peer certificate must be signed by the cluster CA.
+func (ct *ClusterTLS) ClientConfig() *tls.Config {
+	return &tls.Config{
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			certificate, _ := ct.current()
+
+			return certificate, nil
+		},
+		// Verification is performed by VerifyConnection against the CA that
+		// is currently loaded.
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         ClusterTLSServerName,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			return ct.verify(state.PeerCertificates, x509.ExtKeyUsageServerAuth)
+		},
+	}
+}
+
+// Return the certificate and CA pool that are currently loaded, reloading
+// them if the files have changed.
+func (ct *ClusterTLS) current() (*tls.Certificate, *x509.CertPool) {
+	return ct.reloader.Current()
+}
+
+// Load the CA, certificate and key from their files.
+func (ct *ClusterTLS) Reload() error {
+	return ct.reloader.Reload()
+}
+
+// Return the TLS configuration of the node server. Connections for the
+// cluster server name are served with the node certificate, other
+// connections are served with the public certificate when one is given.
+// Client certificates are verified against the cluster CA when presented so
+// the internal routes can require them.
+func (ct *ClusterTLS) ServerConfig(
+	publicCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error),
+	nextProtos []string,
+) *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			certificate, caPool := ct.current()
+
+			if publicCertificate != nil && hello.ServerName != ClusterTLSServerName {
+				var err error
+
+				certificate, err = publicCertificate(hello)
+
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			return &tls.Config{
+				Certificates: []tls.Certificate{*certificate},
+				ClientAuth:   tls.VerifyClientCertIfGiven,
+				ClientCAs:    caPool,
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   nextProtos,
+			}, nil
+		},
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+	}
+}
+
+// Verify that a certificate chain presented by a peer was signed by the
+// cluster CA.
+func (ct *ClusterTLS) verify(certificates []*x509.Certificate, usage x509.ExtKeyUsage) error {
+	if len(certificates) == 0 {
+		return errors.New("the peer did not present a certificate")
+	}
+
+	_, caPool := ct.current()
+
+	intermediates := x509.NewCertPool()
+
+	for _, certificate := range certificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+
+	_, err := certificates[0].Verify(x509.VerifyOptions{
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+		Roots:         caPool,
+	})
+
+	return err
+}
+
+// Return the TLS configuration used to connect to other nodes, or nil if
+// the nodes are not served over TLS. Without mTLS the nodes present the public
+// certificate, which is verified against the host name of the cluster.
+func (cluster *Cluster) TLSClientConfig() *tls.Config {
+	if cluster.tls == nil {
+		if cluster.Config.TLSCertPath == "" {
+			return nil
+		}
+
+		return https.ClientConfig(cluster.Config)
+	}
+
+	return cluster.tls.ClientConfig()
+}
+
+// Return the scheme used for requests to other nodes.
+func (cluster *Cluster) InternalScheme() string {
+	if cluster.tls == nil && cluster.Config.TLSCertPath == "" {
+		return "http"
+	}
+
+	return "https"
+}
+
+// Return the transport used for requests to other nodes. The transport is
+// shared so connections to other nodes can be reused.
+func (cluster *Cluster) InternalTransport() *http.Transport {
+	return cluster.internalTransport
+}
+
+// Verify that a request to an internal route was sent by another node. When
+// mTLS is enabled the request must have been sent with a client certificate
+// that was verified against the cluster CA.
+func (cluster *Cluster) VerifyInternalConnection(state *tls.ConnectionState) error {
+	if cluster.tls == nil {
+		return nil
+	}
+
+	if state == nil || len(state.VerifiedChains) == 0 {
+		return ErrClusterTLSClientCertificateRequired
+	}
+
+	return nil
+}
@@ -0,0 +1,285 @@
+package cluster_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/litebase/litebase/pkg/cluster"
+	"github.com/litebase/litebase/pkg/config"
+)
+
+type testCertificateAuthority struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	pem         []byte
+}
+
+func newTestCertificateAuthority(t *testing.T) *testCertificateAuthority {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		NotAfter:              time.Now().Add(time.Hour),
+		NotBefore:             time.Now().Add(-time.Hour),
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "cluster-ca"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCertificateAuthority{
+		certificate: certificate,
+		key:         key,
+		pem:         pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// Issue a node certificate that can be used as a client and server certificate.
+func (ca *testCertificateAuthority) issue(t *testing.T, serial int64) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		NotAfter:     time.Now().Add(time.Hour),
+		NotBefore:    time.Now().Add(-time.Hour),
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "node"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.certificate, &key.PublicKey, ca.key)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+// Write the CA and a node certificate to a directory and return a config
+// that points to them.
+func writeClusterTLSFiles(t *testing.T, directory string, ca *testCertificateAuthority, serial int64) *config.Config {
+	certificate, key := ca.issue(t, serial)
+
+	c := &config.Config{
+		ClusterTLSCAPath:   filepath.Join(directory, "ca.pem"),
+		ClusterTLSCertPath: filepath.Join(directory, "node.pem"),
+		ClusterTLSKeyPath:  filepath.Join(directory, "node.key"),
+	}
+
+	for path, data := range map[string][]byte{
+		c.ClusterTLSCAPath:   ca.pem,
+		c.ClusterTLSCertPath: certificate,
+		c.ClusterTLSKeyPath:  key,
+	} {
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return c
+}
+
+func TestNewClusterTLSNotConfigured(t *testing.T) {
+	clusterTLS, err := cluster.NewClusterTLS(&config.Config{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if clusterTLS != nil {
+		t.Error("Expected mTLS to be disabled when it is not configured")
+	}
+}
+
+func TestNewClusterTLSIncomplete(t *testing.T) {
+	_, err := cluster.NewClusterTLS(&config.Config{
+		ClusterTLSCertPath: "node.pem",
+	})
+
+	if !errors.Is(err, cluster.ErrClusterTLSIncomplete) {
+		t.Errorf("Expected ErrClusterTLSIncomplete, got %v", err)
+	}
+}
+
+func TestClusterTLSFromConfig(t *testing.T) {
+	c := writeClusterTLSFiles(t, t.TempDir(), newTestCertificateAuthority(t), 2)
+
+	clusterTLS, err := cluster.ClusterTLSFromConfig(c)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	shared, err := cluster.ClusterTLSFromConfig(c)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if clusterTLS != shared {
+		t.Error("Expected the same configuration to share the cluster certificates")
+	}
+}
+
+func TestClusterTLSMutualAuthentication(t *testing.T) {
+	ca := newTestCertificateAuthority(t)
+	clusterTLS, err := cluster.NewClusterTLS(writeClusterTLSFiles(t, t.TempDir(), ca, 2))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	server.TLS = clusterTLS.ServerConfig(nil, nil)
+	server.StartTLS()
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: clusterTLS.ClientConfig()},
+	}
+
+	response, err := client.Get(server.URL)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected the client certificate to be verified, got status %d", response.StatusCode)
+	}
+
+	// A client that does not present a certificate is not authenticated
+	anonymousClient := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+		}},
+	}
+
+	response, err = anonymousClient.Get(server.URL)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without a client certificate, got %d", response.StatusCode)
+	}
+}
+
+func TestClusterTLSRejectsUnknownCA(t *testing.T) {
+	clusterTLS, err := cluster.NewClusterTLS(writeClusterTLSFiles(t, t.TempDir(), newTestCertificateAuthority(t), 2))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	otherTLS, err := cluster.NewClusterTLS(writeClusterTLSFiles(t, t.TempDir(), newTestCertificateAuthority(t), 3))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = otherTLS.ServerConfig(nil, nil)
+	server.StartTLS()
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: clusterTLS.ClientConfig()},
+	}
+
+	_, err = client.Get(server.URL)
+
+	if err == nil {
+		t.Error("Expected the server certificate of another CA to be rejected")
+	}
+}
+
+func TestClusterTLSReload(t *testing.T) {
+	directory := t.TempDir()
+	firstCA := newTestCertificateAuthority(t)
+	clusterTLS, err := cluster.NewClusterTLS(writeClusterTLSFiles(t, directory, firstCA, 2))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Rotate the CA and node certificate on disk
+	secondCA := newTestCertificateAuthority(t)
+	writeClusterTLSFiles(t, directory, secondCA, 3)
+
+	err = clusterTLS.Reload()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	certificate, err := clusterTLS.ClientConfig().GetClientCertificate(&tls.CertificateRequestInfo{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if leaf.SerialNumber.Int64() != 3 {
+		t.Errorf("Expected the rotated certificate to be used, got serial %d", leaf.SerialNumber.Int64())
+	}
+}
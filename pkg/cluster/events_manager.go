@@ -105,6 +105,19 @@ func (em *EventsManager) Init() {
 		}
 	})
 
+	em.cluster.Subscribe("cluster:primary", func(message *EventMessage) {
+		data, ok := message.Value.(map[string]any)
+
+		if !ok {
+			slog.Error("Cluster primary event missing data")
+			return
+		}
+
+		if address, ok := data["address"].(string); ok && address != "" {
+			em.cluster.Node().primaryChanged(address)
+		}
+	})
+
 	em.cluster.Subscribe("database:delete", func(message *EventMessage) {
 		// TODO: Implement database delete
 	})
@@ -125,6 +138,18 @@ func (em *EventsManager) Init() {
 		NextKeyHandler(em.cluster.Config, message.Value)
 	})
 
+	// Access keys and users resolve their roles each time they are authorized,
+	// so reloading the role is enough to apply the change to cached keys.
+	em.cluster.Subscribe("role:purge", func(message *EventMessage) {
+		if name, ok := message.Value.(string); ok {
+			err := em.cluster.Auth.RoleManager().Purge(name)
+
+			if err != nil {
+				slog.Error("Failed to purge role", "error", err)
+			}
+		}
+	})
+
 	em.cluster.Subscribe("user:purge", func(message *EventMessage) {
 		if username, ok := message.Value.(string); ok {
 			err := em.cluster.Auth.UserManager().Purge(username)
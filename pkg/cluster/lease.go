@@ -11,7 +11,8 @@ import (
 )
 
 var (
-	ErrLeaseExpired = errors.New("lease expired")
+	ErrLeaseExpired     = errors.New("lease expired")
+	ErrLeaseTermChanged = errors.New("the lease term changed during the handoff")
 )
 
 type Lease struct {
@@ -227,6 +228,200 @@ func (l *Lease) Renew() error {
 	return nil
 }
 
+// Claim the primary role after the lease has been transferred to the node.
+// The claim only succeeds if the node is still the primary in the primary file
+// and the term has not changed since the transfer, which fences out a previous
+// primary that restored its role after the handoff failed on its side.
+func (l *Lease) Claim(term int64) error {
+	return l.withPrimaryLock(func() error {
+		primaryAddress, err := l.node.Cluster.NetworkFS().ReadFile(l.node.Cluster.PrimaryPath())
+
+		if err != nil {
+			return err
+		}
+
+		if string(primaryAddress) != l.node.address {
+			return fmt.Errorf("primary address verification failed")
+		}
+
+		currentTerm, err := l.readTerm()
+
+		if err != nil {
+			return err
+		}
+
+		if currentTerm != term {
+			return ErrLeaseTermChanged
+		}
+
+		return l.writeTerm(term + 1)
+	})
+}
+
+// Read the current lease term. A cluster without a lease term file is in its
+// first term.
+func (l *Lease) readTerm() (int64, error) {
+	data, err := l.node.Cluster.NetworkFS().ReadFile(l.node.Cluster.LeaseTermPath())
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	return strconv.ParseInt(string(data), 10, 64)
+}
+
+// Restore the primary role of the node after a handoff to the node with the
+// given address failed. The role is only restored if the target has not
+// claimed the transferred lease, otherwise ErrLeaseTermChanged is returned and
+// the node must step down.
+func (l *Lease) Restore(address string, term int64) error {
+	err := l.withPrimaryLock(func() error {
+		primaryAddress, err := l.node.Cluster.NetworkFS().ReadFile(l.node.Cluster.PrimaryPath())
+
+		if err != nil {
+			return err
+		}
+
+		currentTerm, err := l.readTerm()
+
+		if err != nil {
+			return err
+		}
+
+		if string(primaryAddress) != address || currentTerm != term {
+			return ErrLeaseTermChanged
+		}
+
+		err = l.node.Cluster.NetworkFS().WriteFile(l.node.Cluster.PrimaryPath(), []byte(l.node.address), 0600)
+
+		if err != nil {
+			return err
+		}
+
+		return l.writeTerm(term + 1)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return l.Renew()
+}
+
+// Transfer the lease to the node with the given address. The primary file is
+// rewritten with the address of the new primary and the lease file is renewed
+// on its behalf so that other nodes do not start an election while the new
+// primary takes over. The term is incremented so the transfer can be fenced,
+// and the expiration of the transferred lease and the new term are returned.
+func (l *Lease) Transfer(address string) (int64, int64, error) {
+	if l.node.Membership != ClusterMembershipPrimary {
+		return 0, 0, fmt.Errorf("node is not a leader")
+	}
+
+	var expiresAt, term int64
+
+	err := l.withPrimaryLock(func() error {
+		primaryAddress, err := l.node.Cluster.NetworkFS().ReadFile(l.node.Cluster.PrimaryPath())
+
+		if err != nil {
+			return err
+		}
+
+		if string(primaryAddress) != l.node.address {
+			return fmt.Errorf("primary address verification failed")
+		}
+
+		term, err = l.readTerm()
+
+		if err != nil {
+			return err
+		}
+
+		term++
+
+		err = l.writeTerm(term)
+
+		if err != nil {
+			return err
+		}
+
+		expiresAt = time.Now().UTC().Add(LeaseDuration).Unix()
+
+		err = l.node.Cluster.NetworkFS().WriteFile(
+			l.node.Cluster.LeasePath(),
+			[]byte(strconv.FormatInt(expiresAt, 10)),
+			os.ModePerm,
+		)
+
+		if err != nil {
+			return err
+		}
+
+		return l.node.Cluster.NetworkFS().WriteFile(l.node.Cluster.PrimaryPath(), []byte(address), 0600)
+	})
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	l.ExpiresAt = 0
+
+	return expiresAt, term, nil
+}
+
+// Run the function while holding an exclusive lock on the primary file, so
+// changes to the primary role during a handoff are serialized across nodes.
+func (l *Lease) withPrimaryLock(f func() error) error {
+	primaryFile, err := l.node.Cluster.NetworkFS().OpenFile(l.node.Cluster.PrimaryPath(), os.O_RDWR, 0600)
+
+	if err != nil {
+		return err
+	}
+
+	file := primaryFile.(*os.File)
+
+	defer func() {
+		err := primaryFile.Close()
+
+		if err != nil {
+			slog.Debug("Failed to close primary file", "error", err)
+		}
+	}()
+
+	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+
+		if err != nil {
+			slog.Debug("Failed to unlock primary file", "error", err)
+		}
+	}()
+
+	return f()
+}
+
+// Write the lease term.
+func (l *Lease) writeTerm(term int64) error {
+	return l.node.Cluster.NetworkFS().WriteFile(
+		l.node.Cluster.LeaseTermPath(),
+		[]byte(strconv.FormatInt(term, 10)),
+		0600,
+	)
+}
+
 // Determine if the lease should be renewed based on the remaining time.
 func (l *Lease) ShouldRenew() bool {
 	if l.IsExpired() {
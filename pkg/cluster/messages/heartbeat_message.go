@@ -4,6 +4,10 @@ type HeartbeatMessage struct {
 	Address string
 	ID      []byte
 	Time    int64
+
+	// The latest WAL timestamps written on the primary, keyed by database
+	// branch.
+	WALTimestamps map[string]int64
 }
 
 type HeartbeatResponseMessage struct {
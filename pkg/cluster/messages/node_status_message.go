@@ -0,0 +1,16 @@
+package messages
+
+type NodeStatusMessage struct {
+	Address string
+}
+
+type NodeStatusResponseMessage struct {
+	Connections int
+	Databases   []NodeDatabaseStatus
+}
+
+type NodeDatabaseStatus struct {
+	BranchID     string
+	DatabaseID   string
+	WALTimestamp int64
+}
@@ -0,0 +1,12 @@
+package messages
+
+type PrimaryHandoffMessage struct {
+	Address        string
+	LeaseExpiresAt int64
+	LeaseTerm      int64
+	WALTimestamps  map[string]int64
+}
+
+type PrimaryHandoffResponseMessage struct {
+	Address string
+}
@@ -1,14 +1,27 @@
 package messages
 
-import "github.com/litebase/litebase/pkg/sqlite3"
+import (
+	"net/netip"
+	"time"
 
+	"github.com/litebase/litebase/pkg/sqlite3"
+)
+
+// A query forwarded by a replica to the primary. The authorization context of
+// the request the query was received with on the replica is carried along, so
+// the conditions of statements are evaluated against the original request.
 type QueryMessage struct {
 	AccessKeyID  string
+	AccessToken  string
 	BranchID     string
 	BranchName   string
 	DatabaseID   string
 	DatabaseName string
 	ID           string
 	Parameters   []sqlite3.StatementParameter
+	Replica      bool
+	SourceIP     netip.Addr
 	Statement    string
+	TLS          bool
+	Time         time.Time
 }
@@ -18,6 +18,7 @@ import (
 
 	"github.com/litebase/litebase/internal/utils"
 	"github.com/litebase/litebase/pkg/cluster/messages"
+	"github.com/litebase/litebase/pkg/config"
 	"github.com/litebase/litebase/pkg/storage"
 )
 
@@ -29,6 +30,7 @@ const (
 )
 
 var (
+	NodeMemberTimeout        = 3 * NodeStoreAddressInterval
 	NodeStoreAddressInterval = 5 * time.Second
 	NodeTickTimeout          = 3 * time.Second
 )
@@ -43,6 +45,7 @@ type Node struct {
 	electionMoratorium time.Time
 	Election           *ClusterElection
 	Elections          []*ClusterElection
+	handoffMutex       *sync.Mutex
 	Initialized        bool
 	joinedClusterAt    time.Time
 	lastTick           time.Time
@@ -51,30 +54,38 @@ type Node struct {
 	ID                 string
 	Membership         string
 	mutex              *sync.Mutex
+	pendingWrites      *sync.WaitGroup
 	primaryAddress     string
 	primary            *NodePrimary
 	PrimaryHeartbeat   time.Time
 	queryBuilder       NodeQueryBuilder
 	queryResponsePool  NodeQueryResponsePool
 	replica            *NodeReplica
+	replicationLag     *ReplicationLagTracker
 	requestTicker      *time.Ticker
 	started            chan bool
 	State              string
 	startedAt          time.Time
+	statusReporter     NodeStatusReporter
 	storedAddressAt    time.Time
 	walSynchronizer    NodeWalSynchronizer
+	writeDrainer       NodeWriteDrainer
+	writesPaused       chan struct{}
 }
 
 // Create a new instance of a node.
 func NewNode(cluster *Cluster) *Node {
 	node := &Node{
-		address:    "",
-		Cluster:    cluster,
-		LastActive: time.Time{},
-		Membership: ClusterMembershipReplica,
-		mutex:      &sync.Mutex{},
-		started:    make(chan bool, 1),
-		State:      NodeStateActive,
+		address:        "",
+		Cluster:        cluster,
+		handoffMutex:   &sync.Mutex{},
+		LastActive:     time.Time{},
+		Membership:     ClusterMembershipReplica,
+		mutex:          &sync.Mutex{},
+		pendingWrites:  &sync.WaitGroup{},
+		replicationLag: NewReplicationLagTracker(),
+		started:        make(chan bool, 1),
+		State:          NodeStateActive,
 	}
 
 	address, err := node.Address()
@@ -106,7 +117,7 @@ func (n *Node) Address() (string, error) {
 		addressProviderFunc := nodeAddressProviders[NodeAddressProviderKey(n.Cluster.Config.NodeAddressProvider)]
 
 		if addressProviderFunc != nil {
-			address, err = addressProviderFunc()
+			address, err = addressProviderFunc(n.Cluster.Config)
 
 			if err != nil {
 				slog.Debug("Failed to get address from provider", "error", err)
@@ -191,6 +202,11 @@ func (n *Node) heartbeat() {
 	if n.Membership == ClusterMembershipPrimary {
 		n.mutex.Unlock()
 
+		// The lease is managed by the handoff until it completes
+		if n.handingOff() {
+			return
+		}
+
 		if n.Lease() == nil {
 			slog.Error("No lease found for primary node, cannot send heartbeat")
 
@@ -253,6 +269,12 @@ func (n *Node) heartbeat() {
 		break
 	}
 
+	// Remote replicas do not share storage with the primary, so they can
+	// never be elected.
+	if n.IsRemoteReplica() {
+		return
+	}
+
 	if !n.primaryLeaseVerification() {
 		success, err := n.runElection()
 
@@ -338,6 +360,12 @@ func (n *Node) IsReplica() bool {
 	return n.Membership == ClusterMembershipReplica && n.replica != nil
 }
 
+// Check if the node is a remote replica. Remote replicas do not share the
+// network storage of the cluster and receive changes from object storage.
+func (n *Node) IsRemoteReplica() bool {
+	return n.Cluster.Config.ReplicaMode == config.ReplicaModeRemote
+}
+
 func (n *Node) JoinCluster() error {
 	if !n.joinedClusterAt.IsZero() {
 		return nil
@@ -558,6 +586,23 @@ func (n *Node) Replica() *NodeReplica {
 	return n.replica
 }
 
+// Return the replication lag of the node for a database branch. The lag is
+// only tracked on replicas and is always zero on the primary.
+func (n *Node) ReplicationLag(databaseID, branchID string) time.Duration {
+	if n.IsPrimary() {
+		return 0
+	}
+
+	address, _ := n.Address()
+
+	return n.replicationLag.Lag(address, databaseID, branchID)
+}
+
+// Return the tracker used to measure the replication lag of the replicas.
+func (n *Node) ReplicationLagTracker() *ReplicationLagTracker {
+	return n.replicationLag
+}
+
 // Return the query builder of the node.
 func (n *Node) QueryBuilder() NodeQueryBuilder {
 	return n.queryBuilder
@@ -669,7 +714,7 @@ func (n *Node) SendEvent(node *NodeIdentifier, message NodeEvent) error {
 		return nil
 	}
 
-	url := fmt.Sprintf("http://%s/v1/events", node.Address)
+	url := fmt.Sprintf("%s://%s/v1/events", n.Cluster.InternalScheme(), node.Address)
 
 	data, err := json.Marshal(message)
 
@@ -696,7 +741,8 @@ func (n *Node) SendEvent(node *NodeIdentifier, message NodeEvent) error {
 	}
 
 	client := &http.Client{
-		Timeout: 1 * time.Second,
+		Timeout:   1 * time.Second,
+		Transport: n.Cluster.InternalTransport(),
 	}
 
 	res, err := client.Do(req)
@@ -723,6 +769,14 @@ func SetAddressProvider(provider func() string) {
 }
 
 func (n *Node) setInternalHeaders(req *http.Request) error {
+	req.Header.Set("Content-Type", "application/json")
+
+	return n.SetNodeHeaders(req)
+}
+
+// Set the headers that identify this node to another node of the cluster on
+// a request.
+func (n *Node) SetNodeHeaders(req *http.Request) error {
 	address, _ := n.Address()
 
 	encryptedHeader, err := n.Cluster.Auth.SecretsManager.Encrypt(
@@ -734,7 +788,6 @@ func (n *Node) setInternalHeaders(req *http.Request) error {
 		return err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Lbdb-Node", string(encryptedHeader))
 	req.Header.Set("X-Lbdb-Node-Timestamp", fmt.Sprintf("%d", time.Now().UTC().UnixNano()))
 
@@ -3,23 +3,32 @@ package cluster
 import (
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
 	"os"
+
+	"github.com/litebase/litebase/pkg/config"
 )
 
 type NodeAddressProviderKey string
-type NodeAddressProvider func() (string, error)
+type NodeAddressProvider func(c *config.Config) (string, error)
 
 const (
-	NodeAddressProviderKeyAWSECS NodeAddressProviderKey = "aws_ecs"
+	NodeAddressProviderKeyAWSECS     NodeAddressProviderKey = "aws_ecs"
+	NodeAddressProviderKeyInterface  NodeAddressProviderKey = "interface"
+	NodeAddressProviderKeyKubernetes NodeAddressProviderKey = "kubernetes"
+	NodeAddressProviderKeyStatic     NodeAddressProviderKey = "static"
 )
 
 var nodeAddressProviders = map[NodeAddressProviderKey]NodeAddressProvider{
-	NodeAddressProviderKeyAWSECS: AWSECSAddressProvider,
+	NodeAddressProviderKeyAWSECS:     AWSECSAddressProvider,
+	NodeAddressProviderKeyInterface:  InterfaceAddressProvider,
+	NodeAddressProviderKeyKubernetes: KubernetesAddressProvider,
+	NodeAddressProviderKeyStatic:     StaticAddressProvider,
 }
 
 // Get private ip address of the node from ECS_CONTAINER_METADATA_URI_V4
-func AWSECSAddressProvider() (string, error) {
+func AWSECSAddressProvider(c *config.Config) (string, error) {
 	metaDataUri := os.Getenv("ECS_CONTAINER_METADATA_URI_V4")
 
 	if metaDataUri == "" {
@@ -90,3 +99,85 @@ func AWSECSAddressProvider() (string, error) {
 
 	return ipv4Address, nil
 }
+
+// Get the ip address of the first network interface that is up and is not a
+// loopback interface.
+func InterfaceAddressProvider(c *config.Config) (string, error) {
+	interfaces, err := net.Interfaces()
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, networkInterface := range interfaces {
+		if networkInterface.Flags&net.FlagUp == 0 || networkInterface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		addresses, err := networkInterface.Addrs()
+
+		if err != nil {
+			continue
+		}
+
+		for _, address := range addresses {
+			ipNet, ok := address.(*net.IPNet)
+
+			if !ok || ipNet.IP.IsLoopback() || ipNet.IP.To4() == nil {
+				continue
+			}
+
+			return ipNet.IP.String(), nil
+		}
+	}
+
+	return "", errors.New("failed to find a non-loopback network interface")
+}
+
+// Get the ip address of the pod from the POD_IP environment variable, which is
+// set using the Kubernetes downward API. When the variable is not set, the pod
+// hostname is resolved instead, which Kubernetes maps to the pod ip address.
+func KubernetesAddressProvider(c *config.Config) (string, error) {
+	if podIP := os.Getenv("POD_IP"); podIP != "" {
+		if net.ParseIP(podIP) == nil {
+			return "", errors.New("POD_IP is not a valid ip address")
+		}
+
+		return podIP, nil
+	}
+
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return "", errors.New("POD_IP not set and not running in Kubernetes")
+	}
+
+	hostname, err := os.Hostname()
+
+	if err != nil {
+		return "", err
+	}
+
+	addresses, err := net.LookupHost(hostname)
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, address := range addresses {
+		ip := net.ParseIP(address)
+
+		if ip != nil && !ip.IsLoopback() && ip.To4() != nil {
+			return address, nil
+		}
+	}
+
+	return "", errors.New("failed to resolve the pod ip address")
+}
+
+// Get the ip address of the node from the LITEBASE_NODE_ADDRESS configuration.
+func StaticAddressProvider(c *config.Config) (string, error) {
+	if c.NodeAddress == "" {
+		return "", errors.New("LITEBASE_NODE_ADDRESS not set")
+	}
+
+	return c.NodeAddress, nil
+}
@@ -133,7 +133,7 @@ func (nc *NodeConnection) createAndSendRequest() (*http.Response, error) {
 	request, err := http.NewRequestWithContext(
 		nc.context,
 		"POST",
-		fmt.Sprintf("http://%s/v1/cluster/connection", nc.Address),
+		fmt.Sprintf("%s://%s/v1/cluster/connection", nc.node.Cluster.InternalScheme(), nc.Address),
 		nc.reader,
 	)
 
@@ -178,6 +178,7 @@ func (nc *NodeConnection) createHTTPClient() {
 			DialContext: (&net.Dialer{
 				Timeout: 1 * time.Second, // Timeout for establishing a connection
 			}).DialContext,
+			TLSClientConfig: nc.node.Cluster.TLSClientConfig(),
 		},
 	}
 }
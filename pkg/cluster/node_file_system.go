@@ -159,6 +159,15 @@ func (cluster *Cluster) TieredFS() *storage.FileSystem {
 		}
 	}
 
+	quotaFn := func(ctx context.Context, fsd *storage.TieredFileSystemDriver) {
+		fsd.EvictionPolicy = cluster.Config.TieredStoragePolicy
+		fsd.MaxBytes = cluster.Config.TieredStorageMaxBytes
+
+		for _, databaseId := range cluster.Config.TieredStoragePinned {
+			fsd.PinDatabase(databaseId)
+		}
+	}
+
 	if cluster.tieredFileSystem == nil {
 		cluster.fileSystemMutex.Lock()
 		defer cluster.fileSystemMutex.Unlock()
@@ -173,6 +182,7 @@ func (cluster *Cluster) TieredFS() *storage.FileSystem {
 				cluster.NetworkFS(),
 				cluster.ObjectFS(),
 				fileSyncEligibilityFn,
+				quotaFn,
 			),
 		)
 	}
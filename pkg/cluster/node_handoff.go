@@ -0,0 +1,349 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/litebase/litebase/pkg/cluster/messages"
+	"github.com/litebase/litebase/pkg/storage"
+)
+
+var (
+	NodeHandoffCatchUpTimeout = 10 * time.Second
+	NodeHandoffTimeout        = 30 * time.Second
+)
+
+var (
+	ErrHandoffCatchUpTimeout = errors.New("the handoff target did not catch up with the primary in time")
+	ErrHandoffInProgress     = errors.New("a primary handoff is already in progress")
+	ErrHandoffNotPrimary     = errors.New("the node is not the primary")
+	ErrHandoffTargetIsSelf   = errors.New("the handoff target is already the primary")
+	ErrHandoffTargetNotFound = errors.New("the handoff target is not a member of the cluster")
+	ErrWritesPaused          = errors.New("writes are paused for a primary handoff")
+)
+
+// A NodeWriteDrainer waits for the writes that have been accepted by the node
+// to complete and flushes them to storage.
+type NodeWriteDrainer func(ctx context.Context) error
+
+// Transfer the primary role to the replica with the given address. Writes are
+// paused on the node while the pending writes are drained and flushed to
+// storage. The lease is then handed over to the target, which confirms once
+// it has caught up with the WAL timestamps of the primary and has taken over
+// the primary role. Writes that were paused resume once the handoff completes
+// and are forwarded to the new primary.
+func (n *Node) Handoff(ctx context.Context, address string) error {
+	if !n.IsPrimary() || n.Lease() == nil {
+		return ErrHandoffNotPrimary
+	}
+
+	if self, _ := n.Address(); self == address {
+		return ErrHandoffTargetIsSelf
+	}
+
+	n.Cluster.GetMembers(false)
+
+	found := false
+
+	for _, node := range n.Cluster.OtherNodes() {
+		if node.Address == address {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return ErrHandoffTargetNotFound
+	}
+
+	if !n.pauseWrites() {
+		return ErrHandoffInProgress
+	}
+
+	defer n.resumeWrites()
+
+	err := n.waitForPendingWrites(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to wait for pending writes: %w", err)
+	}
+
+	if n.writeDrainer != nil {
+		err := n.writeDrainer(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to drain writes: %w", err)
+		}
+	}
+
+	if driver, ok := n.Cluster.TieredFS().Driver().(*storage.TieredFileSystemDriver); ok {
+		err := driver.SyncDirtyFiles()
+
+		if err != nil {
+			return fmt.Errorf("failed to sync dirty files: %w", err)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	expiresAt, term, err := n.Lease().Transfer(address)
+
+	if err != nil {
+		return fmt.Errorf("failed to transfer lease: %w", err)
+	}
+
+	response, err := n.Primary().Send(address, messages.NodeMessage{
+		Data: messages.PrimaryHandoffMessage{
+			Address:        address,
+			LeaseExpiresAt: expiresAt,
+			LeaseTerm:      term,
+			WALTimestamps:  n.replicationLag.PrimaryTimestamps(),
+		},
+	})
+
+	if err == nil {
+		if _, ok := response.Data.(messages.PrimaryHandoffResponseMessage); !ok {
+			err = errors.New("unexpected handoff response")
+		}
+	}
+
+	if err != nil {
+		// The target may have claimed the lease even though its response was
+		// lost, in which case the node steps down instead of restoring its
+		// primary role.
+		if restoreErr := n.Lease().Restore(address, term); restoreErr != nil {
+			slog.Error("Failed to restore primary after handoff failure", "error", restoreErr)
+
+			primaryAddress, readErr := n.Cluster.NetworkFS().ReadFile(n.Cluster.PrimaryPath())
+
+			if readErr != nil {
+				slog.Debug("Failed to read primary file", "error", readErr)
+			}
+
+			n.stepDown(string(primaryAddress))
+		}
+
+		return fmt.Errorf("target failed to take over the primary role: %w", err)
+	}
+
+	n.stepDown(address)
+
+	return nil
+}
+
+// Mark a write as pending on the node. The write is refused if a primary
+// handoff is in progress, otherwise the handoff waits for it to complete
+// before the lease is transferred. The returned function must be called once
+// the write completes.
+func (n *Node) BeginWrite() (func(), error) {
+	n.handoffMutex.Lock()
+	defer n.handoffMutex.Unlock()
+
+	if n.writesPaused != nil {
+		return nil, ErrWritesPaused
+	}
+
+	n.pendingWrites.Add(1)
+
+	return sync.OnceFunc(n.pendingWrites.Done), nil
+}
+
+// Wait until the node has caught up with the WAL timestamps of the primary.
+func (n *Node) catchUpWithPrimary(timestamps map[string]int64) error {
+	address, _ := n.Address()
+
+	n.replicationLag.SyncPrimary(address, timestamps)
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	timeout := time.After(NodeHandoffCatchUpTimeout)
+
+	for n.replicationLag.ReplicaLag(address) > 0 {
+		select {
+		case <-timeout:
+			return ErrHandoffCatchUpTimeout
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+// Check if a primary handoff is in progress on the node.
+func (n *Node) handingOff() bool {
+	n.handoffMutex.Lock()
+	defer n.handoffMutex.Unlock()
+
+	return n.writesPaused != nil
+}
+
+// Take over the primary role after the lease has been transferred to the node.
+// The node first catches up with the WAL timestamps of the previous primary
+// and then claims the lease, which fails if the previous primary has restored
+// its role in the meantime.
+func (n *Node) handlePrimaryHandoffMessage(message messages.PrimaryHandoffMessage) any {
+	address, _ := n.Address()
+
+	if message.Address != address {
+		return messages.ErrorMessage{
+			Message: "handoff target does not match the node address",
+		}
+	}
+
+	err := n.catchUpWithPrimary(message.WALTimestamps)
+
+	if err != nil {
+		return messages.ErrorMessage{Message: err.Error()}
+	}
+
+	n.mutex.Lock()
+
+	lease := NewLease(n)
+
+	err = lease.Claim(message.LeaseTerm)
+
+	if err != nil {
+		n.mutex.Unlock()
+
+		return messages.ErrorMessage{Message: err.Error()}
+	}
+
+	n.primaryAddress = address
+	n.SetMembership(ClusterMembershipPrimary)
+	n.lease = lease
+	n.lease.ExpiresAt = message.LeaseExpiresAt
+
+	err = n.lease.Renew()
+
+	if err != nil {
+		n.lease = nil
+		n.primary = nil
+		n.Membership = ClusterMembershipReplica
+		n.mutex.Unlock()
+
+		return messages.ErrorMessage{Message: err.Error()}
+	}
+
+	n.mutex.Unlock()
+
+	go func() {
+		err := n.Cluster.Broadcast("cluster:primary", map[string]string{
+			"address": address,
+		})
+
+		if err != nil {
+			slog.Debug("Failed to broadcast primary change", "error", err)
+		}
+	}()
+
+	return messages.PrimaryHandoffResponseMessage{
+		Address: address,
+	}
+}
+
+// Pause writes on the node. False is returned if writes are already paused.
+func (n *Node) pauseWrites() bool {
+	n.handoffMutex.Lock()
+	defer n.handoffMutex.Unlock()
+
+	if n.writesPaused != nil {
+		return false
+	}
+
+	n.writesPaused = make(chan struct{})
+
+	return true
+}
+
+// Update the primary address of the node after the primary has changed.
+func (n *Node) primaryChanged(address string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if self, _ := n.Address(); self == address {
+		return
+	}
+
+	n.primaryAddress = address
+	n.PrimaryHeartbeat = time.Now().UTC()
+
+	if n.Membership == ClusterMembershipReplica && n.replica == nil {
+		n.replica = NewNodeReplica(n)
+	}
+}
+
+// Resume writes on the node.
+func (n *Node) resumeWrites() {
+	n.handoffMutex.Lock()
+	defer n.handoffMutex.Unlock()
+
+	if n.writesPaused != nil {
+		close(n.writesPaused)
+		n.writesPaused = nil
+	}
+}
+
+// Step down to a replica of the primary with the given address after the
+// primary role has been handed off.
+func (n *Node) stepDown(address string) {
+	n.Primary().Shutdown()
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.lease = nil
+	n.primary = nil
+	n.Membership = ClusterMembershipReplica
+	n.primaryAddress = address
+	n.PrimaryHeartbeat = time.Now().UTC()
+	n.replica = NewNodeReplica(n)
+	n.electionMoratorium = time.Now().UTC().Add(LeaseDuration)
+}
+
+// Set the function used to drain writes before a primary handoff.
+func (n *Node) SetWriteDrainer(writeDrainer NodeWriteDrainer) {
+	n.writeDrainer = writeDrainer
+}
+
+// Wait for the writes that were marked as pending before writes were paused.
+func (n *Node) waitForPendingWrites(ctx context.Context) error {
+	done := make(chan struct{})
+
+	go func() {
+		n.pendingWrites.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Wait until the node accepts writes. Writes are paused while a primary
+// handoff is in progress.
+func (n *Node) WaitForWrites(ctx context.Context) error {
+	n.handoffMutex.Lock()
+	writesPaused := n.writesPaused
+	n.handoffMutex.Unlock()
+
+	if writesPaused == nil {
+		return nil
+	}
+
+	select {
+	case <-writesPaused:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,220 @@
+package cluster_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/cluster"
+)
+
+func TestNodeHandoff(t *testing.T) {
+	test.Run(t, func() {
+		testServer1 := test.NewTestServer(t)
+		defer testServer1.Shutdown()
+		testServer2 := test.NewTestServer(t)
+		defer testServer2.Shutdown()
+
+		if !testServer1.App.Cluster.Node().IsPrimary() {
+			t.Fatalf("Node should be primary")
+		}
+
+		address, err := testServer2.App.Cluster.Node().Address()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = testServer1.App.Cluster.Node().Handoff(context.Background(), address)
+
+		if err != nil {
+			t.Fatalf("Handoff should not return an error, got %v", err)
+		}
+
+		if !testServer2.App.Cluster.Node().IsPrimary() {
+			t.Error("Target node should be primary after the handoff")
+		}
+
+		if testServer1.App.Cluster.Node().IsPrimary() {
+			t.Error("Previous primary should not be primary after the handoff")
+		}
+
+		if testServer1.App.Cluster.Node().PrimaryAddress() != address {
+			t.Errorf("Expected primary address %s, got %s", address, testServer1.App.Cluster.Node().PrimaryAddress())
+		}
+	})
+}
+
+func TestNodeHandoffFromReplica(t *testing.T) {
+	test.Run(t, func() {
+		testServer1 := test.NewTestServer(t)
+		defer testServer1.Shutdown()
+		testServer2 := test.NewTestServer(t)
+		defer testServer2.Shutdown()
+
+		address, _ := testServer1.App.Cluster.Node().Address()
+
+		err := testServer2.App.Cluster.Node().Handoff(context.Background(), address)
+
+		if !errors.Is(err, cluster.ErrHandoffNotPrimary) {
+			t.Errorf("Expected ErrHandoffNotPrimary, got %v", err)
+		}
+	})
+}
+
+func TestNodeHandoffToUnknownNode(t *testing.T) {
+	test.Run(t, func() {
+		testServer1 := test.NewTestServer(t)
+		defer testServer1.Shutdown()
+
+		err := testServer1.App.Cluster.Node().Handoff(context.Background(), "10.0.0.0:9876")
+
+		if !errors.Is(err, cluster.ErrHandoffTargetNotFound) {
+			t.Errorf("Expected ErrHandoffTargetNotFound, got %v", err)
+		}
+
+		if !testServer1.App.Cluster.Node().IsPrimary() {
+			t.Error("Node should remain primary after a failed handoff")
+		}
+	})
+}
+
+func TestNodeWaitForWrites(t *testing.T) {
+	test.Run(t, func() {
+		testServer := test.NewTestServer(t)
+		defer testServer.Shutdown()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		err := testServer.App.Cluster.Node().WaitForWrites(ctx)
+
+		if err != nil {
+			t.Errorf("Writes should not be paused, got %v", err)
+		}
+	})
+}
+
+func TestNodeHandoffWaitsForPendingWrites(t *testing.T) {
+	test.Run(t, func() {
+		testServer1 := test.NewTestServer(t)
+		defer testServer1.Shutdown()
+		testServer2 := test.NewTestServer(t)
+		defer testServer2.Shutdown()
+
+		address, _ := testServer2.App.Cluster.Node().Address()
+
+		done, err := testServer1.App.Cluster.Node().BeginWrite()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		handoffErr := make(chan error, 1)
+
+		go func() {
+			handoffErr <- testServer1.App.Cluster.Node().Handoff(context.Background(), address)
+		}()
+
+		timeout := time.After(5 * time.Second)
+
+		for {
+			_, err = testServer1.App.Cluster.Node().BeginWrite()
+
+			if errors.Is(err, cluster.ErrWritesPaused) {
+				break
+			}
+
+			select {
+			case <-timeout:
+				t.Fatal("Expected new writes to be refused during the handoff")
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+
+		select {
+		case err := <-handoffErr:
+			t.Fatalf("Handoff should wait for the pending write, got %v", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		done()
+
+		if err := <-handoffErr; err != nil {
+			t.Fatalf("Handoff should not return an error, got %v", err)
+		}
+
+		if !testServer2.App.Cluster.Node().IsPrimary() {
+			t.Error("Target node should be primary after the handoff")
+		}
+	})
+}
+
+func TestNodeHandoffTargetMustCatchUp(t *testing.T) {
+	test.Run(t, func() {
+		testServer1 := test.NewTestServer(t)
+		defer testServer1.Shutdown()
+		testServer2 := test.NewTestServer(t)
+		defer testServer2.Shutdown()
+
+		defaultTimeout := cluster.NodeHandoffCatchUpTimeout
+		cluster.NodeHandoffCatchUpTimeout = 100 * time.Millisecond
+		defer func() { cluster.NodeHandoffCatchUpTimeout = defaultTimeout }()
+
+		address, _ := testServer2.App.Cluster.Node().Address()
+
+		testServer1.App.Cluster.Node().ReplicationLagTracker().Primary("database", "branch", 200)
+		testServer2.App.Cluster.Node().ReplicationLagTracker().Replica(address, "database", "branch", 100)
+
+		err := testServer1.App.Cluster.Node().Handoff(context.Background(), address)
+
+		if err == nil {
+			t.Fatal("Expected the handoff to fail while the target is behind")
+		}
+
+		if !testServer1.App.Cluster.Node().IsPrimary() {
+			t.Error("Node should remain primary after a failed handoff")
+		}
+
+		if testServer2.App.Cluster.Node().IsPrimary() {
+			t.Error("Target node should not be primary after a failed handoff")
+		}
+	})
+}
+
+func TestLeaseRestoreIsFencedByClaim(t *testing.T) {
+	test.Run(t, func() {
+		testServer1 := test.NewTestServer(t)
+		defer testServer1.Shutdown()
+		testServer2 := test.NewTestServer(t)
+		defer testServer2.Shutdown()
+
+		address, _ := testServer2.App.Cluster.Node().Address()
+
+		_, term, err := testServer1.App.Cluster.Node().Lease().Transfer(address)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = cluster.NewLease(testServer2.App.Cluster.Node()).Claim(term)
+
+		if err != nil {
+			t.Fatalf("Claim should not return an error, got %v", err)
+		}
+
+		err = testServer1.App.Cluster.Node().Lease().Restore(address, term)
+
+		if !errors.Is(err, cluster.ErrLeaseTermChanged) {
+			t.Errorf("Expected ErrLeaseTermChanged, got %v", err)
+		}
+
+		err = cluster.NewLease(testServer2.App.Cluster.Node()).Claim(term)
+
+		if !errors.Is(err, cluster.ErrLeaseTermChanged) {
+			t.Errorf("Expected a stale claim to return ErrLeaseTermChanged, got %v", err)
+		}
+	})
+}
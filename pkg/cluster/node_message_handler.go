@@ -6,6 +6,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/litebase/litebase/pkg/auth"
 	"github.com/litebase/litebase/pkg/cluster/messages"
 )
 
@@ -20,6 +21,10 @@ func (n *Node) HandleMessage(message messages.NodeMessage) (messages.NodeMessage
 		responseMessage = messages.NodeConnectionMessage{
 			ID: message.ID,
 		}
+	case messages.NodeStatusMessage:
+		responseMessage = n.handleNodeStatusMessage()
+	case messages.PrimaryHandoffMessage:
+		responseMessage = n.handlePrimaryHandoffMessage(message)
 	case messages.QueryMessage:
 		responseMessage = n.handleQueryMessage(message)
 	default:
@@ -54,12 +59,12 @@ func (n *Node) handleBroadcastMessage(message interface{}) (interface{}, error)
 			message.Header,
 		)
 	case messages.WALIndexTimestampMessage:
-		log.Println("Received WAL index timestamp message")
 		// n.walSynchronizer.SetCurrentTimestamp(
 		// 	message.DatabaseID,
 		// 	message.BranchID,
 		// 	message.Timestamp,
 		// )
+		n.trackReplicatedWALTimestamp(message.DatabaseID, message.BranchID, message.Timestamp)
 	case messages.WALVersionUsageRequest:
 		responseMessage, err = n.handleWALVersionUsageRequest(message)
 	case messages.WALReplicationWriteMessage:
@@ -103,6 +108,11 @@ func (n *Node) handleHeartbeatMessage(message messages.HeartbeatMessage) any {
 			n.PrimaryHeartbeat = time.Unix(message.Time, 0).UTC()
 			responseMessage.Time = n.PrimaryHeartbeat.Unix()
 		}
+
+		if message.WALTimestamps != nil {
+			address, _ := n.Address()
+			n.replicationLag.SyncPrimary(address, message.WALTimestamps)
+		}
 	}
 
 	return responseMessage
@@ -110,8 +120,20 @@ func (n *Node) handleHeartbeatMessage(message messages.HeartbeatMessage) any {
 
 // Handle a query message from a replica node.
 func (n *Node) handleQueryMessage(message messages.QueryMessage) interface{} {
+	var authorizationContext *auth.AuthorizationContext
+
+	if !message.Time.IsZero() {
+		authorizationContext = &auth.AuthorizationContext{
+			Replica:  message.Replica,
+			SourceIP: message.SourceIP,
+			Time:     message.Time,
+			TLS:      message.TLS,
+		}
+	}
+
 	query, err := n.queryBuilder.Build(
 		message.AccessKeyID,
+		message.AccessToken,
 		message.DatabaseID,
 		message.DatabaseName,
 		message.BranchID,
@@ -119,6 +141,7 @@ func (n *Node) handleQueryMessage(message messages.QueryMessage) interface{} {
 		message.Statement,
 		message.Parameters,
 		message.ID,
+		authorizationContext,
 	)
 
 	if err != nil {
@@ -174,23 +197,34 @@ func (n *Node) handleWALReplicationWriteMessage(message messages.WALReplicationW
 		return errors.New("failed to verify WAL data integrity")
 	}
 
-	// err := n.walSynchronizer.WriteAt(
-	// 	message.DatabaseID,
-	// 	message.BranchID,
-	// 	message.Data,
-	// 	message.Offset,
-	// 	message.Sequence,
-	// 	message.Timestamp,
-	// )
+	// Write and sync the frames before acknowledging them, so a write the
+	// primary reports as replicated survives the loss of the primary.
+	err := n.walSynchronizer.WriteAt(
+		message.DatabaseID,
+		message.BranchID,
+		message.Data,
+		message.Offset,
+		message.Sequence,
+		message.Timestamp,
+	)
+
+	if err != nil {
+		log.Println("Failed to sync WAL data: ", err)
+		return err
+	}
 
-	// if err != nil {
-	// 	log.Println("Failed to sync WAL data: ", err)
-	// 	return err
-	// }
+	n.trackReplicatedWALTimestamp(message.DatabaseID, message.BranchID, message.Timestamp)
 
 	return nil
 }
 
+// Track a WAL timestamp that has been replicated to the node.
+func (n *Node) trackReplicatedWALTimestamp(databaseID, branchID string, timestamp int64) {
+	address, _ := n.Address()
+
+	n.replicationLag.Replica(address, databaseID, branchID, timestamp)
+}
+
 func (n *Node) handleWALVersionUsageRequest(message messages.WALVersionUsageRequest) (interface{}, error) {
 	versions, err := n.walSynchronizer.GetActiveWALVersions(
 		message.DatabaseID,
@@ -14,6 +14,10 @@ func registerNodeMessages() {
 	gob.Register(messages.HeartbeatMessage{})
 	gob.Register(messages.HeartbeatResponseMessage{})
 	gob.Register(messages.NodeConnectionMessage{})
+	gob.Register(messages.NodeStatusMessage{})
+	gob.Register(messages.NodeStatusResponseMessage{})
+	gob.Register(messages.PrimaryHandoffMessage{})
+	gob.Register(messages.PrimaryHandoffResponseMessage{})
 
 	gob.Register(messages.QueryMessage{})
 	gob.Register(messages.QueryMessageResponse{})
@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -13,6 +14,11 @@ import (
 	"github.com/litebase/litebase/pkg/cluster/messages"
 )
 
+var (
+	ErrQuorumNotReached  = errors.New("the write was not acknowledged by a quorum of replicas")
+	ErrQuorumUnavailable = errors.New("not enough replicas are available to reach a quorum")
+)
+
 type NodePrimary struct {
 	mutex           *sync.RWMutex
 	node            *Node
@@ -39,8 +45,9 @@ func (np *NodePrimary) Heartbeat() error {
 
 	_, errorMap := np.Publish(messages.NodeMessage{
 		Data: messages.HeartbeatMessage{
-			Address: address,
-			ID:      []byte("broadcast"),
+			Address:       address,
+			ID:            []byte("broadcast"),
+			WALTimestamps: np.node.replicationLag.PrimaryTimestamps(),
 		},
 	})
 
@@ -72,6 +79,8 @@ func (np *NodePrimary) Publish(message messages.NodeMessage) (map[string]any, ma
 		return nil, nil
 	}
 
+	np.trackWALTimestamp("", message)
+
 	nodes := np.node.Cluster.OtherNodes()
 
 	if len(nodes) == 0 {
@@ -110,6 +119,7 @@ func (np *NodePrimary) Publish(message messages.NodeMessage) (map[string]any, ma
 			response, err := connection.Send(message)
 
 			responseMutex.Lock()
+			defer responseMutex.Unlock()
 
 			if err != nil {
 				errorMap[connection.Address] = err
@@ -118,7 +128,7 @@ func (np *NodePrimary) Publish(message messages.NodeMessage) (map[string]any, ma
 
 			responseMap[connection.Address] = response
 
-			responseMutex.Unlock()
+			np.trackWALTimestamp(connection.Address, message)
 		}(connection)
 	}
 
@@ -127,6 +137,99 @@ func (np *NodePrimary) Publish(message messages.NodeMessage) (map[string]any, ma
 	return responseMap, errorMap
 }
 
+// Publish messages to the replica nodes and wait until the given number of
+// replicas have acknowledged all of them. The messages are sent in order to
+// each replica. An error is returned if the quorum cannot be reached before
+// the context is done.
+func (np *NodePrimary) PublishQuorum(ctx context.Context, quorum int, nodeMessages ...messages.NodeMessage) error {
+	for _, message := range nodeMessages {
+		np.trackWALTimestamp("", message)
+	}
+
+	nodes := np.node.Cluster.OtherNodes()
+
+	if len(nodes) < quorum {
+		return fmt.Errorf("%w: %d replicas available, %d required", ErrQuorumUnavailable, len(nodes), quorum)
+	}
+
+	acknowledgements := make(chan error, len(nodes))
+
+	for _, node := range nodes {
+		go func(address string) {
+			for _, message := range nodeMessages {
+				if ctx.Err() != nil {
+					acknowledgements <- ctx.Err()
+					return
+				}
+
+				_, err := np.Send(address, message)
+
+				if err != nil {
+					acknowledgements <- err
+					return
+				}
+
+				np.trackWALTimestamp(address, message)
+			}
+
+			acknowledgements <- nil
+		}(node.Address)
+	}
+
+	acknowledged, failed := 0, 0
+
+	for acknowledged < quorum {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %d of %d acknowledgements received", ErrQuorumNotReached, acknowledged, quorum)
+		case err := <-acknowledgements:
+			if err != nil {
+				failed++
+
+				slog.Debug("Replica failed to acknowledge messages", "error", err)
+
+				if len(nodes)-failed < quorum {
+					return fmt.Errorf("%w: %d of %d acknowledgements received", ErrQuorumNotReached, acknowledged, quorum)
+				}
+
+				continue
+			}
+
+			acknowledged++
+		}
+	}
+
+	return nil
+}
+
+// Send a message to a single replica node.
+func (np *NodePrimary) Send(address string, message messages.NodeMessage) (messages.NodeMessage, error) {
+	np.mutex.Lock()
+
+	connection, ok := np.nodeConnections[address]
+
+	if !ok {
+		connection = NewNodeConnection(np.node, address)
+		np.nodeConnections[address] = connection
+	}
+
+	np.mutex.Unlock()
+
+	response, err := connection.Send(message)
+
+	if err != nil {
+		return messages.NodeMessage{}, err
+	}
+
+	responseMessage, ok := response.(messages.NodeMessage)
+
+	if !ok {
+		return messages.NodeMessage{}, errors.New("invalid response message")
+	}
+
+	return responseMessage, nil
+}
+
 // Shutdown the primary node.
 func (np *NodePrimary) Shutdown() {
 	np.mutex.Lock()
@@ -141,10 +244,33 @@ func (np *NodePrimary) Shutdown() {
 	}
 }
 
+// Track the WAL timestamp of a replication message. When an address is given,
+// the timestamp has been acknowledged by the replica with that address,
+// otherwise it has been written on the primary.
+func (np *NodePrimary) trackWALTimestamp(address string, message messages.NodeMessage) {
+	var databaseID, branchID string
+	var timestamp int64
+
+	switch data := message.Data.(type) {
+	case messages.WALIndexTimestampMessage:
+		databaseID, branchID, timestamp = data.DatabaseID, data.BranchID, data.Timestamp
+	case messages.WALReplicationWriteMessage:
+		databaseID, branchID, timestamp = data.DatabaseID, data.BranchID, data.Timestamp
+	default:
+		return
+	}
+
+	if address == "" {
+		np.node.replicationLag.Primary(databaseID, branchID, timestamp)
+	} else {
+		np.node.replicationLag.Replica(address, databaseID, branchID, timestamp)
+	}
+}
+
 // Validate that the replica node is still connected by trying to reach it. If
 // the replica cannot be reached, remove replicas node the file from storage.
 func (np *NodePrimary) ValidateReplica(address string) error {
-	request, err := http.NewRequest("GET", fmt.Sprintf("http://%s/health", address), nil)
+	request, err := http.NewRequest("GET", fmt.Sprintf("%s://%s/health", np.node.Cluster.InternalScheme(), address), nil)
 
 	if err != nil {
 		log.Println("Failed to validate replica: ", err)
@@ -157,6 +283,7 @@ func (np *NodePrimary) ValidateReplica(address string) error {
 			DialContext: (&net.Dialer{
 				Timeout: 1 * time.Second, // Timeout for establishing a connection
 			}).DialContext,
+			TLSClientConfig: np.node.Cluster.TLSClientConfig(),
 		},
 	}
 
@@ -184,3 +311,23 @@ func (np *NodePrimary) ValidateReplica(address string) error {
 
 	return nil
 }
+
+// Close and remove the connection to a replica node.
+func (np *NodePrimary) RemoveConnection(address string) {
+	np.mutex.Lock()
+	defer np.mutex.Unlock()
+
+	connection, ok := np.nodeConnections[address]
+
+	if !ok {
+		return
+	}
+
+	err := connection.Close()
+
+	if err != nil {
+		slog.Error("Failed to close node connection", "address", address, "error", err)
+	}
+
+	delete(np.nodeConnections, address)
+}
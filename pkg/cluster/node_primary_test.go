@@ -1,9 +1,12 @@
 package cluster_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/litebase/litebase/internal/test"
 	"github.com/litebase/litebase/pkg/cluster"
@@ -109,6 +112,55 @@ func TestNodePrimaryPublish(t *testing.T) {
 	})
 }
 
+func TestNodePrimaryPublishQuorum(t *testing.T) {
+	test.Run(t, func() {
+		testServer1 := test.NewTestServer(t)
+		defer testServer1.Shutdown()
+		testServer2 := test.NewTestServer(t)
+		defer testServer2.Shutdown()
+		testServer3 := test.NewTestServer(t)
+		defer testServer3.Shutdown()
+
+		if !testServer1.App.Cluster.Node().IsPrimary() {
+			t.Fatalf("Node should be primary")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		err := testServer1.App.Cluster.Node().Primary().PublishQuorum(ctx, 2, messages.NodeMessage{
+			Data: messages.WALIndexTimestampMessage{
+				BranchID:   "branch",
+				DatabaseID: "database",
+				Timestamp:  time.Now().UTC().UnixNano(),
+			},
+		})
+
+		if err != nil {
+			t.Errorf("PublishQuorum should not return an error: %v", err)
+		}
+	})
+}
+
+func TestNodePrimaryPublishQuorumUnavailable(t *testing.T) {
+	test.Run(t, func() {
+		testServer1 := test.NewTestServer(t)
+		defer testServer1.Shutdown()
+
+		err := testServer1.App.Cluster.Node().Primary().PublishQuorum(context.Background(), 1, messages.NodeMessage{
+			Data: messages.WALIndexTimestampMessage{
+				BranchID:   "branch",
+				DatabaseID: "database",
+				Timestamp:  time.Now().UTC().UnixNano(),
+			},
+		})
+
+		if !errors.Is(err, cluster.ErrQuorumUnavailable) {
+			t.Errorf("Expected ErrQuorumUnavailable, got %v", err)
+		}
+	})
+}
+
 func TestNodePrimaryShutdown(t *testing.T) {
 	test.Run(t, func() {
 		testServer1 := test.NewTestServer(t)
@@ -1,12 +1,14 @@
 package cluster
 
 import (
+	"github.com/litebase/litebase/pkg/auth"
 	"github.com/litebase/litebase/pkg/sqlite3"
 )
 
 type NodeQueryBuilder interface {
 	Build(
 		accessKeyId string,
+		accessToken string,
 		databaseId string,
 		databaseName string,
 		branchId string,
@@ -14,6 +16,7 @@ type NodeQueryBuilder interface {
 		statement string,
 		parameters []sqlite3.StatementParameter,
 		id string,
+		authorizationContext *auth.AuthorizationContext,
 	) (NodeQuery, error)
 }
 
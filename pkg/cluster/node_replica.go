@@ -42,10 +42,11 @@ func NewNodeReplica(node *Node) *NodeReplica {
 // Join the cluster by informing the primary node
 func (nr *NodeReplica) JoinCluster() error {
 	httpClient := &http.Client{
-		Timeout: 3 * time.Second,
+		Timeout:   3 * time.Second,
+		Transport: nr.node.Cluster.InternalTransport(),
 	}
 
-	url := fmt.Sprintf("http://%s/v1/cluster/members", nr.node.PrimaryAddress())
+	url := fmt.Sprintf("%s://%s/v1/cluster/members", nr.node.Cluster.InternalScheme(), nr.node.PrimaryAddress())
 
 	address, err := nr.node.Address()
 
@@ -104,10 +105,6 @@ func (nr *NodeReplica) LeaveCluster() error {
 		return fmt.Errorf("node context is canceled")
 	}
 
-	httpClient := &http.Client{
-		Timeout: 3 * time.Second,
-	}
-
 	if nr.node == nil {
 		return nil
 	}
@@ -122,7 +119,12 @@ func (nr *NodeReplica) LeaveCluster() error {
 		return fmt.Errorf("failed to get node address: %w", err)
 	}
 
-	url := fmt.Sprintf("http://%s/v1/cluster/members/%s", nr.node.PrimaryAddress(), address)
+	httpClient := &http.Client{
+		Timeout:   3 * time.Second,
+		Transport: nr.node.Cluster.InternalTransport(),
+	}
+
+	url := fmt.Sprintf("%s://%s/v1/cluster/members/%s", nr.node.Cluster.InternalScheme(), nr.node.PrimaryAddress(), address)
 
 	request, err := http.NewRequestWithContext(nr.node.context, "DELETE", url, nil)
 
@@ -176,12 +178,13 @@ func (nr *NodeReplica) Send(message messages.NodeMessage) (messages.NodeMessage,
 	}
 
 	client := &http.Client{
-		Timeout: 3 * time.Second,
+		Timeout:   3 * time.Second,
+		Transport: nr.node.Cluster.InternalTransport(),
 	}
 
 	request, err := http.NewRequestWithContext(
 		nr.node.context, "POST",
-		fmt.Sprintf("http://%s/v1/cluster/primary", nr.node.PrimaryAddress()),
+		fmt.Sprintf("%s://%s/v1/cluster/primary", nr.node.Cluster.InternalScheme(), nr.node.PrimaryAddress()),
 		data,
 	)
 
@@ -0,0 +1,249 @@
+package cluster
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/litebase/litebase/pkg/cluster/messages"
+)
+
+var (
+	ErrMemberActive   = errors.New("the member is still active")
+	ErrMemberIsSelf   = errors.New("the primary cannot remove itself from the cluster")
+	ErrMemberNotFound = errors.New("the member is not part of the cluster")
+	ErrNotPrimary     = errors.New("the node is not the primary")
+)
+
+// A NodeStatusReporter reports the state of the databases that are open on the
+// node.
+type NodeStatusReporter func() NodeStatusReport
+
+type NodeStatusReport struct {
+	Connections int
+	Databases   []NodeDatabaseStatus
+}
+
+type NodeDatabaseStatus struct {
+	BranchID       string `json:"branch_id"`
+	DatabaseID     string `json:"database_id"`
+	ReplicationLag int64  `json:"replication_lag_ms"`
+	WALTimestamp   int64  `json:"wal_timestamp"`
+}
+
+type NodeStatus struct {
+	Address         string               `json:"address"`
+	Connections     int                  `json:"connections"`
+	Databases       []NodeDatabaseStatus `json:"databases"`
+	Error           string               `json:"error,omitempty"`
+	ID              string               `json:"id"`
+	LastHeartbeatAt time.Time            `json:"last_heartbeat_at"`
+	LeaseExpiresAt  int64                `json:"lease_expires_at"`
+	Reachable       bool                 `json:"reachable"`
+	Role            string               `json:"role"`
+}
+
+// Return the status of every member of the cluster. The status of the replicas
+// is requested over their node connection, so this can only be called on the
+// primary.
+func (c *Cluster) MemberStatuses() ([]*NodeStatus, error) {
+	node := c.Node()
+
+	if !node.IsPrimary() || node.Primary() == nil {
+		return nil, ErrNotPrimary
+	}
+
+	address, _ := node.Address()
+	members := c.GetMembers(false)
+	statuses := make([]*NodeStatus, len(members))
+	wg := sync.WaitGroup{}
+
+	for i, member := range members {
+		statuses[i] = &NodeStatus{
+			Address:         member.Address,
+			Databases:       []NodeDatabaseStatus{},
+			ID:              member.ID,
+			LastHeartbeatAt: member.UpdateAt,
+			Role:            ClusterMembershipReplica,
+		}
+
+		if member.Address == address {
+			report := node.Status()
+
+			statuses[i].Connections = report.Connections
+			statuses[i].Databases = report.Databases
+			statuses[i].Reachable = true
+			statuses[i].Role = ClusterMembershipPrimary
+
+			if lease := node.Lease(); lease != nil {
+				statuses[i].LeaseExpiresAt = lease.ExpiresAt
+			}
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(status *NodeStatus) {
+			defer wg.Done()
+
+			response, err := node.Primary().Send(status.Address, messages.NodeMessage{
+				Data: messages.NodeStatusMessage{
+					Address: address,
+				},
+			})
+
+			if err != nil {
+				status.Error = err.Error()
+				return
+			}
+
+			message, ok := response.Data.(messages.NodeStatusResponseMessage)
+
+			if !ok {
+				status.Error = "unexpected status response"
+				return
+			}
+
+			status.Connections = message.Connections
+			status.Reachable = true
+
+			for _, database := range message.Databases {
+				status.Databases = append(status.Databases, NodeDatabaseStatus{
+					BranchID:     database.BranchID,
+					DatabaseID:   database.DatabaseID,
+					WALTimestamp: database.WALTimestamp,
+				})
+			}
+		}(statuses[i])
+	}
+
+	wg.Wait()
+
+	// Calculate the replication lag of each replica from the latest WAL
+	// timestamps of the primary.
+	primaryTimestamps := map[string]int64{}
+
+	for _, status := range statuses {
+		if status.Role != ClusterMembershipPrimary {
+			continue
+		}
+
+		for _, database := range status.Databases {
+			primaryTimestamps[database.DatabaseID+":"+database.BranchID] = database.WALTimestamp
+		}
+	}
+
+	for _, status := range statuses {
+		if status.Role == ClusterMembershipPrimary {
+			continue
+		}
+
+		for i, database := range status.Databases {
+			timestamp, ok := primaryTimestamps[database.DatabaseID+":"+database.BranchID]
+
+			if ok && timestamp > database.WALTimestamp {
+				status.Databases[i].ReplicationLag = time.Duration(timestamp - database.WALTimestamp).Milliseconds()
+			}
+		}
+	}
+
+	slices.SortFunc(statuses, func(a, b *NodeStatus) int {
+		return strings.Compare(a.Address, b.Address)
+	})
+
+	return statuses, nil
+}
+
+// Remove a member that has stopped sending heartbeats from the cluster. The
+// address file of the member is removed and the other nodes are notified so
+// the member is no longer considered for replication or elections.
+func (c *Cluster) RemoveDeadMember(address string) error {
+	node := c.Node()
+
+	if !node.IsPrimary() {
+		return ErrNotPrimary
+	}
+
+	if self, _ := node.Address(); self == address {
+		return ErrMemberIsSelf
+	}
+
+	var member *NodeIdentifier
+
+	for _, m := range c.GetMembers(false) {
+		if m.Address == address {
+			member = m
+			break
+		}
+	}
+
+	if member == nil {
+		return ErrMemberNotFound
+	}
+
+	if time.Since(member.UpdateAt) <= NodeMemberTimeout {
+		return ErrMemberActive
+	}
+
+	err := c.RemoveMember(address, true)
+
+	if err != nil {
+		return err
+	}
+
+	if node.Primary() != nil {
+		node.Primary().RemoveConnection(address)
+	}
+
+	node.ReplicationLagTracker().RemoveReplica(address)
+
+	err = c.Broadcast("cluster:leave", map[string]any{
+		"address": address,
+	})
+
+	if err != nil {
+		slog.Debug("Failed to broadcast member removal", "address", address, "error", err)
+	}
+
+	return nil
+}
+
+// Handle a status request from the primary.
+func (n *Node) handleNodeStatusMessage() any {
+	report := n.Status()
+
+	response := messages.NodeStatusResponseMessage{
+		Connections: report.Connections,
+		Databases:   make([]messages.NodeDatabaseStatus, len(report.Databases)),
+	}
+
+	for i, database := range report.Databases {
+		response.Databases[i] = messages.NodeDatabaseStatus{
+			BranchID:     database.BranchID,
+			DatabaseID:   database.DatabaseID,
+			WALTimestamp: database.WALTimestamp,
+		}
+	}
+
+	return response
+}
+
+// Set the function used to report the state of the databases on the node.
+func (n *Node) SetStatusReporter(statusReporter NodeStatusReporter) {
+	n.statusReporter = statusReporter
+}
+
+// Return the state of the databases on the node.
+func (n *Node) Status() NodeStatusReport {
+	if n.statusReporter == nil {
+		return NodeStatusReport{
+			Databases: []NodeDatabaseStatus{},
+		}
+	}
+
+	return n.statusReporter()
+}
@@ -0,0 +1,106 @@
+package cluster_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/cluster"
+)
+
+func TestClusterMemberStatuses(t *testing.T) {
+	test.Run(t, func() {
+		testServer1 := test.NewTestServer(t)
+		defer testServer1.Shutdown()
+		testServer2 := test.NewTestServer(t)
+		defer testServer2.Shutdown()
+
+		primaryAddress, _ := testServer1.App.Cluster.Node().Address()
+		replicaAddress, _ := testServer2.App.Cluster.Node().Address()
+
+		statuses, err := testServer1.App.Cluster.MemberStatuses()
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(statuses) != 2 {
+			t.Fatalf("Expected 2 members, got %d", len(statuses))
+		}
+
+		for _, status := range statuses {
+			switch status.Address {
+			case primaryAddress:
+				if status.Role != cluster.ClusterMembershipPrimary {
+					t.Errorf("Expected role %s, got %s", cluster.ClusterMembershipPrimary, status.Role)
+				}
+
+				if status.LeaseExpiresAt == 0 {
+					t.Error("Expected the primary to report the lease expiry")
+				}
+			case replicaAddress:
+				if status.Role != cluster.ClusterMembershipReplica {
+					t.Errorf("Expected role %s, got %s", cluster.ClusterMembershipReplica, status.Role)
+				}
+
+				if !status.Reachable {
+					t.Errorf("Expected the replica to be reachable, got %s", status.Error)
+				}
+			default:
+				t.Errorf("Unexpected member %s", status.Address)
+			}
+		}
+
+		_, err = testServer2.App.Cluster.MemberStatuses()
+
+		if !errors.Is(err, cluster.ErrNotPrimary) {
+			t.Errorf("Expected ErrNotPrimary, got %v", err)
+		}
+	})
+}
+
+func TestClusterRemoveDeadMember(t *testing.T) {
+	test.Run(t, func() {
+		testServer1 := test.NewTestServer(t)
+		defer testServer1.Shutdown()
+		testServer2 := test.NewTestServer(t)
+		defer testServer2.Shutdown()
+
+		primaryAddress, _ := testServer1.App.Cluster.Node().Address()
+		replicaAddress, _ := testServer2.App.Cluster.Node().Address()
+
+		err := testServer1.App.Cluster.RemoveDeadMember(replicaAddress)
+
+		if !errors.Is(err, cluster.ErrMemberActive) {
+			t.Errorf("Expected ErrMemberActive, got %v", err)
+		}
+
+		err = testServer1.App.Cluster.RemoveDeadMember(primaryAddress)
+
+		if !errors.Is(err, cluster.ErrMemberIsSelf) {
+			t.Errorf("Expected ErrMemberIsSelf, got %v", err)
+		}
+
+		err = testServer1.App.Cluster.RemoveDeadMember("10.0.0.0:9876")
+
+		if !errors.Is(err, cluster.ErrMemberNotFound) {
+			t.Errorf("Expected ErrMemberNotFound, got %v", err)
+		}
+
+		memberTimeout := cluster.NodeMemberTimeout
+		cluster.NodeMemberTimeout = 0
+		defer func() { cluster.NodeMemberTimeout = memberTimeout }()
+
+		err = testServer1.App.Cluster.RemoveDeadMember(replicaAddress)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		for _, member := range testServer1.App.Cluster.GetMembers(false) {
+			if member.Address == replicaAddress {
+				t.Error("Expected the member to be removed")
+			}
+		}
+	})
+}
@@ -109,6 +109,84 @@ func TestNode_Address_WithAWSEcsProvider(t *testing.T) {
 	})
 }
 
+func TestNode_Address_WithKubernetesProvider(t *testing.T) {
+	t.Setenv("POD_IP", "10.0.0.12")
+
+	test.Run(t, func() {
+		cluster.SetAddressProvider(nil)
+
+		c := config.NewConfig()
+		c.NodeAddressProvider = "kubernetes"
+		clusterInstance, err := cluster.NewCluster(c)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		node := cluster.NewNode(clusterInstance)
+
+		address, err := node.Address()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if address != "10.0.0.12:8080" {
+			t.Errorf("Invalid node address: %s expected 10.0.0.12:8080", address)
+		}
+	})
+}
+
+func TestNode_Address_WithStaticProvider(t *testing.T) {
+	test.Run(t, func() {
+		cluster.SetAddressProvider(nil)
+
+		c := config.NewConfig()
+		c.NodeAddressProvider = "static"
+		c.NodeAddress = "10.0.0.13"
+		clusterInstance, err := cluster.NewCluster(c)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		node := cluster.NewNode(clusterInstance)
+
+		address, err := node.Address()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if address != "10.0.0.13:8080" {
+			t.Errorf("Invalid node address: %s expected 10.0.0.13:8080", address)
+		}
+	})
+}
+
+func TestNode_Address_WithStaticProviderWithoutAddress(t *testing.T) {
+	test.Run(t, func() {
+		cluster.SetAddressProvider(nil)
+
+		c := config.NewConfig()
+		c.NodeAddressProvider = "static"
+		c.NodeAddress = ""
+		clusterInstance, err := cluster.NewCluster(c)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		node := cluster.NewNode(clusterInstance)
+
+		_, err = node.Address()
+
+		if err == nil {
+			t.Error("Expected an error when no static address is configured")
+		}
+	})
+}
+
 func TestNode_AddressPath(t *testing.T) {
 	test.Run(t, func() {
 		c := config.NewConfig()
@@ -4,4 +4,5 @@ type NodeWalSynchronizer interface {
 	GetActiveWALVersions(databaseId, branchId string) ([]int64, error)
 	SetCurrentTimestamp(databaseId, branchId string, timestamp int64) error
 	SetWALIndexHeader(databaseId, branchId string, header []byte) error
+	WriteAt(databaseId, branchId string, p []byte, off, sequence, timestamp int64) error
 }
@@ -0,0 +1,196 @@
+package cluster
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The ReplicationLagTracker keeps track of the latest WAL timestamp written on
+// the primary for each database branch and the latest WAL timestamp that has
+// been replicated to each replica. The lag of a replica is the difference
+// between the two timestamps.
+type ReplicationLagTracker struct {
+	mutex    *sync.RWMutex
+	primary  map[string]int64
+	replicas map[string]map[string]int64
+}
+
+type ReplicationLagMetric struct {
+	Address          string `json:"address"`
+	BranchID         string `json:"branch_id"`
+	DatabaseID       string `json:"database_id"`
+	Lag              int64  `json:"lag_ms"`
+	PrimaryTimestamp int64  `json:"primary_timestamp"`
+	ReplicaTimestamp int64  `json:"replica_timestamp"`
+}
+
+// Create a new instance of a ReplicationLagTracker.
+func NewReplicationLagTracker() *ReplicationLagTracker {
+	return &ReplicationLagTracker{
+		mutex:    &sync.RWMutex{},
+		primary:  map[string]int64{},
+		replicas: map[string]map[string]int64{},
+	}
+}
+
+// Return the key used to track a database branch.
+func replicationLagKey(databaseID, branchID string) string {
+	return databaseID + ":" + branchID
+}
+
+// Return the replication lag of a replica for a database branch. Replicas
+// that have not reported a timestamp for the database branch are not
+// considered to be lagging.
+func (t *ReplicationLagTracker) Lag(address, databaseID, branchID string) time.Duration {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	key := replicationLagKey(databaseID, branchID)
+
+	replicaTimestamp, ok := t.replicas[address][key]
+
+	if !ok {
+		return 0
+	}
+
+	if primaryTimestamp := t.primary[key]; primaryTimestamp > replicaTimestamp {
+		return time.Duration(primaryTimestamp - replicaTimestamp)
+	}
+
+	return 0
+}
+
+// Return the highest replication lag of a replica across all of the database
+// branches it has reported.
+func (t *ReplicationLagTracker) ReplicaLag(address string) time.Duration {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	var lag time.Duration
+
+	for key, replicaTimestamp := range t.replicas[address] {
+		if primaryTimestamp := t.primary[key]; primaryTimestamp > replicaTimestamp {
+			lag = max(lag, time.Duration(primaryTimestamp-replicaTimestamp))
+		}
+	}
+
+	return lag
+}
+
+// Return the replication lag of every replica for each database branch.
+func (t *ReplicationLagTracker) Metrics() []ReplicationLagMetric {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	metrics := []ReplicationLagMetric{}
+
+	for address, timestamps := range t.replicas {
+		for key, replicaTimestamp := range timestamps {
+			databaseID, branchID, _ := strings.Cut(key, ":")
+			primaryTimestamp := t.primary[key]
+
+			metric := ReplicationLagMetric{
+				Address:          address,
+				BranchID:         branchID,
+				DatabaseID:       databaseID,
+				PrimaryTimestamp: primaryTimestamp,
+				ReplicaTimestamp: replicaTimestamp,
+			}
+
+			if primaryTimestamp > replicaTimestamp {
+				metric.Lag = time.Duration(primaryTimestamp - replicaTimestamp).Milliseconds()
+			}
+
+			metrics = append(metrics, metric)
+		}
+	}
+
+	slices.SortFunc(metrics, func(a, b ReplicationLagMetric) int {
+		if c := strings.Compare(a.Address, b.Address); c != 0 {
+			return c
+		}
+
+		if c := strings.Compare(a.DatabaseID, b.DatabaseID); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.BranchID, b.BranchID)
+	})
+
+	return metrics
+}
+
+// Record the latest WAL timestamp written on the primary for a database branch.
+func (t *ReplicationLagTracker) Primary(databaseID, branchID string, timestamp int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := replicationLagKey(databaseID, branchID)
+
+	if timestamp > t.primary[key] {
+		t.primary[key] = timestamp
+	}
+}
+
+// Return the latest WAL timestamps written on the primary, keyed by database
+// branch.
+func (t *ReplicationLagTracker) PrimaryTimestamps() map[string]int64 {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	timestamps := make(map[string]int64, len(t.primary))
+
+	for key, timestamp := range t.primary {
+		timestamps[key] = timestamp
+	}
+
+	return timestamps
+}
+
+// Remove the timestamps tracked for a replica.
+func (t *ReplicationLagTracker) RemoveReplica(address string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.replicas, address)
+}
+
+// Record the latest WAL timestamp replicated to a replica for a database branch.
+func (t *ReplicationLagTracker) Replica(address, databaseID, branchID string, timestamp int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, ok := t.replicas[address]; !ok {
+		t.replicas[address] = map[string]int64{}
+	}
+
+	key := replicationLagKey(databaseID, branchID)
+
+	if timestamp > t.replicas[address][key] {
+		t.replicas[address][key] = timestamp
+	}
+}
+
+// Replace the primary timestamps with the timestamps reported by the primary.
+// Database branches that the replica has not tracked yet start out in sync,
+// since the replica reads the same durable storage as the primary.
+func (t *ReplicationLagTracker) SyncPrimary(address string, timestamps map[string]int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, ok := t.replicas[address]; !ok {
+		t.replicas[address] = map[string]int64{}
+	}
+
+	for key, timestamp := range timestamps {
+		if timestamp > t.primary[key] {
+			t.primary[key] = timestamp
+		}
+
+		if _, ok := t.replicas[address][key]; !ok {
+			t.replicas[address][key] = timestamp
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package cluster_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/cluster"
+	"github.com/litebase/litebase/pkg/cluster/messages"
+)
+
+func TestReplicationLagTracker(t *testing.T) {
+	tracker := cluster.NewReplicationLagTracker()
+
+	if lag := tracker.Lag("10.0.0.1:8080", "db", "main"); lag != 0 {
+		t.Errorf("Expected no lag for an unknown replica, got %s", lag)
+	}
+
+	tracker.Primary("db", "main", int64(3*time.Second))
+	tracker.Replica("10.0.0.1:8080", "db", "main", int64(1*time.Second))
+
+	if lag := tracker.Lag("10.0.0.1:8080", "db", "main"); lag != 2*time.Second {
+		t.Errorf("Expected a lag of 2s, got %s", lag)
+	}
+
+	// Older timestamps should not move the tracked timestamps backwards.
+	tracker.Replica("10.0.0.1:8080", "db", "main", 0)
+	tracker.Primary("db", "main", 0)
+
+	metrics := tracker.Metrics()
+
+	if len(metrics) != 1 {
+		t.Fatalf("Expected 1 metric, got %d", len(metrics))
+	}
+
+	if metrics[0].Lag != 2000 {
+		t.Errorf("Expected a lag of 2000ms, got %d", metrics[0].Lag)
+	}
+
+	tracker.Replica("10.0.0.1:8080", "db", "main", int64(3*time.Second))
+
+	if lag := tracker.Lag("10.0.0.1:8080", "db", "main"); lag != 0 {
+		t.Errorf("Expected no lag once the replica has caught up, got %s", lag)
+	}
+
+	tracker.RemoveReplica("10.0.0.1:8080")
+
+	if len(tracker.Metrics()) != 0 {
+		t.Error("Expected the replica metrics to be removed")
+	}
+}
+
+func TestReplicationLagTrackerReplicaLag(t *testing.T) {
+	tracker := cluster.NewReplicationLagTracker()
+
+	tracker.Primary("db", "main", int64(5*time.Second))
+	tracker.Primary("db", "dev", int64(5*time.Second))
+	tracker.Replica("10.0.0.1:8080", "db", "main", int64(4*time.Second))
+	tracker.Replica("10.0.0.1:8080", "db", "dev", int64(2*time.Second))
+
+	if lag := tracker.ReplicaLag("10.0.0.1:8080"); lag != 3*time.Second {
+		t.Errorf("Expected the highest lag of 3s, got %s", lag)
+	}
+
+	if lag := tracker.ReplicaLag("10.0.0.2:8080"); lag != 0 {
+		t.Errorf("Expected no lag for an unknown replica, got %s", lag)
+	}
+}
+
+func TestReplicationLagTrackerSyncPrimary(t *testing.T) {
+	tracker := cluster.NewReplicationLagTracker()
+
+	tracker.SyncPrimary("10.0.0.1:8080", map[string]int64{"db:main": 100})
+
+	if lag := tracker.Lag("10.0.0.1:8080", "db", "main"); lag != 0 {
+		t.Errorf("Expected a newly tracked database to start in sync, got %s", lag)
+	}
+
+	tracker.SyncPrimary("10.0.0.1:8080", map[string]int64{"db:main": 300})
+
+	if lag := tracker.Lag("10.0.0.1:8080", "db", "main"); lag != 200 {
+		t.Errorf("Expected a lag of 200ns, got %s", lag)
+	}
+}
+
+func TestNodeReplicationLag(t *testing.T) {
+	test.Run(t, func() {
+		testServer1 := test.NewTestServer(t)
+		defer testServer1.Shutdown()
+		testServer2 := test.NewTestServer(t)
+		defer testServer2.Shutdown()
+
+		replica := testServer2.App.Cluster.Node()
+
+		_, err := replica.HandleMessage(messages.NodeMessage{
+			Data: messages.HeartbeatMessage{
+				Time:          time.Now().UTC().Unix(),
+				WALTimestamps: map[string]int64{"db:main": int64(1 * time.Second)},
+			},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = replica.HandleMessage(messages.NodeMessage{
+			Data: messages.HeartbeatMessage{
+				Time:          time.Now().UTC().Unix(),
+				WALTimestamps: map[string]int64{"db:main": int64(5 * time.Second)},
+			},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if lag := replica.ReplicationLag("db", "main"); lag != 4*time.Second {
+			t.Errorf("Expected a lag of 4s, got %s", lag)
+		}
+
+		_, err = replica.HandleMessage(messages.NodeMessage{
+			Data: messages.WALIndexTimestampMessage{
+				BranchID:   "main",
+				DatabaseID: "db",
+				Timestamp:  int64(5 * time.Second),
+			},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if lag := replica.ReplicationLag("db", "main"); lag != 0 {
+			t.Errorf("Expected no lag after the WAL timestamp is replicated, got %s", lag)
+		}
+
+		if lag := testServer1.App.Cluster.Node().ReplicationLag("db", "main"); lag != 0 {
+			t.Errorf("Expected no lag on the primary, got %s", lag)
+		}
+	})
+}
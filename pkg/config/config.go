@@ -3,6 +3,10 @@ package config
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"os"
 )
@@ -14,39 +18,95 @@ const (
 
 	StorageModeLocal  = "local"
 	StorageModeObject = "object"
+
+	DatabasePrefetchIndex  = "index"
+	DatabasePrefetchRecent = "recent"
+
+	ReplicaLagPolicyForward = "forward"
+	ReplicaLagPolicyRefuse  = "refuse"
+
+	ReplicaModeRemote = "remote"
+	ReplicaModeShared = "shared"
+
+	RouterBalancingAffinity         = "affinity"
+	RouterBalancingLatency          = "latency"
+	RouterBalancingLeastConnections = "least_connections"
 )
 
 type Config struct {
-	ClusterId              string
-	DataPath               string
-	DatabaseDirectory      string
-	Debug                  bool
-	DefaultBranchName      string
-	EncryptionKey          string
-	EncryptionKeyNext      string
-	HostName               string
-	Env                    string
-	FakeObjectStorage      bool
-	FileSystemDriver       string
-	NetworkStoragePath     string
-	NodeAddressProvider    string
-	PageSize               int64
-	Port                   string
-	Region                 string
-	RootPassword           string
-	RootUsername           string
-	RouterNodePort         string
-	StorageAccessKeyId     string
-	StorageBucket          string
-	StorageEndpoint        string
-	StorageObjectMode      string
-	StorageSecretAccessKey string
-	StoragePort            string
-	StorageRegion          string
-	StorageTieredMode      string
-	TmpPath                string
+	AuditWriteStatements           bool
+	ClusterId                      string
+	ClusterTLSCAPath               string
+	ClusterTLSCertPath             string
+	ClusterTLSKeyPath              string
+	DataPath                       string
+	DatabaseDirectory              string
+	DatabasePrefetch               string
+	Debug                          bool
+	DefaultBranchName              string
+	EncryptionKey                  string
+	EncryptionKeyNext              string
+	HostName                       string
+	Env                            string
+	FakeObjectStorage              bool
+	FileSystemDriver               string
+	LoginLockoutMaxSeconds         int64
+	LoginMaxAttempts               int64
+	NetworkStoragePath             string
+	NodeAddress                    string
+	NodeAddressProvider            string
+	OIDCClientID                   string
+	OIDCGroupsClaim                string
+	OIDCIssuer                     string
+	OIDCRoleMappings               []string
+	OIDCScopes                     []string
+	OIDCUsernameClaim              string
+	PageSize                       int64
+	PasswordMinLength              int64
+	PasswordRequireLowercase       bool
+	PasswordRequireNumber          bool
+	PasswordRequireSymbol          bool
+	PasswordRequireUppercase       bool
+	Port                           string
+	PrefetchConcurrency            int
+	Region                         string
+	RemoteReplicaPollInterval      int64
+	ReplicaLagPolicy               string
+	ReplicaMaxLagMs                int64
+	ReplicaMode                    string
+	ReplicationShipping            bool
+	RootPassword                   string
+	RootUsername                   string
+	RouterAffinityLoadBoundPercent int64
+	RouterBalancing                string
+	RouterHealthIntervalMs         int64
+	RouterNodePort                 string
+	RouterNodeScheme               string
+	StorageAccessKeyId             string
+	StorageBucket                  string
+	StorageEndpoint                string
+	StorageObjectMode              string
+	StorageSecretAccessKey         string
+	StoragePort                    string
+	StorageRegion                  string
+	StorageTieredMode              string
+	TLSCertPath                    string
+	TLSHTTP2                       bool
+	TLSKeyPath                     string
+	TLSRedirectPort                string
+	TieredStorageMaxBytes          int64
+	TieredStoragePinned            []string
+	TieredStoragePolicy            string
+	TmpPath                        string
+	TrustedProxies                 []string
 }
 
+var (
+	ErrDatabasePrefetchInvalid = errors.New("the database prefetch mode must be empty, 'index' or 'recent'")
+	ErrReplicaLagPolicyInvalid = errors.New("the replica lag policy must be either 'forward' or 'refuse'")
+	ErrReplicaModeInvalid      = errors.New("the replica mode must be either 'shared' or 'remote'")
+)
+
 func env(key string, defaultValue string) any {
 	if os.Getenv(key) != "" {
 		return os.Getenv(key)
@@ -55,34 +115,125 @@ func env(key string, defaultValue string) any {
 	return defaultValue
 }
 
+// Return the value of an environment variable parsed as an int64, or the
+// default value if the variable is not set or cannot be parsed.
+func envInt64(key string, defaultValue int64) int64 {
+	value, err := strconv.ParseInt(os.Getenv(key), 10, 64)
+
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
+// Return the value of an environment variable as a list of comma separated
+// values. Empty values are omitted.
+func envList(key string) []string {
+	values := []string{}
+
+	for value := range strings.SplitSeq(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
 func NewConfig() *Config {
 	return &Config{
-		ClusterId:              env("LITEBASE_CLUSTER_ID", "").(string),
-		DataPath:               env("LITEBASE_LOCAL_DATA_PATH", "./data").(string),
-		DefaultBranchName:      env("LITEBASE_DEFAULT_BRANCH_NAME", "main").(string),
-		Debug:                  env("LITEBASE_DEBUG", "false") == "true",
-		EncryptionKey:          env("LITEBASE_ENCRYPTION_KEY", "").(string),
-		EncryptionKeyNext:      env("LITEBASE_ENCRYPTION_KEY_NEXT", "").(string),
-		Env:                    env("LITEBASE_ENV", "production").(string),
-		FakeObjectStorage:      env("LITEBASE_FAKE_OBJECT_STORAGE", "false") == "true",
-		HostName:               env("LITEBASE_HOSTNAME", "localhost").(string),
-		NodeAddressProvider:    env("LITEBASE_NODE_ADDRESS_PROVIDER", "").(string),
-		PageSize:               4096,
-		Port:                   env("LITEBASE_PORT", "8080").(string),
-		Region:                 env("LITEBASE_REGION", "").(string),
-		NetworkStoragePath:     env("LITEBASE_NETWORK_STORAGE_PATH", "").(string),
-		RouterNodePort:         env("LITEBASE_ROUTER_NODE_PORT", "8080").(string),
-		RootPassword:           env("LITEBASE_ROOT_PASSWORD", "").(string),
-		RootUsername:           env("LITEBASE_ROOT_USERNAME", "").(string),
-		StorageAccessKeyId:     env("LITEBASE_STORAGE_ACCESS_KEY_ID", "").(string),
-		StorageBucket:          env("LITEBASE_STORAGE_BUCKET", "").(string),
-		StorageEndpoint:        env("LITEBASE_STORAGE_ENDPOINT", "").(string),
-		StorageRegion:          env("LITEBASE_STORAGE_REGION", "").(string),
-		StorageObjectMode:      env("LITEBASE_STORAGE_OBJECT_MODE", "object").(string),
-		StorageSecretAccessKey: env("LITEBASE_STORAGE_SECRET_ACCESS_KEY", "").(string),
-		StorageTieredMode:      env("LITEBASE_STORAGE_TIERED_MODE", env("LITEBASE_STORAGE_OBJECT_MODE", "object").(string)).(string),
-		TmpPath:                env("LITEBASE_TMP_PATH", "").(string),
+		AuditWriteStatements:           env("LITEBASE_AUDIT_WRITE_STATEMENTS", "false") == "true",
+		ClusterId:                      env("LITEBASE_CLUSTER_ID", "").(string),
+		ClusterTLSCAPath:               env("LITEBASE_CLUSTER_TLS_CA_PATH", "").(string),
+		ClusterTLSCertPath:             env("LITEBASE_CLUSTER_TLS_CERT_PATH", "").(string),
+		ClusterTLSKeyPath:              env("LITEBASE_CLUSTER_TLS_KEY_PATH", "").(string),
+		DataPath:                       env("LITEBASE_LOCAL_DATA_PATH", "./data").(string),
+		DatabasePrefetch:               env("LITEBASE_DATABASE_PREFETCH", "").(string),
+		DefaultBranchName:              env("LITEBASE_DEFAULT_BRANCH_NAME", "main").(string),
+		Debug:                          env("LITEBASE_DEBUG", "false") == "true",
+		EncryptionKey:                  env("LITEBASE_ENCRYPTION_KEY", "").(string),
+		EncryptionKeyNext:              env("LITEBASE_ENCRYPTION_KEY_NEXT", "").(string),
+		Env:                            env("LITEBASE_ENV", "production").(string),
+		FakeObjectStorage:              env("LITEBASE_FAKE_OBJECT_STORAGE", "false") == "true",
+		HostName:                       env("LITEBASE_HOSTNAME", "localhost").(string),
+		LoginLockoutMaxSeconds:         envInt64("LITEBASE_LOGIN_LOCKOUT_MAX_SECONDS", 900),
+		LoginMaxAttempts:               envInt64("LITEBASE_LOGIN_MAX_ATTEMPTS", 5),
+		NodeAddress:                    env("LITEBASE_NODE_ADDRESS", "").(string),
+		NodeAddressProvider:            env("LITEBASE_NODE_ADDRESS_PROVIDER", "").(string),
+		OIDCClientID:                   env("LITEBASE_OIDC_CLIENT_ID", "").(string),
+		OIDCGroupsClaim:                env("LITEBASE_OIDC_GROUPS_CLAIM", "groups").(string),
+		OIDCIssuer:                     env("LITEBASE_OIDC_ISSUER", "").(string),
+		OIDCRoleMappings:               envList("LITEBASE_OIDC_ROLE_MAPPINGS"),
+		OIDCScopes:                     envList("LITEBASE_OIDC_SCOPES"),
+		OIDCUsernameClaim:              env("LITEBASE_OIDC_USERNAME_CLAIM", "email").(string),
+		PageSize:                       4096,
+		PasswordMinLength:              envInt64("LITEBASE_PASSWORD_MIN_LENGTH", 8),
+		PasswordRequireLowercase:       env("LITEBASE_PASSWORD_REQUIRE_LOWERCASE", "false") == "true",
+		PasswordRequireNumber:          env("LITEBASE_PASSWORD_REQUIRE_NUMBER", "false") == "true",
+		PasswordRequireSymbol:          env("LITEBASE_PASSWORD_REQUIRE_SYMBOL", "false") == "true",
+		PasswordRequireUppercase:       env("LITEBASE_PASSWORD_REQUIRE_UPPERCASE", "false") == "true",
+		Port:                           env("LITEBASE_PORT", "8080").(string),
+		PrefetchConcurrency:            int(envInt64("LITEBASE_DATABASE_PREFETCH_CONCURRENCY", 4)),
+		Region:                         env("LITEBASE_REGION", "").(string),
+		ReplicaLagPolicy:               env("LITEBASE_REPLICA_LAG_POLICY", ReplicaLagPolicyForward).(string),
+		ReplicaMaxLagMs:                envInt64("LITEBASE_REPLICA_MAX_LAG_MS", 0),
+		ReplicaMode:                    env("LITEBASE_REPLICA_MODE", ReplicaModeShared).(string),
+		ReplicationShipping:            env("LITEBASE_REPLICATION_SHIPPING", "false") == "true",
+		RemoteReplicaPollInterval:      envInt64("LITEBASE_REMOTE_REPLICA_POLL_INTERVAL_MS", 1000),
+		NetworkStoragePath:             env("LITEBASE_NETWORK_STORAGE_PATH", "").(string),
+		RouterAffinityLoadBoundPercent: envInt64("LITEBASE_ROUTER_AFFINITY_LOAD_BOUND_PERCENT", 125),
+		RouterBalancing:                env("LITEBASE_ROUTER_BALANCING_STRATEGY", RouterBalancingAffinity).(string),
+		RouterHealthIntervalMs:         envInt64("LITEBASE_ROUTER_HEALTH_CHECK_INTERVAL_MS", 2000),
+		RouterNodePort:                 env("LITEBASE_ROUTER_NODE_PORT", "8080").(string),
+		RouterNodeScheme:               env("LITEBASE_ROUTER_NODE_SCHEME", "http").(string),
+		RootPassword:                   env("LITEBASE_ROOT_PASSWORD", "").(string),
+		RootUsername:                   env("LITEBASE_ROOT_USERNAME", "").(string),
+		StorageAccessKeyId:             env("LITEBASE_STORAGE_ACCESS_KEY_ID", "").(string),
+		StorageBucket:                  env("LITEBASE_STORAGE_BUCKET", "").(string),
+		StorageEndpoint:                env("LITEBASE_STORAGE_ENDPOINT", "").(string),
+		StorageRegion:                  env("LITEBASE_STORAGE_REGION", "").(string),
+		StorageObjectMode:              env("LITEBASE_STORAGE_OBJECT_MODE", "object").(string),
+		StorageSecretAccessKey:         env("LITEBASE_STORAGE_SECRET_ACCESS_KEY", "").(string),
+		StorageTieredMode:              env("LITEBASE_STORAGE_TIERED_MODE", env("LITEBASE_STORAGE_OBJECT_MODE", "object").(string)).(string),
+		TLSCertPath:                    env("LITEBASE_TLS_CERT_PATH", "").(string),
+		TLSHTTP2:                       env("LITEBASE_TLS_HTTP2", "true") == "true",
+		TLSKeyPath:                     env("LITEBASE_TLS_KEY_PATH", "").(string),
+		TLSRedirectPort:                env("LITEBASE_TLS_REDIRECT_PORT", "").(string),
+		TieredStorageMaxBytes:          envInt64("LITEBASE_TIERED_STORAGE_MAX_BYTES", 0),
+		TieredStoragePinned:            envList("LITEBASE_TIERED_STORAGE_PINNED_DATABASES"),
+		TieredStoragePolicy:            env("LITEBASE_TIERED_STORAGE_EVICTION_POLICY", "lru").(string),
+		TmpPath:                        env("LITEBASE_TMP_PATH", "").(string),
+		TrustedProxies:                 envList("LITEBASE_TRUSTED_PROXIES"),
+	}
+}
+
+// Check that the database prefetch mode is supported. An empty mode disables
+// prefetching.
+func ValidateDatabasePrefetch(mode string) error {
+	if mode != "" && mode != DatabasePrefetchIndex && mode != DatabasePrefetchRecent {
+		return fmt.Errorf("%w, got '%s'", ErrDatabasePrefetchInvalid, mode)
+	}
+
+	return nil
+}
+
+// Check that the policy for replicas that lag too far behind is supported.
+func ValidateReplicaLagPolicy(policy string) error {
+	if policy != ReplicaLagPolicyForward && policy != ReplicaLagPolicyRefuse {
+		return fmt.Errorf("%w, got '%s'", ErrReplicaLagPolicyInvalid, policy)
 	}
+
+	return nil
+}
+
+// Check that the replica mode is supported.
+func ValidateReplicaMode(mode string) error {
+	if mode != ReplicaModeShared && mode != ReplicaModeRemote {
+		return fmt.Errorf("%w, got '%s'", ErrReplicaModeInvalid, mode)
+	}
+
+	return nil
 }
 
 // Generate a hash of the encryption key so that it is not stored in plain text.
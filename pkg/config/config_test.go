@@ -1,6 +1,7 @@
 package config_test
 
 import (
+	"errors"
 	"os"
 	"testing"
 
@@ -53,3 +54,45 @@ func TestGet(t *testing.T) {
 
 	test.Teardown(t, "../../.test", nil)
 }
+
+func TestValidateDatabasePrefetch(t *testing.T) {
+	for _, mode := range []string{"", config.DatabasePrefetchIndex, config.DatabasePrefetchRecent} {
+		if err := config.ValidateDatabasePrefetch(mode); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", mode, err)
+		}
+	}
+
+	for _, mode := range []string{"all", "Recent", "true"} {
+		if err := config.ValidateDatabasePrefetch(mode); !errors.Is(err, config.ErrDatabasePrefetchInvalid) {
+			t.Errorf("Expected %q to be invalid, got %v", mode, err)
+		}
+	}
+}
+
+func TestValidateReplicaLagPolicy(t *testing.T) {
+	for _, policy := range []string{config.ReplicaLagPolicyForward, config.ReplicaLagPolicyRefuse} {
+		if err := config.ValidateReplicaLagPolicy(policy); err != nil {
+			t.Errorf("Expected %s to be valid, got %v", policy, err)
+		}
+	}
+
+	for _, policy := range []string{"", "Refuse", "drop"} {
+		if err := config.ValidateReplicaLagPolicy(policy); !errors.Is(err, config.ErrReplicaLagPolicyInvalid) {
+			t.Errorf("Expected %q to be invalid, got %v", policy, err)
+		}
+	}
+}
+
+func TestValidateReplicaMode(t *testing.T) {
+	for _, mode := range []string{config.ReplicaModeShared, config.ReplicaModeRemote} {
+		if err := config.ValidateReplicaMode(mode); err != nil {
+			t.Errorf("Expected %s to be valid, got %v", mode, err)
+		}
+	}
+
+	for _, mode := range []string{"", "Remote", "local"} {
+		if err := config.ValidateReplicaMode(mode); !errors.Is(err, config.ErrReplicaModeInvalid) {
+			t.Errorf("Expected %q to be invalid, got %v", mode, err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package database
+
+import (
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/sqlite3"
+)
+
+// An AuthorizationCheck is a single call of the authorizer made while a
+// statement is prepared, such as reading a column or inserting into a table.
+type AuthorizationCheck struct {
+	Operation string                      `json:"operation"`
+	Arguments []string                    `json:"arguments"`
+	Allowed   bool                        `json:"allowed"`
+	Reason    string                      `json:"reason,omitempty"`
+	Decision  *auth.AuthorizationDecision `json:"decision,omitempty"`
+}
+
+// An AuthorizationSimulation is the outcome of preparing a statement for an
+// access key without executing it.
+type AuthorizationSimulation struct {
+	Allowed bool                 `json:"allowed"`
+	Checks  []AuthorizationCheck `json:"checks"`
+	Error   string               `json:"error,omitempty"`
+}
+
+// Simulate the authorization of a statement for the access key of the
+// connection. The statement is prepared with the row policies and column
+// masks of the access key applied, but it is never executed. Each check of the
+// authorizer is returned with the decision of the statement that decided it.
+func (con *DatabaseConnection) SimulateAuthorization(statement string) (AuthorizationSimulation, error) {
+	simulation := AuthorizationSimulation{
+		Checks: []AuthorizationCheck{},
+	}
+
+	if con.Closed() {
+		return simulation, ErrDatabaseConnectionClosed
+	}
+
+	preparedStatement, err := con.applyAccessKeyPolicies(statement)
+
+	if err != nil {
+		return simulation, err
+	}
+
+	var decisions []auth.AuthorizationDecision
+
+	if con.AccessKey != nil {
+		con.AccessKey.Trace(func(decision auth.AuthorizationDecision) {
+			decisions = append(decisions, decision)
+		})
+
+		defer con.AccessKey.Trace(nil)
+	}
+
+	con.authorizerTracer = func(check AuthorizationCheck) {
+		check.Decision = decisiveDecision(decisions, check.Allowed)
+
+		if check.Decision == nil && !check.Allowed && check.Reason == "" {
+			check.Reason = "operation is not supported"
+		}
+
+		simulation.Checks = append(simulation.Checks, check)
+		decisions = nil
+	}
+
+	defer func() {
+		con.authorizerTracer = nil
+	}()
+
+	prepared, err := con.Prepare(con.context, preparedStatement)
+
+	if err != nil {
+		simulation.Error = err.Error()
+
+		return simulation, nil
+	}
+
+	prepared.Sqlite3Statement.Finalize()
+
+	simulation.Allowed = true
+
+	return simulation, nil
+}
+
+// Return the decision that decided an authorizer check from the decisions
+// made while checking it. An allowed check is decided by the decision that
+// allowed it. A denied check is decided by the first explicit deny, or by the
+// most specific resource that was evaluated when no statement matched.
+func decisiveDecision(decisions []auth.AuthorizationDecision, allowed bool) *auth.AuthorizationDecision {
+	if len(decisions) == 0 {
+		return nil
+	}
+
+	if !allowed {
+		for _, decision := range decisions {
+			if !decision.Allowed && decision.Statement != nil {
+				return &decision
+			}
+		}
+	}
+
+	for i := len(decisions) - 1; i >= 0; i-- {
+		if decisions[i].Allowed == allowed {
+			return &decisions[i]
+		}
+	}
+
+	return &decisions[len(decisions)-1]
+}
+
+// Report a check of the authorizer to the tracer of the connection, if any,
+// and return the result of the check.
+func (c *DatabaseConnection) traceAuthorizer(actionCode int, arg1, arg2, arg3, arg4 string, result int32, reason string) int32 {
+	if c.authorizerTracer != nil {
+		c.authorizerTracer(AuthorizationCheck{
+			Operation: sqlite3.AuthorizerCodeName(actionCode),
+			Arguments: []string{arg1, arg2, arg3, arg4},
+			Allowed:   result == sqlite3.SQLITE_OK,
+			Reason:    reason,
+		})
+	}
+
+	return result
+}
@@ -278,6 +278,8 @@ func (b *Branch) Delete() error {
 
 	resources.Remove()
 
+	b.DatabaseManager.StorageAccountant().Remove(b.DatabaseID, b.DatabaseBranchID)
+
 	return nil
 }
 
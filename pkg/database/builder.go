@@ -34,6 +34,7 @@ func NewQueryBuilder(
 
 func (qb *QueryBuilder) Build(
 	accessKeyId string,
+	accessToken string,
 	databaseId string,
 	databaseName string,
 	branchId string,
@@ -41,13 +42,28 @@ func (qb *QueryBuilder) Build(
 	statement string,
 	parameters []sqlite3.StatementParameter,
 	id string,
+	authorizationContext *auth.AuthorizationContext,
 ) (cluster.NodeQuery, error) {
-	accessKey, err := qb.accessKeyManager.Get(accessKeyId)
+	var accessKey *auth.AccessKey
+	var err error
+
+	// Queries forwarded for an access token keep the scope of the token
+	if accessToken != "" {
+		accessKey, err = qb.accessKeyManager.VerifyAccessToken(accessToken)
+	} else {
+		accessKey, err = qb.accessKeyManager.Get(accessKeyId)
+	}
 
 	if err != nil {
 		return &Query{}, err
 	}
 
+	// The conditions of statements are evaluated against the request the
+	// query was received with, which may have been on another node.
+	if authorizationContext != nil {
+		accessKey.SetAuthorizationContext(authorizationContext)
+	}
+
 	return NewQuery(
 		qb.cluster,
 		qb.databaseManager,
@@ -0,0 +1,71 @@
+package database_test
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/database"
+	"github.com/litebase/litebase/pkg/server"
+)
+
+func TestQueryBuilderBuildSetsAuthorizationContext(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		accessKey, err := app.Auth.AccessKeyManager.Create("Replica", []auth.AccessKeyStatement{
+			{
+				Effect:     "Allow",
+				Resource:   "*",
+				Actions:    []auth.Privilege{"*"},
+				Conditions: &auth.AccessKeyConditions{SourceIPs: []string{"10.0.0.0/8"}},
+			},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		queryBuilder := database.NewQueryBuilder(
+			app.Cluster,
+			app.Auth.AccessKeyManager,
+			app.DatabaseManager,
+			app.LogManager,
+		)
+
+		// A query forwarded by a replica is authorized against the request
+		// the replica received it with.
+		authorizationContext := &auth.AuthorizationContext{
+			Replica:  true,
+			SourceIP: netip.MustParseAddr("203.0.113.5"),
+			Time:     time.Now().UTC(),
+		}
+
+		query, err := queryBuilder.Build(
+			accessKey.AccessKeyID,
+			"",
+			"db",
+			"db",
+			"main",
+			"main",
+			"SELECT 1",
+			nil,
+			"1",
+			authorizationContext,
+		)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		scopedAccessKey := query.(*database.Query).AccessKey
+
+		if scopedAccessKey.AuthorizationContext() != authorizationContext {
+			t.Error("Expected the authorization context to be set on the access key")
+		}
+
+		if scopedAccessKey.AuthorizeForResource([]string{"database:db:branch:main"}, []auth.Privilege{auth.DatabasePrivilegeQuery}) {
+			t.Error("Expected the conditions to be evaluated against the forwarded source address")
+		}
+	})
+}
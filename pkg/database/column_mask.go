@@ -0,0 +1,31 @@
+package database
+
+import (
+	"crypto/sha256"
+
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/sqlite3"
+)
+
+// The SQL function that applies column masks in rewritten statements.
+const columnMaskFunction = "litebase_mask"
+
+// Apply a column mask to a value. The function is called with the value of
+// the column and the name of the mask, and NULL values are not masked.
+func (con *DatabaseConnection) maskColumnValue(args []*sqlite3.Column) (*sqlite3.Column, error) {
+	if args[0].ColumnType == sqlite3.ColumnTypeNull {
+		return nil, nil
+	}
+
+	if con.columnMaskSecret == nil {
+		secret := sha256.Sum256([]byte(con.config.EncryptionKey + ":" + con.databaseId))
+		con.columnMaskSecret = secret[:]
+	}
+
+	mask := auth.AccessKeyMask(args[1].Text())
+
+	return sqlite3.NewColumn(
+		sqlite3.ColumnTypeText,
+		[]byte(mask.Apply(string(args[0].Text()), con.columnMaskSecret)),
+	), nil
+}
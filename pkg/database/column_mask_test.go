@@ -0,0 +1,110 @@
+package database_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/database"
+	"github.com/litebase/litebase/pkg/server"
+	"github.com/litebase/litebase/pkg/sqlite3"
+)
+
+func TestColumnMasks(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		mock := test.MockDatabase(app)
+
+		connection, err := database.NewDatabaseConnection(app.DatabaseManager.ConnectionManager(), mock.DatabaseID, mock.DatabaseBranchID)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer connection.Close()
+
+		for _, statement := range []string{
+			"CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT, card TEXT, note TEXT)",
+			"INSERT INTO users (email, card, note) VALUES ('jane@example.com', '4111111111111111', 'vip'), (NULL, NULL, NULL)",
+		} {
+			if _, err := connection.Exec(statement, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		columnResource := func(column string) auth.AccessKeyResource {
+			return auth.AccessKeyResource("database:" + mock.DatabaseID + ":branch:" + mock.DatabaseBranchID + ":table:users:column:" + column)
+		}
+
+		accessKey := auth.NewAccessKey(
+			app.Auth.AccessKeyManager,
+			"test",
+			"test",
+			"",
+			[]auth.AccessKeyStatement{
+				{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}},
+				{Effect: "Allow", Resource: columnResource("email"), Actions: []auth.Privilege{auth.DatabasePrivilegeRead}, Mask: auth.AccessKeyMaskHash},
+				{Effect: "Allow", Resource: columnResource("card"), Actions: []auth.Privilege{auth.DatabasePrivilegeRead}, Mask: auth.AccessKeyMaskLast4},
+				{Effect: "Allow", Resource: columnResource("note"), Actions: []auth.Privilege{auth.DatabasePrivilegeRead}, Mask: auth.AccessKeyMaskRedact},
+			},
+		)
+
+		connection.WithAccessKey(accessKey)
+
+		t.Run("Read", func(t *testing.T) {
+			result, err := connection.Exec("SELECT email, card, note, id FROM users ORDER BY id", nil)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			email := string(result.Rows[0][0].Text())
+
+			if len(email) != 32 || strings.Contains(email, "jane") {
+				t.Errorf("Expected the email to be hashed, got %q", email)
+			}
+
+			if card := string(result.Rows[0][1].Text()); card != "************1111" {
+				t.Errorf("Expected the card to show the last 4 digits, got %q", card)
+			}
+
+			if note := string(result.Rows[0][2].Text()); note != "[REDACTED]" {
+				t.Errorf("Expected the note to be redacted, got %q", note)
+			}
+
+			if result.Rows[0][3].Int64() != 1 {
+				t.Error("Expected unmasked columns to be returned as is")
+			}
+
+			if result.Rows[1][0].ColumnType != sqlite3.ColumnTypeNull {
+				t.Error("Expected NULL values to not be masked")
+			}
+		})
+
+		t.Run("Expressions", func(t *testing.T) {
+			result, err := connection.Exec("SELECT count(*) FROM users WHERE email LIKE 'jane%' OR upper(card) = '4111111111111111'", nil)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Rows[0][0].Int64() != 0 {
+				t.Error("Expected expressions to only see the masked values")
+			}
+		})
+
+		t.Run("Write", func(t *testing.T) {
+			if _, err := connection.Exec("UPDATE users SET note = 'regular' WHERE id = 1", nil); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := connection.Exec("UPDATE users SET note = 'regular' WHERE email = 'jane@example.com'", nil); err == nil {
+				t.Error("Expected filtering an update on a masked column to be denied")
+			}
+
+			if _, err := connection.Exec("UPDATE users SET note = 'regular' WHERE id = 1 RETURNING email", nil); err == nil {
+				t.Error("Expected returning a masked column to be denied")
+			}
+		})
+	})
+}
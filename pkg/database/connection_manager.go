@@ -274,6 +274,59 @@ func (c *ConnectionManager) ForceCheckpoint(databaseId string, branchId string)
 	return nil
 }
 
+// Return the number of open connections across all databases.
+func (c *ConnectionManager) ConnectionCount() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	count := 0
+
+	for _, databaseGroup := range c.databases {
+		databaseGroup.lockMutex.RLock()
+
+		for _, branchConnections := range databaseGroup.branches {
+			count += len(branchConnections)
+		}
+
+		databaseGroup.lockMutex.RUnlock()
+	}
+
+	return count
+}
+
+// Force a checkpoint of every database branch that has open connections.
+func (c *ConnectionManager) ForceCheckpointAll() error {
+	c.mutex.RLock()
+
+	branches := map[string][]string{}
+
+	for databaseId, databaseGroup := range c.databases {
+		databaseGroup.lockMutex.RLock()
+
+		for branchId, branchConnections := range databaseGroup.branches {
+			if len(branchConnections) > 0 {
+				branches[databaseId] = append(branches[databaseId], branchId)
+			}
+		}
+
+		databaseGroup.lockMutex.RUnlock()
+	}
+
+	c.mutex.RUnlock()
+
+	for databaseId, branchIds := range branches {
+		for _, branchId := range branchIds {
+			err := c.ForceCheckpoint(databaseId, branchId)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 // Get a client connection for a given database and branch. If there are no
 // available connections, a new one will be created.
 func (c *ConnectionManager) Get(databaseId string, branchId string) (*ClientConnection, error) {
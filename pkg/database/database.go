@@ -50,6 +50,9 @@ func CreateDatabase(databaseManager *DatabaseManager, databaseName string, branc
 				Enabled: true,
 			},
 		},
+		Replication: DatabaseReplicationSettings{
+			Mode: DatabaseReplicationModeAsync,
+		},
 	}
 
 	database.CreatedAt = time.Now().UTC()
@@ -406,6 +409,10 @@ func (database *Database) InvalidateBranchCache(branchName string) {
 // MarshalJSON customizes the JSON representation of the Database struct.
 // It includes the URL for the primary branch.
 func (database *Database) MarshalJSON() ([]byte, error) {
+	return database.marshalJSON(nil)
+}
+
+func (database *Database) marshalJSON(usage *StorageUsage) ([]byte, error) {
 	type Alias Database
 
 	primaryBranch := database.PrimaryBranch()
@@ -416,13 +423,27 @@ func (database *Database) MarshalJSON() ([]byte, error) {
 
 	return json.Marshal(&struct {
 		*Alias
-		Url string `json:"url"`
+		Url   string        `json:"url"`
+		Usage *StorageUsage `json:"usage,omitempty"`
 	}{
 		Alias: (*Alias)(database),
 		Url:   database.Url(primaryBranch.Name),
+		Usage: usage,
 	})
 }
 
+// DatabaseWithUsage is the JSON representation of a database that includes
+// the total storage used by all of its branches.
+type DatabaseWithUsage struct {
+	Database *Database
+	Usage    *StorageUsage
+}
+
+// MarshalJSON includes the storage usage alongside the database fields.
+func (d DatabaseWithUsage) MarshalJSON() ([]byte, error) {
+	return d.Database.marshalJSON(d.Usage)
+}
+
 // Load and return the primary branch of the database
 func (database *Database) PrimaryBranch() *Branch {
 	if database == nil {
@@ -7,6 +7,7 @@ import (
 	"hash/crc32"
 	"log"
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
 
@@ -75,9 +76,12 @@ var DatabaseConnectionConfigStatements = func(config *config.Config) []string {
 
 type DatabaseConnection struct {
 	AccessKey              *auth.AccessKey
+	authorizerBypass       bool
+	authorizerTracer       func(AuthorizationCheck)
 	branchId               string
 	cancel                 context.CancelFunc
 	checkpointer           *Checkpointer
+	columnMaskSecret       []byte
 	committedAt            time.Time
 	config                 *config.Config
 	connectionManager      *ConnectionManager
@@ -90,7 +94,15 @@ type DatabaseConnection struct {
 	mutex                  *sync.Mutex
 	nodeId                 string
 	pageLogger             *storage.PageLogger
+	protectedTables        map[string]protectedTable
+	replication            *replicationCapture
 	resultPool             *sqlite3.ResultPool
+	rowPolicySchemaVersion int64
+	rowPolicySignature     string
+	rowPolicyStale         bool
+	rowPolicyTriggerCount  int64
+	schemaTables           map[string]schemaTable
+	schemaTablesVersion    int64
 	sqlite3                *sqlite3.Connection
 	statements             sync.Map
 	transactionalTimestamp int64
@@ -147,6 +159,7 @@ func NewDatabaseConnection(connectionManager *ConnectionManager, databaseId, bra
 		mutex:             &sync.Mutex{},
 		nodeId:            connectionManager.cluster.Node().ID,
 		pageLogger:        resources.PageLogger(),
+		replication:       newReplicationCapture(walManager),
 		resultPool:        resultPool,
 		statements:        sync.Map{},
 		tmpFileSystem:     connectionManager.cluster.TmpFS(),
@@ -485,6 +498,12 @@ func (con *DatabaseConnection) openSqliteConnection() error {
 		// Set authorizer immediately after opening
 		con.SetAuthorizer()
 
+		err = con.sqliteConnection().CreateFunction(columnMaskFunction, 2, con.maskColumnValue)
+
+		if err != nil {
+			return err
+		}
+
 		// Set the authorizer for the connection
 		con.setTimestamps()
 
@@ -558,7 +577,7 @@ func (con *DatabaseConnection) registerVFS() error {
 		con.VFSDatabaseHash(),
 		con.config.PageSize,
 		con.fileSystem,
-		con.walManager,
+		con.replication,
 	)
 
 	if err != nil {
@@ -601,16 +620,32 @@ func (con *DatabaseConnection) Rollback() error {
 		return ErrDatabaseConnectionClosed
 	}
 
+	con.rowPolicyStale = true
+
 	return con.sqliteConnection().Rollback()
 }
 
 // Set the authorizer for the database connection.
 func (c *DatabaseConnection) SetAuthorizer() {
 	c.sqliteConnection().Authorizer(func(actionCode int, arg1, arg2, arg3, arg4 string) int32 {
-		if c.AccessKey == nil {
+		// A rollback may restore row policy triggers that were dropped in the
+		// transaction.
+		if (actionCode == sqlite3.SQLITE_TRANSACTION || actionCode == sqlite3.SQLITE_SAVEPOINT) && arg1 == "ROLLBACK" {
+			c.rowPolicyStale = true
+		}
+
+		if c.AccessKey == nil || c.authorizerBypass || strings.HasPrefix(arg4, rowPolicyTriggerPrefix) {
+			return sqlite3.SQLITE_OK
+		}
+
+		if actionCode == sqlite3.SQLITE_FUNCTION && strings.EqualFold(arg2, columnMaskFunction) {
 			return sqlite3.SQLITE_OK
 		}
 
+		if c.deniedByAccessKeyPolicy(actionCode, arg1, arg2, arg3, arg4) {
+			return c.traceAuthorizer(actionCode, arg1, arg2, arg3, arg4, sqlite3.SQLITE_DENY, "denied by a row policy or column mask")
+		}
+
 		allowed := true
 		var err error
 
@@ -678,14 +713,14 @@ func (c *DatabaseConnection) SetAuthorizer() {
 		if err != nil {
 			c.sqliteConnection().SetAuthorizationError(err)
 
-			return sqlite3.SQLITE_DENY
+			return c.traceAuthorizer(actionCode, arg1, arg2, arg3, arg4, sqlite3.SQLITE_DENY, err.Error())
 		}
 
 		if allowed {
-			return sqlite3.SQLITE_OK
+			return c.traceAuthorizer(actionCode, arg1, arg2, arg3, arg4, sqlite3.SQLITE_OK, "")
 		}
 
-		return sqlite3.SQLITE_DENY
+		return c.traceAuthorizer(actionCode, arg1, arg2, arg3, arg4, sqlite3.SQLITE_DENY, "")
 	})
 }
 
@@ -742,10 +777,18 @@ func (con *DatabaseConnection) Statement(queryStatement string) (Statement, erro
 		AccessKeyCheckSum: accessKeyChecksum,
 	}
 
+	// Access key policies are applied on every call since the triggers they
+	// depend on are shared by all access keys that use the connection.
+	preparedStatement, err := con.applyAccessKeyPolicies(queryStatement)
+
+	if err != nil {
+		return Statement{}, err
+	}
+
 	statement, ok := con.statements.Load(statementKey)
 
 	if !ok {
-		statement, err = con.Prepare(con.context, queryStatement)
+		statement, err = con.Prepare(con.context, preparedStatement)
 
 		if err == nil {
 			con.statements.Store(statementKey, statement)
@@ -795,6 +838,8 @@ func (con *DatabaseConnection) Transaction(
 			handlerError := handler(con)
 
 			if handlerError != nil {
+				con.rowPolicyStale = true
+
 				err = con.sqliteConnection().Rollback()
 
 				if err != nil {
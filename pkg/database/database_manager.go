@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"log/slog"
@@ -24,6 +25,7 @@ type DatabaseManager struct {
 	pageLogManager         *storage.PageLogManager
 	resources              map[string]*DatabaseResources
 	SecretsManager         *auth.SecretsManager
+	storageAccountant      *StorageAccountant
 	systemDatabase         *SystemDatabase
 	systemDatabaseMutex    *sync.Mutex
 	WriteQueueManager      *WriteQueueManager
@@ -50,6 +52,10 @@ func NewDatabaseManager(
 
 	dbm.pageLogManager.SetCompactionFn(dbm.compaction)
 
+	dbm.pageLogManager.SetAppendFn(func(databaseId, branchId string, size int64) {
+		dbm.StorageAccountant().Add(databaseId, branchId, StorageUsagePageLogs, size)
+	})
+
 	RegisterDriver("litebase-internal", dbm.ConnectionManager())
 
 	return dbm
@@ -117,6 +123,12 @@ func (d *DatabaseManager) All() ([]*Database, error) {
 // database file system, while coordinating with the check pointer to ensure
 // that pages are not being written to while the compaction is happening.
 func (d *DatabaseManager) compaction() {
+	// Remote replicas must not write the compacted pages to the durable
+	// storage that is shared with the primary.
+	if d.Cluster.Node().IsRemoteReplica() {
+		return
+	}
+
 	for _, resource := range d.resources {
 		walmanager, err := resource.DatabaseWALManager()
 
@@ -211,6 +223,18 @@ func (d *DatabaseManager) Delete(database *Database) error {
 
 	resources := d.Resources(database.DatabaseID, primaryBranch.DatabaseBranchID)
 
+	// List the branches before they are removed from the system database so
+	// their storage usage can be forgotten.
+	branchIds := []string{primaryBranch.DatabaseBranchID}
+
+	if branches, err := database.Branches(); err == nil {
+		branchIds = branchIds[:0]
+
+		for _, branch := range branches {
+			branchIds = append(branchIds, branch.DatabaseBranchID)
+		}
+	}
+
 	// Close all database connections to the database before deleting it
 	d.ConnectionManager().CloseDatabaseConnections(database.DatabaseID)
 
@@ -252,9 +276,26 @@ func (d *DatabaseManager) Delete(database *Database) error {
 
 	resources.Remove()
 
+	for _, branchId := range branchIds {
+		d.StorageAccountant().Remove(database.DatabaseID, branchId)
+	}
+
 	return nil
 }
 
+// Wait for the writes that have been submitted to the write queues to
+// complete and checkpoint the databases so that their WAL is flushed to
+// storage.
+func (d *DatabaseManager) DrainWrites(ctx context.Context) error {
+	err := d.WriteQueueManager.Drain(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	return d.ConnectionManager().ForceCheckpointAll()
+}
+
 // Check if a database with the given name exists.
 func (d *DatabaseManager) Exists(name string) (bool, error) {
 	db, err := d.SystemDatabase().DB()
@@ -449,6 +490,43 @@ func (d *DatabaseManager) Remove(databaseId, branchId string) {
 	}
 }
 
+// Report the open connections and the latest WAL timestamp of each database
+// branch that is open on the node.
+func (d *DatabaseManager) Status() cluster.NodeStatusReport {
+	d.mutex.Lock()
+
+	resources := make([]*DatabaseResources, 0, len(d.resources))
+
+	for _, resource := range d.resources {
+		resources = append(resources, resource)
+	}
+
+	d.mutex.Unlock()
+
+	report := cluster.NodeStatusReport{
+		Connections: d.ConnectionManager().ConnectionCount(),
+		Databases:   []cluster.NodeDatabaseStatus{},
+	}
+
+	for _, resource := range resources {
+		resource.mutex.Lock()
+		walManager := resource.walManager
+		resource.mutex.Unlock()
+
+		if walManager == nil {
+			continue
+		}
+
+		report.Databases = append(report.Databases, cluster.NodeDatabaseStatus{
+			BranchID:     resource.BranchID,
+			DatabaseID:   resource.DatabaseID,
+			WALTimestamp: walManager.LatestVersion(),
+		})
+	}
+
+	return report
+}
+
 // Shutdown all of the database resources that have been created.
 func (d *DatabaseManager) ShutdownResources() error {
 	d.mutex.Lock()
@@ -463,6 +541,19 @@ func (d *DatabaseManager) ShutdownResources() error {
 	return nil
 }
 
+// Return the storage accountant used to calculate the storage usage of
+// databases. If it has not been created yet, create it.
+func (d *DatabaseManager) StorageAccountant() *StorageAccountant {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.storageAccountant == nil {
+		d.storageAccountant = NewStorageAccountant(d)
+	}
+
+	return d.storageAccountant
+}
+
 // Return the system database instance. If it has not been created yet, create it.
 func (d *DatabaseManager) SystemDatabase() *SystemDatabase {
 	d.systemDatabaseMutex.Lock()
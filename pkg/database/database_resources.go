@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"log/slog"
@@ -24,8 +25,12 @@ type DatabaseResources struct {
 	fileSystem         *storage.DurableDatabaseFileSystem
 	mutex              *sync.Mutex
 	pageLogger         *storage.PageLogger
+	prefetchCancel     context.CancelFunc
+	prefetchWg         sync.WaitGroup
 	resultPool         *sqlite3.ResultPool
 	rollbackLogger     *backups.RollbackLogger
+	shipper            *ReplicationLogShipper
+	shipperCancel      context.CancelFunc
 	tieredFS           *storage.FileSystem
 	transactionManager *TransactionManager
 	tmpFS              *storage.FileSystem
@@ -101,6 +106,10 @@ func (d *DatabaseResources) DatabaseWALManager() (*DatabaseWALManager, error) {
 		d.databaseManager.Cluster.NetworkFS(),
 	)
 
+	if err == nil && d.config.ReplicationShipping {
+		d.walManager.SetShipper(d.replicationShipper())
+	}
+
 	return d.walManager, err
 }
 
@@ -121,6 +130,10 @@ func (d *DatabaseResources) createFileSystem() (*storage.DurableDatabaseFileSyst
 		pageSize,
 	)
 
+	if d.fileSystem == nil {
+		return nil, fmt.Errorf("unable to create the file system for database %s", d.DatabaseID)
+	}
+
 	d.fileSystem.SetWriteHook(func(offset int64, data []byte) {
 		checkpointer, err := d.Checkpointer()
 
@@ -146,6 +159,18 @@ func (d *DatabaseResources) createFileSystem() (*storage.DurableDatabaseFileSyst
 		}
 	})
 
+	if d.config.ReplicationShipping {
+		shipper := d.replicationShipper()
+
+		d.fileSystem.SetPageHook(func(pageNumber, timestamp int64, data []byte) {
+			shipper.Append(ReplicationLogEntryPage, pageNumber, timestamp, data)
+		})
+	}
+
+	if d.config.DatabasePrefetch != "" {
+		d.prefetch(d.fileSystem)
+	}
+
 	return d.fileSystem, nil
 }
 
@@ -179,6 +204,47 @@ func (d *DatabaseResources) FileSystem() *storage.DurableDatabaseFileSystem {
 	return d.fileSystem
 }
 
+// Warm the tiered file system by prefetching the ranges of the database in
+// the background. Depending on the configuration, the ranges that were open
+// when the database was last closed or all of the ranges in the range index
+// will be loaded with bounded parallelism.
+func (d *DatabaseResources) prefetch(dfs *storage.DurableDatabaseFileSystem) {
+	var ctx context.Context
+
+	ctx, d.prefetchCancel = context.WithCancel(d.databaseManager.Cluster.Node().Context())
+
+	d.prefetchWg.Add(1)
+
+	go func() {
+		defer d.prefetchWg.Done()
+
+		var rangeNumbers []int64
+		var err error
+
+		switch d.config.DatabasePrefetch {
+		case config.DatabasePrefetchIndex:
+			// Every range in the range index is prefetched.
+		case config.DatabasePrefetchRecent:
+			rangeNumbers, err = dfs.RangeManager.RecentlyUsedRanges()
+
+			if err != nil {
+				slog.Error("Error reading recently used ranges", "error", err)
+				return
+			}
+		default:
+			return
+		}
+
+		// Every range in the index is prefetched when no recently used ranges
+		// have been recorded for the database.
+		err = dfs.Prefetch(ctx, rangeNumbers, d.config.PrefetchConcurrency)
+
+		if err != nil && err != context.Canceled {
+			slog.Error("Error prefetching database ranges", "database", d.DatabaseID, "branch", d.BranchID, "error", err)
+		}
+	}()
+}
+
 func (d *DatabaseResources) PageLogger() *storage.PageLogger {
 	if d.pageLogger != nil {
 		return d.pageLogger
@@ -212,6 +278,13 @@ func (d *DatabaseResources) Remove() {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 
+	// Stop any prefetching before the file system is shut down
+	if d.prefetchCancel != nil {
+		d.prefetchCancel()
+		d.prefetchWg.Wait()
+		d.prefetchCancel = nil
+	}
+
 	if d.transactionManager != nil {
 		d.transactionManager.Shutdown()
 	}
@@ -220,6 +293,12 @@ func (d *DatabaseResources) Remove() {
 		d.walManager.Shutdown()
 	}
 
+	// Stopping the shipper flushes the changes that have not been shipped yet
+	if d.shipperCancel != nil {
+		d.shipperCancel()
+		d.shipperCancel = nil
+	}
+
 	if d.rollbackLogger != nil {
 		err := d.rollbackLogger.Close()
 
@@ -259,10 +338,34 @@ func (d *DatabaseResources) Remove() {
 	d.fileSystem = nil
 	d.resultPool = nil
 	d.rollbackLogger = nil
+	d.shipper = nil
 	d.walManager = nil
 	d.pageLogger = nil
 }
 
+// Return the shipper that ships the changes of the database to object storage
+// for remote replicas, starting it if it is not running yet.
+// Note: Caller must hold d.mutex.Lock()
+func (d *DatabaseResources) replicationShipper() *ReplicationLogShipper {
+	if d.shipper != nil {
+		return d.shipper
+	}
+
+	var ctx context.Context
+
+	ctx, d.shipperCancel = context.WithCancel(d.databaseManager.Cluster.Node().Context())
+
+	d.shipper = NewReplicationLogShipper(
+		d.DatabaseID,
+		d.BranchID,
+		d.databaseManager.Cluster.ObjectFS(),
+	)
+
+	go d.shipper.Run(ctx)
+
+	return d.shipper
+}
+
 // Return the result pool for the database.
 func (d *DatabaseResources) ResultPool() *sqlite3.ResultPool {
 	d.mutex.Lock()
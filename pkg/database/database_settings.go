@@ -4,10 +4,21 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
+const (
+	DatabaseReplicationModeAsync  = "async"
+	DatabaseReplicationModeQuorum = "quorum"
+)
+
+// The default amount of time a write waits for replicas to acknowledge the WAL
+// frames when the database uses quorum replication.
+var DatabaseReplicationTimeout = 5 * time.Second
+
 type DatabaseSettings struct {
-	Backups DatabaseBackupSettings `json:"backups"`
+	Backups     DatabaseBackupSettings      `json:"backups"`
+	Replication DatabaseReplicationSettings `json:"replication"`
 }
 
 // Implement sql.Scanner interface for reading JSON from database
@@ -42,3 +53,26 @@ type DatabaseBackupSettings struct {
 type DatabaseIncrementalBackupSettings struct {
 	Enabled bool `json:"enabled"`
 }
+
+// The replication settings determine if a write returns as soon as it has been
+// committed on the primary, or only after a quorum of replicas have
+// acknowledged the WAL frames of the write.
+type DatabaseReplicationSettings struct {
+	Mode    string `json:"mode"`
+	Quorum  int    `json:"quorum"`
+	Timeout int64  `json:"timeout_ms"`
+}
+
+// Check if writes must be acknowledged by a quorum of replicas.
+func (s DatabaseReplicationSettings) IsQuorum() bool {
+	return s.Mode == DatabaseReplicationModeQuorum && s.Quorum > 0
+}
+
+// Return the amount of time a write waits for the quorum to be reached.
+func (s DatabaseReplicationSettings) WaitTimeout() time.Duration {
+	if s.Timeout <= 0 {
+		return DatabaseReplicationTimeout
+	}
+
+	return time.Duration(s.Timeout) * time.Millisecond
+}
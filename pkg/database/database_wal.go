@@ -403,8 +403,29 @@ func (wal *DatabaseWAL) WriteAt(p []byte, off int64) (n int, err error) {
 		return 0, err
 	}
 
+	if wal.lastKnownSize < 0 {
+		if info, err := file.Stat(); err == nil {
+			wal.lastKnownSize = info.Size()
+		}
+	}
+
 	n, err = file.WriteAt(p, off)
 
+	// Frames are appended to the WAL, so the storage usage of the branch
+	// grows by the bytes written past the end of the file.
+	if wal.lastKnownSize >= 0 && off+int64(n) > wal.lastKnownSize {
+		if wal.walManager != nil && wal.walManager.connectionManager != nil {
+			wal.walManager.connectionManager.databaseManager.StorageAccountant().Add(
+				wal.DatabaseID,
+				wal.BranchID,
+				StorageUsageWAL,
+				off+int64(n)-wal.lastKnownSize,
+			)
+		}
+
+		wal.lastKnownSize = off + int64(n)
+	}
+
 	if wal.shouldSync() {
 		wal.performAsynchronousSync()
 	}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -27,6 +28,8 @@ type DatabaseWALManager struct {
 	mutex                   *sync.RWMutex
 	networkFileSystem       *storage.FileSystem
 	node                    *cluster.Node
+	replicationSequence     int64
+	shipper                 *ReplicationLogShipper
 	walIndex                *storage.WALIndex
 	walUsage                map[int64]int64
 	walVersions             map[int64]*DatabaseWAL
@@ -179,6 +182,20 @@ func (w *DatabaseWALManager) createNew(timestamp int64) (*DatabaseWAL, error) {
 		return nil, ErrCreateWALVersionOnReplica
 	}
 
+	wal, err := w.addVersion(timestamp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	go w.publishTimestamp(timestamp)
+
+	return wal, nil
+}
+
+// Add a WAL version and update the WAL index.
+// Note: Caller must hold w.mutex.Lock()
+func (w *DatabaseWALManager) addVersion(timestamp int64) (*DatabaseWAL, error) {
 	// Add the new version
 	w.walVersions[timestamp] = NewDatabaseWAL(
 		w.node,
@@ -213,6 +230,31 @@ func (w *DatabaseWALManager) createNew(timestamp int64) (*DatabaseWAL, error) {
 	return w.walVersions[timestamp], nil
 }
 
+// Apply a WAL write that was shipped by the primary to the WAL version with
+// the given timestamp, adding the version if it does not exist yet. This is
+// used by replicas that acknowledge writes in quorum replication and by remote
+// replicas that do not share storage with the primary.
+func (w *DatabaseWALManager) ApplyReplicatedWrite(timestamp int64, p []byte, off int64) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	wal, ok := w.walVersions[timestamp]
+
+	if !ok {
+		var err error
+
+		wal, err = w.addVersion(timestamp)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := wal.WriteAt(p, off)
+
+	return err
+}
+
 // Find a WAL file for the specified timestamp. The WAL file should have a
 // timestamp that is less than or equal to the specified timestamp
 func (w *DatabaseWALManager) Get(timestamp int64) (*DatabaseWAL, error) {
@@ -364,6 +406,79 @@ func (w *DatabaseWALManager) ReadAt(timestamp int64, p []byte, off int64) (n int
 	return wal.ReadAt(p, off)
 }
 
+// Return the sequence number of the next WAL frame that is replicated.
+func (w *DatabaseWALManager) nextReplicationSequence() int64 {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.replicationSequence++
+
+	return w.replicationSequence
+}
+
+// Replicate WAL frames to the replicas and wait until a quorum of replicas
+// have acknowledged them.
+func (w *DatabaseWALManager) Replicate(ctx context.Context, quorum int, frames []messages.WALReplicationWriteMessage) error {
+	if len(frames) == 0 {
+		return nil
+	}
+
+	primary := w.node.Primary()
+
+	if primary == nil {
+		return cluster.ErrNotPrimary
+	}
+
+	nodeMessages := make([]messages.NodeMessage, len(frames))
+
+	for i, frame := range frames {
+		nodeMessages[i] = messages.NodeMessage{Data: frame}
+	}
+
+	return primary.PublishQuorum(ctx, quorum, nodeMessages...)
+}
+
+// Set the shipper used to ship the WAL frames that are written to object
+// storage for remote replicas.
+func (w *DatabaseWALManager) SetShipper(shipper *ReplicationLogShipper) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.shipper = shipper
+}
+
+// Return the timestamp of the latest WAL version.
+func (w *DatabaseWALManager) LatestVersion() int64 {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	return w.getLatestVersionUnsafe()
+}
+
+// Publish the timestamp of a new WAL version to the replicas so the primary
+// can track how far each replica is behind.
+func (w *DatabaseWALManager) publishTimestamp(timestamp int64) {
+	primary := w.node.Primary()
+
+	if primary == nil {
+		return
+	}
+
+	_, errorMap := primary.Publish(messages.NodeMessage{
+		Data: messages.WALIndexTimestampMessage{
+			BranchID:   w.BranchID,
+			DatabaseID: w.DatabaseID,
+			Timestamp:  timestamp,
+		},
+	})
+
+	for address, err := range errorMap {
+		if err != nil {
+			slog.Debug("Failed to publish WAL timestamp", "address", address, "error", err)
+		}
+	}
+}
+
 // Refresh the WAL manager by creating a new WAL version and running garbage
 // collection on the WAL files
 func (w *DatabaseWALManager) Refresh() error {
@@ -630,7 +745,13 @@ func (w *DatabaseWALManager) WriteAt(timestamp int64, p []byte, off int64) (n in
 		return 0, fmt.Errorf("cannot write to WAL, the version is not the latest: requested=%d, wal=%d, latest=%d", timestamp, wal.Timestamp(), w.getLatestVersionUnsafe())
 	}
 
-	return wal.WriteAt(p, off)
+	n, err = wal.WriteAt(p, off)
+
+	if err == nil && w.shipper != nil {
+		w.shipper.Append(ReplicationLogEntryWAL, off, timestamp, p[:n])
+	}
+
+	return n, err
 }
 
 // getOrCreateCurrent gets the current WAL or creates a new one if needed. All connections should
@@ -1,10 +1,15 @@
 package database_test
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/cluster/messages"
 	"github.com/litebase/litebase/pkg/database"
 	"github.com/litebase/litebase/pkg/server"
 )
@@ -423,3 +428,69 @@ func TestDatabaseWALManager_RunGarbageCollectionWithReplicas(t *testing.T) {
 		}
 	})
 }
+
+func TestDatabaseWALManager_Replicate(t *testing.T) {
+	test.Run(t, func() {
+		primary := test.NewTestServer(t)
+		defer primary.Shutdown()
+
+		db := test.MockDatabase(primary.App)
+
+		replica := test.NewTestServer(t)
+		defer replica.Shutdown()
+
+		walm, err := primary.App.DatabaseManager.Resources(
+			db.DatabaseID,
+			db.DatabaseBranchID,
+		).DatabaseWALManager()
+
+		if err != nil {
+			t.Fatalf("Error creating WAL manager: %v", err)
+		}
+
+		// The frames are only written by the replica, since the primary never
+		// writes to the new WAL version.
+		walVersion, err := walm.Create()
+
+		if err != nil {
+			t.Fatalf("Error creating new WAL version: %v", err)
+		}
+
+		frames := []messages.WALReplicationWriteMessage{}
+
+		for i := range 2 {
+			data := bytes.Repeat([]byte{byte(i + 1)}, 32)
+
+			frames = append(frames, messages.WALReplicationWriteMessage{
+				BranchID:   db.DatabaseBranchID,
+				DatabaseID: db.DatabaseID,
+				Data:       data,
+				Offset:     int64(i * len(data)),
+				Sequence:   int64(i + 1),
+				Sha256:     sha256.Sum256(data),
+				Timestamp:  walVersion.Timestamp(),
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err = walm.Replicate(ctx, 1, frames)
+
+		if err != nil {
+			t.Fatalf("Replicate should not return an error: %v", err)
+		}
+
+		data, err := replica.App.Cluster.NetworkFS().ReadFile(walVersion.Path)
+
+		if err != nil {
+			t.Fatalf("Error reading the WAL of the replica: %v", err)
+		}
+
+		expected := append(bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)...)
+
+		if !bytes.Equal(data, expected) {
+			t.Errorf("Expected the WAL of the replica to contain the replicated frames, got %v", data)
+		}
+	})
+}
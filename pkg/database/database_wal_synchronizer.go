@@ -76,3 +76,32 @@ func (d *DatabaseWalSynchronizer) SetWALIndexHeader(
 	// return databaseWALManager.SetWALIndexHeader(header)
 	return nil
 }
+
+// Write WAL frames replicated from the primary to the WAL version with the
+// given timestamp and sync them to storage.
+func (d *DatabaseWalSynchronizer) WriteAt(
+	databaseId, branchId string,
+	p []byte,
+	off, sequence, timestamp int64,
+) error {
+	databaseWALManager, err := d.databaseManager.Resources(databaseId, branchId).DatabaseWALManager()
+
+	if err != nil {
+		log.Println(err)
+
+		return err
+	}
+
+	if databaseWALManager == nil {
+		log.Println(ErrDatabaseWALNotFound)
+		return ErrDatabaseWALNotFound
+	}
+
+	err = databaseWALManager.ApplyReplicatedWrite(timestamp, p, off)
+
+	if err != nil {
+		return err
+	}
+
+	return databaseWALManager.Sync(timestamp)
+}
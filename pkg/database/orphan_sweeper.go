@@ -0,0 +1,571 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	internalStorage "github.com/litebase/litebase/internal/storage"
+	"github.com/litebase/litebase/pkg/backups"
+	"github.com/litebase/litebase/pkg/file"
+	"github.com/litebase/litebase/pkg/storage"
+)
+
+const (
+	OrphanedObjectBackup   = "backup"
+	OrphanedObjectBranch   = "branch"
+	OrphanedObjectDatabase = "database"
+	OrphanedObjectPageLog  = "page_log"
+	OrphanedObjectRange    = "range"
+)
+
+// Objects written within the grace period are never considered orphaned since
+// they may belong to a write, backup or database that is still being created.
+const OrphanSweeperGracePeriod = 1 * time.Hour
+
+// The file on the network file system that is locked while a sweep deletes
+// objects so that only one node in the cluster sweeps at a time.
+const OrphanSweeperLockPath = "_cluster/SWEEP"
+
+var ErrOrphanSweepInProgress = errors.New("orphaned objects are already being swept by another node")
+
+// Only one sweep on this node may delete objects at a time.
+var orphanSweepMutex = &sync.Mutex{}
+
+// An OrphanedObject is a file or directory left behind in storage that is no
+// longer referenced by the system database or the indexes of its branch.
+type OrphanedObject struct {
+	BranchID   string `json:"branch_id,omitempty"`
+	DatabaseID string `json:"database_id"`
+	Deleted    bool   `json:"deleted"`
+	Error      string `json:"error,omitempty"`
+	Path       string `json:"path"`
+	Reason     string `json:"reason"`
+	Size       int64  `json:"size"`
+	Storage    string `json:"storage"`
+	Type       string `json:"type"`
+
+	directory  bool
+	fileSystem *storage.FileSystem
+}
+
+// The GarbageCollectionReport lists the orphaned objects found by a sweep and
+// whether they were deleted.
+type GarbageCollectionReport struct {
+	CompletedAt time.Time        `json:"completed_at"`
+	DeletedSize int64            `json:"deleted_size"`
+	DryRun      bool             `json:"dry_run"`
+	Objects     []OrphanedObject `json:"objects"`
+	StartedAt   time.Time        `json:"started_at"`
+	TotalSize   int64            `json:"total_size"`
+}
+
+// The OrphanSweeper walks the database directories of each file system and
+// cross references what it finds with the system database, the data range
+// index of each branch and the page logger index of each branch.
+type OrphanSweeper struct {
+	databaseManager *DatabaseManager
+	GracePeriod     time.Duration
+}
+
+type orphanSweeperFileSystem struct {
+	fileSystem *storage.FileSystem
+	name       string
+}
+
+// Create a new instance of the orphan sweeper.
+func NewOrphanSweeper(databaseManager *DatabaseManager) *OrphanSweeper {
+	return &OrphanSweeper{
+		databaseManager: databaseManager,
+		GracePeriod:     OrphanSweeperGracePeriod,
+	}
+}
+
+// Find the orphaned objects in storage without deleting them.
+func (s *OrphanSweeper) Report() (*GarbageCollectionReport, error) {
+	return s.Sweep(true)
+}
+
+// Find the orphaned objects in storage. Unless this is a dry run, each of the
+// orphaned objects that is found is also deleted.
+func (s *OrphanSweeper) Sweep(dryRun bool) (*GarbageCollectionReport, error) {
+	if !dryRun {
+		orphanSweepMutex.Lock()
+		defer orphanSweepMutex.Unlock()
+
+		unlock, err := s.lock()
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer unlock()
+	}
+
+	report := &GarbageCollectionReport{
+		DryRun:    dryRun,
+		Objects:   []OrphanedObject{},
+		StartedAt: time.Now().UTC(),
+	}
+
+	cluster := s.databaseManager.Cluster
+
+	// Reads through the tiered file system are served by object storage while
+	// removals also clear any copies held on the high tier.
+	fileSystems := []orphanSweeperFileSystem{
+		{cluster.TieredFS(), "object"},
+		{cluster.NetworkFS(), "network"},
+	}
+
+	// List the directories before reading the system database so that data
+	// created during the sweep is never mistaken for orphaned data.
+	listings := make([]map[string][]string, len(fileSystems))
+
+	for i, fs := range fileSystems {
+		listing, err := s.listBranchDirectories(fs.fileSystem)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s storage: %w", fs.name, err)
+		}
+
+		listings[i] = listing
+	}
+
+	known, err := s.knownBranches()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, fs := range fileSystems {
+		for databaseId, branchIds := range listings[i] {
+			if databaseId == SystemDatabaseID {
+				continue
+			}
+
+			if _, ok := known[databaseId]; !ok {
+				if object, ok := s.directory(fs, databaseId, "", file.GetDatabaseRootDir(databaseId), OrphanedObjectDatabase, "database does not exist"); ok {
+					report.add(object)
+				}
+
+				continue
+			}
+
+			for _, branchId := range branchIds {
+				if _, ok := known[databaseId][branchId]; !ok {
+					if object, ok := s.directory(fs, databaseId, branchId, file.GetDatabaseBranchRootDir(databaseId, branchId), OrphanedObjectBranch, "branch does not exist"); ok {
+						report.add(object)
+					}
+				}
+			}
+		}
+	}
+
+	for databaseId, branchIds := range known {
+		for branchId := range branchIds {
+			objects, err := s.sweepBranch(fileSystems, databaseId, branchId)
+
+			if err != nil {
+				return nil, fmt.Errorf("failed to sweep branch %s of database %s: %w", branchId, databaseId, err)
+			}
+
+			for _, object := range objects {
+				report.add(object)
+			}
+		}
+	}
+
+	if !dryRun {
+		for i := range report.Objects {
+			s.delete(&report.Objects[i])
+
+			if report.Objects[i].Deleted {
+				report.DeletedSize += report.Objects[i].Size
+			}
+		}
+	}
+
+	report.CompletedAt = time.Now().UTC()
+
+	return report, nil
+}
+
+// Add an object to the report.
+func (r *GarbageCollectionReport) add(object OrphanedObject) {
+	r.Objects = append(r.Objects, object)
+	r.TotalSize += object.Size
+}
+
+// Delete an orphaned object from the file system it was found in.
+func (s *OrphanSweeper) delete(object *OrphanedObject) {
+	var err error
+
+	if object.directory {
+		err = object.fileSystem.RemoveAll(object.Path)
+	} else {
+		err = object.fileSystem.Remove(object.Path)
+	}
+
+	if err != nil && !os.IsNotExist(err) {
+		slog.Error("Error deleting orphaned object", "path", object.Path, "error", err)
+		object.Error = err.Error()
+
+		return
+	}
+
+	object.Deleted = true
+
+	if object.BranchID != "" {
+		s.databaseManager.StorageAccountant().Invalidate(object.DatabaseID, object.BranchID)
+	}
+}
+
+// Create an orphaned object for a directory, including the size of its
+// contents. Directories with contents modified within the grace period are
+// not considered orphaned.
+func (s *OrphanSweeper) directory(fs orphanSweeperFileSystem, databaseId, branchId, path, objectType, reason string) (OrphanedObject, bool) {
+	size, modifiedAt, err := directorySize(fs.fileSystem, path)
+
+	if err != nil {
+		slog.Warn("Error calculating orphaned directory size", "path", path, "error", err)
+
+		return OrphanedObject{}, false
+	}
+
+	if !s.expired(modifiedAt.UnixNano()) {
+		return OrphanedObject{}, false
+	}
+
+	return OrphanedObject{
+		BranchID:   branchId,
+		DatabaseID: databaseId,
+		Path:       path,
+		Reason:     reason,
+		Size:       size,
+		Storage:    fs.name,
+		Type:       objectType,
+		directory:  true,
+		fileSystem: fs.fileSystem,
+	}, true
+}
+
+// Lock the sweep lock file on the network file system, which is shared by all
+// of the nodes in the cluster. The returned function releases the lock.
+func (s *OrphanSweeper) lock() (func(), error) {
+	networkFS := s.databaseManager.Cluster.NetworkFS()
+
+tryOpen:
+	lockFile, err := networkFS.OpenFile(OrphanSweeperLockPath, os.O_CREATE|os.O_RDWR, 0600)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			if err := networkFS.MkdirAll(filepath.Dir(OrphanSweeperLockPath), 0750); err != nil {
+				return nil, err
+			}
+
+			goto tryOpen
+		}
+
+		return nil, err
+	}
+
+	handle, ok := lockFile.(*os.File)
+
+	if !ok {
+		lockFile.Close()
+
+		return nil, fmt.Errorf("the network file system does not support locking")
+	}
+
+	err = syscall.Flock(int(handle.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+
+	if err != nil {
+		lockFile.Close()
+
+		if errors.Is(err, syscall.EWOULDBLOCK) || errors.Is(err, syscall.EAGAIN) {
+			return nil, ErrOrphanSweepInProgress
+		}
+
+		return nil, err
+	}
+
+	return func() {
+		if err := syscall.Flock(int(handle.Fd()), syscall.LOCK_UN); err != nil {
+			slog.Debug("Failed to unlock sweep lock file", "error", err)
+		}
+
+		if err := lockFile.Close(); err != nil {
+			slog.Debug("Failed to close sweep lock file", "error", err)
+		}
+	}, nil
+}
+
+// Determine if a versioned object is old enough to be considered orphaned.
+func (s *OrphanSweeper) expired(timestamp int64) bool {
+	return timestamp < time.Now().UTC().Add(-s.GracePeriod).UnixNano()
+}
+
+// Return the database and branch ids that exist in the system database.
+func (s *OrphanSweeper) knownBranches() (map[string]map[string]struct{}, error) {
+	databases, err := s.databaseManager.All()
+
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]map[string]struct{}, len(databases))
+
+	for _, database := range databases {
+		branches, err := database.Branches()
+
+		if err != nil {
+			return nil, err
+		}
+
+		known[database.DatabaseID] = make(map[string]struct{}, len(branches))
+
+		for _, branch := range branches {
+			known[database.DatabaseID][branch.DatabaseBranchID] = struct{}{}
+		}
+	}
+
+	return known, nil
+}
+
+// List the database directories of a file system and the branch directories
+// within each of them.
+func (s *OrphanSweeper) listBranchDirectories(fileSystem *storage.FileSystem) (map[string][]string, error) {
+	listing := map[string][]string{}
+
+	databaseEntries, err := fileSystem.ReadDir(file.DatabaseDirectory())
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return listing, nil
+		}
+
+		return nil, err
+	}
+
+	for _, databaseEntry := range databaseEntries {
+		if !databaseEntry.IsDir() {
+			continue
+		}
+
+		branchEntries, err := fileSystem.ReadDir(file.GetDatabaseRootDir(databaseEntry.Name()))
+
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		listing[databaseEntry.Name()] = []string{}
+
+		for _, branchEntry := range branchEntries {
+			if branchEntry.IsDir() {
+				listing[databaseEntry.Name()] = append(listing[databaseEntry.Name()], branchEntry.Name())
+			}
+		}
+	}
+
+	return listing, nil
+}
+
+// Find the orphaned objects of an existing branch. The indexes of the branch
+// are read directly from storage so sweeping does not open every branch.
+func (s *OrphanSweeper) sweepBranch(fileSystems []orphanSweeperFileSystem, databaseId, branchId string) ([]OrphanedObject, error) {
+	var entries []internalStorage.DirEntry
+
+	objects := []OrphanedObject{}
+
+	// Range versions that are not referenced by the index or range log. A
+	// branch without a range index is skipped rather than treating all of its
+	// ranges as orphaned.
+	referencedRanges, err := storage.ReadReferencedRanges(fileSystems[0].fileSystem, databaseId, branchId)
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	rangeDirectory := file.GetDatabaseFileDir(databaseId, branchId)
+
+	if referencedRanges != nil {
+		entries, err = fileSystems[0].fileSystem.ReadDir(rangeDirectory)
+
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), "_") {
+			continue
+		}
+
+		_, timestamp, found := strings.Cut(entry.Name(), "_")
+
+		if !found {
+			continue
+		}
+
+		version, err := strconv.ParseInt(timestamp, 10, 64)
+
+		if err != nil || !s.expired(version) {
+			continue
+		}
+
+		if _, ok := referencedRanges[entry.Name()]; ok {
+			continue
+		}
+
+		objects = append(objects, s.file(fileSystems[0], databaseId, branchId, rangeDirectory+entry.Name(), entry, OrphanedObjectRange, "range version is not in the range index"))
+	}
+
+	// Page logs that are not referenced by the page logger index
+	indexedPageLogs, err := storage.ReadIndexedPageLogs(fileSystems[1].fileSystem, databaseId, branchId)
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	pageLogDirectory := fmt.Sprintf("%slogs/page/", file.GetDatabaseFileBaseDir(databaseId, branchId))
+	entries = nil
+
+	if indexedPageLogs != nil {
+		entries, err = fileSystems[1].fileSystem.ReadDir(pageLogDirectory)
+
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), "PAGE_LOG_") {
+			continue
+		}
+
+		name := strings.TrimSuffix(entry.Name(), "_INDEX")
+		parts := strings.Split(name, "_")
+
+		if len(parts) != 4 {
+			continue
+		}
+
+		version, err := strconv.ParseInt(parts[3], 10, 64)
+
+		if err != nil || !s.expired(version) {
+			continue
+		}
+
+		if _, ok := indexedPageLogs[name]; ok {
+			continue
+		}
+
+		objects = append(objects, s.file(fileSystems[1], databaseId, branchId, pageLogDirectory+entry.Name(), entry, OrphanedObjectPageLog, "page log is not in the page logger index"))
+	}
+
+	// Backups that did not complete. Skip the branch while a backup is running.
+	lock := backups.GetBackupLock(file.DatabaseHash(databaseId, branchId))
+
+	if !lock.TryLock() {
+		return objects, nil
+	}
+
+	defer lock.Unlock()
+
+	backupDirectory := file.GetDatabaseBackupsDirectory(databaseId, branchId)
+
+	entries, err = fileSystems[0].fileSystem.ReadDir(backupDirectory)
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		path := fmt.Sprintf("%s%s/", backupDirectory, entry.Name())
+
+		if _, err := strconv.ParseInt(entry.Name(), 10, 64); err != nil {
+			if object, ok := s.directory(fileSystems[0], databaseId, branchId, path, OrphanedObjectBackup, "backup directory is not a restore point"); ok {
+				objects = append(objects, object)
+			}
+
+			continue
+		}
+
+		complete, err := s.backupComplete(fileSystems[0].fileSystem, path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !complete {
+			// Backups running on other nodes are not covered by the backup
+			// lock, so only backups older than the grace period are removed.
+			if object, ok := s.directory(fileSystems[0], databaseId, branchId, path, OrphanedObjectBackup, "backup is incomplete"); ok {
+				objects = append(objects, object)
+			}
+		}
+	}
+
+	return objects, nil
+}
+
+// A backup is complete when it has at least one part and none of its parts
+// are empty.
+func (s *OrphanSweeper) backupComplete(fileSystem *storage.FileSystem, path string) (bool, error) {
+	entries, err := fileSystem.ReadDir(path)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	parts := 0
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), "backup-") {
+			continue
+		}
+
+		if entry.Info() == nil || entry.Info().Size() == 0 {
+			return false, nil
+		}
+
+		parts++
+	}
+
+	return parts > 0, nil
+}
+
+// Create an orphaned object for a single file.
+func (s *OrphanSweeper) file(fs orphanSweeperFileSystem, databaseId, branchId, path string, entry internalStorage.DirEntry, objectType, reason string) OrphanedObject {
+	var size int64
+
+	if entry.Info() != nil {
+		size = entry.Info().Size()
+	}
+
+	return OrphanedObject{
+		BranchID:   branchId,
+		DatabaseID: databaseId,
+		Path:       path,
+		Reason:     reason,
+		Size:       size,
+		Storage:    fs.name,
+		Type:       objectType,
+		fileSystem: fs.fileSystem,
+	}
+}
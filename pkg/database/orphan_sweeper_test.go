@@ -0,0 +1,181 @@
+package database_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/database"
+	"github.com/litebase/litebase/pkg/file"
+	"github.com/litebase/litebase/pkg/server"
+)
+
+func TestOrphanSweeper(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		t.Run("ReportsOrphanedDatabases", func(t *testing.T) {
+			path := fmt.Sprintf("%sorphaned/main/logs/page/PAGE_LOG_1_1", file.DatabaseDirectory())
+
+			err := app.Cluster.NetworkFS().WriteFile(path, make([]byte, 4096), 0600)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			sweeper := database.NewOrphanSweeper(app.DatabaseManager)
+			sweeper.GracePeriod = 0
+
+			report, err := sweeper.Report()
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if !report.DryRun {
+				t.Error("Expected the report to be a dry run")
+			}
+
+			found := false
+
+			for _, object := range report.Objects {
+				if object.Type == database.OrphanedObjectDatabase && object.DatabaseID == "orphaned" {
+					found = true
+
+					if object.Size != 4096 {
+						t.Errorf("Expected orphaned database size to be 4096, got %d", object.Size)
+					}
+				}
+			}
+
+			if !found {
+				t.Fatal("Expected the orphaned database to be reported")
+			}
+
+			if _, err := app.Cluster.NetworkFS().Stat(path); err != nil {
+				t.Errorf("Expected the orphaned database to remain after a report, got %v", err)
+			}
+		})
+
+		t.Run("ReportsOrphanedRangeVersions", func(t *testing.T) {
+			mock := test.MockDatabase(app)
+
+			// Ensure the range index of the branch exists
+			app.DatabaseManager.Resources(mock.DatabaseID, mock.DatabaseBranchID).FileSystem()
+
+			oldVersion := time.Now().Add(-2 * time.Hour).UnixNano()
+			newVersion := time.Now().UnixNano()
+
+			oldPath := fmt.Sprintf("%s%010d_%d", file.GetDatabaseFileDir(mock.DatabaseID, mock.DatabaseBranchID), 99, oldVersion)
+			newPath := fmt.Sprintf("%s%010d_%d", file.GetDatabaseFileDir(mock.DatabaseID, mock.DatabaseBranchID), 99, newVersion)
+
+			for _, path := range []string{oldPath, newPath} {
+				if err := app.Cluster.ObjectFS().WriteFile(path, make([]byte, 4096), 0600); err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+			}
+
+			report, err := database.NewOrphanSweeper(app.DatabaseManager).Sweep(false)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			var oldFound, newFound bool
+
+			for _, object := range report.Objects {
+				if object.Path == oldPath {
+					oldFound = true
+
+					if !object.Deleted {
+						t.Errorf("Expected the orphaned range to be deleted, got error %q", object.Error)
+					}
+				}
+
+				if object.Path == newPath {
+					newFound = true
+				}
+			}
+
+			if !oldFound {
+				t.Error("Expected the abandoned range version to be reported")
+			}
+
+			if newFound {
+				t.Error("Expected range versions within the grace period to be ignored")
+			}
+
+			if _, err := app.Cluster.ObjectFS().Stat(newPath); err != nil {
+				t.Errorf("Expected the recent range version to remain, got %v", err)
+			}
+		})
+
+		t.Run("IgnoresRecentOrphanedDatabases", func(t *testing.T) {
+			path := fmt.Sprintf("%srecent/main/logs/page/PAGE_LOG_1_1", file.DatabaseDirectory())
+
+			err := app.Cluster.NetworkFS().WriteFile(path, make([]byte, 4096), 0600)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			report, err := database.NewOrphanSweeper(app.DatabaseManager).Sweep(false)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			for _, object := range report.Objects {
+				if object.DatabaseID == "recent" {
+					t.Errorf("Expected data within the grace period to be ignored, got %s", object.Path)
+				}
+			}
+
+			if _, err := app.Cluster.NetworkFS().Stat(path); err != nil {
+				t.Errorf("Expected the recent database to remain, got %v", err)
+			}
+		})
+
+		t.Run("RefusesToSweepWhileLocked", func(t *testing.T) {
+			lockFile, err := app.Cluster.NetworkFS().OpenFile(database.OrphanSweeperLockPath, os.O_CREATE|os.O_RDWR, 0600)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			defer lockFile.Close()
+
+			if err := syscall.Flock(int(lockFile.(*os.File).Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			_, err = database.NewOrphanSweeper(app.DatabaseManager).Sweep(false)
+
+			if !errors.Is(err, database.ErrOrphanSweepInProgress) {
+				t.Errorf("Expected ErrOrphanSweepInProgress, got %v", err)
+			}
+
+			if _, err := database.NewOrphanSweeper(app.DatabaseManager).Report(); err != nil {
+				t.Errorf("Expected reports to run while a sweep is in progress, got %v", err)
+			}
+		})
+
+		t.Run("IgnoresExistingBranches", func(t *testing.T) {
+			mock := test.MockDatabase(app)
+
+			report, err := database.NewOrphanSweeper(app.DatabaseManager).Report()
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			for _, object := range report.Objects {
+				if object.DatabaseID == mock.DatabaseID {
+					t.Errorf("Expected no orphaned objects for database %s, got %s", mock.DatabaseID, object.Path)
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,360 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/litebase/litebase/pkg/sqlite3"
+)
+
+/*
+A protected table is a table with a row policy or masked columns for the access
+key that is using the connection.
+
+Reads are limited by rewriting each statement that references a protected
+table. A common table expression with the name of the table is prepended to the
+statement, which shadows the table with only the rows that satisfy the row
+policy and with the masked columns transformed:
+
+	WITH "users" AS (SELECT "id", litebase_mask(CAST("email" AS TEXT), 'hash') AS "email" FROM main."users" WHERE (tenant_id = 'a')) SELECT * FROM users
+
+Writes to tables with row policies are limited by temporary triggers, see
+row_policy.go. Masked columns can only be read through the rewritten statement,
+so they can not be used in the WHERE or RETURNING clause of an UPDATE or
+DELETE statement.
+*/
+
+var (
+	ErrProtectedTableQualified = errors.New("tables with row policies can not be referenced with a schema name")
+	ErrProtectedTableReplace   = errors.New("REPLACE can not be used on tables with row policies")
+	ErrProtectedTableStatement = errors.New("this statement can not be used on tables with row policies")
+)
+
+type protectedTable struct {
+	columns   []string
+	condition string
+	masked    map[string]bool
+	name      string
+}
+
+type schemaTable struct {
+	columns []string
+	name    string
+}
+
+// Apply the row policies and column masks of the access key of the connection
+// to the statement, installing the row policy triggers if needed, and return
+// the statement that should be prepared.
+func (con *DatabaseConnection) applyAccessKeyPolicies(statement string) (string, error) {
+	var conditions map[string]string
+
+	if con.AccessKey != nil {
+		conditions = con.AccessKey.RowPolicyConditions(con.databaseId, con.branchId)
+	}
+
+	con.protectedTables = nil
+
+	if err := con.syncRowPolicyTriggers(conditions); err != nil {
+		return "", err
+	}
+
+	if len(conditions) == 0 && (con.AccessKey == nil || !con.AccessKey.HasColumnMasks()) {
+		return statement, nil
+	}
+
+	tables, err := con.loadSchemaTables()
+
+	if err != nil {
+		return "", err
+	}
+
+	protectedTables := map[string]protectedTable{}
+
+	for key, table := range tables {
+		protected := protectedTable{
+			condition: conditions[key],
+			masked:    map[string]bool{},
+			name:      table.name,
+		}
+
+		for _, column := range table.columns {
+			if allowed, _ := con.AccessKey.CanRead(con.databaseId, con.branchId, table.name, column); !allowed {
+				continue
+			}
+
+			mask := con.AccessKey.ColumnMask(con.databaseId, con.branchId, table.name, column)
+
+			if mask == "" {
+				protected.columns = append(protected.columns, quoteIdentifier(column))
+
+				continue
+			}
+
+			protected.masked[strings.ToLower(column)] = true
+			protected.columns = append(protected.columns, fmt.Sprintf(
+				"%s(CAST(%s AS TEXT), %s) AS %s",
+				columnMaskFunction,
+				quoteIdentifier(column),
+				quoteLiteral(string(mask)),
+				quoteIdentifier(column),
+			))
+		}
+
+		if protected.condition != "" || len(protected.masked) > 0 {
+			protectedTables[key] = protected
+		}
+	}
+
+	con.protectedTables = protectedTables
+
+	return rewriteProtectedTables(statement, sqlite3.Tokenize(statement), protectedTables)
+}
+
+// Determine if the authorizer should deny an action to protect the row
+// policies and masked columns of the connection.
+func (con *DatabaseConnection) deniedByAccessKeyPolicy(actionCode int, arg1, arg2, arg3, arg4 string) bool {
+	switch actionCode {
+	// Objects named like the row policy triggers would not be authorized.
+	case sqlite3.SQLITE_CREATE_TRIGGER,
+		sqlite3.SQLITE_CREATE_TEMP_TRIGGER,
+		sqlite3.SQLITE_CREATE_VIEW,
+		sqlite3.SQLITE_CREATE_TEMP_VIEW:
+		if strings.HasPrefix(strings.ToLower(arg1), rowPolicyTriggerPrefix) {
+			return true
+		}
+	}
+
+	if len(con.protectedTables) == 0 {
+		return false
+	}
+
+	table, isProtected := con.protectedTables[strings.ToLower(arg1)]
+
+	switch actionCode {
+	case sqlite3.SQLITE_READ:
+		if !isProtected || !strings.EqualFold(arg3, "main") || strings.EqualFold(arg4, arg1) {
+			return false
+		}
+
+		// Masked columns may only be read where the table is shadowed, and
+		// views and triggers would bypass the row policy of the table.
+		return table.masked[strings.ToLower(arg2)] || (arg4 != "" && table.condition != "")
+	case sqlite3.SQLITE_CREATE_VIEW, sqlite3.SQLITE_CREATE_TEMP_VIEW:
+		return isProtected
+	}
+
+	return false
+}
+
+// Return the tables of the main schema with their columns, keyed by the lower
+// case table name. The tables are cached until the schema changes.
+func (con *DatabaseConnection) loadSchemaTables() (map[string]schemaTable, error) {
+	con.authorizerBypass = true
+
+	defer func() {
+		con.authorizerBypass = false
+	}()
+
+	result, err := con.sqliteConnection().Exec(con.context, "SELECT schema_version FROM main.pragma_schema_version")
+
+	if err != nil {
+		return nil, err
+	}
+
+	schemaVersion := result.Rows[0][0].Int64()
+
+	if con.schemaTables != nil && con.schemaTablesVersion == schemaVersion {
+		return con.schemaTables, nil
+	}
+
+	result, err = con.sqliteConnection().Exec(
+		con.context,
+		"SELECT m.name, p.name FROM main.sqlite_schema AS m, pragma_table_info(m.name, 'main') AS p WHERE m.type = 'table' ORDER BY m.name, p.cid",
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tables := map[string]schemaTable{}
+
+	for _, row := range result.Rows {
+		name := string(row[0].Text())
+		key := strings.ToLower(name)
+		table := tables[key]
+
+		table.name = name
+		table.columns = append(table.columns, string(row[1].Text()))
+
+		tables[key] = table
+	}
+
+	con.schemaTables = tables
+	con.schemaTablesVersion = schemaVersion
+
+	return tables, nil
+}
+
+// Return the index of the next token that is not whitespace or a comment.
+func nextToken(tokens []sqlite3.Token, i int) int {
+	for i < len(tokens) && tokens[i].IsSpace() {
+		i++
+	}
+
+	return i
+}
+
+// Return the index of the previous token that is not whitespace or a comment.
+func previousToken(tokens []sqlite3.Token, i int) int {
+	for i >= 0 && tokens[i].IsSpace() {
+		i--
+	}
+
+	return i
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// Rewrite the statement so that each protected table that it references is
+// shadowed by the rows and columns the access key can see. Statements that can
+// not be rewritten are rejected when they reference a table with a row policy,
+// while masked columns are protected by the authorizer.
+func rewriteProtectedTables(statement string, tokens []sqlite3.Token, tables map[string]protectedTable) (string, error) {
+	referenced := map[string]bool{}
+	hasCondition := false
+
+	for i, token := range tokens {
+		if !token.IsIdentifier() {
+			continue
+		}
+
+		key := strings.ToLower(token.Value())
+		table, ok := tables[key]
+
+		if !ok {
+			continue
+		}
+
+		if previous := previousToken(tokens, i-1); table.condition != "" && previous >= 0 && tokens[previous].IsPunctuation(".") {
+			if schema := previousToken(tokens, previous-1); schema >= 0 &&
+				tokens[schema].IsIdentifier() &&
+				strings.EqualFold(tokens[schema].Value(), "main") {
+				return "", ErrProtectedTableQualified
+			}
+		}
+
+		referenced[key] = true
+		hasCondition = hasCondition || table.condition != ""
+	}
+
+	if len(referenced) == 0 {
+		return statement, nil
+	}
+
+	if hasCondition {
+		for i, token := range tokens {
+			if token.IsKeyword("REPLACE") {
+				if next := nextToken(tokens, i+1); next >= len(tokens) || !tokens[next].IsPunctuation("(") {
+					return "", ErrProtectedTableReplace
+				}
+			}
+		}
+	}
+
+	// Row policies may reference other protected tables.
+	for changed := true; changed; {
+		changed = false
+
+		for key := range referenced {
+			for _, token := range sqlite3.Tokenize(tables[key].condition) {
+				name := strings.ToLower(token.Value())
+
+				if _, ok := tables[name]; ok && token.IsIdentifier() && !referenced[name] {
+					referenced[name] = true
+					changed = true
+				}
+			}
+		}
+	}
+
+	start := nextToken(tokens, 0)
+
+	if start < len(tokens) && tokens[start].IsKeyword("EXPLAIN") {
+		start = nextToken(tokens, start+1)
+
+		if start < len(tokens) && tokens[start].IsKeyword("QUERY") {
+			start = nextToken(tokens, nextToken(tokens, start+1)+1)
+		}
+	}
+
+	if start >= len(tokens) ||
+		(!tokens[start].IsKeyword("SELECT") &&
+			!tokens[start].IsKeyword("VALUES") &&
+			!tokens[start].IsKeyword("INSERT") &&
+			!tokens[start].IsKeyword("UPDATE") &&
+			!tokens[start].IsKeyword("DELETE") &&
+			!tokens[start].IsKeyword("WITH")) {
+		if hasCondition {
+			return "", ErrProtectedTableStatement
+		}
+
+		return statement, nil
+	}
+
+	leading := tokens[start]
+	keys := make([]string, 0, len(referenced))
+
+	for key := range referenced {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	expressions := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		table := tables[key]
+		columns := "*"
+		condition := "1"
+
+		if len(table.columns) > 0 {
+			columns = strings.Join(table.columns, ", ")
+		}
+
+		if table.condition != "" {
+			condition = table.condition
+		}
+
+		expressions = append(expressions, fmt.Sprintf(
+			"%s AS (SELECT %s FROM main.%s WHERE (%s))",
+			quoteIdentifier(key),
+			columns,
+			quoteIdentifier(table.name),
+			condition,
+		))
+	}
+
+	cte := strings.Join(expressions, ", ")
+
+	if !leading.IsKeyword("WITH") {
+		return statement[:leading.Start] + "WITH " + cte + " " + statement[leading.Start:], nil
+	}
+
+	position := start
+
+	if next := nextToken(tokens, start+1); next < len(tokens) && tokens[next].IsKeyword("RECURSIVE") {
+		position = next
+	}
+
+	insertAt := tokens[position].Start + len(tokens[position].Text)
+
+	return statement[:insertAt] + " " + cte + "," + statement[insertAt:], nil
+}
@@ -0,0 +1,205 @@
+package database
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/litebase/litebase/internal/utils"
+)
+
+const (
+	ReplicationLogEntryPage byte = 1
+	ReplicationLogEntryWAL  byte = 2
+
+	replicationLogDirectory = "_replication/"
+)
+
+var (
+	ErrInvalidReplicationSegment = errors.New("invalid replication log segment")
+
+	replicationSegmentMagic = []byte("LBRS")
+)
+
+// A ReplicationLogEntry is a single change that the primary ships to object
+// storage for remote replicas. WAL entries contain a write to a WAL version at
+// an offset, page entries contain a page version written to the page logs.
+type ReplicationLogEntry struct {
+	Data      []byte
+	Kind      byte
+	Offset    int64
+	Timestamp int64
+}
+
+// A ReplicationSegment is a batch of entries that is stored as a single object.
+// Segments are numbered sequentially per database branch and must be applied
+// in order.
+type ReplicationSegment struct {
+	Entries   []ReplicationLogEntry
+	Sequence  int64
+	ShippedAt int64
+}
+
+// Return the directory that contains the replication log segments of a
+// database branch.
+func replicationLogPath(databaseId, branchId string) string {
+	return fmt.Sprintf("%s%s/%s/", replicationLogDirectory, databaseId, branchId)
+}
+
+// Return the directory that contains the position acknowledged by each remote
+// replica of a database branch. Segments are retained until every replica has
+// acknowledged them.
+func replicationAckPath(databaseId, branchId string) string {
+	return fmt.Sprintf("%sacks/", replicationLogPath(databaseId, branchId))
+}
+
+// Return the directory that contains a marker for every database branch that
+// is shipping its changes.
+func replicationLogBranchesPath() string {
+	return fmt.Sprintf("%sbranches/", replicationLogDirectory)
+}
+
+// Decode a position of a replication log. An empty position is zero.
+func decodeReplicationPosition(data []byte) (int64, error) {
+	if len(data) != 8 {
+		return 0, nil
+	}
+
+	return utils.SafeUint64ToInt64(binary.LittleEndian.Uint64(data))
+}
+
+// Encode a position of a replication log.
+func encodeReplicationPosition(position int64) ([]byte, error) {
+	value, err := utils.SafeInt64ToUint64(position)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint64(data, value)
+
+	return data, nil
+}
+
+// Return the object name of a replication log segment. The sequence is zero
+// padded so segments are listed in order.
+func replicationSegmentName(sequence int64) string {
+	return fmt.Sprintf("%020d", sequence)
+}
+
+// Parse the sequence of a replication log segment from its object name.
+func replicationSegmentSequence(name string) (int64, bool) {
+	if len(name) != 20 {
+		return 0, false
+	}
+
+	sequence, err := strconv.ParseInt(name, 10, 64)
+
+	if err != nil {
+		return 0, false
+	}
+
+	return sequence, true
+}
+
+// Encode the segment into its binary representation. The layout is a magic
+// number, the sequence, the shipped at timestamp and the number of entries,
+// followed by the entries and a sha256 checksum of everything before it.
+func (s *ReplicationSegment) Encode() ([]byte, error) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+
+	count, err := utils.SafeIntToUint32(len(s.Entries))
+
+	if err != nil {
+		return nil, err
+	}
+
+	buffer.Write(replicationSegmentMagic)
+	binary.Write(buffer, binary.LittleEndian, s.Sequence)
+	binary.Write(buffer, binary.LittleEndian, s.ShippedAt)
+	binary.Write(buffer, binary.LittleEndian, count)
+
+	for _, entry := range s.Entries {
+		length, err := utils.SafeIntToUint32(len(entry.Data))
+
+		if err != nil {
+			return nil, err
+		}
+
+		buffer.WriteByte(entry.Kind)
+		binary.Write(buffer, binary.LittleEndian, entry.Offset)
+		binary.Write(buffer, binary.LittleEndian, entry.Timestamp)
+		binary.Write(buffer, binary.LittleEndian, length)
+		buffer.Write(entry.Data)
+	}
+
+	checksum := sha256.Sum256(buffer.Bytes())
+	buffer.Write(checksum[:])
+
+	return buffer.Bytes(), nil
+}
+
+// Decode a replication log segment and verify its checksum.
+func DecodeReplicationSegment(data []byte) (*ReplicationSegment, error) {
+	if len(data) < len(replicationSegmentMagic)+sha256.Size {
+		return nil, ErrInvalidReplicationSegment
+	}
+
+	body := data[:len(data)-sha256.Size]
+
+	if sha256.Sum256(body) != [32]byte(data[len(data)-sha256.Size:]) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidReplicationSegment)
+	}
+
+	if !bytes.Equal(body[:len(replicationSegmentMagic)], replicationSegmentMagic) {
+		return nil, fmt.Errorf("%w: unknown format", ErrInvalidReplicationSegment)
+	}
+
+	reader := bytes.NewReader(body[len(replicationSegmentMagic):])
+	segment := &ReplicationSegment{}
+
+	var count uint32
+
+	for _, value := range []any{&segment.Sequence, &segment.ShippedAt, &count} {
+		if err := binary.Read(reader, binary.LittleEndian, value); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidReplicationSegment, err)
+		}
+	}
+
+	segment.Entries = make([]ReplicationLogEntry, 0, count)
+
+	for range count {
+		var entry ReplicationLogEntry
+		var length uint32
+		var err error
+
+		if entry.Kind, err = reader.ReadByte(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidReplicationSegment, err)
+		}
+
+		for _, value := range []any{&entry.Offset, &entry.Timestamp, &length} {
+			if err := binary.Read(reader, binary.LittleEndian, value); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidReplicationSegment, err)
+			}
+		}
+
+		if int64(length) > int64(reader.Len()) {
+			return nil, fmt.Errorf("%w: entry exceeds segment", ErrInvalidReplicationSegment)
+		}
+
+		entry.Data = make([]byte, length)
+
+		if _, err := io.ReadFull(reader, entry.Data); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidReplicationSegment, err)
+		}
+
+		segment.Entries = append(segment.Entries, entry)
+	}
+
+	return segment, nil
+}
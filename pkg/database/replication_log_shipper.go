@@ -0,0 +1,293 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/litebase/litebase/pkg/storage"
+)
+
+var (
+	ReplicationLogFlushInterval = 250 * time.Millisecond
+	ReplicationLogRetention     = 24 * time.Hour
+)
+
+// The ReplicationLogShipper runs on the primary and ships the WAL frames and
+// page log writes of a database branch to object storage. Remote replicas
+// that do not share the network storage of the cluster tail the shipped
+// segments and apply them locally.
+type ReplicationLogShipper struct {
+	BranchID   string
+	DatabaseID string
+	entries    []ReplicationLogEntry
+	fileSystem *storage.FileSystem
+	flushMutex *sync.Mutex
+	mutex      *sync.Mutex
+	prunedAt   time.Time
+	registered bool
+	sequence   int64
+}
+
+// Create a new instance of a ReplicationLogShipper.
+func NewReplicationLogShipper(databaseId, branchId string, fileSystem *storage.FileSystem) *ReplicationLogShipper {
+	return &ReplicationLogShipper{
+		BranchID:   branchId,
+		DatabaseID: databaseId,
+		entries:    []ReplicationLogEntry{},
+		fileSystem: fileSystem,
+		flushMutex: &sync.Mutex{},
+		mutex:      &sync.Mutex{},
+		sequence:   -1,
+	}
+}
+
+// Append an entry to be shipped with the next segment. The data is copied
+// since the caller may reuse the buffer.
+func (s *ReplicationLogShipper) Append(kind byte, offset, timestamp int64, data []byte) {
+	entry := ReplicationLogEntry{
+		Data:      make([]byte, len(data)),
+		Kind:      kind,
+		Offset:    offset,
+		Timestamp: timestamp,
+	}
+
+	copy(entry.Data, data)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries = append(s.entries, entry)
+}
+
+// Write the pending entries to object storage as the next segment. If the
+// segment cannot be written the entries are kept so they are shipped with the
+// next flush.
+func (s *ReplicationLogShipper) Flush() error {
+	s.flushMutex.Lock()
+	defer s.flushMutex.Unlock()
+
+	s.mutex.Lock()
+	entries := s.entries
+	s.entries = []ReplicationLogEntry{}
+	s.mutex.Unlock()
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	err := s.ship(entries)
+
+	if err != nil {
+		s.mutex.Lock()
+		s.entries = append(entries, s.entries...)
+		s.mutex.Unlock()
+
+		return err
+	}
+
+	if time.Since(s.prunedAt) > ReplicationLogRetention/24 {
+		s.prune()
+	}
+
+	return nil
+}
+
+// Load the sequence of the latest segment that has been shipped.
+func (s *ReplicationLogShipper) loadSequence() error {
+	entries, err := s.fileSystem.ReadDir(replicationLogPath(s.DatabaseID, s.BranchID))
+
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	s.sequence = 0
+
+	for _, entry := range entries {
+		if sequence, ok := replicationSegmentSequence(entry.Name()); ok && sequence > s.sequence {
+			s.sequence = sequence
+		}
+	}
+
+	return nil
+}
+
+// Return the lowest position acknowledged by the remote replicas of the
+// database branch. Replicas that have not acknowledged a position within the
+// retention period are considered gone and are ignored. False is returned when
+// no replica has acknowledged a position.
+func (s *ReplicationLogShipper) acknowledgedSequence() (int64, bool) {
+	entries, err := s.fileSystem.ReadDir(replicationAckPath(s.DatabaseID, s.BranchID))
+
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Debug("Failed to list replication log acknowledgements", "error", err)
+		}
+
+		return 0, false
+	}
+
+	var acknowledged int64
+	found := false
+
+	for _, entry := range entries {
+		if entry.Info() == nil || time.Since(entry.Info().ModTime()) > ReplicationLogRetention {
+			continue
+		}
+
+		data, err := s.fileSystem.ReadFile(replicationAckPath(s.DatabaseID, s.BranchID) + entry.Name())
+
+		if err != nil {
+			slog.Debug("Failed to read replication log acknowledgement", "replica", entry.Name(), "error", err)
+			continue
+		}
+
+		position, err := decodeReplicationPosition(data)
+
+		if err != nil {
+			continue
+		}
+
+		if !found || position < acknowledged {
+			acknowledged = position
+			found = true
+		}
+	}
+
+	return acknowledged, found
+}
+
+// Remove the segments that every remote replica has acknowledged. Segments
+// are only removed by age, once they are older than the retention period,
+// when no replica is tailing the database branch. The latest segment is always
+// kept so the sequence continues after the primary restarts.
+func (s *ReplicationLogShipper) prune() {
+	s.prunedAt = time.Now().UTC()
+
+	entries, err := s.fileSystem.ReadDir(replicationLogPath(s.DatabaseID, s.BranchID))
+
+	if err != nil {
+		slog.Debug("Failed to list replication log segments", "error", err)
+		return
+	}
+
+	acknowledged, hasReplicas := s.acknowledgedSequence()
+
+	for _, entry := range entries {
+		sequence, ok := replicationSegmentSequence(entry.Name())
+
+		if !ok || entry.Info() == nil {
+			continue
+		}
+
+		if sequence >= s.sequence {
+			continue
+		}
+
+		if hasReplicas && sequence > acknowledged {
+			continue
+		}
+
+		if !hasReplicas && time.Since(entry.Info().ModTime()) < ReplicationLogRetention {
+			continue
+		}
+
+		err := s.fileSystem.Remove(replicationLogPath(s.DatabaseID, s.BranchID) + entry.Name())
+
+		if err != nil {
+			slog.Debug("Failed to remove replication log segment", "segment", entry.Name(), "error", err)
+		}
+	}
+}
+
+// Ship the pending entries to object storage at a regular interval until the
+// context is done. The remaining entries are flushed before returning.
+func (s *ReplicationLogShipper) Run(ctx context.Context) {
+	ticker := time.NewTicker(ReplicationLogFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.Flush(); err != nil {
+				slog.Error("Failed to ship replication log", "error", err)
+			}
+
+			return
+		case <-ticker.C:
+			if err := s.Flush(); err != nil {
+				slog.Error("Failed to ship replication log", "error", err)
+			}
+		}
+	}
+}
+
+// Write the entries as the next segment of the database branch.
+func (s *ReplicationLogShipper) ship(entries []ReplicationLogEntry) error {
+	if s.sequence < 0 {
+		if err := s.loadSequence(); err != nil {
+			return err
+		}
+	}
+
+	if !s.registered {
+		err := writeReplicationObject(
+			s.fileSystem,
+			fmt.Sprintf("%s%s_%s", replicationLogBranchesPath(), s.DatabaseID, s.BranchID),
+			[]byte{},
+		)
+
+		if err != nil {
+			return err
+		}
+
+		s.registered = true
+	}
+
+	segment := &ReplicationSegment{
+		Entries:   entries,
+		Sequence:  s.sequence + 1,
+		ShippedAt: time.Now().UTC().UnixNano(),
+	}
+
+	data, err := segment.Encode()
+
+	if err != nil {
+		return err
+	}
+
+	err = writeReplicationObject(
+		s.fileSystem,
+		replicationLogPath(s.DatabaseID, s.BranchID)+replicationSegmentName(segment.Sequence),
+		data,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	s.sequence = segment.Sequence
+
+	return nil
+}
+
+// Write an object, creating its directory if the file system requires it.
+func writeReplicationObject(fileSystem *storage.FileSystem, path string, data []byte) error {
+	err := fileSystem.WriteFile(path, data, 0600)
+
+	if err == nil || !os.IsNotExist(err) {
+		return err
+	}
+
+	err = fileSystem.MkdirAll(filepath.Dir(path), 0750)
+
+	if err != nil {
+		return err
+	}
+
+	return fileSystem.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,152 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/database"
+	"github.com/litebase/litebase/pkg/server"
+)
+
+func TestReplicationLogShipperFlush(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		shipper := database.NewReplicationLogShipper("databaseId", "branchId", app.Cluster.ObjectFS())
+
+		shipper.Append(database.ReplicationLogEntryWAL, 32, 1, []byte("frame"))
+
+		err := shipper.Flush()
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		data, err := app.Cluster.ObjectFS().ReadFile("_replication/databaseId/branchId/00000000000000000001")
+
+		if err != nil {
+			t.Fatalf("Expected the segment to be shipped, got %v", err)
+		}
+
+		segment, err := database.DecodeReplicationSegment(data)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(segment.Entries) != 1 || string(segment.Entries[0].Data) != "frame" {
+			t.Errorf("Expected the shipped entry, got %+v", segment.Entries)
+		}
+
+		// Flushing without pending entries does not ship a segment
+		err = shipper.Flush()
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if _, err := app.Cluster.ObjectFS().Stat("_replication/databaseId/branchId/00000000000000000002"); err == nil {
+			t.Error("Expected no segment to be shipped without pending entries")
+		}
+	})
+}
+
+func TestReplicationLogTailerAppliesShippedWAL(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		shipper := database.NewReplicationLogShipper("databaseId", "branchId", app.Cluster.ObjectFS())
+
+		shipper.Append(database.ReplicationLogEntryWAL, 0, 1, []byte("first"))
+
+		if err := shipper.Flush(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		shipper.Append(database.ReplicationLogEntryWAL, 5, 1, []byte("second"))
+
+		if err := shipper.Flush(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		err := database.NewReplicationLogTailer(app.DatabaseManager).Poll()
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		walManager, err := app.DatabaseManager.Resources("databaseId", "branchId").DatabaseWALManager()
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		data := make([]byte, 11)
+
+		_, err = walManager.ReadAt(1, data, 0)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if string(data) != "firstsecond" {
+			t.Errorf("Expected the shipped WAL data to be applied, got %q", data)
+		}
+
+		if _, err := app.Cluster.LocalFS().Stat("_replication/databaseId/branchId/POSITION"); err != nil {
+			t.Errorf("Expected the position to be stored on the local file system, got %v", err)
+		}
+
+		ack, err := app.Cluster.ObjectFS().ReadFile("_replication/databaseId/branchId/acks/" + app.Cluster.Node().ID)
+
+		if err != nil {
+			t.Fatalf("Expected the position to be acknowledged, got %v", err)
+		}
+
+		if ack[0] != 2 {
+			t.Errorf("Expected the acknowledged position to be 2, got %v", ack)
+		}
+	})
+}
+
+func TestReplicationLogShipperRetainsUnacknowledgedSegments(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		shipper := database.NewReplicationLogShipper("databaseId", "branchId", app.Cluster.ObjectFS())
+
+		for range 3 {
+			shipper.Append(database.ReplicationLogEntryWAL, 0, 1, []byte("frame"))
+
+			if err := shipper.Flush(); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+
+		// One replica has applied the first segment, the other has applied
+		// the first two.
+		err := app.Cluster.ObjectFS().WriteFile("_replication/databaseId/branchId/acks/replica1", []byte{1, 0, 0, 0, 0, 0, 0, 0}, 0600)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = app.Cluster.ObjectFS().WriteFile("_replication/databaseId/branchId/acks/replica2", []byte{2, 0, 0, 0, 0, 0, 0, 0}, 0600)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// A new shipper prunes the segments on its first flush.
+		shipper = database.NewReplicationLogShipper("databaseId", "branchId", app.Cluster.ObjectFS())
+		shipper.Append(database.ReplicationLogEntryWAL, 0, 1, []byte("frame"))
+
+		if err := shipper.Flush(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if _, err := app.Cluster.ObjectFS().Stat("_replication/databaseId/branchId/00000000000000000001"); err == nil {
+			t.Error("Expected the segment acknowledged by every replica to be removed")
+		}
+
+		for _, segment := range []string{"00000000000000000002", "00000000000000000003", "00000000000000000004"} {
+			if _, err := app.Cluster.ObjectFS().Stat("_replication/databaseId/branchId/" + segment); err != nil {
+				t.Errorf("Expected segment %s to be retained, got %v", segment, err)
+			}
+		}
+	})
+}
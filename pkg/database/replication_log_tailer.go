@@ -0,0 +1,295 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/litebase/litebase/pkg/storage"
+)
+
+// The ReplicationLogTailer runs on remote replicas. It polls object storage for
+// the segments shipped by the primary and applies them to the local WAL
+// versions and page logs of each database branch, in order. The position of
+// each branch is acknowledged in object storage so the primary retains the
+// segments the replica has not applied yet.
+type ReplicationLogTailer struct {
+	databaseManager *DatabaseManager
+	mutex           *sync.Mutex
+	objectFS        *storage.FileSystem
+	positions       map[string]int64
+}
+
+// Create a new instance of a ReplicationLogTailer.
+func NewReplicationLogTailer(databaseManager *DatabaseManager) *ReplicationLogTailer {
+	return &ReplicationLogTailer{
+		databaseManager: databaseManager,
+		mutex:           &sync.Mutex{},
+		objectFS:        databaseManager.Cluster.ObjectFS(),
+		positions:       map[string]int64{},
+	}
+}
+
+// Apply the segments of a database branch that have been shipped since the
+// last segment that was applied.
+func (t *ReplicationLogTailer) applyBranch(databaseId, branchId string) error {
+	position, err := t.position(databaseId, branchId)
+
+	if err != nil {
+		return err
+	}
+
+	entries, err := t.objectFS.ReadDir(replicationLogPath(databaseId, branchId))
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	sequences := []int64{}
+	var latestShippedAt time.Time
+
+	for _, entry := range entries {
+		sequence, ok := replicationSegmentSequence(entry.Name())
+
+		if !ok || sequence <= position {
+			continue
+		}
+
+		sequences = append(sequences, sequence)
+
+		if entry.Info() != nil && entry.Info().ModTime().After(latestShippedAt) {
+			latestShippedAt = entry.Info().ModTime()
+		}
+	}
+
+	if len(sequences) == 0 {
+		return nil
+	}
+
+	slices.Sort(sequences)
+
+	lagTracker := t.databaseManager.Cluster.Node().ReplicationLagTracker()
+	address, _ := t.databaseManager.Cluster.Node().Address()
+
+	lagTracker.Primary(databaseId, branchId, latestShippedAt.UnixNano())
+
+	for _, sequence := range sequences {
+		// Segments that are missing were removed from object storage before
+		// they could be applied and the replica cannot catch up.
+		if sequence != position+1 {
+			return fmt.Errorf("replication log segment %d of %s/%s is missing", position+1, databaseId, branchId)
+		}
+
+		data, err := t.objectFS.ReadFile(replicationLogPath(databaseId, branchId) + replicationSegmentName(sequence))
+
+		if err != nil {
+			return err
+		}
+
+		segment, err := DecodeReplicationSegment(data)
+
+		if err != nil {
+			return err
+		}
+
+		err = t.applySegment(databaseId, branchId, segment)
+
+		if err != nil {
+			return err
+		}
+
+		position = sequence
+
+		err = t.setPosition(databaseId, branchId, position)
+
+		if err != nil {
+			return err
+		}
+
+		lagTracker.Replica(address, databaseId, branchId, segment.ShippedAt)
+	}
+
+	return t.acknowledge(databaseId, branchId, position)
+}
+
+// Acknowledge the position of a database branch so the primary can remove the
+// segments that have been applied.
+func (t *ReplicationLogTailer) acknowledge(databaseId, branchId string, position int64) error {
+	data, err := encodeReplicationPosition(position)
+
+	if err != nil {
+		return err
+	}
+
+	return writeReplicationObject(
+		t.objectFS,
+		replicationAckPath(databaseId, branchId)+t.databaseManager.Cluster.Node().ID,
+		data,
+	)
+}
+
+// Apply the entries of a segment to the local storage of the database branch.
+func (t *ReplicationLogTailer) applySegment(databaseId, branchId string, segment *ReplicationSegment) error {
+	resources := t.databaseManager.Resources(databaseId, branchId)
+
+	walManager, err := resources.DatabaseWALManager()
+
+	if err != nil {
+		return err
+	}
+
+	fileSystem := resources.FileSystem()
+
+	if fileSystem == nil {
+		return fmt.Errorf("unable to open the file system for database %s", databaseId)
+	}
+
+	for _, entry := range segment.Entries {
+		switch entry.Kind {
+		case ReplicationLogEntryWAL:
+			err = walManager.ApplyReplicatedWrite(entry.Timestamp, entry.Data, entry.Offset)
+		case ReplicationLogEntryPage:
+			_, err = fileSystem.PageLogger.Write(entry.Offset, entry.Timestamp, entry.Data)
+		default:
+			err = fmt.Errorf("unknown replication log entry kind %d", entry.Kind)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Return the database branches that are shipping their changes.
+func (t *ReplicationLogTailer) branches() ([][2]string, error) {
+	entries, err := t.objectFS.ReadDir(replicationLogBranchesPath())
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	branches := make([][2]string, 0, len(entries))
+
+	for _, entry := range entries {
+		databaseId, branchId, ok := strings.Cut(entry.Name(), "_")
+
+		if !ok {
+			continue
+		}
+
+		branches = append(branches, [2]string{databaseId, branchId})
+	}
+
+	return branches, nil
+}
+
+// Poll object storage once and apply the new segments of every database
+// branch.
+func (t *ReplicationLogTailer) Poll() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	branches, err := t.branches()
+
+	if err != nil {
+		return err
+	}
+
+	for _, branch := range branches {
+		err := t.applyBranch(branch[0], branch[1])
+
+		if err != nil {
+			slog.Error("Failed to apply replication log", "database_id", branch[0], "branch_id", branch[1], "error", err)
+		}
+	}
+
+	return nil
+}
+
+// Return the sequence of the last segment applied to a database branch. The
+// position is stored on the local file system of the replica, next to the
+// local WAL files, so it survives restarts.
+func (t *ReplicationLogTailer) position(databaseId, branchId string) (int64, error) {
+	key := databaseId + ":" + branchId
+
+	if position, ok := t.positions[key]; ok {
+		return position, nil
+	}
+
+	data, err := t.databaseManager.Cluster.LocalFS().ReadFile(replicationLogPath(databaseId, branchId) + "POSITION")
+
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	position, err := decodeReplicationPosition(data)
+
+	if err != nil {
+		return 0, err
+	}
+
+	t.positions[key] = position
+
+	return position, nil
+}
+
+// Poll object storage at the configured interval until the context is done.
+func (t *ReplicationLogTailer) Run(ctx context.Context) {
+	interval := time.Duration(t.databaseManager.Cluster.Config.RemoteReplicaPollInterval) * time.Millisecond
+
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.Poll(); err != nil {
+				slog.Error("Failed to poll replication log", "error", err)
+			}
+		}
+	}
+}
+
+// Store the sequence of the last segment applied to a database branch.
+func (t *ReplicationLogTailer) setPosition(databaseId, branchId string, position int64) error {
+	data, err := encodeReplicationPosition(position)
+
+	if err != nil {
+		return err
+	}
+
+	err = writeReplicationObject(
+		t.databaseManager.Cluster.LocalFS(),
+		replicationLogPath(databaseId, branchId)+"POSITION",
+		data,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	t.positions[databaseId+":"+branchId] = position
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package database_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/litebase/litebase/pkg/database"
+)
+
+func TestReplicationSegmentEncodeDecode(t *testing.T) {
+	segment := &database.ReplicationSegment{
+		Entries: []database.ReplicationLogEntry{
+			{Data: []byte("frame"), Kind: database.ReplicationLogEntryWAL, Offset: 32, Timestamp: 1},
+			{Data: bytes.Repeat([]byte{1}, 4096), Kind: database.ReplicationLogEntryPage, Offset: 2, Timestamp: 1},
+		},
+		Sequence:  7,
+		ShippedAt: 1000,
+	}
+
+	data, err := segment.Encode()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	decoded, err := database.DecodeReplicationSegment(data)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if decoded.Sequence != 7 || decoded.ShippedAt != 1000 {
+		t.Errorf("Expected sequence 7 shipped at 1000, got %d shipped at %d", decoded.Sequence, decoded.ShippedAt)
+	}
+
+	if len(decoded.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(decoded.Entries))
+	}
+
+	for i, entry := range decoded.Entries {
+		expected := segment.Entries[i]
+
+		if entry.Kind != expected.Kind || entry.Offset != expected.Offset || entry.Timestamp != expected.Timestamp {
+			t.Errorf("Expected entry %d to be %+v, got %+v", i, expected, entry)
+		}
+
+		if !bytes.Equal(entry.Data, expected.Data) {
+			t.Errorf("Expected entry %d data to match", i)
+		}
+	}
+}
+
+func TestDecodeReplicationSegmentWithInvalidChecksum(t *testing.T) {
+	segment := &database.ReplicationSegment{
+		Entries: []database.ReplicationLogEntry{
+			{Data: []byte("frame"), Kind: database.ReplicationLogEntryWAL, Offset: 32, Timestamp: 1},
+		},
+		Sequence: 1,
+	}
+
+	data, err := segment.Encode()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data[10] ^= 0xFF
+
+	_, err = database.DecodeReplicationSegment(data)
+
+	if !errors.Is(err, database.ErrInvalidReplicationSegment) {
+		t.Errorf("Expected ErrInvalidReplicationSegment, got %v", err)
+	}
+}
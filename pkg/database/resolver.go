@@ -1,32 +1,76 @@
 package database
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"log/slog"
 	"time"
 
+	"github.com/litebase/litebase/pkg/cluster"
 	"github.com/litebase/litebase/pkg/cluster/messages"
+	"github.com/litebase/litebase/pkg/config"
 	"github.com/litebase/litebase/pkg/logs"
 	"github.com/litebase/litebase/pkg/sqlite3"
 )
 
+var (
+	ErrRemoteReplicaReadOnly = errors.New("writes are not allowed on a remote replica")
+	ErrReplicaLagExceeded    = errors.New("the replica is too far behind the primary to serve reads")
+)
+
 func ResolveQuery(logManager *logs.LogManager, query *Query, response *QueryResponse) (*QueryResponse, error) {
 	if query.invalid {
 		return nil, fmt.Errorf("invalid or malformed query")
 	}
 
-	// Determine if the query should be forwarded to the primary node.
-	if shouldForwardToPrimary(query) {
-		// TODO: There is an issue where if a node has been inactive and a query
-		// is being resolved before election that the single node may try to
-		// communicate to a non-existent primary node. This should be fixed and tested.
-		// Create primary server and replica, write to replica, stop primary
-		return forwardQueryToPrimary(query, response)
+	// Remote replicas only serve reads from the changes shipped by the primary
+	// and cannot forward writes to it.
+	if query.cluster.Node().IsRemoteReplica() && query.IsWrite() {
+		return nil, ErrRemoteReplicaReadOnly
 	}
 
-	return resolveQueryLocally(logManager, query, response)
+	for {
+		// Writes and new transactions wait while the primary role is handed
+		// off to another node. Queries within a transaction are not paused,
+		// since the handoff waits for open transactions to complete.
+		if query.IsTransactionStart() || (query.IsWrite() && !query.IsTransactional()) {
+			ctx, cancel := context.WithTimeout(context.Background(), cluster.NodeHandoffTimeout)
+			err := query.cluster.Node().WaitForWrites(ctx)
+			cancel()
+
+			if err != nil {
+				return nil, fmt.Errorf("writes are paused for a primary handoff: %w", err)
+			}
+		}
+
+		// Reads are refused on replicas that are too far behind the primary when
+		// the lag policy does not allow them to be forwarded.
+		if replicaLagExceeded(query) &&
+			query.cluster.Config.ReplicaLagPolicy == config.ReplicaLagPolicyRefuse {
+			return nil, ErrReplicaLagExceeded
+		}
+
+		// Determine if the query should be forwarded to the primary node.
+		if shouldForwardToPrimary(query) {
+			// TODO: There is an issue where if a node has been inactive and a query
+			// is being resolved before election that the single node may try to
+			// communicate to a non-existent primary node. This should be fixed and tested.
+			// Create primary server and replica, write to replica, stop primary
+			return forwardQueryToPrimary(query, response)
+		}
+
+		result, err := resolveQueryLocally(logManager, query, response)
+
+		// A write that raced with the start of a primary handoff is refused
+		// before it is queued and is resolved again once the handoff completes.
+		if errors.Is(err, cluster.ErrWritesPaused) && !query.IsTransactionStart() {
+			continue
+		}
+
+		return result, err
+	}
 }
 
 func resolveQueryLocally(logManager *logs.LogManager, query *Query, response *QueryResponse) (*QueryResponse, error) {
@@ -96,6 +140,16 @@ func resolveQueryLocally(logManager *logs.LogManager, query *Query, response *Qu
 			db = db.WithAccessKey(query.AccessKey)
 		}
 
+		if query.IsWrite() {
+			err = prepareReplication(query, db.GetConnection())
+
+			if err != nil {
+				response.SetError(err.Error())
+
+				return response, err
+			}
+		}
+
 		if !query.IsTransactionStart() && !query.IsTransactionEnd() && !query.IsTransactionRollback() {
 			if query.IsVacuum() {
 				response.SetError(errors.New("VACUUM is not supported from this context").Error())
@@ -149,6 +203,16 @@ func resolveQueryLocally(logManager *logs.LogManager, query *Query, response *Qu
 			return response, err
 		}
 
+		if query.IsWrite() && !query.IsTransactional() {
+			err = waitForReplication(query.cluster, db.GetConnection())
+
+			if err != nil {
+				response.SetError(err.Error())
+
+				return response, err
+			}
+		}
+
 		response.SetChanges(changes)
 		response.SetLastInsertRowID(lastInsertRowID)
 
@@ -174,6 +238,22 @@ func resolveQueryLocally(logManager *logs.LogManager, query *Query, response *Qu
 			slog.Error("Error logging query", "error", err)
 		}
 
+		if query.cluster.Config.AuditWriteStatements && query.IsWrite() {
+			err = logManager.AuditAsync(query.cluster, logs.AuditLogEntry{
+				ActorType: logs.AuditActorAccessKey,
+				Actor:     query.AccessKey.AccessKeyID,
+				Action:    logs.AuditActionDatabaseWrite,
+				Resource:  fmt.Sprintf("database:%s:branch:%s", query.DatabaseKey.DatabaseID, query.DatabaseKey.DatabaseBranchID),
+				Details: map[string]string{
+					"statement": query.Input.Statement,
+				},
+			})
+
+			if err != nil {
+				slog.Error("Error writing audit log entry", "error", err)
+			}
+		}
+
 		return response, nil
 	})
 }
@@ -190,6 +270,18 @@ func resolveWithQueue(
 			return nil, fmt.Errorf("database not found")
 		}
 
+		// The write is marked as pending before it is queued, so a primary
+		// handoff either refuses it or waits for it to complete.
+		if !query.IsTransactional() {
+			done, err := query.cluster.Node().BeginWrite()
+
+			if err != nil {
+				return nil, err
+			}
+
+			defer done()
+		}
+
 		return queue.Handle(
 			func(f func(query *Query, response *QueryResponse) (*QueryResponse, error),
 				query *Query,
@@ -203,16 +295,26 @@ func resolveWithQueue(
 }
 
 func forwardQueryToPrimary(query *Query, response *QueryResponse) (*QueryResponse, error) {
+	queryMessage := messages.QueryMessage{
+		AccessKeyID: query.AccessKey.AccessKeyID,
+		AccessToken: query.AccessKey.Token(),
+		BranchID:    query.DatabaseKey.DatabaseBranchID,
+		DatabaseID:  query.DatabaseKey.DatabaseID,
+		ID:          query.Input.ID,
+		Statement:   query.Input.Statement,
+		Parameters:  query.Input.Parameters,
+	}
+
+	if context := query.AccessKey.AuthorizationContext(); context != nil {
+		queryMessage.Replica = context.Replica
+		queryMessage.SourceIP = context.SourceIP
+		queryMessage.TLS = context.TLS
+		queryMessage.Time = context.Time
+	}
+
 	responseMessage, err := query.cluster.Node().Send(
 		messages.NodeMessage{
-			Data: messages.QueryMessage{
-				AccessKeyID: query.AccessKey.AccessKeyID,
-				BranchID:    query.DatabaseKey.DatabaseBranchID,
-				DatabaseID:  query.DatabaseKey.DatabaseID,
-				ID:          query.Input.ID,
-				Statement:   query.Input.Statement,
-				Parameters:  query.Input.Parameters,
-			},
+			Data: queryMessage,
 		},
 	)
 
@@ -256,7 +358,25 @@ func forwardQueryToPrimary(query *Query, response *QueryResponse) (*QueryRespons
 	return response, nil
 }
 
+// Check if the node is a replica that is further behind the primary for the
+// database branch of the query than the configured maximum lag.
+func replicaLagExceeded(query *Query) bool {
+	maxLag := query.cluster.Config.ReplicaMaxLagMs
+
+	if maxLag <= 0 || query.cluster.Node().IsPrimary() {
+		return false
+	}
+
+	lag := query.cluster.Node().ReplicationLag(
+		query.DatabaseKey.DatabaseID,
+		query.DatabaseKey.DatabaseBranchID,
+	)
+
+	return lag > time.Duration(maxLag)*time.Millisecond
+}
+
 func shouldForwardToPrimary(query *Query) bool {
 	return !query.cluster.Node().IsPrimary() &&
-		(query.IsPragma() || query.IsDML())
+		!query.cluster.Node().IsRemoteReplica() &&
+		(query.IsPragma() || query.IsDML() || replicaLagExceeded(query))
 }
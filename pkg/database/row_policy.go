@@ -0,0 +1,221 @@
+package database
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+/*
+Writes to tables with row policies are limited by temporary triggers on each
+table. Rows that are not visible to the access key are silently skipped by
+UPDATE and DELETE statements, and rows that would not be visible after an
+INSERT or UPDATE abort the statement. The triggers are installed for the access
+key that is using the connection and are replaced when another access key uses
+it. Reads are limited by rewriting the statement, see protected_table.go.
+*/
+
+// The prefix of the temporary triggers that enforce row policies.
+const rowPolicyTriggerPrefix = "litebase_row_policy_"
+
+var ErrRowPolicyViolation = errors.New("row violates row-level security policy")
+
+// Ensure the triggers of the connection match the row policies. The state of
+// the triggers is verified against the temporary schema whenever policies
+// apply, since a rollback or a schema change may have altered it.
+func (con *DatabaseConnection) syncRowPolicyTriggers(conditions map[string]string) error {
+	signature := ""
+
+	if len(conditions) > 0 {
+		signature = rowPolicySignature(conditions)
+	} else if !con.rowPolicyStale && con.rowPolicySignature == "" {
+		return nil
+	}
+
+	con.authorizerBypass = true
+
+	defer func() {
+		con.authorizerBypass = false
+	}()
+
+	result, err := con.sqliteConnection().Exec(
+		con.context,
+		fmt.Sprintf(
+			`SELECT count(*), coalesce(sum(name GLOB '%s%s_*'), 0), (SELECT schema_version FROM main.pragma_schema_version) FROM temp.sqlite_schema WHERE type = 'trigger' AND name GLOB '%s*'`,
+			rowPolicyTriggerPrefix,
+			signature,
+			rowPolicyTriggerPrefix,
+		),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	count, matching, schemaVersion := result.Rows[0][0].Int64(), result.Rows[0][1].Int64(), result.Rows[0][2].Int64()
+
+	if signature == "" && count == 0 {
+		con.rowPolicySignature = ""
+		con.rowPolicyStale = false
+
+		return nil
+	}
+
+	if signature != "" &&
+		signature == con.rowPolicySignature &&
+		schemaVersion == con.rowPolicySchemaVersion &&
+		count == con.rowPolicyTriggerCount &&
+		matching == count {
+		return nil
+	}
+
+	return con.installRowPolicyTriggers(conditions, signature)
+}
+
+// Replace the row policy triggers of the connection with triggers for the
+// given policies.
+func (con *DatabaseConnection) installRowPolicyTriggers(conditions map[string]string, signature string) error {
+	result, err := con.sqliteConnection().Exec(
+		con.context,
+		"SELECT type, name FROM temp.sqlite_schema WHERE type IN ('trigger', 'view')",
+	)
+
+	if err != nil {
+		return err
+	}
+
+	drops := []string{}
+
+	for _, row := range result.Rows {
+		objectType, name := string(row[0].Text()), string(row[1].Text())
+
+		if objectType == "trigger" && strings.HasPrefix(name, rowPolicyTriggerPrefix) {
+			drops = append(drops, "DROP TRIGGER IF EXISTS temp."+quoteIdentifier(name))
+		}
+
+		// A temporary view would shadow the table in the statements of
+		// other access keys that can write to it.
+		if _, ok := conditions[strings.ToLower(name)]; ok && objectType == "view" {
+			drops = append(drops, "DROP VIEW IF EXISTS temp."+quoteIdentifier(name))
+		}
+	}
+
+	for _, drop := range drops {
+		if _, err := con.sqliteConnection().Exec(con.context, drop); err != nil {
+			return err
+		}
+	}
+
+	con.rowPolicySignature = ""
+	con.rowPolicyTriggerCount = 0
+	con.rowPolicyStale = false
+
+	tables := make([]string, 0, len(conditions))
+
+	for table := range conditions {
+		tables = append(tables, table)
+	}
+
+	slices.Sort(tables)
+
+	for i, table := range tables {
+		result, err := con.sqliteConnection().Exec(
+			con.context,
+			fmt.Sprintf("SELECT name FROM pragma_table_info(%s, 'main')", quoteLiteral(table)),
+		)
+
+		if err != nil {
+			return err
+		}
+
+		if len(result.Rows) == 0 {
+			continue
+		}
+
+		columns := make([]string, 0, len(result.Rows))
+
+		for _, row := range result.Rows {
+			columns = append(columns, string(row[0].Text()))
+		}
+
+		for _, trigger := range rowPolicyTriggers(signature, i, table, conditions[table], columns) {
+			if _, err := con.sqliteConnection().Exec(con.context, trigger); err != nil {
+				return fmt.Errorf("invalid row policy for table %s: %w", table, err)
+			}
+
+			con.rowPolicyTriggerCount++
+		}
+	}
+
+	// The schema version may have changed since the triggers were checked.
+	result, err = con.sqliteConnection().Exec(con.context, "SELECT schema_version FROM main.pragma_schema_version")
+
+	if err != nil {
+		return err
+	}
+
+	con.rowPolicySchemaVersion = result.Rows[0][0].Int64()
+	con.rowPolicySignature = signature
+
+	return nil
+}
+
+// Return a short signature of the row policies that identifies their triggers.
+func rowPolicySignature(conditions map[string]string) string {
+	tables := make([]string, 0, len(conditions))
+
+	for table := range conditions {
+		tables = append(tables, table)
+	}
+
+	slices.Sort(tables)
+
+	hash := sha256.New()
+
+	for _, table := range tables {
+		hash.Write([]byte(table))
+		hash.Write([]byte{0})
+		hash.Write([]byte(conditions[table]))
+		hash.Write([]byte{0})
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)[:8])
+}
+
+// Return the statements that create the triggers enforcing the row policy
+// condition on writes to the table.
+func rowPolicyTriggers(signature string, index int, table, condition string, columns []string) []string {
+	check := func(row string) string {
+		values := make([]string, len(columns))
+
+		for i, column := range columns {
+			values[i] = fmt.Sprintf("%s.%s AS %s", row, quoteIdentifier(column), quoteIdentifier(column))
+		}
+
+		return fmt.Sprintf("coalesce((SELECT (%s) FROM (SELECT %s)), 0)", condition, strings.Join(values, ", "))
+	}
+
+	name := func(operation string) string {
+		return quoteIdentifier(fmt.Sprintf("%s%s_%d_%s", rowPolicyTriggerPrefix, signature, index, operation))
+	}
+
+	violation := quoteLiteral(ErrRowPolicyViolation.Error())
+	target := "main." + quoteIdentifier(table)
+
+	return []string{
+		fmt.Sprintf(
+			"CREATE TEMP TRIGGER %s BEFORE INSERT ON %s FOR EACH ROW WHEN NOT %s BEGIN SELECT RAISE(ABORT, %s); END",
+			name("insert"), target, check("NEW"), violation,
+		),
+		fmt.Sprintf(
+			"CREATE TEMP TRIGGER %s BEFORE UPDATE ON %s FOR EACH ROW BEGIN SELECT CASE WHEN NOT %s THEN RAISE(IGNORE) WHEN NOT %s THEN RAISE(ABORT, %s) END; END",
+			name("update"), target, check("OLD"), check("NEW"), violation,
+		),
+		fmt.Sprintf(
+			"CREATE TEMP TRIGGER %s BEFORE DELETE ON %s FOR EACH ROW WHEN NOT %s BEGIN SELECT RAISE(IGNORE); END",
+			name("delete"), target, check("OLD"),
+		),
+	}
+}
@@ -0,0 +1,136 @@
+package database_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/database"
+	"github.com/litebase/litebase/pkg/server"
+)
+
+func TestRowPolicies(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		setup := func(t *testing.T) (*database.DatabaseConnection, *auth.AccessKey) {
+			mock := test.MockDatabase(app)
+
+			connection, err := database.NewDatabaseConnection(app.DatabaseManager.ConnectionManager(), mock.DatabaseID, mock.DatabaseBranchID)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, statement := range []string{
+				"CREATE TABLE orders (id INTEGER PRIMARY KEY, tenant_id TEXT, total INTEGER)",
+				"INSERT INTO orders (tenant_id, total) VALUES ('a', 10), ('b', 20), ('a', 30)",
+			} {
+				if _, err := connection.Exec(statement, nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			accessKey := auth.NewAccessKey(
+				app.Auth.AccessKeyManager,
+				"test",
+				"test",
+				"",
+				[]auth.AccessKeyStatement{{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}}},
+			)
+
+			accessKey.Claims = map[string]string{"tenant": "a"}
+			accessKey.RowPolicies = []auth.AccessKeyRowPolicy{
+				{Resource: "database:*:branch:*:table:orders", Condition: "tenant_id = :claim.tenant"},
+			}
+
+			return connection, accessKey
+		}
+
+		count := func(t *testing.T, connection *database.DatabaseConnection) int64 {
+			result, err := connection.Exec("SELECT COUNT(*) FROM orders", nil)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return result.Rows[0][0].Int64()
+		}
+
+		t.Run("Read", func(t *testing.T) {
+			connection, accessKey := setup(t)
+			defer connection.Close()
+
+			if count(t, connection.WithAccessKey(accessKey)) != 2 {
+				t.Error("Expected only the rows of the tenant to be visible")
+			}
+
+			if count(t, connection.WithAccessKey(nil)) != 3 {
+				t.Error("Expected all rows to be visible without row policies")
+			}
+		})
+
+		t.Run("Write", func(t *testing.T) {
+			connection, accessKey := setup(t)
+			defer connection.Close()
+
+			connection.WithAccessKey(accessKey)
+
+			if _, err := connection.Exec("UPDATE orders SET total = 0", nil); err != nil {
+				t.Fatal(err)
+			}
+
+			if connection.Changes() != 2 {
+				t.Errorf("Expected 2 rows to be updated, got %d", connection.Changes())
+			}
+
+			_, err := connection.Exec("INSERT INTO orders (tenant_id, total) VALUES ('b', 40)", nil)
+
+			if err == nil {
+				t.Error("Expected an insert for another tenant to fail")
+			}
+
+			if _, err := connection.Exec("DELETE FROM orders", nil); err != nil {
+				t.Fatal(err)
+			}
+
+			connection.WithAccessKey(nil)
+
+			result, err := connection.Exec("SELECT tenant_id, total FROM orders", nil)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(result.Rows) != 1 || string(result.Rows[0][0].Text()) != "b" || result.Rows[0][1].Int64() != 20 {
+				t.Error("Expected the rows of other tenants to be unchanged")
+			}
+		})
+
+		t.Run("RejectsBypass", func(t *testing.T) {
+			connection, accessKey := setup(t)
+			defer connection.Close()
+
+			if _, err := connection.Exec("CREATE VIEW all_orders AS SELECT * FROM orders", nil); err != nil {
+				t.Fatal(err)
+			}
+
+			connection.WithAccessKey(accessKey)
+
+			_, err := connection.Exec("SELECT * FROM main.orders", nil)
+
+			if !errors.Is(err, database.ErrProtectedTableQualified) {
+				t.Errorf("Expected a qualified table error, got %v", err)
+			}
+
+			_, err = connection.Exec("REPLACE INTO orders (id, tenant_id, total) VALUES (2, 'a', 0)", nil)
+
+			if !errors.Is(err, database.ErrProtectedTableReplace) {
+				t.Errorf("Expected a replace error, got %v", err)
+			}
+
+			if _, err = connection.Exec("SELECT * FROM all_orders", nil); err == nil {
+				t.Error("Expected reading through a view to be denied")
+			}
+		})
+	})
+}
@@ -0,0 +1,412 @@
+package database
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/litebase/litebase/pkg/file"
+	"github.com/litebase/litebase/pkg/storage"
+)
+
+// The amount of time after which the sources of a branch that change with
+// writes are measured again. These are not invalidated on each write, the
+// bytes appended to the page logs and WAL are added to the usage as they are
+// written and the measurement corrects the usage periodically.
+const StorageUsageTTL = 1 * time.Minute
+
+// A StorageUsageSource is one of the places the data of a branch is stored.
+type StorageUsageSource uint8
+
+const (
+	StorageUsageBackups StorageUsageSource = 1 << iota
+	StorageUsagePageLogs
+	StorageUsageRanges
+	StorageUsageRollbackLogs
+	StorageUsageSnapshots
+	StorageUsageWAL
+
+	StorageUsageAll = StorageUsageBackups | StorageUsagePageLogs | StorageUsageRanges |
+		StorageUsageRollbackLogs | StorageUsageSnapshots | StorageUsageWAL
+
+	// The sources that change with writes and are measured again once the
+	// StorageUsageTTL has passed.
+	StorageUsageWrites = StorageUsagePageLogs | StorageUsageRanges | StorageUsageRollbackLogs |
+		StorageUsageSnapshots | StorageUsageWAL
+)
+
+// StorageUsage is the number of bytes used by a database branch across each
+// of the places its data is stored.
+type StorageUsage struct {
+	Backups      int64     `json:"backups"`
+	CalculatedAt time.Time `json:"calculated_at"`
+	PageLogs     int64     `json:"page_logs"`
+	Ranges       int64     `json:"ranges"`
+	RollbackLogs int64     `json:"rollback_logs"`
+	Snapshots    int64     `json:"snapshots"`
+	Total        int64     `json:"total"`
+	WAL          int64     `json:"wal"`
+}
+
+// Add the usage of another branch to this usage.
+func (u *StorageUsage) Add(other *StorageUsage) {
+	u.Backups += other.Backups
+	u.PageLogs += other.PageLogs
+	u.Ranges += other.Ranges
+	u.RollbackLogs += other.RollbackLogs
+	u.Snapshots += other.Snapshots
+	u.Total += other.Total
+	u.WAL += other.WAL
+
+	if other.CalculatedAt.Before(u.CalculatedAt) || u.CalculatedAt.IsZero() {
+		u.CalculatedAt = other.CalculatedAt
+	}
+}
+
+// Return the number of bytes used by a source.
+func (u *StorageUsage) source(source StorageUsageSource) *int64 {
+	switch source {
+	case StorageUsageBackups:
+		return &u.Backups
+	case StorageUsagePageLogs:
+		return &u.PageLogs
+	case StorageUsageRanges:
+		return &u.Ranges
+	case StorageUsageRollbackLogs:
+		return &u.RollbackLogs
+	case StorageUsageSnapshots:
+		return &u.Snapshots
+	default:
+		return &u.WAL
+	}
+}
+
+// BranchStorageUsage is the storage usage of a single database branch.
+type BranchStorageUsage struct {
+	DatabaseBranchID string `json:"database_branch_id"`
+	Name             string `json:"name"`
+	StorageUsage
+}
+
+// DatabaseStorageUsage is the storage usage of a database and each of its
+// branches.
+type DatabaseStorageUsage struct {
+	Branches   []BranchStorageUsage `json:"branches"`
+	DatabaseID string               `json:"database_id"`
+	Name       string               `json:"name"`
+	StorageUsage
+}
+
+/*
+The StorageAccountant keeps track of the storage used by each database branch.
+Usage is calculated from the directory listings of the file systems that store
+the data of a branch. Listing object storage is expensive, so the usage is
+calculated in the background and only the sources that were invalidated by a
+mutation are listed again. Requests are served the last calculated usage and
+only wait for the first calculation of a branch.
+*/
+type StorageAccountant struct {
+	databaseManager *DatabaseManager
+	entries         map[string]*storageUsageEntry
+	mutex           *sync.Mutex
+}
+
+type storageUsageEntry struct {
+	branchId   string
+	calculated chan struct{}
+	databaseId string
+	err        error
+	refreshing bool
+	stale      StorageUsageSource
+	usage      StorageUsage
+}
+
+// Create a new instance of the storage accountant.
+func NewStorageAccountant(databaseManager *DatabaseManager) *StorageAccountant {
+	return &StorageAccountant{
+		databaseManager: databaseManager,
+		entries:         map[string]*storageUsageEntry{},
+		mutex:           &sync.Mutex{},
+	}
+}
+
+// Add bytes written to a source of a branch to its usage, so the usage grows
+// with writes between measurements of the source.
+func (sa *StorageAccountant) Add(databaseId, branchId string, source StorageUsageSource, size int64) {
+	sa.mutex.Lock()
+	defer sa.mutex.Unlock()
+
+	entry, ok := sa.entries[file.DatabaseHash(databaseId, branchId)]
+
+	// Usage that has never been calculated includes the bytes once it is.
+	if !ok || entry.usage.CalculatedAt.IsZero() {
+		return
+	}
+
+	*entry.usage.source(source) += size
+	entry.usage.Total += size
+}
+
+// Return the storage usage of a branch. The last calculated usage is returned
+// while stale sources are measured again in the background.
+func (sa *StorageAccountant) BranchUsage(databaseId, branchId string) (*StorageUsage, error) {
+	key := file.DatabaseHash(databaseId, branchId)
+
+	sa.mutex.Lock()
+
+	entry, ok := sa.entries[key]
+
+	if !ok {
+		entry = &storageUsageEntry{
+			branchId:   branchId,
+			calculated: make(chan struct{}),
+			databaseId: databaseId,
+			stale:      StorageUsageAll,
+		}
+
+		sa.entries[key] = entry
+	}
+
+	if !entry.usage.CalculatedAt.IsZero() && time.Since(entry.usage.CalculatedAt) >= StorageUsageTTL {
+		entry.stale |= StorageUsageWrites
+	}
+
+	sa.refresh(entry)
+
+	calculated := entry.calculated
+
+	sa.mutex.Unlock()
+
+	<-calculated
+
+	sa.mutex.Lock()
+	defer sa.mutex.Unlock()
+
+	if entry.usage.CalculatedAt.IsZero() {
+		return nil, entry.err
+	}
+
+	usage := entry.usage
+
+	return &usage, nil
+}
+
+// Calculate the usage of the given sources of a branch. The usage of the other
+// sources is kept from the previous calculation.
+func (sa *StorageAccountant) calculate(databaseId, branchId string, usage StorageUsage, sources StorageUsageSource) (StorageUsage, error) {
+	cluster := sa.databaseManager.Cluster
+	baseDirectory := file.GetDatabaseFileBaseDir(databaseId, branchId)
+
+	// Object storage is listed through the tiered file system, like the
+	// orphan sweeper does, so both see the same files.
+	measurements := []struct {
+		source     StorageUsageSource
+		fileSystem *storage.FileSystem
+		path       string
+		size       *int64
+	}{
+		{StorageUsageRanges, cluster.TieredFS(), file.GetDatabaseFileDir(databaseId, branchId), &usage.Ranges},
+		{StorageUsagePageLogs, cluster.NetworkFS(), fmt.Sprintf("%slogs/page/", baseDirectory), &usage.PageLogs},
+		{StorageUsageWAL, cluster.NetworkFS(), fmt.Sprintf("%slogs/wal/", baseDirectory), &usage.WAL},
+		{StorageUsageRollbackLogs, cluster.TieredFS(), file.GetDatabaseRollbackDirectory(databaseId, branchId) + "/", &usage.RollbackLogs},
+		{StorageUsageSnapshots, cluster.TieredFS(), file.GetDatabaseSnapshotDirectory(databaseId, branchId) + "/", &usage.Snapshots},
+		{StorageUsageBackups, cluster.TieredFS(), file.GetDatabaseBackupsDirectory(databaseId, branchId), &usage.Backups},
+	}
+
+	usage.Total = 0
+
+	for _, measurement := range measurements {
+		if sources&measurement.source != 0 {
+			size, _, err := directorySize(measurement.fileSystem, measurement.path)
+
+			if err != nil {
+				return usage, err
+			}
+
+			*measurement.size = size
+		}
+
+		usage.Total += *measurement.size
+	}
+
+	usage.CalculatedAt = time.Now().UTC()
+
+	return usage, nil
+}
+
+// Return the storage usage of a database and all of its branches.
+func (sa *StorageAccountant) DatabaseUsage(database *Database) (*DatabaseStorageUsage, error) {
+	branches, err := database.Branches()
+
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &DatabaseStorageUsage{
+		Branches:   make([]BranchStorageUsage, 0, len(branches)),
+		DatabaseID: database.DatabaseID,
+		Name:       database.Name,
+	}
+
+	for _, branch := range branches {
+		branchUsage, err := sa.BranchUsage(database.DatabaseID, branch.DatabaseBranchID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		usage.Branches = append(usage.Branches, BranchStorageUsage{
+			DatabaseBranchID: branch.DatabaseBranchID,
+			Name:             branch.Name,
+			StorageUsage:     *branchUsage,
+		})
+
+		usage.StorageUsage.Add(branchUsage)
+	}
+
+	return usage, nil
+}
+
+// Mark sources of a branch as changed so they are measured again in the
+// background. All sources are invalidated when none are given.
+func (sa *StorageAccountant) Invalidate(databaseId, branchId string, sources ...StorageUsageSource) {
+	sa.mutex.Lock()
+	defer sa.mutex.Unlock()
+
+	entry, ok := sa.entries[file.DatabaseHash(databaseId, branchId)]
+
+	// Usage that has never been requested is calculated in full on the
+	// first request.
+	if !ok {
+		return
+	}
+
+	if len(sources) == 0 {
+		entry.stale |= StorageUsageAll
+	}
+
+	for _, source := range sources {
+		entry.stale |= source
+	}
+
+	sa.refresh(entry)
+}
+
+// Measure the stale sources of a branch in the background. Sources that are
+// invalidated while a calculation is running are measured once it completes.
+// The mutex must be held by the caller.
+func (sa *StorageAccountant) refresh(entry *storageUsageEntry) {
+	if entry.refreshing || entry.stale == 0 {
+		return
+	}
+
+	entry.refreshing = true
+	sources := entry.stale
+	entry.stale = 0
+	previous := entry.usage
+
+	go func() {
+		usage, err := sa.calculate(entry.databaseId, entry.branchId, previous, sources)
+
+		sa.mutex.Lock()
+		defer sa.mutex.Unlock()
+
+		entry.refreshing = false
+		entry.err = err
+
+		if err != nil {
+			slog.Error("Error calculating storage usage", "error", err, "databaseId", entry.databaseId, "branchId", entry.branchId)
+
+			// Measure the sources again on the next request.
+			entry.stale |= sources
+		} else {
+			// Keep the bytes added to the sources that were not measured
+			// while the calculation was running.
+			usage.Total = 0
+
+			for source := StorageUsageBackups; source <= StorageUsageWAL; source <<= 1 {
+				if sources&source == 0 {
+					*usage.source(source) = *entry.usage.source(source)
+				}
+
+				usage.Total += *usage.source(source)
+			}
+
+			entry.usage = usage
+		}
+
+		select {
+		case <-entry.calculated:
+		default:
+			close(entry.calculated)
+		}
+
+		if entry.usage.CalculatedAt.IsZero() {
+			// The usage has never been calculated, so the next request
+			// waits for another attempt.
+			entry.calculated = make(chan struct{})
+
+			return
+		}
+
+		if err == nil {
+			sa.refresh(entry)
+		}
+	}()
+}
+
+// Stop tracking the storage usage of a branch that has been deleted.
+func (sa *StorageAccountant) Remove(databaseId, branchId string) {
+	sa.mutex.Lock()
+	defer sa.mutex.Unlock()
+
+	delete(sa.entries, file.DatabaseHash(databaseId, branchId))
+}
+
+// Return the total size of the files in a directory and its subdirectories,
+// along with the latest time one of those files was modified.
+func directorySize(fileSystem *storage.FileSystem, path string) (int64, time.Time, error) {
+	var modifiedAt time.Time
+
+	entries, err := fileSystem.ReadDir(path)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, modifiedAt, nil
+		}
+
+		return 0, modifiedAt, err
+	}
+
+	var size int64
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			directorySize, directoryModifiedAt, err := directorySize(fileSystem, fmt.Sprintf("%s%s/", path, entry.Name()))
+
+			if err != nil {
+				return 0, modifiedAt, err
+			}
+
+			size += directorySize
+
+			if directoryModifiedAt.After(modifiedAt) {
+				modifiedAt = directoryModifiedAt
+			}
+
+			continue
+		}
+
+		if entry.Info() != nil {
+			size += entry.Info().Size()
+
+			if entry.Info().ModTime().After(modifiedAt) {
+				modifiedAt = entry.Info().ModTime()
+			}
+		}
+	}
+
+	return size, modifiedAt, nil
+}
@@ -0,0 +1,152 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/database"
+	"github.com/litebase/litebase/pkg/file"
+	"github.com/litebase/litebase/pkg/server"
+)
+
+func TestStorageAccountant(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		t.Run("BranchUsage", func(t *testing.T) {
+			mock := test.MockDatabase(app)
+
+			path := file.GetDatabaseBackupsDirectory(mock.DatabaseID, mock.DatabaseBranchID) + "test/backup.tar.gz"
+
+			err := app.Cluster.ObjectFS().WriteFile(path, make([]byte, 1024), 0600)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			usage, err := app.DatabaseManager.StorageAccountant().BranchUsage(mock.DatabaseID, mock.DatabaseBranchID)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if usage.Backups != 1024 {
+				t.Errorf("Expected 1024 bytes of backups, got %d", usage.Backups)
+			}
+
+			if usage.Total < usage.Backups {
+				t.Errorf("Expected total to include backups, got %d", usage.Total)
+			}
+
+			if usage.CalculatedAt.IsZero() {
+				t.Error("Expected the usage to have a calculation time")
+			}
+		})
+
+		t.Run("Invalidate", func(t *testing.T) {
+			mock := test.MockDatabase(app)
+			accountant := app.DatabaseManager.StorageAccountant()
+
+			usage, err := accountant.BranchUsage(mock.DatabaseID, mock.DatabaseBranchID)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			path := file.GetDatabaseBackupsDirectory(mock.DatabaseID, mock.DatabaseBranchID) + "backup.tar.gz"
+
+			err = app.Cluster.ObjectFS().WriteFile(path, make([]byte, 512), 0600)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			cached, err := accountant.BranchUsage(mock.DatabaseID, mock.DatabaseBranchID)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if cached.Backups != usage.Backups {
+				t.Errorf("Expected cached usage to be returned, got %d backup bytes", cached.Backups)
+			}
+
+			accountant.Invalidate(mock.DatabaseID, mock.DatabaseBranchID, database.StorageUsageBackups)
+
+			// The invalidated usage is calculated in the background.
+			timeout := time.After(5 * time.Second)
+
+			for usage.Backups != cached.Backups+512 {
+				select {
+				case <-timeout:
+					t.Fatalf("Expected %d bytes of backups, got %d", cached.Backups+512, usage.Backups)
+				case <-time.After(10 * time.Millisecond):
+				}
+
+				usage, err = accountant.BranchUsage(mock.DatabaseID, mock.DatabaseBranchID)
+
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+			}
+		})
+
+		t.Run("Remove", func(t *testing.T) {
+			mock := test.MockDatabase(app)
+			accountant := app.DatabaseManager.StorageAccountant()
+
+			_, err := accountant.BranchUsage(mock.DatabaseID, mock.DatabaseBranchID)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			path := file.GetDatabaseBackupsDirectory(mock.DatabaseID, mock.DatabaseBranchID) + "backup.tar.gz"
+
+			err = app.Cluster.ObjectFS().WriteFile(path, make([]byte, 256), 0600)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			accountant.Remove(mock.DatabaseID, mock.DatabaseBranchID)
+
+			usage, err := accountant.BranchUsage(mock.DatabaseID, mock.DatabaseBranchID)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if usage.Backups != 256 {
+				t.Errorf("Expected the usage to be calculated again, got %d backup bytes", usage.Backups)
+			}
+		})
+
+		t.Run("DatabaseUsage", func(t *testing.T) {
+			mock := test.MockDatabase(app)
+
+			db, err := app.DatabaseManager.Get(mock.DatabaseID)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			usage, err := app.DatabaseManager.StorageAccountant().DatabaseUsage(db)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if len(usage.Branches) != 1 {
+				t.Fatalf("Expected 1 branch, got %d", len(usage.Branches))
+			}
+
+			if usage.Branches[0].DatabaseBranchID != mock.DatabaseBranchID {
+				t.Errorf("Expected branch %s, got %s", mock.DatabaseBranchID, usage.Branches[0].DatabaseBranchID)
+			}
+
+			if usage.Total != usage.Branches[0].Total {
+				t.Errorf("Expected total %d, got %d", usage.Branches[0].Total, usage.Total)
+			}
+		})
+	})
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/cluster"
+	"github.com/litebase/litebase/pkg/cluster/messages"
+)
+
+/*
+A replicationCapture wraps the WAL manager of a database connection and
+captures the WAL frames written through the connection. Frames are captured
+per connection so the frames replicated for a commit never include the writes
+of other connections to the same database branch.
+*/
+type replicationCapture struct {
+	*DatabaseWALManager
+	frames   []messages.WALReplicationWriteMessage
+	mutex    *sync.Mutex
+	settings *DatabaseReplicationSettings
+}
+
+// Create a new instance of a replicationCapture.
+func newReplicationCapture(walManager *DatabaseWALManager) *replicationCapture {
+	return &replicationCapture{
+		DatabaseWALManager: walManager,
+		mutex:              &sync.Mutex{},
+	}
+}
+
+// Discard the captured frames and stop capturing.
+func (c *replicationCapture) Reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.frames = nil
+	c.settings = nil
+}
+
+// Start capturing the frames written through the connection, replicated with
+// the given settings once the write is committed.
+func (c *replicationCapture) Start(settings DatabaseReplicationSettings) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.settings = &settings
+}
+
+// Return the captured frames with the settings they were captured with and
+// stop capturing. The settings are nil when no frames were captured.
+func (c *replicationCapture) Take() ([]messages.WALReplicationWriteMessage, *DatabaseReplicationSettings) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	frames, settings := c.frames, c.settings
+
+	c.frames = nil
+	c.settings = nil
+
+	return frames, settings
+}
+
+// Write to the WAL and capture the frame when capturing.
+func (c *replicationCapture) WriteAt(timestamp int64, p []byte, off int64) (int, error) {
+	n, err := c.DatabaseWALManager.WriteAt(timestamp, p, off)
+
+	if err != nil {
+		return n, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.settings == nil {
+		return n, nil
+	}
+
+	data := make([]byte, n)
+	copy(data, p[:n])
+
+	c.frames = append(c.frames, messages.WALReplicationWriteMessage{
+		BranchID:   c.BranchID,
+		DatabaseID: c.DatabaseID,
+		Data:       data,
+		Offset:     off,
+		Sequence:   c.nextReplicationSequence(),
+		Sha256:     sha256.Sum256(data),
+		Timestamp:  timestamp,
+	})
+
+	return n, nil
+}
+
+// Capture the WAL frames written by a query when the database requires writes
+// to be acknowledged by a quorum of replicas. Frames captured for an earlier
+// query are discarded unless the query is part of a transaction.
+func prepareReplication(query *Query, connection *DatabaseConnection) error {
+	if !query.cluster.Node().IsPrimary() {
+		return nil
+	}
+
+	if !query.IsTransactional() {
+		connection.replication.Reset()
+	}
+
+	settings := replicationSettings(query.databaseManager, query.DatabaseKey)
+
+	if settings.IsQuorum() {
+		connection.replication.Start(settings)
+	}
+
+	return nil
+}
+
+// Return the replication settings of a database.
+func replicationSettings(databaseManager *DatabaseManager, databaseKey *auth.DatabaseKey) DatabaseReplicationSettings {
+	database, err := databaseManager.Get(databaseKey.DatabaseID)
+
+	if err != nil || database.Settings == nil {
+		return DatabaseReplicationSettings{}
+	}
+
+	return database.Settings.Replication
+}
+
+// Wait for a quorum of replicas to acknowledge the WAL frames captured for a
+// committed write on the connection. The frames are replicated with the
+// settings that were resolved when the capture started.
+func waitForReplication(cluster *cluster.Cluster, connection *DatabaseConnection) error {
+	frames, settings := connection.replication.Take()
+
+	if settings == nil || len(frames) == 0 || !cluster.Node().IsPrimary() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(cluster.Node().Context(), settings.WaitTimeout())
+	defer cancel()
+
+	err := connection.walManager.Replicate(ctx, settings.Quorum, frames)
+
+	if err != nil {
+		return fmt.Errorf("the write was committed on the primary but not replicated: %w", err)
+	}
+
+	return nil
+}
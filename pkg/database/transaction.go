@@ -29,6 +29,7 @@ type Transaction struct {
 	queryChannel     chan TransactionQuery
 	StartedAt        time.Time
 	responseChannel  chan *QueryResponse
+	writeDone        func()
 	writesToDatabase bool
 }
 
@@ -52,6 +53,16 @@ func NewTransaction(
 		return nil, err
 	}
 
+	// A primary handoff waits for open transactions to complete, and new
+	// transactions are refused once the handoff has started.
+	writeDone, err := cluster.Node().BeginWrite()
+
+	if err != nil {
+		databaseManager.ConnectionManager().Release(connection)
+
+		return nil, err
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 
 	transaction := &Transaction{
@@ -67,12 +78,17 @@ func NewTransaction(
 		queryChannel:    make(chan TransactionQuery, 1),
 		responseChannel: make(chan *QueryResponse, 1),
 		StartedAt:       time.Now().UTC(),
+		writeDone:       writeDone,
 	}
 
 	err = transaction.Begin()
 
 	if err != nil {
 		log.Println("Error beginning transaction", err)
+		cancel()
+		writeDone()
+		databaseManager.ConnectionManager().Release(connection)
+
 		return nil, err
 	}
 
@@ -87,6 +103,7 @@ func (t *Transaction) Begin() error {
 	// have a consistent timestamp for the transaction and the vfs reads from
 	// the proper WAL file and Page Log.
 	t.connection.connection.setTimestamps()
+	t.connection.connection.replication.Reset()
 
 	return t.connection.GetConnection().Begin()
 }
@@ -103,6 +120,7 @@ func (t *Transaction) Close() {
 	t.connection.GetConnection().releaseTimestamps()
 	t.closed = true
 	t.cancel()
+	t.writeDone()
 
 	t.databaseManager.ConnectionManager().Release(t.connection)
 
@@ -119,7 +137,13 @@ func (t *Transaction) Commit() error {
 		t.connection.GetConnection().committedAt = time.Now().UTC()
 	}
 
-	return t.connection.GetConnection().Commit()
+	err := t.connection.GetConnection().Commit()
+
+	if err != nil || !t.writesToDatabase {
+		return err
+	}
+
+	return waitForReplication(t.cluster, t.connection.GetConnection())
 }
 
 // Rollback the transaction. This will close the transaction and rollback the
@@ -128,6 +152,8 @@ func (t *Transaction) Commit() error {
 func (t *Transaction) Rollback() error {
 	defer t.Close()
 
+	t.connection.GetConnection().replication.Reset()
+
 	return t.connection.GetConnection().Rollback()
 }
 
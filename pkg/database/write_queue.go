@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,6 +17,7 @@ type WriteQueue struct {
 	databaseId        string
 	jobs              chan WriteQueueJob
 	mutex             sync.Mutex
+	pending           atomic.Int64
 	resultPool        sync.Pool
 	resultChannelPool sync.Pool
 	running           bool
@@ -46,6 +48,9 @@ func (wq *WriteQueue) Handle(
 	query *Query,
 	response *QueryResponse,
 ) (*QueryResponse, error) {
+	wq.pending.Add(1)
+	defer wq.pending.Add(-1)
+
 	if !wq.running {
 		wq.mutex.Lock()
 		shouldStart := !wq.running
@@ -19,6 +19,36 @@ func NewWriteQueueManager(context context.Context) *WriteQueueManager {
 	}
 }
 
+// Drain waits for all of the jobs that have been submitted to the write queues
+// to complete.
+func (wqm *WriteQueueManager) Drain(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		pending := false
+
+		wqm.queues.Range(func(key, value any) bool {
+			if value.(*WriteQueue).pending.Load() > 0 {
+				pending = true
+				return false
+			}
+
+			return true
+		})
+
+		if !pending {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
 // GetWriteQueue returns the write queue that matches the database of the query.
 func (wqm *WriteQueueManager) GetWriteQueue(query *Query) *WriteQueue {
 	ctx := context.Background()
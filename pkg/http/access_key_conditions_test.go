@@ -0,0 +1,81 @@
+package http_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestAccessKeyConditions(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		testCases := []struct {
+			name       string
+			conditions *auth.AccessKeyConditions
+			statusCode int
+		}{
+			{"SourceIPAllowed", &auth.AccessKeyConditions{SourceIPs: []string{"127.0.0.0/8", "::1"}}, 200},
+			{"SourceIPDenied", &auth.AccessKeyConditions{SourceIPs: []string{"203.0.113.0/24"}}, 403},
+			{"RequireTLS", &auth.AccessKeyConditions{RequireTLS: true}, 403},
+			{"ReplicaOnly", &auth.AccessKeyConditions{ReplicaOnly: true}, 403},
+			{"TimeWindow", &auth.AccessKeyConditions{TimeWindows: []auth.AccessKeyTimeWindow{{Start: "00:00", End: "23:59"}, {Start: "23:59", End: "00:00"}}}, 200},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+					{
+						Effect:     "Allow",
+						Resource:   "*",
+						Actions:    []auth.Privilege{"*"},
+						Conditions: tc.conditions,
+					},
+				})
+
+				_, statusCode, err := client.Send("/v1/access-keys", "GET", nil)
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if statusCode != tc.statusCode {
+					t.Errorf("Unexpected status code: %d, expected %d", statusCode, tc.statusCode)
+				}
+			})
+		}
+	})
+}
+
+func TestAccessKeyConditions_Validation(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}},
+		})
+
+		_, statusCode, err := client.Send("/v1/access-keys", "POST", map[string]any{
+			"description": "Invalid conditions",
+			"statements": []map[string]any{
+				{
+					"effect":     "Allow",
+					"resource":   "*",
+					"actions":    []string{"*"},
+					"conditions": map[string]any{"source_ips": []string{"not-an-ip"}},
+				},
+			},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if statusCode != 422 {
+			t.Errorf("Unexpected status code: %d, expected 422", statusCode)
+		}
+	})
+}
@@ -3,8 +3,10 @@ package http
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/logs"
 )
 
 // List all access keys
@@ -78,8 +80,12 @@ func AccessKeyControllerShow(request *Request) Response {
 }
 
 type AccessKeyStoreRequest struct {
+	Claims      map[string]string         `json:"claims" validate:"omitempty,max=50"`
 	Description string                    `json:"description" validate:"omitempty,max=255"`
-	Statements  []auth.AccessKeyStatement `json:"statements" validate:"required,min=1,max=100,dive,validateFn=IsValid"`
+	ExpiresAt   *time.Time                `json:"expires_at" validate:"omitempty,gt"`
+	Roles       []string                  `json:"roles" validate:"omitempty,max=20"`
+	RowPolicies []auth.AccessKeyRowPolicy `json:"row_policies" validate:"omitempty,max=100,dive,validateFn=IsValid"`
+	Statements  []auth.AccessKeyStatement `json:"statements" validate:"required_without=Roles,omitempty,min=1,max=100,dive,validateFn=IsValid"`
 }
 
 // Create a new access key
@@ -103,26 +109,38 @@ func AccessKeyControllerStore(request *Request) Response {
 
 	// Validate the input
 	validationErrors := request.Validate(input, map[string]string{
-		"description.max":                  "The description field must be at most 255 characters long",
-		"statements.max":                   "The statements field must contain at most 100 items",
-		"statements.min":                   "The statements field must contain at least 1 item",
-		"statements.required":              "The statements field is required",
-		"statements.*.validateFn":          "This statement is not valid. All actions must match the resource.",
-		"statements.*.effect.required":     "Each statement must have an effect",
-		"statements.*.effect.validateFn":   "The effect of the statement must be one of 'Allow' or 'Deny'",
-		"statements.*.resource.required":   "This statement is missing a resource",
-		"statements.*.resource.validateFn": "This resource is not valid",
-		"statements.*.actions.required":    "This statement is missing actions",
-		"statements.*.actions.min":         "Each statement must have at least one action",
-		"statements.*.actions.max":         "Each statement can have at most 100 actions",
+		"description.max":                   "The description field must be at most 255 characters long",
+		"expires_at.gt":                     "The expiration date must be in the future",
+		"statements.max":                    "The statements field must contain at most 100 items",
+		"statements.min":                    "The statements field must contain at least 1 item",
+		"statements.required_without":       "The statements field is required when no roles are assigned",
+		"roles.max":                         "The roles field must contain at most 20 items",
+		"statements.*.validateFn":           "This statement is not valid. All actions must match the resource.",
+		"statements.*.effect.required":      "Each statement must have an effect",
+		"statements.*.effect.validateFn":    "The effect of the statement must be one of 'Allow' or 'Deny'",
+		"statements.*.resource.required":    "This statement is missing a resource",
+		"statements.*.resource.validateFn":  "This resource is not valid",
+		"statements.*.actions.required":     "This statement is missing actions",
+		"statements.*.actions.min":          "Each statement must have at least one action",
+		"statements.*.actions.max":          "Each statement can have at most 100 actions",
+		"claims.max":                        "The claims field must contain at most 50 items",
+		"row_policies.max":                  "The row policies field must contain at most 100 items",
+		"row_policies.*.validateFn":         "This row policy is not valid. The resource must be a table and the condition a single expression.",
+		"row_policies.*.resource.required":  "This row policy is missing a resource",
+		"row_policies.*.condition.required": "This row policy is missing a condition",
+		"row_policies.*.condition.max":      "The condition of a row policy must be at most 1024 characters long",
 	})
 
 	if validationErrors != nil {
 		return ValidationErrorResponse(validationErrors)
 	}
 
-	// Create the access key
-	accessKey, err := request.accessKeyManager.Create(
+	if validationErrors := validateRoles(request, input.(*AccessKeyStoreRequest).Roles); validationErrors != nil {
+		return ValidationErrorResponse(validationErrors)
+	}
+
+	// Create the access key with all of its fields so it is stored at once
+	accessKey, err := request.accessKeyManager.New(
 		input.(*AccessKeyStoreRequest).Description,
 		input.(*AccessKeyStoreRequest).Statements,
 	)
@@ -134,6 +152,22 @@ func AccessKeyControllerStore(request *Request) Response {
 		}, 500, nil)
 	}
 
+	accessKey.Claims = input.(*AccessKeyStoreRequest).Claims
+	accessKey.ExpiresAt = input.(*AccessKeyStoreRequest).ExpiresAt
+	accessKey.Roles = input.(*AccessKeyStoreRequest).Roles
+	accessKey.RowPolicies = input.(*AccessKeyStoreRequest).RowPolicies
+
+	err = request.accessKeyManager.Store(accessKey)
+
+	if err != nil {
+		return JsonResponse(map[string]any{
+			"status":  "error",
+			"message": fmt.Sprintf("Access key could not be created: %s", err.Error()),
+		}, 500, nil)
+	}
+
+	request.Audit(logs.AuditActionAccessKeyCreate, fmt.Sprintf("access-key:%s", accessKey.AccessKeyID), nil)
+
 	return JsonResponse(map[string]any{
 		"status":  "success",
 		"message": "Access key created successfully",
@@ -142,8 +176,12 @@ func AccessKeyControllerStore(request *Request) Response {
 }
 
 type AccessKeyUpdateRequest struct {
+	Claims      map[string]string         `json:"claims" validate:"omitempty,max=50"`
 	Description string                    `json:"description" validate:"omitempty,max=255"`
-	Statements  []auth.AccessKeyStatement `json:"statements" validate:"required,min=1,max=100,dive,validateFn=IsValid"`
+	ExpiresAt   *time.Time                `json:"expires_at" validate:"omitempty,gt"`
+	Roles       []string                  `json:"roles" validate:"omitempty,max=20"`
+	RowPolicies []auth.AccessKeyRowPolicy `json:"row_policies" validate:"omitempty,max=100,dive,validateFn=IsValid"`
+	Statements  []auth.AccessKeyStatement `json:"statements" validate:"required_without=Roles,omitempty,min=1,max=100,dive,validateFn=IsValid"`
 }
 
 // Update an existing access key
@@ -175,6 +213,14 @@ func AccessKeyControllerUpdate(request *Request) Response {
 		return ForbiddenResponse(err)
 	}
 
+	// An explicit null or empty expiration date removes the expiration
+	expiresAt, clearExpiresAt := request.All()["expires_at"]
+	clearExpiresAt = clearExpiresAt && (expiresAt == nil || expiresAt == "")
+
+	if clearExpiresAt {
+		delete(request.All(), "expires_at")
+	}
+
 	input, err := request.Input(&AccessKeyUpdateRequest{})
 
 	if err != nil {
@@ -186,32 +232,68 @@ func AccessKeyControllerUpdate(request *Request) Response {
 
 	// Validate the input
 	validationErrors := request.Validate(input, map[string]string{
-		"statements.max":                   "The statements field must contain at most 100 items",
-		"statements.min":                   "The statements field must contain at least 1 item",
-		"statements.required":              "The statements field is required",
-		"statements.*.validateFn":          "This statement is not valid. All actions must match the resource.",
-		"statements.*.effect.required":     "Each statement must have an effect",
-		"statements.*.effect.validateFn":   "The effect of the statement must be one of 'Allow' or 'Deny'",
-		"statements.*.resource.required":   "This statement is missing a resource",
-		"statements.*.resource.validateFn": "This resource is not valid",
-		"statements.*.actions.required":    "This statement is missing actions",
-		"statements.*.actions.min":         "Each statement must have at least one action",
-		"statements.*.actions.max":         "Each statement can have at most 100 actions",
+		"expires_at.gt":                     "The expiration date must be in the future",
+		"statements.max":                    "The statements field must contain at most 100 items",
+		"statements.min":                    "The statements field must contain at least 1 item",
+		"statements.required_without":       "The statements field is required when no roles are assigned",
+		"roles.max":                         "The roles field must contain at most 20 items",
+		"statements.*.validateFn":           "This statement is not valid. All actions must match the resource.",
+		"statements.*.effect.required":      "Each statement must have an effect",
+		"statements.*.effect.validateFn":    "The effect of the statement must be one of 'Allow' or 'Deny'",
+		"statements.*.resource.required":    "This statement is missing a resource",
+		"statements.*.resource.validateFn":  "This resource is not valid",
+		"statements.*.actions.required":     "This statement is missing actions",
+		"statements.*.actions.min":          "Each statement must have at least one action",
+		"statements.*.actions.max":          "Each statement can have at most 100 actions",
+		"claims.max":                        "The claims field must contain at most 50 items",
+		"row_policies.max":                  "The row policies field must contain at most 100 items",
+		"row_policies.*.validateFn":         "This row policy is not valid. The resource must be a table and the condition a single expression.",
+		"row_policies.*.resource.required":  "This row policy is missing a resource",
+		"row_policies.*.condition.required": "This row policy is missing a condition",
+		"row_policies.*.condition.max":      "The condition of a row policy must be at most 1024 characters long",
 	})
 
 	if validationErrors != nil {
 		return ValidationErrorResponse(validationErrors)
 	}
 
+	if validationErrors := validateRoles(request, input.(*AccessKeyUpdateRequest).Roles); validationErrors != nil {
+		return ValidationErrorResponse(validationErrors)
+	}
+
 	description := accessKey.Description
 
 	if input.(*AccessKeyUpdateRequest).Description != "" {
 		description = input.(*AccessKeyUpdateRequest).Description
 	}
 
+	if clearExpiresAt {
+		accessKey.ExpiresAt = nil
+	} else if input.(*AccessKeyUpdateRequest).ExpiresAt != nil {
+		accessKey.ExpiresAt = input.(*AccessKeyUpdateRequest).ExpiresAt
+	}
+
+	if input.(*AccessKeyUpdateRequest).Claims != nil {
+		accessKey.Claims = input.(*AccessKeyUpdateRequest).Claims
+	}
+
+	if input.(*AccessKeyUpdateRequest).RowPolicies != nil {
+		accessKey.RowPolicies = input.(*AccessKeyUpdateRequest).RowPolicies
+	}
+
+	if input.(*AccessKeyUpdateRequest).Roles != nil {
+		accessKey.Roles = input.(*AccessKeyUpdateRequest).Roles
+	}
+
+	statements := accessKey.Statements
+
+	if input.(*AccessKeyUpdateRequest).Statements != nil {
+		statements = input.(*AccessKeyUpdateRequest).Statements
+	}
+
 	err = accessKey.Update(
 		description,
-		input.(*AccessKeyUpdateRequest).Statements,
+		statements,
 	)
 
 	if err != nil {
@@ -221,6 +303,8 @@ func AccessKeyControllerUpdate(request *Request) Response {
 		}, 500, nil)
 	}
 
+	request.Audit(logs.AuditActionAccessKeyUpdate, fmt.Sprintf("access-key:%s", accessKey.AccessKeyID), nil)
+
 	return JsonResponse(map[string]any{
 		"status":  "success",
 		"message": "Access key updated successfully.",
@@ -228,6 +312,86 @@ func AccessKeyControllerUpdate(request *Request) Response {
 	}, 200, nil)
 }
 
+type AccessKeyRotateRequest struct {
+	GracePeriod *int64 `json:"grace_period" validate:"omitempty,min=0,max=2592000"`
+}
+
+// Issue a new secret for an access key. The previous secret remains valid for
+// the grace period, in seconds, so clients can switch to the new secret.
+func AccessKeyControllerRotate(request *Request) Response {
+	accessKeyId := request.Param("accessKeyId")
+
+	err := request.Authorize(
+		[]string{"*", "access-key:*", fmt.Sprintf("access-key:%s", accessKeyId)},
+		[]auth.Privilege{auth.AccessKeyPrivilegeUpdate},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	var input any = &AccessKeyRotateRequest{}
+
+	// The request body is optional
+	if len(request.All()) > 0 {
+		input, err = request.Input(&AccessKeyRotateRequest{})
+
+		if err != nil {
+			return BadRequestResponse(errors.New("the request input is invalid"))
+		}
+	}
+
+	validationErrors := request.Validate(input, map[string]string{
+		"grace_period.min": "The grace period must be at least 0 seconds",
+		"grace_period.max": "The grace period must be at most 2592000 seconds",
+	})
+
+	if validationErrors != nil {
+		return ValidationErrorResponse(validationErrors)
+	}
+
+	accessKey, err := request.accessKeyManager.Get(accessKeyId)
+
+	if err != nil {
+		return JsonResponse(map[string]any{
+			"status":  "error",
+			"message": "Access key could not be found",
+		}, 404, nil)
+	}
+
+	gracePeriod := auth.DefaultAccessKeyRotationGracePeriod
+
+	if input.(*AccessKeyRotateRequest).GracePeriod != nil {
+		gracePeriod = time.Duration(*input.(*AccessKeyRotateRequest).GracePeriod) * time.Second
+	}
+
+	err = accessKey.Rotate(gracePeriod)
+
+	if err != nil {
+		return JsonResponse(map[string]any{
+			"status":  "error",
+			"message": "Access key could not be rotated",
+		}, 500, nil)
+	}
+
+	request.Audit(logs.AuditActionAccessKeyRotate, fmt.Sprintf("access-key:%s", accessKey.AccessKeyID), map[string]string{
+		"grace_period": gracePeriod.String(),
+	})
+
+	return JsonResponse(map[string]any{
+		"status":  "success",
+		"message": "Access key rotated successfully",
+		"data": map[string]any{
+			"access_key_id":              accessKey.AccessKeyID,
+			"access_key_secret":          accessKey.AccessKeySecret,
+			"description":                accessKey.Description,
+			"expires_at":                 accessKey.ExpiresAt,
+			"previous_secret_expires_at": accessKey.PreviousSecretExpiresAt,
+			"rotated_at":                 accessKey.RotatedAt,
+		},
+	}, 200, nil)
+}
+
 func AccessKeyControllerDestroy(request *Request) Response {
 	// Get the access key ID from the request parameters
 	accessKeyId := request.Param("accessKeyId")
@@ -264,6 +428,8 @@ func AccessKeyControllerDestroy(request *Request) Response {
 		}, 500, nil)
 	}
 
+	request.Audit(logs.AuditActionAccessKeyDelete, fmt.Sprintf("access-key:%s", accessKey.AccessKeyID), nil)
+
 	return JsonResponse(map[string]any{
 		"status":  "success",
 		"message": "Access key deleted successfully.",
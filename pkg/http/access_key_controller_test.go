@@ -3,6 +3,7 @@ package http_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/litebase/litebase/internal/test"
 	"github.com/litebase/litebase/pkg/auth"
@@ -316,6 +317,88 @@ func TestAccessKeyControllerStore(t *testing.T) {
 	})
 }
 
+func TestAccessKeyControllerStore_WithRowPolicies(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   "Allow",
+				Resource: "*",
+				Actions:  []auth.Privilege{"access-key:create"},
+			},
+		})
+
+		response, statusCode, err := client.Send("/v1/access-keys", "POST", map[string]any{
+			"claims": map[string]string{"tenant": "a"},
+			"row_policies": []map[string]any{
+				{
+					"resource":  "database:*:branch:*:table:orders",
+					"condition": "tenant_id = :claim.tenant",
+				},
+			},
+			"statements": []map[string]any{
+				{
+					"effect":   "allow",
+					"resource": "*",
+					"actions":  []auth.Privilege{"*"},
+				},
+			},
+		})
+
+		if err != nil {
+			t.Fatalf("Failed to create access key: %v", err)
+		}
+
+		if statusCode != 201 {
+			t.Fatalf("Unexpected status code: %d, expected 201", statusCode)
+		}
+
+		accessKey, err := server.App.Auth.AccessKeyManager.Get(response["data"].(map[string]any)["access_key_id"].(string))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if accessKey.Claims["tenant"] != "a" {
+			t.Errorf("Expected the tenant claim to be stored, got %v", accessKey.Claims)
+		}
+
+		if len(accessKey.RowPolicies) != 1 || accessKey.RowPolicies[0].Condition != "tenant_id = :claim.tenant" {
+			t.Errorf("Expected the row policy to be stored, got %v", accessKey.RowPolicies)
+		}
+
+		response, statusCode, err = client.Send("/v1/access-keys", "POST", map[string]any{
+			"row_policies": []map[string]any{
+				{
+					"resource":  "database:*:branch:*:table:orders",
+					"condition": "1; DROP TABLE orders",
+				},
+			},
+			"statements": []map[string]any{
+				{
+					"effect":   "allow",
+					"resource": "*",
+					"actions":  []auth.Privilege{"*"},
+				},
+			},
+		})
+
+		if err != nil {
+			t.Fatalf("Failed to create access key: %v", err)
+		}
+
+		if statusCode != 422 {
+			t.Fatalf("Unexpected status code: %d, expected 422", statusCode)
+		}
+
+		if response["errors"] == nil || response["errors"].(map[string]any)["row_policies.0"] == nil {
+			t.Errorf("Expected a row policy error, got: %v", response["errors"])
+		}
+	})
+}
+
 func TestAccessKeyControllerStore_WithInvalidAccessKey(t *testing.T) {
 	test.Run(t, func() {
 		server := test.NewTestServer(t)
@@ -531,6 +614,58 @@ func TestAccessKeyControllerUpdate(t *testing.T) {
 	})
 }
 
+func TestAccessKeyControllerUpdate_ClearsExpiration(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		for _, expiresAt := range []any{nil, ""} {
+			expiration := time.Now().UTC().Add(time.Hour)
+
+			accessKey, err := server.App.Auth.AccessKeyManager.CreateWithExpiration(
+				"test",
+				[]auth.AccessKeyStatement{{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}}},
+				&expiration,
+			)
+
+			if err != nil {
+				t.Fatalf("Failed to create test access key: %v", err)
+			}
+
+			client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+				{
+					Effect:   "Allow",
+					Resource: "*",
+					Actions:  []auth.Privilege{"access-key:update"},
+				},
+			})
+
+			response, statusCode, err := client.Send(fmt.Sprintf("/v1/access-keys/%s", accessKey.AccessKeyID), "PUT", map[string]any{
+				"expires_at": expiresAt,
+				"statements": []map[string]any{
+					{
+						"effect":   "allow",
+						"resource": "*",
+						"actions":  []auth.Privilege{"*"},
+					},
+				},
+			})
+
+			if err != nil {
+				t.Fatalf("Failed to update access key: %v", err)
+			}
+
+			if statusCode != 200 {
+				t.Fatalf("Unexpected status code: %d, expected 200: %v", statusCode, response)
+			}
+
+			if response["data"].(map[string]any)["expires_at"] != nil {
+				t.Errorf("Expected the expiration to be removed, got %v", response["data"].(map[string]any)["expires_at"])
+			}
+		}
+	})
+}
+
 func TestAccessKeyControllerUpdate_WithInvalidAccessKey(t *testing.T) {
 	test.Run(t, func() {
 		server := test.NewTestServer(t)
@@ -576,3 +711,211 @@ func TestAccessKeyControllerUpdate_WithInvalidAccessKey(t *testing.T) {
 		}
 	})
 }
+
+func TestAccessKeyControllerRotate(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		accessKey, err := server.App.Auth.AccessKeyManager.Create(
+			"test",
+			[]auth.AccessKeyStatement{{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}}},
+		)
+
+		if err != nil {
+			t.Fatalf("Failed to create test access key: %v", err)
+		}
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   "Allow",
+				Resource: "*",
+				Actions:  []auth.Privilege{"access-key:update"},
+			},
+		})
+
+		response, statusCode, err := client.Send(fmt.Sprintf("/v1/access-keys/%s/rotate", accessKey.AccessKeyID), "POST", map[string]any{
+			"grace_period": 60,
+		})
+
+		if err != nil {
+			t.Fatalf("Failed to rotate access key: %v", err)
+		}
+
+		if statusCode != 200 {
+			t.Fatalf("Unexpected status code: %d, expected 200", statusCode)
+		}
+
+		data := response["data"].(map[string]any)
+
+		if data["access_key_secret"] == nil || data["access_key_secret"] == accessKey.AccessKeySecret {
+			t.Errorf("Expected a new access key secret, got %v", data["access_key_secret"])
+		}
+
+		if data["rotated_at"] == nil || data["previous_secret_expires_at"] == nil {
+			t.Errorf("Unexpected response: %v", response)
+		}
+
+		// Both the previous and the new secret are accepted during the grace period
+		for _, secret := range []string{accessKey.AccessKeySecret, data["access_key_secret"].(string)} {
+			keyClient := &test.TestClient{
+				AccessKey: &auth.AccessKey{AccessKeyID: accessKey.AccessKeyID, AccessKeySecret: secret},
+				URL:       server.Server.URL,
+			}
+
+			_, statusCode, err := keyClient.Send("/v1/access-keys", "GET", nil)
+
+			if err != nil {
+				t.Fatalf("Failed to send request: %v", err)
+			}
+
+			if statusCode != 200 {
+				t.Errorf("Unexpected status code: %d, expected 200", statusCode)
+			}
+		}
+	})
+}
+
+func TestAccessKeyControllerRotate_WithoutGracePeriod(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		accessKey, err := server.App.Auth.AccessKeyManager.Create(
+			"test",
+			[]auth.AccessKeyStatement{{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}}},
+		)
+
+		if err != nil {
+			t.Fatalf("Failed to create test access key: %v", err)
+		}
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"access-key:update"}},
+		})
+
+		_, statusCode, err := client.Send(fmt.Sprintf("/v1/access-keys/%s/rotate", accessKey.AccessKeyID), "POST", map[string]any{
+			"grace_period": 0,
+		})
+
+		if err != nil || statusCode != 200 {
+			t.Fatalf("Failed to rotate access key: %d, %v", statusCode, err)
+		}
+
+		oldClient := &test.TestClient{
+			AccessKey: &auth.AccessKey{AccessKeyID: accessKey.AccessKeyID, AccessKeySecret: accessKey.AccessKeySecret},
+			URL:       server.Server.URL,
+		}
+
+		_, statusCode, err = oldClient.Send("/v1/access-keys", "GET", nil)
+
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+
+		if statusCode != 401 {
+			t.Errorf("Unexpected status code: %d, expected 401", statusCode)
+		}
+	})
+}
+
+func TestAccessKeyControllerRotate_WithInvalidInput(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		accessKey, err := server.App.Auth.AccessKeyManager.Create(
+			"test",
+			[]auth.AccessKeyStatement{{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}}},
+		)
+
+		if err != nil {
+			t.Fatalf("Failed to create test access key: %v", err)
+		}
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"access-key:update"}},
+		})
+
+		_, statusCode, err := client.Send(fmt.Sprintf("/v1/access-keys/%s/rotate", accessKey.AccessKeyID), "POST", map[string]any{
+			"grace_period": -1,
+		})
+
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+
+		if statusCode != 422 {
+			t.Errorf("Unexpected status code: %d, expected 422", statusCode)
+		}
+	})
+}
+
+func TestAccessKeyControllerRotate_WithUnauthorizedAccessKey(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		accessKey, err := server.App.Auth.AccessKeyManager.Create(
+			"test",
+			[]auth.AccessKeyStatement{{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}}},
+		)
+
+		if err != nil {
+			t.Fatalf("Failed to create test access key: %v", err)
+		}
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{Effect: "Allow", Resource: "access-key:foobar", Actions: []auth.Privilege{"access-key:update"}},
+		})
+
+		_, statusCode, err := client.Send(fmt.Sprintf("/v1/access-keys/%s/rotate", accessKey.AccessKeyID), "POST", nil)
+
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+
+		if statusCode != 403 {
+			t.Errorf("Unexpected status code: %d, expected 403", statusCode)
+		}
+	})
+}
+
+func TestAccessKeyControllerIndex_WithExpiredAccessKey(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		expiresAt := time.Now().UTC().Add(time.Second)
+
+		accessKey, err := server.App.Auth.AccessKeyManager.CreateWithExpiration(
+			"test",
+			[]auth.AccessKeyStatement{{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}}},
+			&expiresAt,
+		)
+
+		if err != nil {
+			t.Fatalf("Failed to create test access key: %v", err)
+		}
+
+		client := &test.TestClient{AccessKey: accessKey, URL: server.Server.URL}
+
+		_, statusCode, err := client.Send("/v1/access-keys", "GET", nil)
+
+		if err != nil || statusCode != 200 {
+			t.Fatalf("Expected the access key to be valid before it expires: %d, %v", statusCode, err)
+		}
+
+		time.Sleep(time.Until(expiresAt))
+
+		_, statusCode, err = client.Send("/v1/access-keys", "GET", nil)
+
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+
+		if statusCode != 401 {
+			t.Errorf("Unexpected status code: %d, expected 401", statusCode)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"errors"
+	"time"
+
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+type AccessTokenStoreRequest struct {
+	Claims      map[string]string         `json:"claims" validate:"omitempty,max=50"`
+	RowPolicies []auth.AccessKeyRowPolicy `json:"row_policies" validate:"omitempty,max=100,dive,validateFn=IsValid"`
+	Statements  []auth.AccessKeyStatement `json:"statements" validate:"required,min=1,max=100,dive,validateFn=IsValid"`
+	TTL         *int64                    `json:"ttl" validate:"omitempty,min=1,max=3600"`
+}
+
+// Issue a short-lived access token for the access key of the request. The
+// token is limited to the given statements, which must be a subset of the
+// statements of the access key.
+func AccessTokenControllerStore(request *Request) Response {
+	requestToken := request.RequestToken("Authorization")
+
+	// Access tokens can only be issued with a signed request so that a token
+	// can not be used to extend its own lifetime.
+	if !requestToken.Valid() || requestToken.IsBearer() {
+		return ForbiddenResponse(errors.New("access tokens can only be issued for a request signed with an access key"))
+	}
+
+	accessKey := requestToken.AccessKey()
+
+	if accessKey == nil {
+		return ErrInvalidAccessKeyResponse
+	}
+
+	input, err := request.Input(&AccessTokenStoreRequest{})
+
+	if err != nil {
+		return BadRequestResponse(errors.New("the request input is invalid"))
+	}
+
+	validationErrors := request.Validate(input, map[string]string{
+		"statements.max":                    "The statements field must contain at most 100 items",
+		"statements.min":                    "The statements field must contain at least 1 item",
+		"statements.required":               "The statements field is required",
+		"statements.*.validateFn":           "This statement is not valid. All actions must match the resource.",
+		"statements.*.effect.required":      "Each statement must have an effect",
+		"statements.*.effect.validateFn":    "The effect of the statement must be one of 'Allow' or 'Deny'",
+		"statements.*.resource.required":    "This statement is missing a resource",
+		"statements.*.resource.validateFn":  "This resource is not valid",
+		"statements.*.actions.required":     "This statement is missing actions",
+		"statements.*.actions.min":          "Each statement must have at least one action",
+		"statements.*.actions.max":          "Each statement can have at most 100 actions",
+		"claims.max":                        "The claims field must contain at most 50 items",
+		"row_policies.max":                  "The row policies field must contain at most 100 items",
+		"row_policies.*.validateFn":         "This row policy is not valid. The resource must be a table and the condition a single expression.",
+		"row_policies.*.resource.required":  "This row policy is missing a resource",
+		"row_policies.*.condition.required": "This row policy is missing a condition",
+		"row_policies.*.condition.max":      "The condition of a row policy must be at most 1024 characters long",
+		"ttl.min":                           "The ttl must be at least 1 second",
+		"ttl.max":                           "The ttl must be at most 3600 seconds",
+	})
+
+	if validationErrors != nil {
+		return ValidationErrorResponse(validationErrors)
+	}
+
+	ttl := auth.AccessTokenDefaultTTL
+
+	if input.(*AccessTokenStoreRequest).TTL != nil {
+		ttl = time.Duration(*input.(*AccessTokenStoreRequest).TTL) * time.Second
+	}
+
+	token, accessToken, err := request.accessKeyManager.IssueAccessTokenWithRowPolicies(
+		accessKey,
+		input.(*AccessTokenStoreRequest).Statements,
+		input.(*AccessTokenStoreRequest).Claims,
+		input.(*AccessTokenStoreRequest).RowPolicies,
+		ttl,
+	)
+
+	if err != nil {
+		if errors.Is(err, auth.ErrAccessTokenScope) {
+			return ForbiddenResponse(err)
+		}
+
+		return ServerErrorResponse(err)
+	}
+
+	return JsonResponse(map[string]any{
+		"status":  "success",
+		"message": "Access token issued successfully",
+		"data": map[string]any{
+			"access_key_id": accessToken.AccessKeyID,
+			"access_token":  token,
+			"claims":        accessToken.Claims,
+			"expires_at":    time.Unix(accessToken.ExpiresAt, 0).UTC(),
+			"row_policies":  accessToken.RowPolicies,
+			"statements":    accessToken.Statements,
+			"token_type":    "Bearer",
+		},
+	}, 201, nil)
+}
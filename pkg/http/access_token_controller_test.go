@@ -0,0 +1,136 @@
+package http_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func sendWithAccessToken(t *testing.T, url, method, token string) int {
+	request, err := http.NewRequest(method, url, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	response, err := http.DefaultClient.Do(request)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer response.Body.Close()
+
+	return response.StatusCode
+}
+
+func TestAccessTokenControllerStore(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}},
+		})
+
+		response, statusCode, err := client.Send("/v1/access-tokens", "POST", map[string]any{
+			"statements": []map[string]any{
+				{"effect": "allow", "resource": "access-key:*", "actions": []auth.Privilege{auth.AccessKeyPrivilegeList}},
+			},
+			"ttl": 60,
+		})
+
+		if err != nil {
+			t.Fatalf("Failed to issue access token: %v", err)
+		}
+
+		if statusCode != 201 {
+			t.Fatalf("Unexpected status code: %d, expected 201: %v", statusCode, response)
+		}
+
+		data := response["data"].(map[string]any)
+		token, ok := data["access_token"].(string)
+
+		if !ok || token == "" {
+			t.Fatalf("Expected an access token, got %v", data)
+		}
+
+		if data["expires_at"] == nil || data["token_type"] != "Bearer" {
+			t.Errorf("Unexpected response: %v", response)
+		}
+
+		// The token authorizes the requests within its statements
+		if statusCode := sendWithAccessToken(t, server.Server.URL+"/v1/access-keys", "GET", token); statusCode != 200 {
+			t.Errorf("Unexpected status code: %d, expected 200", statusCode)
+		}
+
+		// The token does not authorize requests outside of its statements
+		if statusCode := sendWithAccessToken(t, server.Server.URL+"/v1/databases", "GET", token); statusCode != 403 {
+			t.Errorf("Unexpected status code: %d, expected 403", statusCode)
+		}
+
+		// The token can not be used to issue another token
+		if statusCode := sendWithAccessToken(t, server.Server.URL+"/v1/access-tokens", "POST", token); statusCode != 403 {
+			t.Errorf("Unexpected status code: %d, expected 403", statusCode)
+		}
+
+		if statusCode := sendWithAccessToken(t, server.Server.URL+"/v1/access-keys", "GET", token+"x"); statusCode != 401 {
+			t.Errorf("Unexpected status code: %d, expected 401", statusCode)
+		}
+	})
+}
+
+func TestAccessTokenControllerStore_WithBroaderStatements(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{Effect: "Allow", Resource: "database:test:*", Actions: []auth.Privilege{auth.DatabasePrivilegeQuery}},
+		})
+
+		_, statusCode, err := client.Send("/v1/access-tokens", "POST", map[string]any{
+			"statements": []map[string]any{
+				{"effect": "allow", "resource": "*", "actions": []auth.Privilege{"*"}},
+			},
+		})
+
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+
+		if statusCode != 403 {
+			t.Errorf("Unexpected status code: %d, expected 403", statusCode)
+		}
+	})
+}
+
+func TestAccessTokenControllerStore_WithInvalidInput(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}},
+		})
+
+		_, statusCode, err := client.Send("/v1/access-tokens", "POST", map[string]any{
+			"statements": []map[string]any{
+				{"effect": "allow", "resource": "*", "actions": []auth.Privilege{"*"}},
+			},
+			"ttl": 7200,
+		})
+
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+
+		if statusCode != 422 {
+			t.Errorf("Unexpected status code: %d, expected 422", statusCode)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/logs"
+)
+
+// List a page of the entries of the audit log, optionally filtered by a time
+// range, an action, or an actor.
+func AuditController(request *Request) Response {
+	err := request.Authorize(
+		[]string{"*", fmt.Sprintf("cluster:%s", request.cluster.Id)},
+		[]auth.Privilege{auth.ClusterPrivilegeManage},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	filter := logs.AuditLogFilter{
+		Action: request.QueryParam("action"),
+		Actor:  request.QueryParam("actor"),
+	}
+
+	if start := request.QueryParam("start"); start != "" {
+		filter.Start, err = strconv.ParseInt(start, 10, 64)
+
+		if err != nil {
+			return BadRequestResponse(errors.New("invalid start timestamp"))
+		}
+	}
+
+	if end := request.QueryParam("end"); end != "" {
+		filter.End, err = strconv.ParseInt(end, 10, 64)
+
+		if err != nil {
+			return BadRequestResponse(errors.New("invalid end timestamp"))
+		}
+	}
+
+	if limit := request.QueryParam("limit"); limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+
+		if err != nil || filter.Limit < 1 {
+			return BadRequestResponse(errors.New("invalid limit"))
+		}
+	}
+
+	if offset := request.QueryParam("offset"); offset != "" {
+		filter.Offset, err = strconv.Atoi(offset)
+
+		if err != nil || filter.Offset < 0 {
+			return BadRequestResponse(errors.New("invalid offset"))
+		}
+	}
+
+	entries, next, err := request.logManager.GetAuditLog(request.cluster).Read(filter)
+
+	if err != nil {
+		if errors.Is(err, logs.ErrAuditLogRange) {
+			return BadRequestResponse(err)
+		}
+
+		return ServerErrorResponse(err)
+	}
+
+	response := SuccessResponse(
+		"Successfully retrieved audit log entries.",
+		entries,
+		200,
+	)
+
+	// The offset of the next page is only returned when there are more
+	// entries.
+	if next > 0 {
+		response.Body["next_offset"] = next
+	}
+
+	return response
+}
+
+// Verify the hash chains of the audit log.
+func AuditVerifyController(request *Request) Response {
+	err := request.Authorize(
+		[]string{"*", fmt.Sprintf("cluster:%s", request.cluster.Id)},
+		[]auth.Privilege{auth.ClusterPrivilegeManage},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	chains, err := request.logManager.GetAuditLog(request.cluster).Verify()
+
+	if err != nil {
+		if errors.Is(err, logs.ErrAuditLogTampered) {
+			return JsonResponse(map[string]any{
+				"status":  "error",
+				"message": err.Error(),
+			}, 409, nil)
+		}
+
+		return ServerErrorResponse(err)
+	}
+
+	return SuccessResponse(
+		"The audit log is intact.",
+		chains,
+		200,
+	)
+}
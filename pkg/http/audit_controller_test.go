@@ -0,0 +1,96 @@
+package http_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/logs"
+)
+
+func TestAuditController(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   "Allow",
+				Resource: "*",
+				Actions:  []auth.Privilege{"*"},
+			},
+		})
+
+		_, statusCode, err := client.Send("/v1/access-keys", "POST", map[string]any{
+			"description": "Audited",
+			"statements": []map[string]any{
+				{"effect": "Allow", "resource": "*", "actions": []string{"*"}},
+			},
+		})
+
+		if err != nil || statusCode != 201 {
+			t.Fatalf("Failed to create access key: %d %v", statusCode, err)
+		}
+
+		response, statusCode, err := client.Send("/v1/audit?action="+logs.AuditActionAccessKeyCreate, "GET", nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if statusCode != 200 {
+			t.Fatalf("Unexpected status code: %d, expected 200", statusCode)
+		}
+
+		entries, ok := response["data"].([]any)
+
+		if !ok || len(entries) != 1 {
+			t.Fatalf("Expected a single audit log entry, got %v", response["data"])
+		}
+
+		entry := entries[0].(map[string]any)
+
+		if entry["actor"] != client.AccessKey.AccessKeyID || entry["actor_type"] != logs.AuditActorAccessKey {
+			t.Errorf("Expected the entry to record the access key, got %v", entry)
+		}
+
+		if entry["hash"] == "" {
+			t.Error("Expected the entry to have a hash")
+		}
+
+		response, statusCode, err = client.Send("/v1/audit/verify", "GET", nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if statusCode != 200 {
+			t.Fatalf("Expected the audit log to be intact, got %d: %v", statusCode, response["message"])
+		}
+	})
+}
+
+func TestAuditController_Unauthorized(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   "Allow",
+				Resource: "database:*",
+				Actions:  []auth.Privilege{auth.DatabasePrivilegeQuery},
+			},
+		})
+
+		_, statusCode, err := client.Send("/v1/audit", "GET", nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if statusCode != 403 {
+			t.Errorf("Unexpected status code: %d, expected 403", statusCode)
+		}
+	})
+}
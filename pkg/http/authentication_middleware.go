@@ -1,22 +1,61 @@
 package http
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"strconv"
 	"time"
+
+	"github.com/litebase/litebase/pkg/auth"
 )
 
-func basicAuth(request *Request) bool {
+// Authenticate a request with basic auth. Logins that are locked out after
+// too many failed attempts are refused with a response asking the client to
+// retry later.
+func basicAuth(request *Request) (bool, *Response) {
 	username, password, ok := request.BaseRequest.BasicAuth()
 
-	if ok {
-		return request.cluster.Auth.UserManager().Authenticate(username, password)
+	if !ok {
+		return false, nil
+	}
+
+	err := request.cluster.Auth.UserManager().AuthenticateFrom(
+		username,
+		password,
+		request.AuthorizationContext().SourceIP,
+	)
+
+	var lockedError *auth.LoginLockedError
+
+	if errors.As(err, &lockedError) {
+		response := JsonResponse(map[string]any{
+			"status":  "error",
+			"message": fmt.Sprintf("Too Many Requests: %s", lockedError.Error()),
+		}, 429, map[string]string{
+			"Retry-After": strconv.Itoa(int(math.Ceil(lockedError.RetryAfter.Seconds()))),
+		})
+
+		return false, &response
 	}
 
-	return false
+	return err == nil, nil
 }
 
 func Authentication(request *Request) (*Request, Response) {
-	if basicAuth(request) {
+	if ok, response := basicAuth(request); ok {
+		return request, Response{}
+	} else if response != nil {
+		return request, *response
+	}
+
+	// Requests with a valid single sign-on session do not need to be signed
+	if request.UserSession() != nil {
+		return request, Response{}
+	}
+
+	// Requests with a valid access token do not need to be signed
+	if request.RequestToken("Authorization").IsBearer() {
 		return request, Response{}
 	}
 
@@ -89,3 +89,82 @@ func TestAuthenticationMiddleware(t *testing.T) {
 		}
 	})
 }
+
+func TestAuthenticationMiddlewareLocksOutFailedLogins(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		_, err := server.App.Auth.UserManager().Add("jane", "correct-horse", []auth.AccessKeyStatement{
+			{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		now := time.Now()
+
+		server.App.Auth.UserManager().SetClock(func() time.Time {
+			return now
+		})
+
+		authenticate := func(username, password, remoteAddr string) appHttp.Response {
+			request, err := http.NewRequest("GET", "/users", nil)
+
+			if err != nil {
+				t.Fatalf("Failed to create request: %s", err.Error())
+			}
+
+			request.RemoteAddr = remoteAddr
+			request.SetBasicAuth(username, password)
+
+			_, res := appHttp.Authentication(appHttp.NewRequest(
+				server.App.Cluster,
+				server.App.DatabaseManager,
+				server.App.LogManager,
+				request,
+			))
+
+			return res
+		}
+
+		for i := range server.App.Config.LoginMaxAttempts + 1 {
+			if res := authenticate("jane", "wrong-password", "10.0.0.1:1234"); res.StatusCode != http.StatusUnauthorized {
+				t.Fatalf("Attempt %d: expected status code %d, got %d", i+1, http.StatusUnauthorized, res.StatusCode)
+			}
+		}
+
+		// The user is locked out even with the correct password
+		res := authenticate("jane", "correct-horse", "10.0.0.2:1234")
+
+		if res.StatusCode != http.StatusTooManyRequests {
+			t.Fatalf("Expected status code %d, got %d", http.StatusTooManyRequests, res.StatusCode)
+		}
+
+		if res.Headers["Retry-After"] != "1" {
+			t.Errorf("Expected a Retry-After header of 1 second, got %q", res.Headers["Retry-After"])
+		}
+
+		// The source address is locked out for other users as well
+		res = authenticate(server.App.Config.RootUsername, server.App.Config.RootPassword, "10.0.0.1:1234")
+
+		if res.StatusCode != http.StatusTooManyRequests {
+			t.Fatalf("Expected status code %d, got %d", http.StatusTooManyRequests, res.StatusCode)
+		}
+
+		res = authenticate(server.App.Config.RootUsername, server.App.Config.RootPassword, "10.0.0.3:1234")
+
+		if res.StatusCode != 0 {
+			t.Fatalf("Expected status code %d, got %d", 0, res.StatusCode)
+		}
+
+		now = now.Add(time.Second)
+
+		res = authenticate("jane", "correct-horse", "10.0.0.2:1234")
+
+		if res.StatusCode != 0 {
+			t.Fatalf("Expected the lockout to end, got status code %d", res.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+// Return the context the conditions of access key statements are evaluated
+// against for this request. The X-Forwarded-For and X-Forwarded-Proto headers
+// are only trusted when the request was sent by one of the trusted proxies, or
+// forwarded by a node that passed the internal connection checks.
+func (request *Request) AuthorizationContext() *auth.AuthorizationContext {
+	if request.authorizationContext != nil {
+		return request.authorizationContext
+	}
+
+	trustedProxies := request.trustedProxies()
+	sourceIP := remoteAddr(request.BaseRequest.RemoteAddr)
+	tls := request.BaseRequest.TLS != nil
+
+	if isTrustedProxy(trustedProxies, sourceIP) || request.forwardedByNode() {
+		if proto := request.BaseRequest.Header.Get("X-Forwarded-Proto"); proto != "" {
+			tls = strings.EqualFold(strings.TrimSpace(strings.Split(proto, ",")[0]), "https")
+		}
+
+		// Walk the forwarded addresses from the closest proxy to the client
+		// and use the first address that is not a trusted proxy.
+		var forwarded []string
+
+		for _, value := range request.BaseRequest.Header.Values("X-Forwarded-For") {
+			forwarded = append(forwarded, strings.Split(value, ",")...)
+		}
+
+		for _, value := range slices.Backward(forwarded) {
+			address, err := netip.ParseAddr(strings.TrimSpace(value))
+
+			if err != nil {
+				break
+			}
+
+			sourceIP = address.Unmap()
+
+			if !isTrustedProxy(trustedProxies, sourceIP) {
+				break
+			}
+		}
+	}
+
+	request.authorizationContext = &auth.AuthorizationContext{
+		Replica:  !request.cluster.Node().IsPrimary(),
+		SourceIP: sourceIP,
+		Time:     time.Now().UTC(),
+		TLS:      tls,
+	}
+
+	return request.authorizationContext
+}
+
+// Determine if the request was forwarded by another node of the cluster. The
+// request must pass the same checks as requests to internal routes.
+func (request *Request) forwardedByNode() bool {
+	if request.BaseRequest.Header.Get("X-Lbdb-Node") == "" {
+		return false
+	}
+
+	_, response := Internal(request)
+
+	return response.StatusCode == 0
+}
+
+// Parse the trusted proxies of the configuration.
+func (request *Request) trustedProxies() []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(request.cluster.Config.TrustedProxies))
+
+	for _, value := range request.cluster.Config.TrustedProxies {
+		if !strings.Contains(value, "/") {
+			address, err := netip.ParseAddr(value)
+
+			if err != nil {
+				continue
+			}
+
+			address = address.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(address, address.BitLen()))
+
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(value)
+
+		if err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		}
+	}
+
+	return prefixes
+}
+
+// Determine if the address is one of the trusted proxies.
+func isTrustedProxy(trustedProxies []netip.Prefix, address netip.Addr) bool {
+	if !address.IsValid() {
+		return false
+	}
+
+	return slices.ContainsFunc(trustedProxies, func(prefix netip.Prefix) bool {
+		return prefix.Contains(address)
+	})
+}
+
+// Parse the address of the remote end of the connection.
+func remoteAddr(value string) netip.Addr {
+	if addressPort, err := netip.ParseAddrPort(value); err == nil {
+		return addressPort.Addr().Unmap()
+	}
+
+	if address, err := netip.ParseAddr(value); err == nil {
+		return address.Unmap()
+	}
+
+	return netip.Addr{}
+}
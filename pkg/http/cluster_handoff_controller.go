@@ -0,0 +1,79 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/cluster"
+)
+
+type ClusterHandoffRequest struct {
+	Address string `json:"address" validate:"required"`
+}
+
+// Transfer the primary role of the cluster to the replica with the given
+// address.
+func ClusterHandoffController(request *Request) Response {
+	// Authorize the request
+	err := request.Authorize(
+		[]string{"*", fmt.Sprintf("cluster:%s", request.cluster.Id)},
+		[]auth.Privilege{auth.ClusterPrivilegeManage},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	input, err := request.Input(&ClusterHandoffRequest{})
+
+	if err != nil {
+		return BadRequestResponse(err)
+	}
+
+	validationErrors := request.Validate(input, map[string]string{
+		"address.required": "The address field is required.",
+	})
+
+	if validationErrors != nil {
+		return ValidationErrorResponse(validationErrors)
+	}
+
+	address := input.(*ClusterHandoffRequest).Address
+	previousPrimary, _ := request.cluster.Node().Address()
+
+	ctx, cancel := context.WithTimeout(request.BaseRequest.Context(), cluster.NodeHandoffTimeout)
+	defer cancel()
+
+	err = request.cluster.Node().Handoff(ctx, address)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, cluster.ErrHandoffInProgress):
+			return JsonResponse(map[string]any{
+				"status":  "error",
+				"message": fmt.Sprintf("Error: %s", err.Error()),
+			}, 409, nil)
+		case errors.Is(err, cluster.ErrHandoffNotPrimary):
+			return JsonResponse(map[string]any{
+				"status":  "error",
+				"message": fmt.Sprintf("Error: %s", err.Error()),
+			}, 503, nil)
+		case errors.Is(err, cluster.ErrHandoffTargetIsSelf),
+			errors.Is(err, cluster.ErrHandoffTargetNotFound):
+			return BadRequestResponse(err)
+		}
+
+		return ServerErrorResponse(err)
+	}
+
+	return SuccessResponse(
+		"Successfully handed off the primary role.",
+		map[string]any{
+			"previous_primary": previousPrimary,
+			"primary":          address,
+		},
+		200,
+	)
+}
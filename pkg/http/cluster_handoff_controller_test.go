@@ -0,0 +1,106 @@
+package http_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestClusterHandoffController(t *testing.T) {
+	test.Run(t, func() {
+		server1 := test.NewTestServer(t)
+		defer server1.Shutdown()
+
+		server2 := test.NewTestServer(t)
+		defer server2.Shutdown()
+
+		address, err := server2.App.Cluster.Node().Address()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		client := server1.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   auth.AccessKeyEffectAllow,
+				Resource: "*",
+				Actions:  []auth.Privilege{auth.ClusterPrivilegeManage},
+			},
+		})
+
+		resp, statusCode, err := client.Send("/v1/cluster/handoff", "POST", map[string]any{
+			"address": address,
+		})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if statusCode != 200 {
+			t.Fatalf("Expected status code 200, got %d: %v", statusCode, resp)
+		}
+
+		data := resp["data"].(map[string]any)
+
+		if data["primary"] != address {
+			t.Errorf("Expected primary %s, got %v", address, data["primary"])
+		}
+
+		if !server2.App.Cluster.Node().IsPrimary() {
+			t.Error("Expected the target node to be primary")
+		}
+	})
+}
+
+func TestClusterHandoffController_Validation(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   auth.AccessKeyEffectAllow,
+				Resource: "*",
+				Actions:  []auth.Privilege{auth.ClusterPrivilegeManage},
+			},
+		})
+
+		_, statusCode, _ := client.Send("/v1/cluster/handoff", "POST", map[string]any{})
+
+		if statusCode != 422 {
+			t.Errorf("Expected status code 422, got %d", statusCode)
+		}
+
+		_, statusCode, _ = client.Send("/v1/cluster/handoff", "POST", map[string]any{
+			"address": "10.0.0.0:9876",
+		})
+
+		if statusCode != 400 {
+			t.Errorf("Expected status code 400, got %d", statusCode)
+		}
+	})
+}
+
+func TestClusterHandoffController_Forbidden(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   auth.AccessKeyEffectAllow,
+				Resource: "*",
+				Actions:  []auth.Privilege{auth.DatabasePrivilegeList},
+			},
+		})
+
+		_, statusCode, _ := client.Send("/v1/cluster/handoff", "POST", map[string]any{
+			"address": "10.0.0.0:9876",
+		})
+
+		if statusCode != 403 {
+			t.Errorf("Expected status code 403, got %d", statusCode)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/cluster"
+)
+
+// List the members of the cluster with their role, heartbeat, lease and
+// replication state.
+func ClusterNodeControllerIndex(request *Request) Response {
+	err := request.Authorize(
+		[]string{"*", fmt.Sprintf("cluster:%s", request.cluster.Id)},
+		[]auth.Privilege{auth.ClusterPrivilegeManage},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	statuses, err := request.cluster.MemberStatuses()
+
+	if err != nil {
+		if errors.Is(err, cluster.ErrNotPrimary) {
+			return JsonResponse(map[string]any{
+				"status":  "error",
+				"message": fmt.Sprintf("Error: %s", err.Error()),
+			}, 503, nil)
+		}
+
+		return ServerErrorResponse(err)
+	}
+
+	return SuccessResponse(
+		"Cluster members retrieved successfully.",
+		statuses,
+		200,
+	)
+}
+
+// Remove a member that is no longer sending heartbeats from the cluster.
+func ClusterNodeControllerDestroy(request *Request) Response {
+	err := request.Authorize(
+		[]string{"*", fmt.Sprintf("cluster:%s", request.cluster.Id)},
+		[]auth.Privilege{auth.ClusterPrivilegeManage},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	address := request.Param("address")
+
+	err = request.cluster.RemoveDeadMember(address)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, cluster.ErrMemberNotFound):
+			return NotFoundResponse(err)
+		case errors.Is(err, cluster.ErrMemberActive):
+			return JsonResponse(map[string]any{
+				"status":  "error",
+				"message": fmt.Sprintf("Error: %s", err.Error()),
+			}, 409, nil)
+		case errors.Is(err, cluster.ErrMemberIsSelf):
+			return BadRequestResponse(err)
+		case errors.Is(err, cluster.ErrNotPrimary):
+			return JsonResponse(map[string]any{
+				"status":  "error",
+				"message": fmt.Sprintf("Error: %s", err.Error()),
+			}, 503, nil)
+		}
+
+		return ServerErrorResponse(err)
+	}
+
+	return SuccessResponse(
+		"Cluster member removed successfully.",
+		map[string]any{
+			"address": address,
+		},
+		200,
+	)
+}
@@ -0,0 +1,96 @@
+package http_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestClusterNodeControllerIndex(t *testing.T) {
+	test.Run(t, func() {
+		server1 := test.NewTestServer(t)
+		defer server1.Shutdown()
+
+		server2 := test.NewTestServer(t)
+		defer server2.Shutdown()
+
+		client := server1.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   auth.AccessKeyEffectAllow,
+				Resource: "*",
+				Actions:  []auth.Privilege{auth.ClusterPrivilegeManage},
+			},
+		})
+
+		resp, statusCode, err := client.Send("/v1/cluster/nodes", "GET", nil)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if statusCode != 200 {
+			t.Fatalf("Expected status code 200, got %d: %v", statusCode, resp)
+		}
+
+		members, ok := resp["data"].([]any)
+
+		if !ok || len(members) != 2 {
+			t.Fatalf("Expected 2 members, got %v", resp["data"])
+		}
+	})
+}
+
+func TestClusterNodeControllerDestroy(t *testing.T) {
+	test.Run(t, func() {
+		server1 := test.NewTestServer(t)
+		defer server1.Shutdown()
+
+		server2 := test.NewTestServer(t)
+		defer server2.Shutdown()
+
+		address, _ := server2.App.Cluster.Node().Address()
+
+		client := server1.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   auth.AccessKeyEffectAllow,
+				Resource: "*",
+				Actions:  []auth.Privilege{auth.ClusterPrivilegeManage},
+			},
+		})
+
+		_, statusCode, _ := client.Send(fmt.Sprintf("/v1/cluster/nodes/%s", address), "DELETE", nil)
+
+		if statusCode != 409 {
+			t.Errorf("Expected status code 409, got %d", statusCode)
+		}
+
+		_, statusCode, _ = client.Send("/v1/cluster/nodes/10.0.0.0:9876", "DELETE", nil)
+
+		if statusCode != 404 {
+			t.Errorf("Expected status code 404, got %d", statusCode)
+		}
+	})
+}
+
+func TestClusterNodeController_Forbidden(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   auth.AccessKeyEffectAllow,
+				Resource: "*",
+				Actions:  []auth.Privilege{auth.DatabasePrivilegeList},
+			},
+		})
+
+		_, statusCode, _ := client.Send("/v1/cluster/nodes", "GET", nil)
+
+		if statusCode != 403 {
+			t.Errorf("Expected status code 403, got %d", statusCode)
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+// Return the replication lag of each replica for every database branch that
+// has been replicated by the primary.
+func ClusterReplicationController(request *Request) Response {
+	err := request.Authorize(
+		[]string{"*", fmt.Sprintf("cluster:%s", request.cluster.Id)},
+		[]auth.Privilege{auth.ClusterPrivilegeManage},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	return SuccessResponse(
+		"Replication lag retrieved successfully.",
+		request.cluster.Node().ReplicationLagTracker().Metrics(),
+		200,
+	)
+}
@@ -0,0 +1,46 @@
+package http_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestClusterReplicationController(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		server.App.Cluster.Node().ReplicationLagTracker().Primary("db", "main", 2_000_000_000)
+		server.App.Cluster.Node().ReplicationLagTracker().Replica("10.0.0.1:8080", "db", "main", 1_000_000_000)
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   auth.AccessKeyEffectAllow,
+				Resource: "*",
+				Actions:  []auth.Privilege{auth.ClusterPrivilegeManage},
+			},
+		})
+
+		resp, statusCode, err := client.Send("/v1/cluster/replication", "GET", nil)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if statusCode != 200 {
+			t.Fatalf("Expected status code 200, got %d", statusCode)
+		}
+
+		metrics, ok := resp["data"].([]any)
+
+		if !ok || len(metrics) != 1 {
+			t.Fatalf("Expected 1 metric, got %v", resp["data"])
+		}
+
+		if lag := metrics[0].(map[string]any)["lag_ms"]; lag != float64(1000) {
+			t.Errorf("Expected a lag of 1000ms, got %v", lag)
+		}
+	})
+}
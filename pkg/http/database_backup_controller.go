@@ -9,6 +9,8 @@ import (
 
 	"github.com/litebase/litebase/pkg/auth"
 	"github.com/litebase/litebase/pkg/backups"
+	"github.com/litebase/litebase/pkg/database"
+	"github.com/litebase/litebase/pkg/logs"
 )
 
 func DatabaseBackupIndexController(request *Request) Response {
@@ -134,6 +136,12 @@ func DatabaseBackupStoreController(request *Request) Response {
 		return ServerErrorResponse(err)
 	}
 
+	request.databaseManager.StorageAccountant().Invalidate(db.DatabaseID, branch.DatabaseBranchID, database.StorageUsageBackups)
+
+	request.Audit(logs.AuditActionBackupCreate, fmt.Sprintf("database:%s:branch:%s", db.DatabaseID, branch.DatabaseBranchID), map[string]string{
+		"timestamp": strconv.FormatInt(backup.RestorePoint.Timestamp, 10),
+	})
+
 	return JsonResponse(map[string]any{
 		"status":  "success",
 		"message": "Database backup created successfully",
@@ -259,6 +267,12 @@ func DatabaseBackupDestroyController(request *Request) Response {
 		return ServerErrorResponse(err)
 	}
 
+	request.databaseManager.StorageAccountant().Invalidate(databaseKey.DatabaseID, databaseKey.DatabaseBranchID, database.StorageUsageBackups)
+
+	request.Audit(logs.AuditActionBackupDelete, fmt.Sprintf("database:%s:branch:%s", databaseKey.DatabaseID, databaseKey.DatabaseBranchID), map[string]string{
+		"timestamp": strconv.FormatInt(timestamp, 10),
+	})
+
 	return JsonResponse(map[string]any{
 		"status":  "success",
 		"message": "Database backup deleted successfully",
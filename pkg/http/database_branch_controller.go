@@ -8,6 +8,7 @@ import (
 
 	"github.com/litebase/litebase/pkg/auth"
 	"github.com/litebase/litebase/pkg/database"
+	"github.com/litebase/litebase/pkg/logs"
 )
 
 // List all branches for a specific database
@@ -155,6 +156,10 @@ func DatabaseBranchStoreController(request *Request) Response {
 		return ServerErrorResponse(err)
 	}
 
+	request.Audit(logs.AuditActionBranchCreate, fmt.Sprintf("database:%s:branch:%s", db.DatabaseID, branch.DatabaseBranchID), map[string]string{
+		"name": branch.Name,
+	})
+
 	return SuccessResponse(
 		"Database branch created successfully.",
 		branch,
@@ -210,6 +215,10 @@ func DatabaseBranchDestroyController(request *Request) Response {
 		return ServerErrorResponse(err)
 	}
 
+	request.Audit(logs.AuditActionBranchDelete, fmt.Sprintf("database:%s:branch:%s", db.DatabaseID, branch.DatabaseBranchID), map[string]string{
+		"name": branch.Name,
+	})
+
 	return SuccessResponse(
 		"Database branch deleted successfully.",
 		map[string]any{},
@@ -9,6 +9,7 @@ import (
 
 	"github.com/litebase/litebase/pkg/auth"
 	"github.com/litebase/litebase/pkg/database"
+	"github.com/litebase/litebase/pkg/logs"
 )
 
 func DatabaseIndexController(request *Request) Response {
@@ -62,9 +63,64 @@ func DatabaseShowController(request *Request) Response {
 		return BadRequestResponse(err)
 	}
 
+	usage, err := request.databaseManager.StorageAccountant().DatabaseUsage(db)
+
+	if err != nil {
+		slog.Error("Failed to calculate database storage usage", "error", err, "databaseId", db.DatabaseID)
+
+		return SuccessResponse(
+			"Successfully retrieved database.",
+			db,
+			200,
+		)
+	}
+
 	return SuccessResponse(
 		"Successfully retrieved database.",
-		db,
+		database.DatabaseWithUsage{
+			Database: db,
+			Usage:    &usage.StorageUsage,
+		},
+		200,
+	)
+}
+
+func DatabaseUsageController(request *Request) Response {
+	databaseName := request.Param("databaseName")
+
+	if databaseName == "" {
+		return ErrValidDatabaseNameRequiredResponse
+	}
+
+	// Authorize the request
+	err := request.Authorize(
+		[]string{fmt.Sprintf("database:%s", databaseName)},
+		[]auth.Privilege{auth.DatabasePrivilegeShow},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	db, err := request.databaseManager.GetByName(databaseName)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return NotFoundResponse(errors.New("database not found"))
+		}
+
+		return BadRequestResponse(err)
+	}
+
+	usage, err := request.databaseManager.StorageAccountant().DatabaseUsage(db)
+
+	if err != nil {
+		return ServerErrorResponse(err)
+	}
+
+	return SuccessResponse(
+		"Successfully retrieved database storage usage.",
+		usage,
 		200,
 	)
 }
@@ -128,6 +184,10 @@ func DatabaseStoreController(request *Request) Response {
 		return ServerErrorResponse(err)
 	}
 
+	request.Audit(logs.AuditActionDatabaseCreate, fmt.Sprintf("database:%s", db.DatabaseID), map[string]string{
+		"name": db.Name,
+	})
+
 	return SuccessResponse(
 		"Database created successfully.",
 		db,
@@ -170,6 +230,10 @@ func DatabaseDestroyController(request *Request) Response {
 		return ServerErrorResponse(err)
 	}
 
+	request.Audit(logs.AuditActionDatabaseDelete, fmt.Sprintf("database:%s", db.DatabaseID), map[string]string{
+		"name": db.Name,
+	})
+
 	return SuccessResponse(
 		"Database deleted successfully.",
 		map[string]any{},
@@ -286,3 +286,73 @@ func TestDatabaseControllerDestroy(t *testing.T) {
 		})
 	})
 }
+
+func TestDatabaseControllerUsage(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		database := test.MockDatabase(server.App)
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{{
+			Effect:   "Allow",
+			Resource: "*",
+			Actions:  []auth.Privilege{auth.DatabasePrivilegeShow},
+		}})
+
+		resp, statusCode, err := client.Send(fmt.Sprintf("/v1/databases/%s/usage", database.DatabaseName), "GET", nil)
+
+		if err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+
+		if statusCode != 200 {
+			t.Fatalf("expected status code 200, got %d", statusCode)
+		}
+
+		data, ok := resp["data"].(map[string]any)
+
+		if !ok {
+			t.Fatalf("expected data to be an object, got %T", resp["data"])
+		}
+
+		if data["database_id"] != database.DatabaseID {
+			t.Fatalf("expected database id to be %s, got %v", database.DatabaseID, data["database_id"])
+		}
+
+		if _, ok := data["total"].(float64); !ok {
+			t.Fatalf("expected total to be a number, got %T", data["total"])
+		}
+
+		branches, ok := data["branches"].([]any)
+
+		if !ok || len(branches) != 1 {
+			t.Fatalf("expected 1 branch, got %v", data["branches"])
+		}
+	})
+}
+
+func TestDatabaseControllerUsage_Forbidden(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		database := test.MockDatabase(server.App)
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{{
+			Effect:   "Allow",
+			Resource: "*",
+			Actions:  []auth.Privilege{auth.DatabasePrivilegeList},
+		}})
+
+		_, statusCode, err := client.Send(fmt.Sprintf("/v1/databases/%s/usage", database.DatabaseName), "GET", nil)
+
+		if err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+
+		if statusCode != 403 {
+			t.Fatalf("expected status code 403, got %d", statusCode)
+		}
+	})
+}
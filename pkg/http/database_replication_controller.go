@@ -0,0 +1,86 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/database"
+)
+
+type DatabaseReplicationUpdateRequest struct {
+	Mode    string `json:"mode" validate:"required,oneof=async quorum"`
+	Quorum  int    `json:"quorum" validate:"required_if=Mode quorum,gte=0"`
+	Timeout int64  `json:"timeout_ms" validate:"gte=0"`
+}
+
+// Update the replication mode of a database. In quorum mode, writes return
+// only after the given number of replicas have acknowledged the WAL frames.
+func DatabaseReplicationUpdateController(request *Request) Response {
+	databaseName := request.Param("databaseName")
+
+	if databaseName == "" {
+		return ErrValidDatabaseNameRequiredResponse
+	}
+
+	db, err := request.databaseManager.GetByName(databaseName)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return NotFoundResponse(errors.New("database not found"))
+		}
+
+		return BadRequestResponse(err)
+	}
+
+	// Authorize the request
+	err = request.Authorize(
+		[]string{fmt.Sprintf("database:%s", db.DatabaseID)},
+		[]auth.Privilege{auth.DatabasePrivilegeManage},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	input, err := request.Input(&DatabaseReplicationUpdateRequest{})
+
+	if err != nil {
+		return BadRequestResponse(err)
+	}
+
+	validationErrors := request.Validate(input, map[string]string{
+		"mode.required":      "The mode field is required.",
+		"mode.oneof":         "The mode field must be either async or quorum.",
+		"quorum.required_if": "The quorum field is required when the mode is quorum.",
+		"quorum.gte":         "The quorum field must be at least 0.",
+		"timeout_ms.gte":     "The timeout_ms field must be at least 0.",
+	})
+
+	if validationErrors != nil {
+		return ValidationErrorResponse(validationErrors)
+	}
+
+	if db.Settings == nil {
+		db.Settings = &database.DatabaseSettings{}
+	}
+
+	db.Settings.Replication = database.DatabaseReplicationSettings{
+		Mode:    input.(*DatabaseReplicationUpdateRequest).Mode,
+		Quorum:  input.(*DatabaseReplicationUpdateRequest).Quorum,
+		Timeout: input.(*DatabaseReplicationUpdateRequest).Timeout,
+	}
+
+	err = db.Save()
+
+	if err != nil {
+		return ServerErrorResponse(err)
+	}
+
+	return SuccessResponse(
+		"Database replication settings updated successfully.",
+		db.Settings.Replication,
+		200,
+	)
+}
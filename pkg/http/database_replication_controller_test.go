@@ -0,0 +1,110 @@
+package http_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestDatabaseReplicationUpdateController(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		database := test.MockDatabase(server.App)
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{{
+			Effect:   "Allow",
+			Resource: "*",
+			Actions:  []auth.Privilege{auth.DatabasePrivilegeManage},
+		}})
+
+		resp, statusCode, err := client.Send(fmt.Sprintf("/v1/databases/%s/replication", database.DatabaseName), "PUT", map[string]any{
+			"mode":       "quorum",
+			"quorum":     1,
+			"timeout_ms": 1000,
+		})
+
+		if err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+
+		if statusCode != 200 {
+			t.Fatalf("expected status code 200, got %d: %v", statusCode, resp)
+		}
+
+		data, ok := resp["data"].(map[string]any)
+
+		if !ok {
+			t.Fatalf("expected data to be an object, got %T", resp["data"])
+		}
+
+		if data["mode"] != "quorum" {
+			t.Errorf("expected mode to be quorum, got %v", data["mode"])
+		}
+
+		if data["quorum"] != float64(1) {
+			t.Errorf("expected quorum to be 1, got %v", data["quorum"])
+		}
+	})
+}
+
+func TestDatabaseReplicationUpdateControllerValidation(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		database := test.MockDatabase(server.App)
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{{
+			Effect:   "Allow",
+			Resource: "*",
+			Actions:  []auth.Privilege{auth.DatabasePrivilegeManage},
+		}})
+
+		for _, body := range []map[string]any{
+			{"mode": "sync"},
+			{"mode": "quorum"},
+			{"mode": "async", "timeout_ms": -1},
+		} {
+			_, statusCode, err := client.Send(fmt.Sprintf("/v1/databases/%s/replication", database.DatabaseName), "PUT", body)
+
+			if err != nil {
+				t.Fatalf("failed to send request: %v", err)
+			}
+
+			if statusCode != 422 {
+				t.Errorf("expected status code 422 for %v, got %d", body, statusCode)
+			}
+		}
+	})
+}
+
+func TestDatabaseReplicationUpdateControllerForbidden(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		database := test.MockDatabase(server.App)
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{{
+			Effect:   "Allow",
+			Resource: "*",
+			Actions:  []auth.Privilege{auth.DatabasePrivilegeShow},
+		}})
+
+		_, statusCode, err := client.Send(fmt.Sprintf("/v1/databases/%s/replication", database.DatabaseName), "PUT", map[string]any{
+			"mode": "async",
+		})
+
+		if err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+
+		if statusCode != 403 {
+			t.Errorf("expected status code 403, got %d", statusCode)
+		}
+	})
+}
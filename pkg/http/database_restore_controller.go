@@ -6,6 +6,7 @@ import (
 
 	"github.com/litebase/litebase/pkg/auth"
 	"github.com/litebase/litebase/pkg/backups"
+	"github.com/litebase/litebase/pkg/logs"
 )
 
 type DatabaseRestoreRequest struct {
@@ -118,6 +119,13 @@ func DatabaseRestoreController(request *Request) Response {
 		}, 500, nil)
 	}
 
+	request.databaseManager.StorageAccountant().Invalidate(targetDatabase.DatabaseID, targetBranch.DatabaseBranchID)
+
+	request.Audit(logs.AuditActionDatabaseRestore, fmt.Sprintf("database:%s:branch:%s", targetDatabase.DatabaseID, targetBranch.DatabaseBranchID), map[string]string{
+		"source":    fmt.Sprintf("database:%s:branch:%s", database.DatabaseID, branch.DatabaseBranchID),
+		"timestamp": strconv.FormatInt(timestamp, 10),
+	})
+
 	return JsonResponse(map[string]any{
 		"status":  "success",
 		"message": "Database restored successfully",
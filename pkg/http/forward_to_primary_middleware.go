@@ -2,6 +2,7 @@ package http
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -31,7 +32,7 @@ func ForwardToPrimary(request *Request) (*Request, Response) {
 	}
 
 	// Parse the primary URL
-	primaryURL, err := url.Parse(fmt.Sprintf("http://%s", primaryAddress))
+	primaryURL, err := url.Parse(fmt.Sprintf("%s://%s", request.cluster.InternalScheme(), primaryAddress))
 
 	if err != nil {
 		return request, Response{
@@ -43,8 +44,33 @@ func ForwardToPrimary(request *Request) (*Request, Response) {
 		}
 	}
 
-	// Create and configure the reverse proxy
-	proxy := httputil.NewSingleHostReverseProxy(primaryURL)
+	authorizationContext := request.AuthorizationContext()
+
+	// Create and configure the reverse proxy. The forwarded headers of the
+	// client are replaced with the address and scheme the client used, and
+	// the node headers identify this node, so the primary only trusts them
+	// because they were sent by a member of the cluster.
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(primaryURL)
+			r.Out.Host = r.In.Host
+
+			if authorizationContext.SourceIP.IsValid() {
+				r.Out.Header.Set("X-Forwarded-For", authorizationContext.SourceIP.String())
+			}
+
+			if authorizationContext.TLS {
+				r.Out.Header.Set("X-Forwarded-Proto", "https")
+			} else {
+				r.Out.Header.Set("X-Forwarded-Proto", "http")
+			}
+
+			if err := request.cluster.Node().SetNodeHeaders(r.Out); err != nil {
+				slog.Error("Error setting node headers on forwarded request", "error", err)
+			}
+		},
+		Transport: request.cluster.InternalTransport(),
+	}
 
 	// Return a streaming response that proxies to the primary
 	return request, Response{
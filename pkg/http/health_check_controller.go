@@ -1,8 +1,37 @@
 package http
 
+import (
+	"net/http"
+	"time"
+)
+
+// Report the health of the node along with its role and replication lag so
+// that load balancers can route requests to the primary and away from
+// lagging replicas.
 func HealthCheckController(request *Request) Response {
-	return Response{
-		StatusCode: 200,
-		Body:       nil,
+	if !request.cluster.Initialized || !request.cluster.Node().Initialized {
+		return JsonResponse(map[string]any{
+			"status":  "error",
+			"message": "Node is not ready",
+		}, http.StatusServiceUnavailable, nil)
 	}
+
+	node := request.cluster.Node()
+	address, _ := node.Address()
+	role := "replica"
+	var lag time.Duration
+
+	if node.IsPrimary() {
+		role = "primary"
+	} else {
+		lag = node.ReplicationLagTracker().ReplicaLag(address)
+	}
+
+	return SuccessResponse("Node is healthy", map[string]any{
+		"address":            address,
+		"primary_address":    node.PrimaryAddress(),
+		"remote":             node.IsRemoteReplica(),
+		"replication_lag_ms": lag.Milliseconds(),
+		"role":               role,
+	}, http.StatusOK)
 }
@@ -22,3 +22,40 @@ func TestHealthCheckController(t *testing.T) {
 		}
 	})
 }
+
+func TestHealthCheckControllerReportsRole(t *testing.T) {
+	test.Run(t, func() {
+		primary := test.NewTestServer(t)
+		defer primary.Shutdown()
+
+		replica := test.NewTestServer(t)
+		defer replica.Shutdown()
+
+		for server, role := range map[*test.TestServer]string{
+			primary: "primary",
+			replica: "replica",
+		} {
+			client := &test.TestClient{URL: server.Server.URL}
+
+			response, statusCode, err := client.Send("/v1/health", "GET", nil)
+
+			if err != nil {
+				t.Fatalf("Failed to send request: %v", err)
+			}
+
+			if statusCode != 200 {
+				t.Fatalf("Expected status code 200, got %d", statusCode)
+			}
+
+			data := response["data"].(map[string]any)
+
+			if data["role"] != role {
+				t.Errorf("Expected role %s, got %v", role, data["role"])
+			}
+
+			if data["primary_address"] != primary.App.Cluster.Node().PrimaryAddress() {
+				t.Errorf("Expected primary address %s, got %v", primary.App.Cluster.Node().PrimaryAddress(), data["primary_address"])
+			}
+		}
+	})
+}
@@ -6,6 +6,12 @@ import (
 )
 
 func Internal(request *Request) (*Request, Response) {
+	request, response := InternalConnection(request)
+
+	if response.StatusCode != 0 {
+		return request, response
+	}
+
 	nodeHeader := request.Headers().Get("X-Lbdb-Node")
 	nodeTimestamp := request.Headers().Get("X-Lbdb-Node-Timestamp")
 
@@ -68,3 +74,17 @@ func Internal(request *Request) (*Request, Response) {
 
 	return request, Response{}
 }
+
+// Verify the connection of an internal route. When mTLS is enabled, internal
+// routes only accept connections from nodes presenting a certificate signed by
+// the cluster CA. This is used on its own for routes called by nodes that are
+// not members of the cluster yet.
+func InternalConnection(request *Request) (*Request, Response) {
+	if err := request.cluster.VerifyInternalConnection(request.BaseRequest.TLS); err != nil {
+		return request, Response{
+			StatusCode: 401,
+		}
+	}
+
+	return request, Response{}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/config"
+	"github.com/litebase/litebase/pkg/logs"
 )
 
 type KeyActivateRequest struct {
@@ -56,5 +58,9 @@ func KeyActivateController(request *Request) Response {
 		return ServerErrorResponse(err)
 	}
 
+	request.Audit(logs.AuditActionKeyActivate, fmt.Sprintf("cluster:%s", request.cluster.Id), map[string]string{
+		"key_hash": config.EncryptionKeyHash(input.(*KeyActivateRequest).EncryptionKey),
+	})
+
 	return SuccessResponse("encryption key activated successfully", map[string]any{}, 200)
 }
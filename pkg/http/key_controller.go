@@ -6,6 +6,8 @@ import (
 	"fmt"
 
 	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/config"
+	"github.com/litebase/litebase/pkg/logs"
 )
 
 type KeyStoreRequest struct {
@@ -58,5 +60,9 @@ func KeyStoreController(request *Request) Response {
 		return ServerErrorResponse(err)
 	}
 
+	request.Audit(logs.AuditActionKeyCreate, fmt.Sprintf("cluster:%s", request.cluster.Id), map[string]string{
+		"key_hash": config.EncryptionKeyHash(input.(*KeyStoreRequest).EncryptionKey),
+	})
+
 	return SuccessResponse("next encryption key stored successfully", map[string]any{}, 200)
 }
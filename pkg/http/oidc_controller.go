@@ -0,0 +1,119 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/logs"
+)
+
+// Show the single sign-on configuration that clients need to sign in with the
+// identity provider of the cluster.
+func OIDCControllerShow(request *Request) Response {
+	provider := request.cluster.Auth.OIDCProvider()
+
+	if provider == nil {
+		return NotFoundResponse(auth.ErrOIDCNotConfigured)
+	}
+
+	metadata, err := provider.Metadata()
+
+	if err != nil {
+		return ServerErrorResponse(err)
+	}
+
+	return SuccessResponse(
+		"Single sign-on configuration retrieved successfully",
+		map[string]any{
+			"authorization_endpoint":        metadata.AuthorizationEndpoint,
+			"client_id":                     provider.ClientID,
+			"device_authorization_endpoint": metadata.DeviceAuthorizationEndpoint,
+			"issuer":                        provider.Issuer,
+			"scopes":                        provider.Scopes,
+			"token_endpoint":                metadata.TokenEndpoint,
+		},
+		200,
+	)
+}
+
+type OIDCControllerTokenRequest struct {
+	IDToken string `json:"id_token" validate:"required"`
+}
+
+// Exchange an identity token issued by the identity provider for a session
+// that authenticates requests to the cluster as a bearer token.
+func OIDCControllerToken(request *Request) Response {
+	if request.cluster.Auth.OIDCProvider() == nil {
+		return NotFoundResponse(auth.ErrOIDCNotConfigured)
+	}
+
+	input, err := request.Input(&OIDCControllerTokenRequest{})
+
+	if err != nil {
+		return BadRequestResponse(fmt.Errorf("invalid input: %w", err))
+	}
+
+	validationErrors := request.Validate(input, map[string]string{
+		"id_token.required": "The ID token field is required.",
+	})
+
+	if validationErrors != nil {
+		return ValidationErrorResponse(validationErrors)
+	}
+
+	identity, err := request.cluster.Auth.OIDCProvider().Verify(input.(*OIDCControllerTokenRequest).IDToken)
+
+	if err != nil {
+		if errors.Is(err, auth.ErrOIDCTokenInvalid) || errors.Is(err, auth.ErrOIDCTokenExpired) {
+			return JsonResponse(map[string]any{
+				"status":  "error",
+				"message": fmt.Sprintf("Unauthorized: %s", err.Error()),
+			}, 401, nil)
+		}
+
+		return ServerErrorResponse(err)
+	}
+
+	token, session, err := request.cluster.Auth.IssueUserSession(identity)
+
+	if err != nil {
+		if errors.Is(err, auth.ErrUserSessionNoRoles) {
+			return ForbiddenResponse(err)
+		}
+
+		return ServerErrorResponse(err)
+	}
+
+	roles := request.cluster.Auth.SessionUser(session).Roles
+
+	err = request.logManager.Audit(request.cluster, logs.AuditLogEntry{
+		Action:    logs.AuditActionSSOLogin,
+		Actor:     session.Username,
+		ActorType: logs.AuditActorSSOUser,
+		Details: map[string]string{
+			"roles":   strings.Join(roles, ","),
+			"subject": session.Subject,
+		},
+		Resource: "session",
+	})
+
+	if err != nil {
+		slog.Error("Error writing audit log entry", "action", logs.AuditActionSSOLogin, "error", err)
+	}
+
+	return SuccessResponse(
+		"Signed in successfully",
+		map[string]any{
+			"expires_at": time.Unix(session.ExpiresAt, 0).UTC(),
+			"roles":      roles,
+			"token":      token,
+			"token_type": "Bearer",
+			"username":   session.Username,
+		},
+		201,
+	)
+}
@@ -0,0 +1,128 @@
+package http_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestOIDCController(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{})
+
+		_, statusCode, err := client.Send("/v1/auth/oidc", "GET", nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if statusCode != 404 {
+			t.Errorf("Expected single sign-on to be disabled by default, got %d", statusCode)
+		}
+
+		provider := test.NewMockOIDCProvider(t)
+
+		server.App.Config.OIDCIssuer = provider.URL
+		server.App.Config.OIDCClientID = provider.ClientID
+		server.App.Config.OIDCRoleMappings = []string{"engineering=readers"}
+
+		_, err = server.App.Auth.RoleManager().Add("readers", "", []auth.AccessKeyStatement{
+			{Effect: "Allow", Resource: "access-key:*", Actions: []auth.Privilege{auth.AccessKeyPrivilegeList}},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		response, statusCode, err := client.Send("/v1/auth/oidc", "GET", nil)
+
+		if err != nil || statusCode != 200 {
+			t.Fatalf("Failed to retrieve the single sign-on configuration: %d %v", statusCode, err)
+		}
+
+		configuration := response["data"].(map[string]any)
+
+		if configuration["client_id"] != provider.ClientID ||
+			configuration["device_authorization_endpoint"] != provider.URL+"/device/code" {
+			t.Errorf("Unexpected configuration: %v", configuration)
+		}
+
+		response, statusCode, err = client.Send("/v1/auth/oidc/token", "POST", map[string]any{
+			"id_token": provider.IDToken(nil),
+		})
+
+		if err != nil || statusCode != 201 {
+			t.Fatalf("Failed to sign in: %d %v %v", statusCode, err, response)
+		}
+
+		session := response["data"].(map[string]any)
+		token := session["token"].(string)
+
+		if session["username"] != "jane@example.com" {
+			t.Errorf("Expected the username claim to be used, got %v", session["username"])
+		}
+
+		if roles, ok := session["roles"].([]any); !ok || len(roles) != 1 || roles[0] != "readers" {
+			t.Errorf("Expected the group to be mapped to the readers role, got %v", session["roles"])
+		}
+
+		// The session authorizes requests within the statements of its roles
+		if statusCode := sendWithAccessToken(t, server.Server.URL+"/v1/access-keys", "GET", token); statusCode != 200 {
+			t.Errorf("Unexpected status code: %d, expected 200", statusCode)
+		}
+
+		if statusCode := sendWithAccessToken(t, server.Server.URL+"/v1/users", "GET", token); statusCode != 403 {
+			t.Errorf("Unexpected status code: %d, expected 403", statusCode)
+		}
+
+		if statusCode := sendWithAccessToken(t, server.Server.URL+"/v1/access-keys", "GET", token+"x"); statusCode != 401 {
+			t.Errorf("Unexpected status code: %d, expected 401", statusCode)
+		}
+	})
+}
+
+func TestOIDCControllerTokenRejectsInvalidTokens(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		provider := test.NewMockOIDCProvider(t)
+
+		server.App.Config.OIDCIssuer = provider.URL
+		server.App.Config.OIDCClientID = provider.ClientID
+		server.App.Config.OIDCRoleMappings = []string{"engineering=readers"}
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{})
+
+		testCases := []struct {
+			name       string
+			token      string
+			statusCode int
+		}{
+			{"expired", provider.IDToken(map[string]any{"exp": time.Now().Add(-time.Hour).Unix()}), 401},
+			{"other audience", provider.IDToken(map[string]any{"aud": "other-client"}), 401},
+			{"other issuer", provider.IDToken(map[string]any{"iss": "https://issuer.example.com"}), 401},
+			{"tampered", provider.IDToken(nil) + "x", 401},
+			{"unmapped group", provider.IDToken(map[string]any{"groups": []string{"sales"}}), 403},
+		}
+
+		for _, tc := range testCases {
+			_, statusCode, err := client.Send("/v1/auth/oidc/token", "POST", map[string]any{
+				"id_token": tc.token,
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if statusCode != tc.statusCode {
+				t.Errorf("%s: expected %d, got %d", tc.name, tc.statusCode, statusCode)
+			}
+		}
+	})
+}
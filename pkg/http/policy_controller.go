@@ -0,0 +1,172 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+type PolicyControllerSimulateRequest struct {
+	AccessKeyID string                           `json:"access_key_id" validate:"required_without=Username,excluded_with=Username"`
+	Username    string                           `json:"username" validate:"required_without=AccessKeyID"`
+	Resource    string                           `json:"resource" validate:"required_without=Statement,excluded_with=Statement"`
+	Action      auth.Privilege                   `json:"action" validate:"required_with=Resource"`
+	Database    string                           `json:"database" validate:"required_with=Statement"`
+	Branch      string                           `json:"branch" validate:"required_with=Statement"`
+	Statement   string                           `json:"statement" validate:"required_without=Resource"`
+	Context     *PolicyControllerSimulateContext `json:"context"`
+}
+
+// The request context to simulate. Fields that are not set are taken from the
+// context of the request made to the simulator.
+type PolicyControllerSimulateContext struct {
+	Replica  *bool      `json:"replica"`
+	SourceIP string     `json:"source_ip" validate:"omitempty,ip"`
+	Time     *time.Time `json:"time"`
+	TLS      *bool      `json:"tls"`
+}
+
+// Simulate the authorization of an access key or user. Either an action on a
+// resource is evaluated, or a SQL statement is prepared against a branch with
+// the access key, and the decision is returned with the statement that
+// decided it.
+func PolicyControllerSimulate(request *Request) Response {
+	err := request.Authorize(
+		[]string{"*", fmt.Sprintf("cluster:%s", request.cluster.Id)},
+		[]auth.Privilege{auth.ClusterPrivilegeManage},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	input, err := request.Input(&PolicyControllerSimulateRequest{})
+
+	if err != nil {
+		return BadRequestResponse(fmt.Errorf("invalid input: %w", err))
+	}
+
+	validationErrors := request.Validate(input, map[string]string{
+		"access_key_id.required_without": "The access key ID field is required when no username is given.",
+		"access_key_id.excluded_with":    "Only one of the access key ID and username fields may be given.",
+		"username.required_without":      "The username field is required when no access key ID is given.",
+		"resource.required_without":      "The resource field is required when no statement is given.",
+		"resource.excluded_with":         "Only one of the resource and statement fields may be given.",
+		"action.required_with":           "The action field is required with a resource.",
+		"database.required_with":         "The database field is required with a statement.",
+		"branch.required_with":           "The branch field is required with a statement.",
+		"statement.required_without":     "The statement field is required when no resource is given.",
+		"source_ip.ip":                   "The source IP must be a valid IP address.",
+	})
+
+	if validationErrors != nil {
+		return ValidationErrorResponse(validationErrors)
+	}
+
+	data := input.(*PolicyControllerSimulateRequest)
+	context := request.simulatedAuthorizationContext(data.Context)
+
+	if data.Username != "" {
+		if data.Statement != "" {
+			return ValidationErrorResponse(map[string][]string{
+				"statement": {"Statements can only be simulated for access keys."},
+			})
+		}
+
+		user := request.cluster.Auth.UserManager().Get(data.Username)
+
+		if user == nil {
+			return NotFoundResponse(errors.New("the user was not found"))
+		}
+
+		return SuccessResponse(
+			"Policy simulated successfully",
+			user.Explain(data.Resource, data.Action, context),
+			200,
+		)
+	}
+
+	err = request.cluster.Auth.SecretsManager.Init()
+
+	if err != nil {
+		return ServerErrorResponse(err)
+	}
+
+	accessKey, err := request.accessKeyManager.Get(data.AccessKeyID)
+
+	if err != nil {
+		return NotFoundResponse(errors.New("the access key was not found"))
+	}
+
+	accessKey.SetAuthorizationContext(context)
+
+	if data.Statement == "" {
+		return SuccessResponse(
+			"Policy simulated successfully",
+			accessKey.Explain(data.Resource, data.Action),
+			200,
+		)
+	}
+
+	databaseKey, errResponse := request.databaseKeyFor(data.Database, data.Branch)
+
+	if !errResponse.IsEmpty() {
+		return errResponse
+	}
+
+	db, err := request.databaseManager.ConnectionManager().Get(
+		databaseKey.DatabaseID,
+		databaseKey.DatabaseBranchID,
+	)
+
+	if err != nil {
+		return ServerErrorResponse(err)
+	}
+
+	defer request.databaseManager.ConnectionManager().Release(db)
+
+	simulation, err := db.WithAccessKey(accessKey).GetConnection().SimulateAuthorization(data.Statement)
+
+	if err != nil {
+		return ServerErrorResponse(err)
+	}
+
+	return SuccessResponse(
+		"Policy simulated successfully",
+		simulation,
+		200,
+	)
+}
+
+// Return the authorization context of the request with the simulated values
+// applied.
+func (request *Request) simulatedAuthorizationContext(simulated *PolicyControllerSimulateContext) *auth.AuthorizationContext {
+	context := *request.AuthorizationContext()
+
+	if simulated == nil {
+		return &context
+	}
+
+	if simulated.Replica != nil {
+		context.Replica = *simulated.Replica
+	}
+
+	if simulated.SourceIP != "" {
+		if address, err := netip.ParseAddr(simulated.SourceIP); err == nil {
+			context.SourceIP = address.Unmap()
+		}
+	}
+
+	if simulated.Time != nil {
+		context.Time = *simulated.Time
+	}
+
+	if simulated.TLS != nil {
+		context.TLS = *simulated.TLS
+	}
+
+	return &context
+}
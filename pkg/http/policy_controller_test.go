@@ -0,0 +1,138 @@
+package http_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestPolicyControllerSimulate(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		db := test.MockDatabase(server.App)
+
+		con, err := server.App.DatabaseManager.ConnectionManager().Get(db.DatabaseID, db.DatabaseBranchID)
+
+		if err != nil {
+			t.Fatalf("failed to get database connection: %v", err)
+		}
+
+		_, err = con.GetConnection().Exec("CREATE TABLE secrets (id INTEGER PRIMARY KEY, value TEXT)", nil)
+
+		server.App.DatabaseManager.ConnectionManager().Release(con)
+
+		if err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+
+		accessKey, err := server.App.Auth.AccessKeyManager.Create(
+			"Simulated access key",
+			[]auth.AccessKeyStatement{
+				{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}},
+				{
+					Effect:   "Deny",
+					Resource: "*",
+					Actions:  []auth.Privilege{auth.DatabasePrivilegeRead},
+				},
+			},
+		)
+
+		if err != nil {
+			t.Fatalf("Failed to create test access key: %v", err)
+		}
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{"*"}},
+		})
+
+		response, statusCode, err := client.Send("/v1/policy/simulate", "POST", map[string]any{
+			"access_key_id": accessKey.AccessKeyID,
+			"resource":      "access-key:*",
+			"action":        "access-key:list",
+		})
+
+		if err != nil || statusCode != 200 {
+			t.Fatalf("Failed to simulate policy: %d %v %v", statusCode, err, response)
+		}
+
+		decision := response["data"].(map[string]any)
+
+		if decision["allowed"] != true {
+			t.Errorf("Expected the action to be allowed, got %v", decision)
+		}
+
+		if statement, ok := decision["statement"].(map[string]any); !ok || statement["resource"] != "*" {
+			t.Errorf("Expected the matching statement to be returned, got %v", decision["statement"])
+		}
+
+		response, statusCode, err = client.Send("/v1/policy/simulate", "POST", map[string]any{
+			"access_key_id": accessKey.AccessKeyID,
+			"database":      db.DatabaseName,
+			"branch":        db.BranchName,
+			"statement":     "SELECT value FROM secrets",
+		})
+
+		if err != nil || statusCode != 200 {
+			t.Fatalf("Failed to simulate statement: %d %v %v", statusCode, err, response)
+		}
+
+		simulation := response["data"].(map[string]any)
+
+		if simulation["allowed"] != false {
+			t.Fatalf("Expected the statement to be denied, got %v", simulation)
+		}
+
+		checks := simulation["checks"].([]any)
+		check := checks[len(checks)-1].(map[string]any)
+
+		if check["operation"] != "SQLITE_READ" || check["allowed"] != false {
+			t.Fatalf("Expected the read of the secrets table to be denied, got %v", check)
+		}
+
+		statement := check["decision"].(map[string]any)["statement"].(map[string]any)
+
+		if statement["effect"] != "Deny" {
+			t.Errorf("Expected the deny statement to decide, got %v", statement)
+		}
+
+		_, statusCode, err = client.Send("/v1/policy/simulate", "POST", map[string]any{
+			"access_key_id": accessKey.AccessKeyID,
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if statusCode != 422 {
+			t.Errorf("Expected a validation error without a resource or statement, got %d", statusCode)
+		}
+	})
+}
+
+func TestPolicyControllerSimulateRequiresManage(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{Effect: "Allow", Resource: "*", Actions: []auth.Privilege{auth.DatabasePrivilegeQuery}},
+		})
+
+		_, statusCode, err := client.Send("/v1/policy/simulate", "POST", map[string]any{
+			"username": "root",
+			"resource": "*",
+			"action":   "cluster:manage",
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if statusCode != 403 {
+			t.Errorf("Expected a forbidden response, got %d", statusCode)
+		}
+	})
+}
@@ -18,19 +18,22 @@ import (
 )
 
 type Request struct {
-	accessKeyManager *auth.AccessKeyManager
-	BaseRequest      *http.Request
-	Body             map[string]any
-	bodyHash         string
-	databaseKey      *auth.DatabaseKey
-	databaseManager  *database.DatabaseManager
-	logManager       *logs.LogManager
-	cluster          *cluster.Cluster
-	headers          Headers
-	Method           string
-	QueryParams      map[string]string
-	requestToken     auth.RequestToken
-	Route            Route
+	accessKeyManager     *auth.AccessKeyManager
+	authorizationContext *auth.AuthorizationContext
+	BaseRequest          *http.Request
+	Body                 map[string]any
+	bodyHash             string
+	databaseKey          *auth.DatabaseKey
+	databaseManager      *database.DatabaseManager
+	logManager           *logs.LogManager
+	cluster              *cluster.Cluster
+	headers              Headers
+	Method               string
+	QueryParams          map[string]string
+	requestToken         auth.RequestToken
+	Route                Route
+	userSession          *auth.UserSession
+	userSessionChecked   bool
 }
 
 // Create a new Request instance.
@@ -123,6 +126,31 @@ func (r *Request) BodyHash() string {
 	return r.bodyHash
 }
 
+// Record an operation performed by the request in the audit log. Failures are
+// logged since the operation has already been performed.
+func (r *Request) Audit(action, resource string, details map[string]string) {
+	entry := logs.AuditLogEntry{
+		Action:   action,
+		Details:  details,
+		Resource: resource,
+	}
+
+	if username, _, ok := r.BaseRequest.BasicAuth(); ok {
+		entry.ActorType = logs.AuditActorUser
+		entry.Actor = username
+	} else if session := r.UserSession(); session != nil {
+		entry.ActorType = logs.AuditActorSSOUser
+		entry.Actor = session.Username
+	} else if accessKey := r.RequestToken("Authorization").AccessKey(); accessKey != nil {
+		entry.ActorType = logs.AuditActorAccessKey
+		entry.Actor = accessKey.AccessKeyID
+	}
+
+	if err := r.logManager.Audit(r.cluster, entry); err != nil {
+		slog.Error("Error writing audit log entry", "action", action, "error", err)
+	}
+}
+
 // Authorize the request based on the access key and the specified resource and actions.
 func (r *Request) Authorize(resources []string, actions []auth.Privilege) error {
 	username, password, ok := r.BaseRequest.BasicAuth()
@@ -132,9 +160,22 @@ func (r *Request) Authorize(resources []string, actions []auth.Privilege) error
 			return fmt.Errorf("invalid username or password")
 		}
 
-		if r.cluster.Auth.UserManager().Get(username).AuthorizeForResource(
+		if r.cluster.Auth.UserManager().Get(username).AuthorizeForResourceWithContext(
+			resources,
+			actions,
+			r.AuthorizationContext(),
+		) {
+			return nil
+		}
+
+		return fmt.Errorf("user is not authorized to perform this request")
+	}
+
+	if session := r.UserSession(); session != nil {
+		if r.cluster.Auth.SessionUser(session).AuthorizeForResourceWithContext(
 			resources,
 			actions,
+			r.AuthorizationContext(),
 		) {
 			return nil
 		}
@@ -176,6 +217,19 @@ func (r *Request) DatabaseKey() (*auth.DatabaseKey, Response) {
 		return nil, ErrValidBranchNameRequiredResponse
 	}
 
+	databaseKey, errResponse := r.databaseKeyFor(databaseName, branchName)
+
+	if !errResponse.IsEmpty() {
+		return nil, errResponse
+	}
+
+	r.databaseKey = databaseKey
+
+	return r.databaseKey, Response{}
+}
+
+// Return a database key for the database and branch with the given names.
+func (r *Request) databaseKeyFor(databaseName, branchName string) (*auth.DatabaseKey, Response) {
 	db, err := r.databaseManager.GetByName(databaseName)
 
 	if err != nil {
@@ -196,14 +250,12 @@ func (r *Request) DatabaseKey() (*auth.DatabaseKey, Response) {
 		return nil, BadRequestResponse(err)
 	}
 
-	r.databaseKey = auth.NewDatabaseKey(
+	return auth.NewDatabaseKey(
 		db.DatabaseID,
 		db.Name,
 		branch.DatabaseBranchID,
 		branch.Name,
-	)
-
-	return r.databaseKey, Response{}
+	), Response{}
 }
 
 // Get a value from the request body by its key.
@@ -272,6 +324,12 @@ func (request *Request) RequestToken(header string) auth.RequestToken {
 			request.accessKeyManager,
 			request.headers.Get(header),
 		)
+
+		if request.requestToken.Valid() {
+			if accessKey := request.requestToken.AccessKey(); accessKey != nil {
+				accessKey.SetAuthorizationContext(request.AuthorizationContext())
+			}
+		}
 	}
 
 	return request.requestToken
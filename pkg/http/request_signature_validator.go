@@ -105,17 +105,34 @@ func RequestSignatureValidator(
 	signedRequestHash.Write([]byte(requestString))
 	signedRequest := fmt.Sprintf("%x", signedRequestHash.Sum(nil))
 
+	if signatureMatches(secret, headers["x-lbdb-date"], signedRequest, request.RequestToken(header).Signature) {
+		return true
+	}
+
+	// The previous secret of a rotated access key remains valid until the
+	// grace period of the rotation has passed.
+	accessKey := request.RequestToken(header).AccessKey()
+
+	if accessKey == nil || accessKey.PreviousSecret() == "" {
+		return false
+	}
+
+	return signatureMatches(accessKey.PreviousSecret(), headers["x-lbdb-date"], signedRequest, request.RequestToken(header).Signature)
+}
+
+// Determine if a signature was created for the signed request with the secret.
+func signatureMatches(secret, date, signedRequest, signature string) bool {
 	dateHash := hmac.New(sha256.New, []byte(secret))
-	dateHash.Write([]byte(headers["x-lbdb-date"]))
-	date := fmt.Sprintf("%x", dateHash.Sum(nil))
+	dateHash.Write([]byte(date))
+	dateSignature := fmt.Sprintf("%x", dateHash.Sum(nil))
 
-	serviceHash := hmac.New(sha256.New, []byte(date))
+	serviceHash := hmac.New(sha256.New, []byte(dateSignature))
 	serviceHash.Write([]byte("litebase_request"))
 	service := fmt.Sprintf("%x", serviceHash.Sum(nil))
 
 	signatureHash := hmac.New(sha256.New, []byte(service))
 	signatureHash.Write([]byte(signedRequest))
-	signature := fmt.Sprintf("%x", signatureHash.Sum(nil))
+	expectedSignature := fmt.Sprintf("%x", signatureHash.Sum(nil))
 
-	return subtle.ConstantTimeCompare([]byte(signature), []byte(request.RequestToken(header).Signature)) == 1
+	return subtle.ConstantTimeCompare([]byte(expectedSignature), []byte(signature)) == 1
 }
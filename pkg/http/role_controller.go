@@ -0,0 +1,247 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/logs"
+)
+
+var roleStatementValidationMessages = map[string]string{
+	"description.max":                  "The description field must be at most 255 characters long",
+	"statements.max":                   "The statements field must contain at most 100 items",
+	"statements.min":                   "The statements field must contain at least 1 item",
+	"statements.required":              "The statements field is required",
+	"statements.*.validateFn":          "This statement is not valid. All actions must match the resource.",
+	"statements.*.effect.required":     "Each statement must have an effect",
+	"statements.*.effect.validateFn":   "The effect of the statement must be one of 'Allow' or 'Deny'",
+	"statements.*.resource.required":   "This statement is missing a resource",
+	"statements.*.resource.validateFn": "This resource is not valid",
+	"statements.*.actions.required":    "This statement is missing actions",
+	"statements.*.actions.min":         "Each statement must have at least one action",
+	"statements.*.actions.max":         "Each statement can have at most 100 actions",
+}
+
+// Validate that each of the roles exists.
+func validateRoles(request *Request, roles []string) map[string][]string {
+	var messages []string
+
+	for _, role := range roles {
+		if request.cluster.Auth.RoleManager().Get(role) == nil {
+			messages = append(messages, fmt.Sprintf("The role '%s' does not exist", role))
+		}
+	}
+
+	if len(messages) > 0 {
+		return map[string][]string{"roles": messages}
+	}
+
+	return nil
+}
+
+// List all roles
+func RoleControllerIndex(request *Request) Response {
+	err := request.Authorize(
+		[]string{"*", fmt.Sprintf("cluster:%s", request.cluster.Id)},
+		[]auth.Privilege{auth.ClusterPrivilegeManage},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	roles, err := request.cluster.Auth.RoleManager().All()
+
+	if err != nil {
+		return ServerErrorResponse(err)
+	}
+
+	return SuccessResponse(
+		"Roles retrieved successfully",
+		roles,
+		200,
+	)
+}
+
+// Show a role
+func RoleControllerShow(request *Request) Response {
+	err := request.Authorize(
+		[]string{"*", fmt.Sprintf("cluster:%s", request.cluster.Id)},
+		[]auth.Privilege{auth.ClusterPrivilegeManage},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	name := request.Param("name")
+
+	role := request.cluster.Auth.RoleManager().Get(name)
+
+	if role == nil {
+		return NotFoundResponse(fmt.Errorf("the role was not found"))
+	}
+
+	return SuccessResponse(
+		fmt.Sprintf("Role '%s' retrieved successfully", name),
+		role,
+		200,
+	)
+}
+
+type RoleControllerStoreRequest struct {
+	Name        string                    `json:"name" validate:"required"`
+	Description string                    `json:"description" validate:"omitempty,max=255"`
+	Statements  []auth.AccessKeyStatement `json:"statements" validate:"required,min=1,max=100,dive,validateFn=IsValid"`
+}
+
+// Create a new role
+func RoleControllerStore(request *Request) Response {
+	err := request.Authorize(
+		[]string{"*", fmt.Sprintf("cluster:%s", request.cluster.Id)},
+		[]auth.Privilege{auth.ClusterPrivilegeManage},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	input, err := request.Input(&RoleControllerStoreRequest{})
+
+	if err != nil {
+		return BadRequestResponse(fmt.Errorf("invalid input: %w", err))
+	}
+
+	messages := map[string]string{
+		"name.required": "The name field is required.",
+	}
+
+	maps.Copy(messages, roleStatementValidationMessages)
+
+	validationErrors := request.Validate(input, messages)
+
+	if validationErrors != nil {
+		return ValidationErrorResponse(validationErrors)
+	}
+
+	data := input.(*RoleControllerStoreRequest)
+
+	if !auth.IsValidRoleName(data.Name) {
+		return ValidationErrorResponse(map[string][]string{
+			"name": {"The name may only contain letters, numbers, dashes, and underscores and be at most 64 characters long."},
+		})
+	}
+
+	if request.cluster.Auth.RoleManager().Get(data.Name) != nil {
+		return BadRequestResponse(errors.New("the role already exists"))
+	}
+
+	role, err := request.cluster.Auth.RoleManager().Add(
+		data.Name,
+		data.Description,
+		data.Statements,
+	)
+
+	if err != nil {
+		return ServerErrorResponse(err)
+	}
+
+	request.Audit(logs.AuditActionRoleCreate, fmt.Sprintf("role:%s", role.Name), nil)
+
+	return SuccessResponse(
+		"Role created successfully",
+		role,
+		201,
+	)
+}
+
+type RoleControllerUpdateRequest struct {
+	Description string                    `json:"description" validate:"omitempty,max=255"`
+	Statements  []auth.AccessKeyStatement `json:"statements" validate:"required,min=1,max=100,dive,validateFn=IsValid"`
+}
+
+// Update the description and statements of a role. The change applies to all
+// users and access keys the role is assigned to.
+func RoleControllerUpdate(request *Request) Response {
+	err := request.Authorize(
+		[]string{"*", fmt.Sprintf("cluster:%s", request.cluster.Id)},
+		[]auth.Privilege{auth.ClusterPrivilegeManage},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	name := request.Param("name")
+
+	role := request.cluster.Auth.RoleManager().Get(name)
+
+	if role == nil {
+		return NotFoundResponse(fmt.Errorf("the role was not found"))
+	}
+
+	input, err := request.Input(&RoleControllerUpdateRequest{})
+
+	if err != nil {
+		return BadRequestResponse(fmt.Errorf("invalid input: %w", err))
+	}
+
+	validationErrors := request.Validate(input, roleStatementValidationMessages)
+
+	if validationErrors != nil {
+		return ValidationErrorResponse(validationErrors)
+	}
+
+	data := input.(*RoleControllerUpdateRequest)
+
+	description := role.Description
+
+	if data.Description != "" {
+		description = data.Description
+	}
+
+	role, err = request.cluster.Auth.RoleManager().Update(name, description, data.Statements)
+
+	if err != nil {
+		return ServerErrorResponse(err)
+	}
+
+	request.Audit(logs.AuditActionRoleUpdate, fmt.Sprintf("role:%s", name), nil)
+
+	return SuccessResponse(
+		fmt.Sprintf("Role '%s' updated successfully", name),
+		role,
+		200,
+	)
+}
+
+// Delete a role. Users and access keys the role is assigned to lose the
+// statements of the role.
+func RoleControllerDestroy(request *Request) Response {
+	err := request.Authorize(
+		[]string{"*", fmt.Sprintf("cluster:%s", request.cluster.Id)},
+		[]auth.Privilege{auth.ClusterPrivilegeManage},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	name := request.Param("name")
+
+	if request.cluster.Auth.RoleManager().Get(name) == nil {
+		return NotFoundResponse(fmt.Errorf("the role was not found"))
+	}
+
+	err = request.cluster.Auth.RoleManager().Remove(name)
+
+	if err != nil {
+		return ServerErrorResponse(err)
+	}
+
+	request.Audit(logs.AuditActionRoleDelete, fmt.Sprintf("role:%s", name), nil)
+
+	return SuccessResponse("", nil, 204)
+}
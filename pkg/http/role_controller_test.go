@@ -0,0 +1,178 @@
+package http_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestRoleController(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   "Allow",
+				Resource: "*",
+				Actions:  []auth.Privilege{"*"},
+			},
+		})
+
+		_, statusCode, err := client.Send("/v1/roles", "POST", map[string]any{
+			"name":        "readers",
+			"description": "Read only access",
+			"statements": []map[string]any{
+				{"effect": "Allow", "resource": "database:*", "actions": []string{"database:read"}},
+			},
+		})
+
+		if err != nil || statusCode != 201 {
+			t.Fatalf("Failed to create role: %d %v", statusCode, err)
+		}
+
+		response, statusCode, err := client.Send("/v1/roles", "GET", nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if statusCode != 200 {
+			t.Fatalf("Unexpected status code: %d, expected 200", statusCode)
+		}
+
+		if roles, ok := response["data"].([]any); !ok || len(roles) != 1 {
+			t.Errorf("Expected a single role, got %v", response["data"])
+		}
+
+		response, statusCode, err = client.Send("/v1/access-keys", "POST", map[string]any{
+			"description": "Reader",
+			"roles":       []string{"readers"},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if statusCode != 201 {
+			t.Fatalf("Expected an access key with only roles to be created, got %d: %v", statusCode, response)
+		}
+
+		accessKeyID := response["data"].(map[string]any)["access_key_id"].(string)
+
+		accessKey, err := server.App.Auth.AccessKeyManager.Get(accessKeyID)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !accessKey.AuthorizeForResource([]string{"database:*"}, []auth.Privilege{auth.DatabasePrivilegeRead}) {
+			t.Error("Expected the access key to be authorized by its role")
+		}
+
+		_, statusCode, err = client.Send("/v1/access-keys", "POST", map[string]any{
+			"description": "Missing role",
+			"roles":       []string{"missing"},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if statusCode != 422 {
+			t.Errorf("Unexpected status code: %d, expected 422", statusCode)
+		}
+
+		_, statusCode, err = client.Send("/v1/roles/readers", "PUT", map[string]any{
+			"statements": []map[string]any{
+				{"effect": "Allow", "resource": "database:*", "actions": []string{"database:insert"}},
+			},
+		})
+
+		if err != nil || statusCode != 200 {
+			t.Fatalf("Failed to update role: %d %v", statusCode, err)
+		}
+
+		accessKey, err = server.App.Auth.AccessKeyManager.Get(accessKeyID)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if accessKey.AuthorizeForResource([]string{"database:*"}, []auth.Privilege{auth.DatabasePrivilegeRead}) {
+			t.Error("Expected the access key to lose the statements removed from its role")
+		}
+
+		_, statusCode, err = client.Send("/v1/roles/readers", "DELETE", nil)
+
+		if err != nil || statusCode != 204 {
+			t.Fatalf("Failed to delete role: %d %v", statusCode, err)
+		}
+
+		_, statusCode, err = client.Send("/v1/roles/readers", "GET", nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if statusCode != 404 {
+			t.Errorf("Unexpected status code: %d, expected 404", statusCode)
+		}
+	})
+}
+
+func TestRoleController_InvalidName(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   "Allow",
+				Resource: "*",
+				Actions:  []auth.Privilege{"*"},
+			},
+		})
+
+		_, statusCode, err := client.Send("/v1/roles", "POST", map[string]any{
+			"name": "not a valid name",
+			"statements": []map[string]any{
+				{"effect": "Allow", "resource": "*", "actions": []string{"*"}},
+			},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if statusCode != 422 {
+			t.Errorf("Unexpected status code: %d, expected 422", statusCode)
+		}
+	})
+}
+
+func TestRoleController_Unauthorized(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   "Allow",
+				Resource: "database:*",
+				Actions:  []auth.Privilege{auth.DatabasePrivilegeQuery},
+			},
+		})
+
+		_, statusCode, err := client.Send("/v1/roles", "GET", nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if statusCode != 403 {
+			t.Errorf("Unexpected status code: %d, expected 403", statusCode)
+		}
+	})
+}
@@ -14,6 +14,17 @@ func LoadRoutes(router *Router) {
 		Authentication,
 	})
 
+	// Single sign-on routes are public since they are used to authenticate
+	router.Get(
+		"/v1/auth/oidc",
+		OIDCControllerShow,
+	)
+
+	router.Post(
+		"/v1/auth/oidc/token",
+		OIDCControllerToken,
+	)
+
 	router.Get(
 		"/v1/users",
 		UserControllerIndex,
@@ -52,6 +63,51 @@ func LoadRoutes(router *Router) {
 		Authentication,
 	})
 
+	router.Get(
+		"/v1/roles",
+		RoleControllerIndex,
+	).Middleware([]Middleware{
+		Authentication,
+	})
+
+	router.Get(
+		"/v1/roles/{name}",
+		RoleControllerShow,
+	).Middleware([]Middleware{
+		Authentication,
+	})
+
+	router.Post(
+		"/v1/roles",
+		RoleControllerStore,
+	).Middleware([]Middleware{
+		ForwardToPrimary,
+		Authentication,
+	})
+
+	router.Put(
+		"/v1/roles/{name}",
+		RoleControllerUpdate,
+	).Middleware([]Middleware{
+		ForwardToPrimary,
+		Authentication,
+	})
+
+	router.Delete(
+		"/v1/roles/{name}",
+		RoleControllerDestroy,
+	).Middleware([]Middleware{
+		ForwardToPrimary,
+		Authentication,
+	})
+
+	router.Post(
+		"/v1/policy/simulate",
+		PolicyControllerSimulate,
+	).Middleware([]Middleware{
+		Authentication,
+	})
+
 	router.Get(
 		"/v1/access-keys",
 		AccessKeyControllerIndex,
@@ -82,6 +138,14 @@ func LoadRoutes(router *Router) {
 		Authentication,
 	})
 
+	router.Post(
+		"/v1/access-keys/{accessKeyId}/rotate",
+		AccessKeyControllerRotate,
+	).Middleware([]Middleware{
+		ForwardToPrimary,
+		Authentication,
+	})
+
 	router.Delete(
 		"/v1/access-keys/{accessKeyId}",
 		AccessKeyControllerDestroy,
@@ -90,6 +154,13 @@ func LoadRoutes(router *Router) {
 		Authentication,
 	})
 
+	router.Post(
+		"/v1/access-tokens",
+		AccessTokenControllerStore,
+	).Middleware([]Middleware{
+		Authentication,
+	})
+
 	router.Get(
 		"/v1/databases/{databaseName}/branches",
 		DatabaseBranchIndexController,
@@ -134,6 +205,13 @@ func LoadRoutes(router *Router) {
 		Authentication,
 	})
 
+	router.Get(
+		"/v1/databases/{databaseName}/usage",
+		DatabaseUsageController,
+	).Middleware([]Middleware{
+		Authentication,
+	})
+
 	router.Post(
 		"/v1/databases",
 		DatabaseStoreController,
@@ -150,6 +228,14 @@ func LoadRoutes(router *Router) {
 		Authentication,
 	})
 
+	router.Put(
+		"/v1/databases/{databaseName}/replication",
+		DatabaseReplicationUpdateController,
+	).Middleware([]Middleware{
+		ForwardToPrimary,
+		Authentication,
+	})
+
 	router.Post(
 		"/v1/keys",
 		KeyStoreController,
@@ -166,6 +252,77 @@ func LoadRoutes(router *Router) {
 		Authentication,
 	})
 
+	router.Post(
+		"/v1/cluster/handoff",
+		ClusterHandoffController,
+	).Middleware([]Middleware{
+		ForwardToPrimary,
+		Authentication,
+	}).Timeout(0)
+
+	router.Get(
+		"/v1/cluster/nodes",
+		ClusterNodeControllerIndex,
+	).Middleware([]Middleware{
+		ForwardToPrimary,
+		Authentication,
+	})
+
+	router.Delete(
+		"/v1/cluster/nodes/{address}",
+		ClusterNodeControllerDestroy,
+	).Middleware([]Middleware{
+		ForwardToPrimary,
+		Authentication,
+	})
+
+	router.Get(
+		"/v1/cluster/replication",
+		ClusterReplicationController,
+	).Middleware([]Middleware{
+		ForwardToPrimary,
+		Authentication,
+	})
+
+	router.Get(
+		"/v1/storage/tiered",
+		TieredStorageController,
+	).Middleware([]Middleware{
+		Authentication,
+	})
+
+	router.Get(
+		"/v1/storage/gc",
+		StorageGarbageCollectionController,
+	).Middleware([]Middleware{
+		ForwardToPrimary,
+		Authentication,
+	}).Timeout(0)
+
+	router.Post(
+		"/v1/storage/gc",
+		StorageGarbageCollectionStoreController,
+	).Middleware([]Middleware{
+		ForwardToPrimary,
+		Authentication,
+	}).Timeout(0)
+
+	router.Get(
+		"/v1/audit",
+		AuditController,
+	).Middleware([]Middleware{
+		ForwardToPrimary,
+		Authentication,
+	})
+
+	router.Get(
+		"/v1/audit/verify",
+		AuditVerifyController,
+	).Middleware([]Middleware{
+		ForwardToPrimary,
+		Authentication,
+	}).Timeout(0)
+
 	// Internal routes for cluster operations.
 	router.Post(
 		"/v1/cluster/connection",
@@ -185,7 +342,7 @@ func LoadRoutes(router *Router) {
 		"/v1/cluster/members",
 		ClusterMemberStoreController,
 	).Middleware(
-		[]Middleware{},
+		[]Middleware{InternalConnection},
 	).Timeout(3 * time.Second)
 
 	router.Delete(
@@ -212,9 +369,7 @@ func LoadRoutes(router *Router) {
 	router.Get(
 		"/v1/health",
 		HealthCheckController,
-	).Middleware([]Middleware{
-		Internal,
-	})
+	)
 
 	router.Get("/v1/databases/{databaseName}/{branchName}/backups",
 		DatabaseBackupIndexController,
@@ -58,6 +58,18 @@ func TestRoutesMiddleware(t *testing.T) {
 			ExpectedMiddleware: []string{"RequireHost", "Authentication"},
 			Description:        "Cluster status route should have RequireHost and Authentication middleware",
 		},
+		{
+			Method:             "GET",
+			Path:               "/v1/auth/oidc",
+			ExpectedMiddleware: []string{},
+			Description:        "Single sign-on configuration route should be public so clients can sign in",
+		},
+		{
+			Method:             "POST",
+			Path:               "/v1/auth/oidc/token",
+			ExpectedMiddleware: []string{},
+			Description:        "Single sign-on token route should be public so clients can sign in",
+		},
 		{
 			Method:             "GET",
 			Path:               "/v1/users",
@@ -82,6 +94,42 @@ func TestRoutesMiddleware(t *testing.T) {
 			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
 			Description:        "User destroy route should have ForwardToPrimary and Authentication middleware",
 		},
+		{
+			Method:             "GET",
+			Path:               "/v1/roles",
+			ExpectedMiddleware: []string{"Authentication"},
+			Description:        "Role index route should have Authentication middleware",
+		},
+		{
+			Method:             "GET",
+			Path:               "/v1/roles/{name}",
+			ExpectedMiddleware: []string{"Authentication"},
+			Description:        "Role show route should have Authentication middleware",
+		},
+		{
+			Method:             "POST",
+			Path:               "/v1/roles",
+			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
+			Description:        "Role store route should have ForwardToPrimary and Authentication middleware",
+		},
+		{
+			Method:             "PUT",
+			Path:               "/v1/roles/{name}",
+			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
+			Description:        "Role update route should have ForwardToPrimary and Authentication middleware",
+		},
+		{
+			Method:             "DELETE",
+			Path:               "/v1/roles/{name}",
+			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
+			Description:        "Role destroy route should have ForwardToPrimary and Authentication middleware",
+		},
+		{
+			Method:             "POST",
+			Path:               "/v1/policy/simulate",
+			ExpectedMiddleware: []string{"Authentication"},
+			Description:        "Policy simulate route should have Authentication middleware",
+		},
 		{
 			Method:             "GET",
 			Path:               "/v1/access-keys",
@@ -106,12 +154,24 @@ func TestRoutesMiddleware(t *testing.T) {
 			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
 			Description:        "Access key update route should have ForwardToPrimary and Authentication middleware",
 		},
+		{
+			Method:             "POST",
+			Path:               "/v1/access-keys/{accessKeyId}/rotate",
+			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
+			Description:        "Access key rotate route should have ForwardToPrimary and Authentication middleware",
+		},
 		{
 			Method:             "DELETE",
 			Path:               "/v1/access-keys/{accessKeyId}",
 			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
 			Description:        "Access key destroy route should have ForwardToPrimary and Authentication middleware",
 		},
+		{
+			Method:             "POST",
+			Path:               "/v1/access-tokens",
+			ExpectedMiddleware: []string{"Authentication"},
+			Description:        "Access token store route should have Authentication middleware",
+		},
 		{
 			Method:             "GET",
 			Path:               "/v1/databases/{databaseName}/branches",
@@ -148,6 +208,12 @@ func TestRoutesMiddleware(t *testing.T) {
 			ExpectedMiddleware: []string{"Authentication"},
 			Description:        "Database show route should have Authentication middleware",
 		},
+		{
+			Method:             "GET",
+			Path:               "/v1/databases/{databaseName}/usage",
+			ExpectedMiddleware: []string{"Authentication"},
+			Description:        "Database usage route should have Authentication middleware",
+		},
 		{
 			Method:             "POST",
 			Path:               "/v1/databases",
@@ -160,6 +226,12 @@ func TestRoutesMiddleware(t *testing.T) {
 			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
 			Description:        "Database destroy route should have ForwardToPrimary and Authentication middleware",
 		},
+		{
+			Method:             "PUT",
+			Path:               "/v1/databases/{databaseName}/replication",
+			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
+			Description:        "Database replication update route should have ForwardToPrimary and Authentication middleware",
+		},
 		{
 			Method:             "POST",
 			Path:               "/v1/keys",
@@ -172,6 +244,60 @@ func TestRoutesMiddleware(t *testing.T) {
 			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
 			Description:        "Key activate route should have ForwardToPrimary and Authentication middleware",
 		},
+		{
+			Method:             "POST",
+			Path:               "/v1/cluster/handoff",
+			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
+			Description:        "Cluster handoff route should have ForwardToPrimary and Authentication middleware",
+		},
+		{
+			Method:             "GET",
+			Path:               "/v1/cluster/nodes",
+			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
+			Description:        "Cluster nodes index route should have ForwardToPrimary and Authentication middleware",
+		},
+		{
+			Method:             "DELETE",
+			Path:               "/v1/cluster/nodes/{address}",
+			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
+			Description:        "Cluster node destroy route should have ForwardToPrimary and Authentication middleware",
+		},
+		{
+			Method:             "GET",
+			Path:               "/v1/cluster/replication",
+			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
+			Description:        "Cluster replication route should have ForwardToPrimary and Authentication middleware",
+		},
+		{
+			Method:             "GET",
+			Path:               "/v1/storage/tiered",
+			ExpectedMiddleware: []string{"Authentication"},
+			Description:        "Tiered storage route should have Authentication middleware",
+		},
+		{
+			Method:             "GET",
+			Path:               "/v1/storage/gc",
+			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
+			Description:        "Storage garbage collection report route should have ForwardToPrimary and Authentication middleware",
+		},
+		{
+			Method:             "POST",
+			Path:               "/v1/storage/gc",
+			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
+			Description:        "Storage garbage collection sweep route should have ForwardToPrimary and Authentication middleware",
+		},
+		{
+			Method:             "GET",
+			Path:               "/v1/audit",
+			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
+			Description:        "Audit log route should have ForwardToPrimary and Authentication middleware",
+		},
+		{
+			Method:             "GET",
+			Path:               "/v1/audit/verify",
+			ExpectedMiddleware: []string{"ForwardToPrimary", "Authentication"},
+			Description:        "Audit log verification route should have ForwardToPrimary and Authentication middleware",
+		},
 		// Internal cluster routes
 		{
 			Method:             "POST",
@@ -188,8 +314,8 @@ func TestRoutesMiddleware(t *testing.T) {
 		{
 			Method:             "POST",
 			Path:               "/v1/cluster/members",
-			ExpectedMiddleware: []string{},
-			Description:        "Cluster member store route should have no specific middleware",
+			ExpectedMiddleware: []string{"InternalConnection"},
+			Description:        "Cluster member store route should have InternalConnection middleware",
 		},
 		{
 			Method:             "DELETE",
@@ -212,8 +338,8 @@ func TestRoutesMiddleware(t *testing.T) {
 		{
 			Method:             "GET",
 			Path:               "/v1/health",
-			ExpectedMiddleware: []string{"Internal"},
-			Description:        "Health check route should have Internal middleware",
+			ExpectedMiddleware: []string{},
+			Description:        "Health check route should be public so load balancers can check nodes",
 		},
 		// Database routes
 		{
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/database"
+)
+
+type StorageGarbageCollectionRequest struct {
+	DryRun *bool `json:"dry_run"`
+}
+
+// Report the orphaned objects in storage without deleting them.
+func StorageGarbageCollectionController(request *Request) Response {
+	// Authorize the request
+	err := request.Authorize(
+		[]string{"*", fmt.Sprintf("cluster:%s", request.cluster.Id)},
+		[]auth.Privilege{auth.ClusterPrivilegeManage},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	report, err := database.NewOrphanSweeper(request.databaseManager).Report()
+
+	if err != nil {
+		return ServerErrorResponse(err)
+	}
+
+	return SuccessResponse(
+		"Successfully created garbage collection report.",
+		report,
+		200,
+	)
+}
+
+// Sweep the orphaned objects in storage. Objects are only deleted when the
+// request explicitly disables the dry run.
+func StorageGarbageCollectionStoreController(request *Request) Response {
+	// Authorize the request
+	err := request.Authorize(
+		[]string{"*", fmt.Sprintf("cluster:%s", request.cluster.Id)},
+		[]auth.Privilege{auth.ClusterPrivilegeManage},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	input, err := request.Input(&StorageGarbageCollectionRequest{})
+
+	if err != nil {
+		return BadRequestResponse(err)
+	}
+
+	dryRun := true
+
+	if input.(*StorageGarbageCollectionRequest).DryRun != nil {
+		dryRun = *input.(*StorageGarbageCollectionRequest).DryRun
+	}
+
+	report, err := database.NewOrphanSweeper(request.databaseManager).Sweep(dryRun)
+
+	if err != nil {
+		if errors.Is(err, database.ErrOrphanSweepInProgress) {
+			return JsonResponse(map[string]any{
+				"status":  "error",
+				"message": fmt.Sprintf("Error: %s", err.Error()),
+			}, 409, nil)
+		}
+
+		return ServerErrorResponse(err)
+	}
+
+	message := "Successfully swept orphaned objects."
+
+	if dryRun {
+		message = "Successfully created garbage collection report."
+	}
+
+	return SuccessResponse(message, report, 200)
+}
@@ -0,0 +1,140 @@
+package http_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestStorageGarbageCollectionController(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   "Allow",
+				Resource: "*",
+				Actions:  []auth.Privilege{auth.ClusterPrivilegeManage},
+			},
+		})
+
+		err := server.App.Cluster.ObjectFS().WriteFile("_databases/orphaned/main/backups/1/backup-1.tar.gz", []byte("backup"), 0600)
+
+		if err != nil {
+			t.Fatalf("Failed to write orphaned object: %v", err)
+		}
+
+		response, statusCode, err := client.Send("/v1/storage/gc", "GET", nil)
+
+		if err != nil {
+			t.Fatalf("Failed to retrieve garbage collection report: %v", err)
+		}
+
+		if statusCode != 200 {
+			t.Fatalf("Unexpected status code: %d, expected 200", statusCode)
+		}
+
+		data, ok := response["data"].(map[string]any)
+
+		if !ok {
+			t.Fatalf("Expected data to be a map, got %T", response["data"])
+		}
+
+		if data["dry_run"] != true {
+			t.Errorf("Expected dry_run to be true, got %v", data["dry_run"])
+		}
+
+		objects, ok := data["objects"].([]any)
+
+		if !ok || len(objects) == 0 {
+			t.Fatalf("Expected orphaned objects, got %v", data["objects"])
+		}
+
+		if _, err := server.App.Cluster.ObjectFS().Stat("_databases/orphaned/main/backups/1/backup-1.tar.gz"); err != nil {
+			t.Errorf("Expected orphaned object to remain after a dry run, got %v", err)
+		}
+	})
+}
+
+func TestStorageGarbageCollectionStoreController(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   "Allow",
+				Resource: "*",
+				Actions:  []auth.Privilege{auth.ClusterPrivilegeManage},
+			},
+		})
+
+		err := server.App.Cluster.ObjectFS().WriteFile("_databases/orphaned/main/backups/1/backup-1.tar.gz", []byte("backup"), 0600)
+
+		if err != nil {
+			t.Fatalf("Failed to write orphaned object: %v", err)
+		}
+
+		// Sweeping is a dry run unless explicitly disabled
+		_, statusCode, err := client.Send("/v1/storage/gc", "POST", map[string]any{})
+
+		if err != nil {
+			t.Fatalf("Failed to sweep orphaned objects: %v", err)
+		}
+
+		if statusCode != 200 {
+			t.Fatalf("Unexpected status code: %d, expected 200", statusCode)
+		}
+
+		if _, err := server.App.Cluster.ObjectFS().Stat("_databases/orphaned/main/backups/1/backup-1.tar.gz"); err != nil {
+			t.Fatalf("Expected orphaned object to remain after a dry run, got %v", err)
+		}
+
+		response, statusCode, err := client.Send("/v1/storage/gc", "POST", map[string]any{
+			"dry_run": false,
+		})
+
+		if err != nil {
+			t.Fatalf("Failed to sweep orphaned objects: %v", err)
+		}
+
+		if statusCode != 200 {
+			t.Fatalf("Unexpected status code: %d, expected 200", statusCode)
+		}
+
+		data, ok := response["data"].(map[string]any)
+
+		if !ok {
+			t.Fatalf("Expected data to be a map, got %T", response["data"])
+		}
+
+		if data["dry_run"] != false {
+			t.Errorf("Expected dry_run to be false, got %v", data["dry_run"])
+		}
+
+		if _, err := server.App.Cluster.ObjectFS().Stat("_databases/orphaned/main/backups/1/backup-1.tar.gz"); err == nil {
+			t.Error("Expected orphaned object to be deleted")
+		}
+	})
+}
+
+func TestStorageGarbageCollectionController_Forbidden(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{})
+
+		_, statusCode, err := client.Send("/v1/storage/gc", "POST", map[string]any{"dry_run": false})
+
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+
+		if statusCode != 403 {
+			t.Fatalf("Unexpected status code: %d, expected 403", statusCode)
+		}
+	})
+}
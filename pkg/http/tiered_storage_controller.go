@@ -0,0 +1,88 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/storage"
+)
+
+type TieredStorageDatabaseUsageResponse struct {
+	DatabaseID   string `json:"database_id"`
+	DatabaseName string `json:"database_name"`
+	*storage.TieredFileSystemDatabaseUsage
+}
+
+type TieredStorageUsageResponse struct {
+	Databases      []TieredStorageDatabaseUsageResponse `json:"databases"`
+	EvictionPolicy string                               `json:"eviction_policy"`
+	FileCount      int                                  `json:"file_count"`
+	MaxBytes       int64                                `json:"max_bytes"`
+	MaxFiles       int                                  `json:"max_files"`
+	NodeID         string                               `json:"node_id"`
+	UsedBytes      int64                                `json:"used_bytes"`
+}
+
+// Show the usage of the local tier of the tiered file system on this node.
+func TieredStorageController(request *Request) Response {
+	// Authorize the request
+	err := request.Authorize(
+		[]string{"*", fmt.Sprintf("cluster:%s", request.cluster.Id)},
+		[]auth.Privilege{auth.ClusterPrivilegeManage},
+	)
+
+	if err != nil {
+		return ForbiddenResponse(err)
+	}
+
+	driver, ok := request.cluster.TieredFS().Driver().(*storage.TieredFileSystemDriver)
+
+	if !ok {
+		return ServerErrorResponse(errors.New("the tiered file system is not available"))
+	}
+
+	usage := driver.Usage()
+
+	databaseNames := map[string]string{}
+
+	dbs, err := request.databaseManager.All()
+
+	if err != nil {
+		return ServerErrorResponse(err)
+	}
+
+	for _, db := range dbs {
+		databaseNames[db.DatabaseID] = db.Name
+	}
+
+	response := TieredStorageUsageResponse{
+		Databases:      make([]TieredStorageDatabaseUsageResponse, 0, len(usage.Databases)),
+		EvictionPolicy: usage.EvictionPolicy,
+		FileCount:      usage.FileCount,
+		MaxBytes:       usage.MaxBytes,
+		MaxFiles:       usage.MaxFiles,
+		NodeID:         request.cluster.Node().ID,
+		UsedBytes:      usage.UsedBytes,
+	}
+
+	for databaseId, databaseUsage := range usage.Databases {
+		response.Databases = append(response.Databases, TieredStorageDatabaseUsageResponse{
+			DatabaseID:                    databaseId,
+			DatabaseName:                  databaseNames[databaseId],
+			TieredFileSystemDatabaseUsage: databaseUsage,
+		})
+	}
+
+	slices.SortFunc(response.Databases, func(a, b TieredStorageDatabaseUsageResponse) int {
+		return strings.Compare(a.DatabaseID, b.DatabaseID)
+	})
+
+	return SuccessResponse(
+		"Successfully retrieved tiered storage usage.",
+		response,
+		200,
+	)
+}
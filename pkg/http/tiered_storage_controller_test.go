@@ -0,0 +1,66 @@
+package http_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+func TestTieredStorageController(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   "Allow",
+				Resource: "*",
+				Actions:  []auth.Privilege{auth.ClusterPrivilegeManage},
+			},
+		})
+
+		response, statusCode, err := client.Send("/v1/storage/tiered", "GET", nil)
+
+		if err != nil {
+			t.Fatalf("Failed to retrieve tiered storage usage: %v", err)
+		}
+
+		if statusCode != 200 {
+			t.Fatalf("Unexpected status code: %d, expected 200", statusCode)
+		}
+
+		data, ok := response["data"].(map[string]any)
+
+		if !ok {
+			t.Fatalf("Expected data to be a map, got %T", response["data"])
+		}
+
+		if data["node_id"] != server.App.Cluster.Node().ID {
+			t.Errorf("Expected node_id to be %s, got %v", server.App.Cluster.Node().ID, data["node_id"])
+		}
+
+		if _, ok := data["databases"].([]any); !ok {
+			t.Errorf("Expected databases to be a list, got %T", data["databases"])
+		}
+	})
+}
+
+func TestTieredStorageController_Forbidden(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{})
+
+		_, statusCode, err := client.Send("/v1/storage/tiered", "GET", nil)
+
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+
+		if statusCode != 403 {
+			t.Fatalf("Unexpected status code: %d, expected 403", statusCode)
+		}
+	})
+}
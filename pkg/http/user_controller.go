@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/logs"
 )
 
 func UserControllerIndex(request *Request) Response {
@@ -40,8 +41,9 @@ func UserControllerIndex(request *Request) Response {
 
 type UserControllerStoreRequest struct {
 	Username   string                    `json:"username" validate:"required"`
-	Password   string                    `json:"password" validate:"required,min=8"`
-	Statements []auth.AccessKeyStatement `json:"statements" validate:"required"`
+	Password   string                    `json:"password" validate:"required"`
+	Roles      []string                  `json:"roles" validate:"omitempty,max=20"`
+	Statements []auth.AccessKeyStatement `json:"statements" validate:"required_without=Roles"`
 }
 
 func UserControllerShow(request *Request) Response {
@@ -65,6 +67,7 @@ func UserControllerShow(request *Request) Response {
 
 	userResponse := &auth.UserResponse{
 		Username:   user.Username,
+		Roles:      user.Roles,
 		Statements: user.Statements,
 		CreatedAt:  user.CreatedAt,
 		UpdatedAt:  user.UpdatedAt,
@@ -97,8 +100,8 @@ func UserControllerStore(request *Request) Response {
 	validationErrors := request.Validate(input, map[string]string{
 		"username.required":                "The username field is required.",
 		"password.required":                "The password field is required.",
-		"password.min":                     "The password field should be a minimum of 8 characters.",
-		"statements.required":              "The statements field is required",
+		"statements.required_without":      "The statements field is required when no roles are assigned",
+		"roles.max":                        "The roles field must contain at most 20 items",
 		"statements.*.validateFn":          "This statement is not valid. All actions must match the resource.",
 		"statements.*.effect.required":     "Each statement must have an effect",
 		"statements.*.effect.validateFn":   "The effect of the statement must be one of 'Allow' or 'Deny'",
@@ -113,6 +116,18 @@ func UserControllerStore(request *Request) Response {
 		return ValidationErrorResponse(validationErrors)
 	}
 
+	if validationErrors := validateRoles(request, input.(*UserControllerStoreRequest).Roles); validationErrors != nil {
+		return ValidationErrorResponse(validationErrors)
+	}
+
+	if messages := auth.ValidatePassword(
+		request.cluster.Config,
+		input.(*UserControllerStoreRequest).Username,
+		input.(*UserControllerStoreRequest).Password,
+	); len(messages) > 0 {
+		return ValidationErrorResponse(map[string][]string{"password": messages})
+	}
+
 	if input.(*UserControllerStoreRequest).Username == "root" {
 		return BadRequestResponse(fmt.Errorf("the username is invalid, 'root' is reserved"))
 	}
@@ -123,19 +138,23 @@ func UserControllerStore(request *Request) Response {
 
 	data := input.(*UserControllerStoreRequest)
 
-	user, err := request.cluster.Auth.UserManager().Add(
+	user, err := request.cluster.Auth.UserManager().AddWithRoles(
 		data.Username,
 		data.Password,
 		data.Statements,
+		data.Roles,
 	)
 
 	if err != nil {
 		return ServerErrorResponse(err)
 	}
 
+	request.Audit(logs.AuditActionUserCreate, fmt.Sprintf("user:%s", user.Username), nil)
+
 	// Convert the user to a response format
 	userResponse := &auth.UserResponse{
 		Username:   user.Username,
+		Roles:      user.Roles,
 		Statements: user.Statements,
 		CreatedAt:  user.CreatedAt,
 		UpdatedAt:  user.UpdatedAt,
@@ -149,7 +168,8 @@ func UserControllerStore(request *Request) Response {
 }
 
 type UserControllerUpdateRequest struct {
-	Statements []auth.AccessKeyStatement `json:"statements" validate:"required"`
+	Roles      []string                  `json:"roles" validate:"omitempty,max=20"`
+	Statements []auth.AccessKeyStatement `json:"statements" validate:"required_without=Roles"`
 }
 
 func UserControllerUpdate(request *Request) Response {
@@ -178,7 +198,8 @@ func UserControllerUpdate(request *Request) Response {
 	}
 
 	validationErrors := request.Validate(input, map[string]string{
-		"statements.required":              "The statements field is required",
+		"statements.required_without":      "The statements field is required when no roles are assigned",
+		"roles.max":                        "The roles field must contain at most 20 items",
 		"statements.*.validateFn":          "This statement is not valid. All actions must match the resource.",
 		"statements.*.effect.required":     "Each statement must have an effect",
 		"statements.*.effect.validateFn":   "The effect of the statement must be one of 'Allow' or 'Deny'",
@@ -195,13 +216,25 @@ func UserControllerUpdate(request *Request) Response {
 
 	data := input.(*UserControllerUpdateRequest)
 
+	if validationErrors := validateRoles(request, data.Roles); validationErrors != nil {
+		return ValidationErrorResponse(validationErrors)
+	}
+
 	// Update the user
-	user.Statements = data.Statements
+	if data.Roles != nil {
+		user.Roles = data.Roles
+	}
+
+	if data.Statements != nil {
+		user.Statements = data.Statements
+	}
 
 	if err := request.cluster.Auth.UserManager().Update(user); err != nil {
 		return ServerErrorResponse(err)
 	}
 
+	request.Audit(logs.AuditActionUserUpdate, fmt.Sprintf("user:%s", username), nil)
+
 	return SuccessResponse(
 		fmt.Sprintf("User '%s' updated successfully", username),
 		user,
@@ -236,5 +269,7 @@ func UserControllerDestroy(request *Request) Response {
 		return ServerErrorResponse(err)
 	}
 
+	request.Audit(logs.AuditActionUserDelete, fmt.Sprintf("user:%s", username), nil)
+
 	return SuccessResponse("", nil, 204)
 }
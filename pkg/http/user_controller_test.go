@@ -1,6 +1,7 @@
 package http_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/litebase/litebase/internal/test"
@@ -190,6 +191,64 @@ func TestUserController_StoreWithInvalidData(t *testing.T) {
 	})
 }
 
+func TestUserController_StoreEnforcesPasswordPolicy(t *testing.T) {
+	test.Run(t, func() {
+		server := test.NewTestServer(t)
+		defer server.Shutdown()
+
+		server.App.Config.PasswordRequireNumber = true
+		server.App.Config.PasswordRequireUppercase = true
+
+		client := server.WithAccessKeyClient([]auth.AccessKeyStatement{
+			{
+				Effect:   "Allow",
+				Resource: "*",
+				Actions:  []auth.Privilege{auth.ClusterPrivilegeManage},
+			},
+		})
+
+		testCases := []struct {
+			password   string
+			statusCode int
+		}{
+			{"password", 422},
+			{"Password", 422},
+			{"Password123", 201},
+		}
+
+		for i, tc := range testCases {
+			response, statusCode, err := client.Send(
+				"/v1/users",
+				"POST", map[string]any{
+					"username": fmt.Sprintf("testuser%d", i),
+					"password": tc.password,
+					"statements": []auth.AccessKeyStatement{
+						{
+							Effect:   "Allow",
+							Resource: "*",
+							Actions:  []auth.Privilege{auth.ClusterPrivilegeManage},
+						},
+					},
+				},
+			)
+
+			if err != nil {
+				t.Fatalf("Failed to create user: %v", err)
+			}
+
+			if statusCode != tc.statusCode {
+				t.Fatalf("%s: expected status code %d, got %d", tc.password, tc.statusCode, statusCode)
+			}
+
+			if tc.statusCode == 422 {
+				if errors, ok := response["errors"].(map[string]any); !ok || errors["password"] == nil {
+					t.Errorf("%s: expected password errors, got %v", tc.password, response["errors"])
+				}
+			}
+		}
+	})
+}
+
 func TestUserControllerUpdate(t *testing.T) {
 	test.Run(t, func() {
 		server := test.NewTestServer(t)
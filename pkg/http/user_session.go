@@ -0,0 +1,33 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/litebase/litebase/pkg/auth"
+)
+
+// Return the user session of the request when it is authenticated with the
+// bearer token of a single sign-on session, or nil otherwise.
+func (request *Request) UserSession() *auth.UserSession {
+	if request.userSessionChecked {
+		return request.userSession
+	}
+
+	request.userSessionChecked = true
+
+	token, ok := strings.CutPrefix(request.headers.Get("Authorization"), "Bearer ")
+
+	if !ok || request.cluster.Auth.OIDCProvider() == nil {
+		return nil
+	}
+
+	session, err := request.cluster.Auth.VerifyUserSession(token)
+
+	if err != nil {
+		return nil
+	}
+
+	request.userSession = session
+
+	return request.userSession
+}
@@ -0,0 +1,172 @@
+package https
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+var CertificateReloadInterval = 5 * time.Second
+
+// The CertificateReloader serves a certificate and key loaded from disk, and
+// optionally a CA used to verify peers. The files are checked for changes
+// periodically so certificates can be renewed without restarting the process.
+type CertificateReloader struct {
+	caPath      string
+	caPool      *x509.CertPool
+	certificate *tls.Certificate
+	certPath    string
+	checkedAt   time.Time
+	keyPath     string
+	modTimes    []time.Time
+	mutex       *sync.RWMutex
+}
+
+// Create a new instance of a CertificateReloader and load the certificate.
+func NewCertificateReloader(certPath, keyPath string) (*CertificateReloader, error) {
+	return NewCertificateReloaderWithCA("", certPath, keyPath)
+}
+
+// Create a new instance of a CertificateReloader that also loads a CA and load
+// the certificate and CA.
+func NewCertificateReloaderWithCA(caPath, certPath, keyPath string) (*CertificateReloader, error) {
+	reloader := &CertificateReloader{
+		caPath:   caPath,
+		certPath: certPath,
+		keyPath:  keyPath,
+		mutex:    &sync.RWMutex{},
+	}
+
+	err := reloader.Reload()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+// Return the certificate that is currently loaded, reloading it if the files
+// have changed. The current certificate is kept if the files cannot be loaded,
+// for example while they are being replaced.
+func (cr *CertificateReloader) Certificate() *tls.Certificate {
+	certificate, _ := cr.Current()
+
+	return certificate
+}
+
+// Return the certificate and CA pool that are currently loaded, reloading
+// them if the files have changed. The CA pool is nil if no CA is loaded.
+func (cr *CertificateReloader) Current() (*tls.Certificate, *x509.CertPool) {
+	cr.mutex.RLock()
+	shouldCheck := time.Since(cr.checkedAt) > CertificateReloadInterval
+	cr.mutex.RUnlock()
+
+	if shouldCheck {
+		if err := cr.reloadIfChanged(); err != nil {
+			slog.Error("Failed to reload the TLS certificate", "error", err)
+		}
+	}
+
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	return cr.certificate, cr.caPool
+}
+
+// Return the modification times of the watched files.
+func (cr *CertificateReloader) fileModTimes() ([]time.Time, error) {
+	paths := []string{cr.certPath, cr.keyPath}
+
+	if cr.caPath != "" {
+		paths = append(paths, cr.caPath)
+	}
+
+	modTimes := make([]time.Time, len(paths))
+
+	for i, path := range paths {
+		info, err := os.Stat(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		modTimes[i] = info.ModTime()
+	}
+
+	return modTimes, nil
+}
+
+// Return the current certificate for a TLS handshake. This can be used as the
+// GetCertificate function of a tls.Config.
+func (cr *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return cr.Certificate(), nil
+}
+
+// Load the certificate, key and CA from their files.
+func (cr *CertificateReloader) Reload() error {
+	modTimes, err := cr.fileModTimes()
+
+	if err != nil {
+		return err
+	}
+
+	certificate, err := tls.LoadX509KeyPair(cr.certPath, cr.keyPath)
+
+	if err != nil {
+		return fmt.Errorf("failed to load the TLS certificate: %w", err)
+	}
+
+	var caPool *x509.CertPool
+
+	if cr.caPath != "" {
+		caData, err := os.ReadFile(cr.caPath)
+
+		if err != nil {
+			return fmt.Errorf("failed to read the CA: %w", err)
+		}
+
+		caPool = x509.NewCertPool()
+
+		if !caPool.AppendCertsFromPEM(caData) {
+			return errors.New("failed to parse the CA")
+		}
+	}
+
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	cr.caPool = caPool
+	cr.certificate = &certificate
+	cr.checkedAt = time.Now().UTC()
+	cr.modTimes = modTimes
+
+	return nil
+}
+
+// Reload the certificate if the files have been modified since it was last
+// loaded.
+func (cr *CertificateReloader) reloadIfChanged() error {
+	cr.mutex.Lock()
+	cr.checkedAt = time.Now().UTC()
+	loadedModTimes := cr.modTimes
+	cr.mutex.Unlock()
+
+	modTimes, err := cr.fileModTimes()
+
+	if err != nil {
+		return err
+	}
+
+	if slices.Equal(modTimes, loadedModTimes) {
+		return nil
+	}
+
+	return cr.Reload()
+}
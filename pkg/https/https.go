@@ -0,0 +1,100 @@
+package https
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/litebase/litebase/pkg/config"
+)
+
+var ErrTLSIncomplete = errors.New("both the TLS certificate and key paths must be set to enable TLS")
+
+// Return the TLS configuration used to connect to nodes that serve the public
+// certificate. Nodes are addressed by their IP address so the certificate is
+// verified against the host name of the cluster instead.
+func ClientConfig(c *config.Config) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: c.HostName,
+	}
+}
+
+// Return true if TLS termination has been configured.
+func Enabled(c *config.Config) bool {
+	return c.TLSCertPath != "" || c.TLSKeyPath != ""
+}
+
+// Create a certificate reloader for the configured certificate and key. Nil
+// is returned if TLS has not been configured.
+func NewCertificateReloaderFromConfig(c *config.Config) (*CertificateReloader, error) {
+	if !Enabled(c) {
+		return nil, nil
+	}
+
+	if c.TLSCertPath == "" || c.TLSKeyPath == "" {
+		return nil, ErrTLSIncomplete
+	}
+
+	return NewCertificateReloader(c.TLSCertPath, c.TLSKeyPath)
+}
+
+// Return the application protocols negotiated over TLS.
+func NextProtos(c *config.Config) []string {
+	if c.TLSHTTP2 {
+		return []string{"h2", "http/1.1"}
+	}
+
+	return []string{"http/1.1"}
+}
+
+// Configure an HTTP server to serve TLS with the given configuration. HTTP/2
+// is disabled on the server unless it has been enabled in the configuration.
+func Configure(server *http.Server, c *config.Config, tlsConfig *tls.Config) {
+	if !c.TLSHTTP2 {
+		server.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	}
+
+	server.TLSConfig = tlsConfig
+}
+
+// Return the TLS configuration used to serve the certificate of the reloader.
+func ServerConfig(c *config.Config, reloader *CertificateReloader) *tls.Config {
+	return &tls.Config{
+		GetCertificate: reloader.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+		NextProtos:     NextProtos(c),
+	}
+}
+
+// Create an HTTP server that redirects every request to the same host and
+// path over HTTPS on the given port.
+func NewRedirectServer(c *config.Config, httpsPort string) *http.Server {
+	return &http.Server{
+		Addr: net.JoinHostPort("", c.TLSRedirectPort),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, RedirectURL(r, httpsPort), http.StatusPermanentRedirect)
+		}),
+		ReadHeaderTimeout: 2 * time.Second,
+	}
+}
+
+// Return the HTTPS URL of a request. The port is omitted when it is the
+// default HTTPS port.
+func RedirectURL(r *http.Request, httpsPort string) string {
+	host := r.Host
+
+	if hostname, _, err := net.SplitHostPort(r.Host); err == nil {
+		host = hostname
+	}
+
+	if httpsPort != "" && httpsPort != "443" {
+		host = net.JoinHostPort(host, httpsPort)
+	} else if net.ParseIP(host) != nil && net.ParseIP(host).To4() == nil {
+		host = "[" + host + "]"
+	}
+
+	return "https://" + host + r.URL.RequestURI()
+}
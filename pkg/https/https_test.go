@@ -0,0 +1,266 @@
+package https_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/litebase/litebase/pkg/config"
+	"github.com/litebase/litebase/pkg/https"
+)
+
+// Write a self signed certificate and key to a directory and return a config
+// that points to them.
+func writeCertificate(t *testing.T, directory string, serial int64, modTime time.Time) *config.Config {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		DNSNames:     []string{"localhost"},
+		NotAfter:     time.Now().Add(time.Hour),
+		NotBefore:    time.Now().Add(-time.Hour),
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &config.Config{
+		TLSCertPath: filepath.Join(directory, "cert.pem"),
+		TLSHTTP2:    true,
+		TLSKeyPath:  filepath.Join(directory, "key.pem"),
+	}
+
+	for path, block := range map[string]*pem.Block{
+		c.TLSCertPath: {Type: "CERTIFICATE", Bytes: der},
+		c.TLSKeyPath:  {Type: "EC PRIVATE KEY", Bytes: keyDer},
+	} {
+		if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return c
+}
+
+func serialOf(t *testing.T, certificate *tls.Certificate) int64 {
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return leaf.SerialNumber.Int64()
+}
+
+func TestNewCertificateReloaderFromConfig(t *testing.T) {
+	reloader, err := https.NewCertificateReloaderFromConfig(&config.Config{})
+
+	if err != nil || reloader != nil {
+		t.Errorf("Expected TLS to be disabled, got %v, %v", reloader, err)
+	}
+
+	_, err = https.NewCertificateReloaderFromConfig(&config.Config{TLSCertPath: "cert.pem"})
+
+	if !errors.Is(err, https.ErrTLSIncomplete) {
+		t.Errorf("Expected ErrTLSIncomplete, got %v", err)
+	}
+
+	c := writeCertificate(t, t.TempDir(), 2, time.Now())
+	reloader, err = https.NewCertificateReloaderFromConfig(c)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if serialOf(t, reloader.Certificate()) != 2 {
+		t.Error("Expected the configured certificate to be loaded")
+	}
+}
+
+func TestCertificateReloaderReloadsChangedFiles(t *testing.T) {
+	defaultInterval := https.CertificateReloadInterval
+	https.CertificateReloadInterval = 0
+	defer func() { https.CertificateReloadInterval = defaultInterval }()
+
+	directory := t.TempDir()
+	c := writeCertificate(t, directory, 2, time.Now().Add(-time.Minute))
+
+	reloader, err := https.NewCertificateReloader(c.TLSCertPath, c.TLSKeyPath)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	writeCertificate(t, directory, 3, time.Now())
+
+	certificate, err := reloader.GetCertificate(&tls.ClientHelloInfo{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if serialOf(t, certificate) != 3 {
+		t.Errorf("Expected the renewed certificate to be served, got serial %d", serialOf(t, certificate))
+	}
+
+	// A certificate that cannot be loaded does not replace the current one
+	if err := os.WriteFile(c.TLSCertPath, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if serialOf(t, reloader.Certificate()) != 3 {
+		t.Error("Expected the current certificate to be kept")
+	}
+}
+
+func TestCertificateReloaderWithCA(t *testing.T) {
+	c := writeCertificate(t, t.TempDir(), 2, time.Now())
+
+	// The certificate is self signed, so it is also used as the CA
+	reloader, err := https.NewCertificateReloaderWithCA(c.TLSCertPath, c.TLSCertPath, c.TLSKeyPath)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	certificate, caPool := reloader.Current()
+
+	if caPool == nil {
+		t.Fatal("Expected the CA to be loaded")
+	}
+
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := leaf.Verify(x509.VerifyOptions{Roots: caPool}); err != nil {
+		t.Errorf("Expected the certificate to be verified against the CA, got %v", err)
+	}
+
+	reloader, err = https.NewCertificateReloader(c.TLSCertPath, c.TLSKeyPath)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, caPool := reloader.Current(); caPool != nil {
+		t.Error("Expected no CA to be loaded without a CA path")
+	}
+}
+
+func TestServerConfig(t *testing.T) {
+	c := writeCertificate(t, t.TempDir(), 2, time.Now())
+
+	reloader, err := https.NewCertificateReloader(c.TLSCertPath, c.TLSKeyPath)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, http2 := range []bool{true, false} {
+		c.TLSHTTP2 = http2
+
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.Proto))
+		}))
+
+		https.Configure(server.Config, c, https.ServerConfig(c, reloader))
+		server.TLS = server.Config.TLSConfig
+		server.EnableHTTP2 = http2
+		server.StartTLS()
+
+		transport := server.Client().Transport.(*http.Transport)
+		transport.ForceAttemptHTTP2 = true
+
+		response, err := server.Client().Get(server.URL)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		response.Body.Close()
+
+		if response.ProtoMajor == 2 != http2 {
+			t.Errorf("Expected HTTP/2 to be %v, got %s", http2, response.Proto)
+		}
+
+		server.Close()
+	}
+}
+
+func TestRedirectURL(t *testing.T) {
+	testCases := []struct {
+		host      string
+		httpsPort string
+		target    string
+		expected  string
+	}{
+		{"example.com", "443", "/v1/health?verbose=true", "https://example.com/v1/health?verbose=true"},
+		{"example.com:80", "8443", "/", "https://example.com:8443/"},
+		{"127.0.0.1:8080", "", "/path", "https://127.0.0.1/path"},
+		{"[::1]:80", "443", "/", "https://[::1]/"},
+	}
+
+	for _, testCase := range testCases {
+		request := httptest.NewRequest("GET", testCase.target, nil)
+		request.Host = testCase.host
+
+		if url := https.RedirectURL(request, testCase.httpsPort); url != testCase.expected {
+			t.Errorf("Expected %s, got %s", testCase.expected, url)
+		}
+	}
+}
+
+func TestRedirectServer(t *testing.T) {
+	server := https.NewRedirectServer(&config.Config{TLSRedirectPort: "80"}, "443")
+
+	if server.Addr != ":80" {
+		t.Errorf("Expected the redirect server to listen on :80, got %s", server.Addr)
+	}
+
+	request := httptest.NewRequest("POST", "/v1/query", nil)
+	request.Host = "example.com"
+	recorder := httptest.NewRecorder()
+
+	server.Handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusPermanentRedirect {
+		t.Errorf("Expected status %d, got %d", http.StatusPermanentRedirect, recorder.Code)
+	}
+
+	if location := recorder.Header().Get("Location"); location != "https://example.com/v1/query" {
+		t.Errorf("Expected the request to be redirected to HTTPS, got %s", location)
+	}
+}
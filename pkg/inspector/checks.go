@@ -0,0 +1,464 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/litebase/litebase/internal/utils"
+	"github.com/litebase/litebase/pkg/backups"
+	"github.com/litebase/litebase/pkg/storage"
+)
+
+const snapshotLogEntrySize = 12
+
+// The layout of a SQLite WAL file.
+const (
+	walFrameHeaderSize   = 24
+	walHeaderSize        = 32
+	walMagicBigEndian    = 0x377f0683
+	walMagicLittleEndian = 0x377f0682
+)
+
+// Check that the index of a page log is present.
+func (i *Inspector) inspectPageLog(report *FileReport, siblings []string) error {
+	if report.Size%i.pageSize != 0 {
+		report.finding(SeverityError, "size %d is not a multiple of the page size %d", report.Size, i.pageSize)
+	}
+
+	report.Entries = int(report.Size / i.pageSize)
+
+	name := report.Path[strings.LastIndex(report.Path, "/")+1:]
+
+	if !slices.Contains(siblings, name+"_INDEX") {
+		report.finding(SeverityWarning, "page log has no index file")
+	}
+
+	return nil
+}
+
+// Check that every entry of a page log index points to a page in the page log
+// that belongs to the page group of the log.
+func (i *Inspector) inspectPageLogIndex(report *FileReport, directory, name string) error {
+	if report.Size%storage.PageLogIndexEntryLength != 0 {
+		report.finding(SeverityError, "size %d is not a multiple of the entry length %d", report.Size, storage.PageLogIndexEntryLength)
+	}
+
+	pageLogPath := directory + strings.TrimSuffix(name, "_INDEX")
+	pageLogSize := int64(-1)
+
+	if info, err := i.fileSystem.Stat(pageLogPath); err == nil {
+		pageLogSize = info.Size()
+	} else {
+		report.finding(SeverityError, "page log %s does not exist", pageLogPath)
+	}
+
+	pageGroup, err := strconv.ParseInt(pageLogIndexPattern.FindStringSubmatch(name)[1], 10, 64)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := i.fileSystem.ReadFile(report.Path)
+
+	if err != nil {
+		return err
+	}
+
+	for offset := 0; offset+storage.PageLogIndexEntryLength <= len(data); offset += storage.PageLogIndexEntryLength {
+		entry := storage.DecodePageLogIndexEntry(data[offset : offset+storage.PageLogIndexEntryLength])
+		report.Entries++
+
+		if entry.Tombstoned {
+			continue
+		}
+
+		if entry.PageNumber < 1 || (int64(entry.PageNumber)-1)/storage.PageLoggerPageGroups+1 != pageGroup {
+			report.finding(SeverityError, "page %d does not belong to page group %d", entry.PageNumber, pageGroup)
+		}
+
+		if entry.Offset%i.pageSize != 0 {
+			report.finding(SeverityError, "page %d version %d has an unaligned offset %d", entry.PageNumber, entry.Version, entry.Offset)
+		}
+
+		if pageLogSize >= 0 && entry.Offset+i.pageSize > pageLogSize {
+			report.finding(SeverityError, "page %d version %d points past the end of the page log", entry.PageNumber, entry.Version)
+		}
+	}
+
+	return nil
+}
+
+// Check that a range holds whole pages and does not exceed the maximum size.
+func (i *Inspector) inspectRange(report *FileReport) {
+	if report.Size%i.pageSize != 0 {
+		report.finding(SeverityError, "size %d is not a multiple of the page size %d", report.Size, i.pageSize)
+	}
+
+	report.Entries = int(report.Size / i.pageSize)
+
+	if int64(report.Entries) > storage.RangeMaxPages {
+		report.finding(SeverityError, "range holds %d pages, the maximum is %d", report.Entries, storage.RangeMaxPages)
+	}
+}
+
+// Check that every range in the range index exists and is the latest version.
+func (i *Inspector) inspectRangeIndex(report *FileReport, directory string, siblings []string) error {
+	if report.Size%8 != 0 {
+		report.finding(SeverityError, "size %d is not a multiple of 8", report.Size)
+	}
+
+	data, err := i.fileSystem.ReadFile(report.Path)
+
+	if err != nil {
+		return err
+	}
+
+	// Versions written after the last completed checkpoint are not expected
+	// to be in the index yet.
+	checkpointedAt, err := i.lastCheckpoint(directory)
+
+	if err != nil {
+		return err
+	}
+
+	latest := latestRanges(siblings, checkpointedAt)
+
+	for offset := 0; offset+8 <= len(data); offset += 8 {
+		version, err := utils.SafeUint64ToInt64(binary.LittleEndian.Uint64(data[offset : offset+8]))
+
+		if err != nil {
+			report.finding(SeverityError, "invalid version at offset %d: %s", offset, err)
+			continue
+		}
+
+		entry := storage.DataRangeIndexEntry{
+			Number:  int64(offset/8) + 1,
+			Version: version,
+		}
+
+		if entry.Version == 0 {
+			continue
+		}
+
+		report.Entries++
+
+		if !slices.Contains(siblings, entry.Name()) {
+			report.finding(SeverityError, "range %s does not exist", directory+entry.Name())
+			continue
+		}
+
+		if version := latest[entry.Number]; version > entry.Version {
+			report.finding(SeverityWarning, "range %d is indexed at version %d but version %d exists", entry.Number, entry.Version, version)
+		}
+	}
+
+	return nil
+}
+
+// Check that every line of the range log can be parsed.
+func (i *Inspector) inspectRangeLog(report *FileReport) error {
+	data, err := i.fileSystem.ReadFile(report.Path)
+
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" {
+			continue
+		}
+
+		report.Entries++
+
+		if !rangePattern.MatchString(line) {
+			report.finding(SeverityError, "invalid range log entry %q", line)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Check that the frames of a rollback log are chained and that every entry
+// decodes and matches its checksum.
+func (i *Inspector) inspectRollbackLog(report *FileReport) error {
+	data, err := i.fileSystem.ReadFile(report.Path)
+
+	if err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(data)
+	offset := int64(0)
+
+	for offset < int64(len(data)) {
+		if offset+backups.RollbackFrameHeaderSize > int64(len(data)) {
+			report.finding(SeverityError, "truncated frame header at offset %d", offset)
+			break
+		}
+
+		frame, err := backups.DeserializeRollbackLogFrame(data[offset : offset+backups.RollbackFrameHeaderSize])
+
+		if err != nil {
+			report.finding(SeverityError, "invalid frame at offset %d: %s", offset, err)
+			break
+		}
+
+		report.Entries++
+
+		if frame.Offset != offset {
+			report.finding(SeverityError, "frame at offset %d records offset %d", offset, frame.Offset)
+			break
+		}
+
+		if frame.Size < backups.RollbackFrameHeaderSize || offset+frame.Size > int64(len(data)) {
+			report.finding(SeverityError, "frame at offset %d has an invalid size %d", offset, frame.Size)
+			break
+		}
+
+		if frame.Committed == 0 {
+			report.finding(SeverityWarning, "frame at offset %d is not committed", offset)
+		}
+
+		section := io.NewSectionReader(reader, offset+backups.RollbackFrameHeaderSize, frame.Size-backups.RollbackFrameHeaderSize)
+		remaining := frame.Size - backups.RollbackFrameHeaderSize
+
+		for remaining > 0 {
+			entry, err := backups.DeserializeRollbackLogEntry(section)
+
+			if err != nil {
+				report.finding(SeverityError, "invalid entry in frame at offset %d: %s", offset, err)
+				break
+			}
+
+			remaining -= int64(backups.RollbackLogEntryHeaderSize + entry.SizeCompressed)
+		}
+
+		offset += frame.Size
+	}
+
+	return nil
+}
+
+// Check that the snapshot log holds whole entries in chronological order.
+func (i *Inspector) inspectSnapshotLog(report *FileReport) error {
+	if report.Size%snapshotLogEntrySize != 0 {
+		report.finding(SeverityError, "size %d is not a multiple of the entry size %d", report.Size, snapshotLogEntrySize)
+	}
+
+	data, err := i.fileSystem.ReadFile(report.Path)
+
+	if err != nil {
+		return err
+	}
+
+	previous := int64(0)
+
+	for offset := 0; offset+snapshotLogEntrySize <= len(data); offset += snapshotLogEntrySize {
+		timestamp, err := utils.SafeUint64ToInt64(binary.LittleEndian.Uint64(data[offset : offset+8]))
+
+		if err != nil {
+			report.finding(SeverityError, "invalid timestamp at offset %d: %s", offset, err)
+			break
+		}
+
+		report.Entries++
+
+		if timestamp < previous {
+			report.finding(SeverityError, "snapshot %d is older than the snapshot before it", timestamp)
+		}
+
+		previous = timestamp
+	}
+
+	return nil
+}
+
+// Check that a WAL file holds a SQLite WAL header for the page size of the
+// database followed by whole frames.
+func (i *Inspector) inspectWAL(report *FileReport) error {
+	// WAL files are created empty and written when the first transaction
+	// commits.
+	if report.Size == 0 {
+		return nil
+	}
+
+	if report.Size < walHeaderSize {
+		report.finding(SeverityError, "size %d is smaller than the WAL header", report.Size)
+		return nil
+	}
+
+	data, err := i.fileSystem.ReadFile(report.Path)
+
+	if err != nil {
+		return err
+	}
+
+	if magic := binary.BigEndian.Uint32(data[0:4]); magic != walMagicBigEndian && magic != walMagicLittleEndian {
+		report.finding(SeverityError, "invalid WAL header magic %#x", magic)
+		return nil
+	}
+
+	if pageSize := int64(binary.BigEndian.Uint32(data[8:12])); pageSize != i.pageSize {
+		report.finding(SeverityError, "page size %d does not match the database page size %d", pageSize, i.pageSize)
+		return nil
+	}
+
+	frameSize := walFrameHeaderSize + i.pageSize
+	report.Entries = int((report.Size - walHeaderSize) / frameSize)
+
+	// SQLite ignores a frame that was only partially written before a crash.
+	if remainder := (report.Size - walHeaderSize) % frameSize; remainder != 0 {
+		report.finding(SeverityWarning, "WAL ends with a partial frame of %d bytes", remainder)
+	}
+
+	return nil
+}
+
+// Check that the WAL index is sorted and consistent with the WAL files.
+func (i *Inspector) inspectWALIndex(report *FileReport, siblings []string) error {
+	if report.Size%8 != 0 {
+		report.finding(SeverityError, "size %d is not a multiple of 8", report.Size)
+	}
+
+	data, err := i.fileSystem.ReadFile(report.Path)
+
+	if err != nil {
+		return err
+	}
+
+	indexed := map[string]struct{}{}
+	previous := int64(0)
+
+	for offset := 0; offset+8 <= len(data); offset += 8 {
+		version, err := utils.SafeUint64ToInt64(binary.LittleEndian.Uint64(data[offset : offset+8]))
+
+		if err != nil {
+			report.finding(SeverityError, "invalid version at offset %d: %s", offset, err)
+			break
+		}
+
+		if version == 0 {
+			break
+		}
+
+		report.Entries++
+
+		if version < previous {
+			report.finding(SeverityError, "version %d is out of order", version)
+		}
+
+		previous = version
+		name := "WAL_" + strconv.FormatInt(version, 10)
+		indexed[name] = struct{}{}
+
+		if !slices.Contains(siblings, name) {
+			report.finding(SeverityError, "WAL file %s does not exist", name)
+		}
+	}
+
+	for _, name := range siblings {
+		if _, ok := indexed[name]; !ok && walPattern.MatchString(name) {
+			report.finding(SeverityWarning, "WAL file %s is not in the index", name)
+		}
+	}
+
+	return nil
+}
+
+// Return the highest version of every range in a list of file names. Versions
+// newer than the bound are ignored unless the bound is zero.
+func latestRanges(names []string, bound int64) map[int64]int64 {
+	latest := map[int64]int64{}
+
+	for _, name := range names {
+		matches := rangePattern.FindStringSubmatch(name)
+
+		if matches == nil {
+			continue
+		}
+
+		number, err := strconv.ParseInt(matches[1], 10, 64)
+
+		if err != nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[2], 10, 64)
+
+		if err != nil {
+			continue
+		}
+
+		if bound > 0 && version > bound {
+			continue
+		}
+
+		if version > latest[number] {
+			latest[number] = version
+		}
+	}
+
+	return latest
+}
+
+// Return the timestamp of the last completed checkpoint of the branch that a
+// range directory belongs to, which is the newest entry in the snapshot logs
+// of the branch. Zero is returned when the branch has no snapshot logs.
+func (i *Inspector) lastCheckpoint(rangeDirectory string) (int64, error) {
+	branchDirectory := path.Dir(strings.TrimSuffix(rangeDirectory, "/"))
+
+	if branchDirectory == "." {
+		branchDirectory = ""
+	} else {
+		branchDirectory += "/"
+	}
+
+	snapshotDirectory := branchDirectory + "logs/snapshots/"
+
+	entries, err := i.fileSystem.ReadDir(snapshotDirectory)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	checkpointedAt := int64(0)
+
+	for _, entry := range entries {
+		if entry.IsDir() || !timestampPattern.MatchString(entry.Name()) {
+			continue
+		}
+
+		data, err := i.fileSystem.ReadFile(snapshotDirectory + entry.Name())
+
+		if err != nil {
+			return 0, err
+		}
+
+		for offset := 0; offset+snapshotLogEntrySize <= len(data); offset += snapshotLogEntrySize {
+			timestamp, err := utils.SafeUint64ToInt64(binary.LittleEndian.Uint64(data[offset : offset+8]))
+
+			if err != nil {
+				break
+			}
+
+			checkpointedAt = max(checkpointedAt, timestamp)
+		}
+	}
+
+	return checkpointedAt, nil
+}
@@ -0,0 +1,255 @@
+package inspector
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/litebase/litebase/pkg/storage"
+)
+
+/*
+The Inspector decodes the files that make up the storage of a database branch
+without a running cluster. It walks a data directory, recognizes each file by
+its name and location, verifies the internal consistency of the file and of
+the files it references, and can rebuild the indexes that are derived from
+other files.
+*/
+
+const (
+	KindPageLog      = "page_log"
+	KindPageLogIndex = "page_log_index"
+	KindRange        = "range"
+	KindRangeIndex   = "range_index"
+	KindRangeLog     = "range_log"
+	KindRollbackLog  = "rollback_log"
+	KindSnapshotLog  = "snapshot_log"
+	KindWAL          = "wal"
+	KindWALIndex     = "wal_index"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+var (
+	pageLogPattern      = regexp.MustCompile(`^PAGE_LOG_(\d+)_(\d+)$`)
+	pageLogIndexPattern = regexp.MustCompile(`^PAGE_LOG_(\d+)_(\d+)_INDEX$`)
+	rangePattern        = regexp.MustCompile(`^(\d{10})_(\d+)$`)
+	timestampPattern    = regexp.MustCompile(`^\d+$`)
+	walPattern          = regexp.MustCompile(`^WAL_(\d+)$`)
+)
+
+// A Finding is a problem discovered while inspecting a file.
+type Finding struct {
+	Message  string `json:"message"`
+	Path     string `json:"path"`
+	Severity string `json:"severity"`
+}
+
+// A FileReport describes a single file that was inspected.
+type FileReport struct {
+	Entries  int       `json:"entries"`
+	Findings []Finding `json:"findings"`
+	Kind     string    `json:"kind"`
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+}
+
+// The Report of an inspection.
+type Report struct {
+	Errors   int          `json:"errors"`
+	Files    []FileReport `json:"files"`
+	Rebuilt  []string     `json:"rebuilt"`
+	Warnings int          `json:"warnings"`
+}
+
+type Inspector struct {
+	fileSystem *storage.FileSystem
+	pageSize   int64
+}
+
+// Create a new instance of an inspector for the given file system.
+func NewInspector(fileSystem *storage.FileSystem, pageSize int64) *Inspector {
+	return &Inspector{
+		fileSystem: fileSystem,
+		pageSize:   pageSize,
+	}
+}
+
+// Inspect every recognized file in the directory and its subdirectories.
+func (i *Inspector) Inspect(directory string) (*Report, error) {
+	report := &Report{
+		Files:   []FileReport{},
+		Rebuilt: []string{},
+	}
+
+	err := i.walk(directory, func(directory string, entries []string) error {
+		for _, name := range entries {
+			kind := i.kind(directory, name)
+
+			if kind == "" {
+				continue
+			}
+
+			fileReport, err := i.inspectFile(directory, name, entries, kind)
+
+			if err != nil {
+				return err
+			}
+
+			report.add(fileReport)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Add a file report and tally its findings.
+func (r *Report) add(fileReport FileReport) {
+	for _, finding := range fileReport.Findings {
+		switch finding.Severity {
+		case SeverityError:
+			r.Errors++
+		case SeverityWarning:
+			r.Warnings++
+		}
+	}
+
+	r.Files = append(r.Files, fileReport)
+}
+
+// Inspect a single file.
+func (i *Inspector) inspectFile(directory, name string, siblings []string, kind string) (FileReport, error) {
+	path := directory + name
+
+	info, err := i.fileSystem.Stat(path)
+
+	if err != nil {
+		return FileReport{}, err
+	}
+
+	fileReport := FileReport{
+		Findings: []Finding{},
+		Kind:     kind,
+		Path:     path,
+		Size:     info.Size(),
+	}
+
+	switch kind {
+	case KindPageLog:
+		err = i.inspectPageLog(&fileReport, siblings)
+	case KindPageLogIndex:
+		err = i.inspectPageLogIndex(&fileReport, directory, name)
+	case KindRange:
+		i.inspectRange(&fileReport)
+	case KindRangeIndex:
+		err = i.inspectRangeIndex(&fileReport, directory, siblings)
+	case KindRangeLog:
+		err = i.inspectRangeLog(&fileReport)
+	case KindRollbackLog:
+		err = i.inspectRollbackLog(&fileReport)
+	case KindSnapshotLog:
+		err = i.inspectSnapshotLog(&fileReport)
+	case KindWAL:
+		err = i.inspectWAL(&fileReport)
+	case KindWALIndex:
+		err = i.inspectWALIndex(&fileReport, siblings)
+	}
+
+	return fileReport, err
+}
+
+// Determine the kind of a file from its name and the directory it is in.
+func (i *Inspector) kind(directory, name string) string {
+	switch {
+	case strings.HasSuffix(directory, "logs/rollback/") && timestampPattern.MatchString(name):
+		return KindRollbackLog
+	case strings.HasSuffix(directory, "logs/snapshots/") && timestampPattern.MatchString(name):
+		return KindSnapshotLog
+	case name == "_RANGE_INDEX":
+		return KindRangeIndex
+	case name == "_RANGE_LOG":
+		return KindRangeLog
+	case name == "WAL_INDEX":
+		return KindWALIndex
+	case rangePattern.MatchString(name):
+		return KindRange
+	case pageLogIndexPattern.MatchString(name):
+		return KindPageLogIndex
+	case pageLogPattern.MatchString(name):
+		return KindPageLog
+	case walPattern.MatchString(name):
+		return KindWAL
+	}
+
+	return ""
+}
+
+// Record a finding on a file report.
+func (r *FileReport) finding(severity, format string, args ...any) {
+	r.Findings = append(r.Findings, Finding{
+		Message:  fmt.Sprintf(format, args...),
+		Path:     r.Path,
+		Severity: severity,
+	})
+}
+
+// Walk a directory tree, calling fn with the sorted names of the files in each
+// directory.
+func (i *Inspector) walk(directory string, fn func(directory string, files []string) error) error {
+	directory = strings.Trim(directory, "/")
+
+	if directory != "" {
+		directory += "/"
+	}
+
+	entries, err := i.fileSystem.ReadDir(directory)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	files := []string{}
+	directories := []string{}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			directories = append(directories, entry.Name())
+		} else {
+			files = append(files, entry.Name())
+		}
+	}
+
+	slices.Sort(files)
+	slices.Sort(directories)
+
+	err = fn(directory, files)
+
+	if err != nil {
+		return err
+	}
+
+	for _, name := range directories {
+		err = i.walk(directory+name, fn)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
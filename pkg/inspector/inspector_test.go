@@ -0,0 +1,296 @@
+package inspector_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/litebase/litebase/pkg/backups"
+	"github.com/litebase/litebase/pkg/inspector"
+	"github.com/litebase/litebase/pkg/storage"
+)
+
+func newTestFileSystem(t *testing.T) *storage.FileSystem {
+	fileSystem := storage.NewFileSystem(storage.NewLocalFileSystemDriver(t.TempDir()))
+
+	for _, directory := range []string{"db/main/logs/page", "db/main/logs/rollback", "db/main/logs/snapshots", "db/main/logs/wal", "db/main/ranges"} {
+		if err := fileSystem.MkdirAll(directory, 0750); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return fileSystem
+}
+
+func writeFile(t *testing.T, fileSystem *storage.FileSystem, path string, data []byte) {
+	if err := fileSystem.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func uint64s(values ...uint64) []byte {
+	data := []byte{}
+
+	for _, value := range values {
+		data = binary.LittleEndian.AppendUint64(data, value)
+	}
+
+	return data
+}
+
+func findFile(report *inspector.Report, path string) *inspector.FileReport {
+	for i := range report.Files {
+		if report.Files[i].Path == path {
+			return &report.Files[i]
+		}
+	}
+
+	return nil
+}
+
+func TestInspector(t *testing.T) {
+	t.Run("ReportsNoFindingsForConsistentFiles", func(t *testing.T) {
+		fileSystem := newTestFileSystem(t)
+
+		writeFile(t, fileSystem, "db/main/ranges/0000000001_100", make([]byte, 4096*2))
+		writeFile(t, fileSystem, "db/main/ranges/_RANGE_INDEX", uint64s(100))
+		writeFile(t, fileSystem, "db/main/ranges/_RANGE_LOG", []byte("0000000001_50\n"))
+		writeFile(t, fileSystem, "db/main/logs/wal/WAL_10", []byte{})
+		writeFile(t, fileSystem, "db/main/logs/wal/WAL_20", []byte{})
+		writeFile(t, fileSystem, "db/main/logs/wal/WAL_INDEX", uint64s(10, 20))
+		writeFile(t, fileSystem, "db/main/logs/snapshots/1", append(uint64s(1), 0, 0, 0, 0))
+
+		entry := storage.NewPageLogIndexEntry(1, 10, 0, false)
+		writeFile(t, fileSystem, "db/main/logs/page/PAGE_LOG_1_10", make([]byte, 4096))
+		writeFile(t, fileSystem, "db/main/logs/page/PAGE_LOG_1_10_INDEX", entry.Encode())
+
+		report, err := inspector.NewInspector(fileSystem, 4096).Inspect("db")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if report.Errors != 0 || report.Warnings != 0 {
+			t.Fatalf("expected no findings, got %+v", report.Files)
+		}
+
+		if len(report.Files) != 9 {
+			t.Errorf("expected 9 files, got %d", len(report.Files))
+		}
+
+		if file := findFile(report, "db/main/ranges/0000000001_100"); file == nil || file.Entries != 2 {
+			t.Errorf("expected the range to hold 2 pages, got %+v", file)
+		}
+	})
+
+	t.Run("ReportsInconsistentIndexes", func(t *testing.T) {
+		fileSystem := newTestFileSystem(t)
+
+		writeFile(t, fileSystem, "db/main/ranges/0000000001_100", make([]byte, 4096))
+		writeFile(t, fileSystem, "db/main/ranges/0000000001_200", make([]byte, 4096))
+		writeFile(t, fileSystem, "db/main/ranges/0000000002_100", make([]byte, 100))
+		writeFile(t, fileSystem, "db/main/ranges/_RANGE_INDEX", uint64s(100, 0, 300))
+		writeFile(t, fileSystem, "db/main/logs/wal/WAL_10", []byte{})
+		writeFile(t, fileSystem, "db/main/logs/wal/WAL_INDEX", uint64s(20))
+
+		entry := storage.NewPageLogIndexEntry(5000, 10, 4096, false)
+		writeFile(t, fileSystem, "db/main/logs/page/PAGE_LOG_1_10", make([]byte, 4096))
+		writeFile(t, fileSystem, "db/main/logs/page/PAGE_LOG_1_10_INDEX", entry.Encode())
+
+		report, err := inspector.NewInspector(fileSystem, 4096).Inspect("db")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := map[string][]string{
+			"db/main/ranges/_RANGE_INDEX":           {inspector.SeverityWarning, inspector.SeverityError},
+			"db/main/ranges/0000000002_100":         {inspector.SeverityError},
+			"db/main/logs/wal/WAL_INDEX":            {inspector.SeverityError, inspector.SeverityWarning},
+			"db/main/logs/page/PAGE_LOG_1_10_INDEX": {inspector.SeverityError, inspector.SeverityError},
+		}
+
+		for path, severities := range expected {
+			file := findFile(report, path)
+
+			if file == nil {
+				t.Fatalf("expected %s to be inspected", path)
+			}
+
+			if len(file.Findings) != len(severities) {
+				t.Fatalf("expected %d findings for %s, got %+v", len(severities), path, file.Findings)
+			}
+
+			for i, severity := range severities {
+				if file.Findings[i].Severity != severity {
+					t.Errorf("expected finding %d of %s to be a %s, got %+v", i, path, severity, file.Findings[i])
+				}
+			}
+		}
+	})
+
+	t.Run("DecodesWALFiles", func(t *testing.T) {
+		fileSystem := newTestFileSystem(t)
+
+		header := binary.BigEndian.AppendUint32(nil, 0x377f0682)
+		header = binary.BigEndian.AppendUint32(header, 3007000)
+		header = binary.BigEndian.AppendUint32(header, 4096)
+		header = append(header, make([]byte, 20)...)
+
+		wal := append(append([]byte{}, header...), make([]byte, 24+4096)...)
+		writeFile(t, fileSystem, "db/main/logs/wal/WAL_10", wal)
+		writeFile(t, fileSystem, "db/main/logs/wal/WAL_20", append(wal, make([]byte, 100)...))
+		writeFile(t, fileSystem, "db/main/logs/wal/WAL_30", make([]byte, 32))
+		writeFile(t, fileSystem, "db/main/logs/wal/WAL_INDEX", uint64s(10, 20, 30))
+
+		report, err := inspector.NewInspector(fileSystem, 4096).Inspect("db")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if file := findFile(report, "db/main/logs/wal/WAL_10"); file == nil || file.Entries != 1 || len(file.Findings) != 0 {
+			t.Errorf("expected a valid WAL with 1 frame, got %+v", file)
+		}
+
+		if file := findFile(report, "db/main/logs/wal/WAL_20"); file == nil || len(file.Findings) != 1 || file.Findings[0].Severity != inspector.SeverityWarning {
+			t.Errorf("expected a WAL with a partial frame, got %+v", file)
+		}
+
+		if file := findFile(report, "db/main/logs/wal/WAL_30"); file == nil || len(file.Findings) != 1 || file.Findings[0].Severity != inspector.SeverityError {
+			t.Errorf("expected a WAL with an invalid header, got %+v", file)
+		}
+	})
+
+	t.Run("DecodesRollbackLogs", func(t *testing.T) {
+		fileSystem := newTestFileSystem(t)
+
+		entry, err := backups.NewRollbackLogEntry(1, 100, make([]byte, 4096)).Serialize(bytes.NewBuffer(nil))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		frame, err := backups.RollbackLogFrame{
+			Committed: 1,
+			Offset:    0,
+			Size:      int64(backups.RollbackFrameHeaderSize + len(entry)),
+			Timestamp: 100,
+		}.Serialize()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		writeFile(t, fileSystem, "db/main/logs/rollback/100", append(frame, entry...))
+
+		corrupted := append(frame, entry...)
+		corrupted[len(corrupted)-1] ^= 0xff
+		writeFile(t, fileSystem, "db/main/logs/rollback/200", corrupted)
+
+		report, err := inspector.NewInspector(fileSystem, 4096).Inspect("db")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if file := findFile(report, "db/main/logs/rollback/100"); file == nil || file.Entries != 1 || len(file.Findings) != 0 {
+			t.Errorf("expected a valid rollback log, got %+v", file)
+		}
+
+		if file := findFile(report, "db/main/logs/rollback/200"); file == nil || len(file.Findings) != 1 {
+			t.Errorf("expected a corrupted rollback log, got %+v", file)
+		}
+	})
+
+	t.Run("RebuildsIndexes", func(t *testing.T) {
+		fileSystem := newTestFileSystem(t)
+
+		writeFile(t, fileSystem, "db/main/ranges/0000000001_100", make([]byte, 4096))
+		writeFile(t, fileSystem, "db/main/ranges/0000000001_200", make([]byte, 4096))
+		writeFile(t, fileSystem, "db/main/ranges/0000000003_100", make([]byte, 4096))
+		writeFile(t, fileSystem, "db/main/logs/wal/WAL_20", []byte{})
+		writeFile(t, fileSystem, "db/main/logs/wal/WAL_10", []byte{})
+
+		rebuilt, err := inspector.NewInspector(fileSystem, 4096).Rebuild("db")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(rebuilt) != 2 {
+			t.Fatalf("expected 2 rebuilt indexes, got %v", rebuilt)
+		}
+
+		rangeIndex, err := fileSystem.ReadFile("db/main/ranges/_RANGE_INDEX")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(rangeIndex, uint64s(200, 0, 100)) {
+			t.Errorf("unexpected range index %v", rangeIndex)
+		}
+
+		walIndex, err := fileSystem.ReadFile("db/main/logs/wal/WAL_INDEX")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(walIndex, uint64s(10, 20)) {
+			t.Errorf("unexpected WAL index %v", walIndex)
+		}
+
+		report, err := inspector.NewInspector(fileSystem, 4096).Inspect("db")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if report.Errors != 0 || report.Warnings != 0 {
+			t.Errorf("expected no findings after rebuilding, got %+v", report.Files)
+		}
+	})
+	t.Run("RebuildsRangeIndexAtLastCheckpoint", func(t *testing.T) {
+		fileSystem := newTestFileSystem(t)
+
+		writeFile(t, fileSystem, "db/main/ranges/0000000001_100", make([]byte, 4096))
+		writeFile(t, fileSystem, "db/main/ranges/0000000001_200", make([]byte, 4096))
+		writeFile(t, fileSystem, "db/main/ranges/0000000003_100", make([]byte, 4096))
+		writeFile(t, fileSystem, "db/main/ranges/_RANGE_INDEX", uint64s(100))
+
+		// The last checkpoint completed before version 200 was written
+		writeFile(t, fileSystem, "db/main/logs/snapshots/1", append(uint64s(150), 0, 0, 0, 0))
+
+		_, err := inspector.NewInspector(fileSystem, 4096).Rebuild("db")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rangeIndex, err := fileSystem.ReadFile("db/main/ranges/_RANGE_INDEX")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(rangeIndex, uint64s(100, 0, 100)) {
+			t.Errorf("unexpected range index %v", rangeIndex)
+		}
+
+		backup, err := fileSystem.ReadFile("db/main/ranges/_RANGE_INDEX.bak")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(backup, uint64s(100)) {
+			t.Errorf("expected the previous range index to be kept, got %v", backup)
+		}
+
+		if _, err := fileSystem.Stat("db/main/ranges/_RANGE_INDEX.tmp"); err == nil {
+			t.Error("expected the temporary range index to be renamed")
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package inspector
+
+import (
+	"encoding/binary"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/litebase/litebase/internal/utils"
+)
+
+// Rebuild the indexes that can be derived from other files in the directory
+// and its subdirectories. A range index is rebuilt from the latest version of
+// every range file that was written by a completed checkpoint and a WAL index
+// is rebuilt from the WAL files. The previous version of each index is kept
+// with a .bak suffix. The paths of the rebuilt indexes are returned.
+func (i *Inspector) Rebuild(directory string) ([]string, error) {
+	rebuilt := []string{}
+
+	err := i.walk(directory, func(directory string, files []string) error {
+		if path, ok, err := i.rebuildRangeIndex(directory, files); err != nil {
+			return err
+		} else if ok {
+			rebuilt = append(rebuilt, path)
+		}
+
+		if path, ok, err := i.rebuildWALIndex(directory, files); err != nil {
+			return err
+		} else if ok {
+			rebuilt = append(rebuilt, path)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rebuilt, nil
+}
+
+// Rebuild the range index of a directory that contains range files.
+func (i *Inspector) rebuildRangeIndex(directory string, files []string) (string, bool, error) {
+	if !slices.ContainsFunc(files, rangePattern.MatchString) {
+		return "", false, nil
+	}
+
+	checkpointedAt, err := i.lastCheckpoint(directory)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	latest := latestRanges(files, checkpointedAt)
+
+	if len(latest) == 0 {
+		return "", false, nil
+	}
+
+	maxNumber := int64(0)
+
+	for number := range latest {
+		maxNumber = max(maxNumber, number)
+	}
+
+	data := make([]byte, maxNumber*8)
+
+	for number, version := range latest {
+		uint64Version, err := utils.SafeInt64ToUint64(version)
+
+		if err != nil {
+			return "", false, err
+		}
+
+		binary.LittleEndian.PutUint64(data[(number-1)*8:number*8], uint64Version)
+	}
+
+	path := directory + "_RANGE_INDEX"
+
+	return path, true, i.replace(path, data)
+}
+
+// Rebuild the WAL index of a directory that contains WAL files.
+func (i *Inspector) rebuildWALIndex(directory string, files []string) (string, bool, error) {
+	if !strings.HasSuffix(directory, "logs/wal/") {
+		return "", false, nil
+	}
+
+	versions := []int64{}
+
+	for _, name := range files {
+		matches := walPattern.FindStringSubmatch(name)
+
+		if matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+
+		if err != nil {
+			continue
+		}
+
+		versions = append(versions, version)
+	}
+
+	if len(versions) == 0 {
+		return "", false, nil
+	}
+
+	slices.Sort(versions)
+
+	data := make([]byte, 0, len(versions)*8)
+
+	for _, version := range versions {
+		uint64Version, err := utils.SafeInt64ToUint64(version)
+
+		if err != nil {
+			return "", false, err
+		}
+
+		data = binary.LittleEndian.AppendUint64(data, uint64Version)
+	}
+
+	path := directory + "WAL_INDEX"
+
+	return path, true, i.replace(path, data)
+}
+
+// Replace the contents of an index. The new index is written to a temporary
+// file that is renamed into place once it has been written, so the index is
+// never left partially written. The previous index is kept with a .bak suffix.
+func (i *Inspector) replace(path string, data []byte) error {
+	previous, err := i.fileSystem.ReadFile(path)
+
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err == nil {
+		if err := i.fileSystem.WriteFile(path+".bak", previous, 0600); err != nil {
+			return err
+		}
+	}
+
+	if err := i.fileSystem.WriteFile(path+".tmp", data, 0600); err != nil {
+		return err
+	}
+
+	return i.fileSystem.Rename(path+".tmp", path)
+}
@@ -0,0 +1,937 @@
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	internalStorage "github.com/litebase/litebase/internal/storage"
+	"github.com/litebase/litebase/pkg/auth"
+	"github.com/litebase/litebase/pkg/cluster"
+	"github.com/litebase/litebase/pkg/storage"
+)
+
+/*
+The audit log is an append-only record of administrative and data-changing
+operations. Each entry contains the hash of the entry that was written before
+it by the same node, which forms a chain that is broken when an entry is
+modified, removed, or reordered. Hashes are HMACs keyed with a secret derived
+from the encryption key of the cluster, so a chain can not be rebuilt without
+it. Entries are stored in the tiered file system as JSON lines, in a file for
+each node and day:
+
+	_audit/<day timestamp>/AUDIT_LOG_<node id>
+
+The last entry of each chain is also recorded in a signed head, which is
+stored apart from the entries so that entries removed from the end of a chain
+are detected:
+
+	_audit/heads/<node id>
+*/
+
+const (
+	AuditActionAccessKeyCreate = "access-key:create"
+	AuditActionAccessKeyDelete = "access-key:delete"
+	AuditActionAccessKeyRotate = "access-key:rotate"
+	AuditActionAccessKeyUpdate = "access-key:update"
+	AuditActionBackupCreate    = "database:backup:create"
+	AuditActionBackupDelete    = "database:backup:delete"
+	AuditActionBranchCreate    = "database:branch:create"
+	AuditActionBranchDelete    = "database:branch:delete"
+	AuditActionDatabaseCreate  = "database:create"
+	AuditActionDatabaseDelete  = "database:delete"
+	AuditActionDatabaseRestore = "database:restore"
+	AuditActionDatabaseWrite   = "database:write"
+	AuditActionKeyActivate     = "key:activate"
+	AuditActionKeyCreate       = "key:create"
+	AuditActionRoleCreate      = "role:create"
+	AuditActionRoleDelete      = "role:delete"
+	AuditActionRoleUpdate      = "role:update"
+	AuditActionSSOLogin        = "sso:login"
+	AuditActionUserCreate      = "user:create"
+	AuditActionUserDelete      = "user:delete"
+	AuditActionUserUpdate      = "user:update"
+
+	AuditActorAccessKey = "access-key"
+	AuditActorSSOUser   = "sso-user"
+	AuditActorUser      = "user"
+)
+
+const (
+	// The range read when a filter does not have a start.
+	AuditLogDefaultRange = 24 * time.Hour
+
+	// The number of entries read when a filter does not have a limit.
+	AuditLogDefaultLimit = 100
+
+	// The maximum number of entries read at once.
+	AuditLogMaxLimit = 1000
+
+	// The maximum number of entries buffered before they are written.
+	AuditLogMaxPending = 10000
+
+	// The maximum range that can be read at once.
+	AuditLogMaxRange = 31 * 24 * time.Hour
+)
+
+// The interval after which buffered entries are written again when writing
+// them failed.
+var AuditLogRetryInterval = time.Second
+
+var (
+	ErrAuditLogBufferFull = errors.New("the audit log buffer is full")
+	ErrAuditLogRange      = fmt.Errorf("the audit log range must end after it starts and not exceed %s", AuditLogMaxRange)
+	ErrAuditLogTampered   = errors.New("the audit log has been tampered with")
+)
+
+type AuditLog struct {
+	cluster       *cluster.Cluster
+	done          chan struct{}
+	file          internalStorage.File
+	fileTimestamp int64
+	hash          string
+	head          *auditLogHead
+	loaded        bool
+	mutex         sync.Mutex
+	pending       []AuditLogEntry
+	secrets       sync.Map
+	sequence      uint64
+	signal        chan struct{}
+	tieredFS      *storage.FileSystem
+	writeMutex    sync.Mutex
+}
+
+// The head of the chain of audit log entries written by a node.
+type AuditLogChain struct {
+	Entries  int    `json:"entries"`
+	Hash     string `json:"hash"`
+	NodeID   string `json:"node_id"`
+	Sequence uint64 `json:"sequence"`
+}
+
+type AuditLogEntry struct {
+	Sequence     uint64            `json:"sequence"`
+	Timestamp    int64             `json:"timestamp"`
+	NodeID       string            `json:"node_id"`
+	ActorType    string            `json:"actor_type"`
+	Actor        string            `json:"actor"`
+	Action       string            `json:"action"`
+	Resource     string            `json:"resource"`
+	Details      map[string]string `json:"details,omitempty"`
+	KeyHash      string            `json:"key_hash"`
+	PreviousHash string            `json:"previous_hash"`
+	Hash         string            `json:"hash"`
+}
+
+/*
+Filters applied when reading the audit log. Empty action and actor filters
+match every entry. The range defaults to the AuditLogDefaultRange before the
+end, which defaults to now, and entries are returned in pages of Limit entries
+starting at Offset.
+*/
+type AuditLogFilter struct {
+	Action string
+	Actor  string
+	End    int64
+	Limit  int
+	Offset int
+	Start  int64
+}
+
+// The signed head of the chain of entries written by a node.
+type auditLogHead struct {
+	Hash      string `json:"hash"`
+	KeyHash   string `json:"key_hash"`
+	NodeID    string `json:"node_id"`
+	Sequence  uint64 `json:"sequence"`
+	Signature string `json:"signature"`
+}
+
+func NewAuditLog(cluster *cluster.Cluster) *AuditLog {
+	return &AuditLog{
+		cluster:  cluster,
+		signal:   make(chan struct{}, 1),
+		tieredFS: cluster.TieredFS(),
+	}
+}
+
+// Return the hash of the entry, which covers every field but the hash itself.
+func (e AuditLogEntry) ComputeHash(secret []byte) string {
+	e.Hash = ""
+
+	data, err := json.Marshal(e)
+
+	if err != nil {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(data)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Return the signature of the head.
+func (h auditLogHead) sign(secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%s:%d:%s", h.NodeID, h.Sequence, h.Hash)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Append an entry to the audit log without waiting for it to be written. The
+// entry is chained to the last entry written by this node and buffered until
+// the writer of the audit log syncs it to storage.
+func (a *AuditLog) Append(entry AuditLogEntry) (AuditLogEntry, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	entry, err := a.chain(entry)
+
+	if err != nil {
+		return AuditLogEntry{}, err
+	}
+
+	if a.done == nil {
+		a.done = make(chan struct{})
+
+		go a.run(a.done)
+	}
+
+	select {
+	case a.signal <- struct{}{}:
+	default:
+	}
+
+	return entry, nil
+}
+
+// Chain an entry to the last entry appended by this node and buffer it. The
+// mutex must be held by the caller.
+func (a *AuditLog) chain(entry AuditLogEntry) (AuditLogEntry, error) {
+	if !a.loaded {
+		if err := a.loadHead(); err != nil {
+			return AuditLogEntry{}, err
+		}
+	}
+
+	if len(a.pending) >= AuditLogMaxPending {
+		return AuditLogEntry{}, ErrAuditLogBufferFull
+	}
+
+	keyHash := auth.EncryptionKeyHash(a.cluster.Config.EncryptionKey)
+	secret, err := a.secret(keyHash)
+
+	if err != nil {
+		return AuditLogEntry{}, err
+	}
+
+	entry.Sequence = a.sequence + 1
+	entry.Timestamp = time.Now().UTC().Unix()
+	entry.NodeID = a.cluster.Node().ID
+	entry.KeyHash = keyHash
+	entry.PreviousHash = a.hash
+	entry.Hash = entry.ComputeHash(secret)
+
+	a.pending = append(a.pending, entry)
+	a.sequence = entry.Sequence
+	a.hash = entry.Hash
+
+	return entry, nil
+}
+
+// Write the buffered entries, stop the writer, and close the file of the
+// audit log.
+func (a *AuditLog) Close() error {
+	a.mutex.Lock()
+
+	if a.done != nil {
+		close(a.done)
+		a.done = nil
+	}
+
+	a.mutex.Unlock()
+
+	err := a.flush()
+
+	a.writeMutex.Lock()
+	defer a.writeMutex.Unlock()
+
+	if a.file == nil {
+		return err
+	}
+
+	closeErr := a.file.Close()
+	a.file = nil
+
+	return errors.Join(err, closeErr)
+}
+
+func (a *AuditLog) directory(timestamp int64) string {
+	return fmt.Sprintf("_audit/%d", timestamp)
+}
+
+// Return the timestamps of the day directories of the audit log in ascending
+// order.
+func (a *AuditLog) days() ([]int64, error) {
+	entries, err := a.tieredFS.ReadDir("_audit")
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	days := make([]int64, 0, len(entries))
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		day, err := strconv.ParseInt(entry.Name(), 10, 64)
+
+		if err != nil {
+			continue
+		}
+
+		days = append(days, day)
+	}
+
+	slices.Sort(days)
+
+	return days, nil
+}
+
+// Return the paths of the files of every node in the day directory.
+func (a *AuditLog) dayFiles(day int64) ([]string, error) {
+	files, err := a.tieredFS.ReadDir(a.directory(day))
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasPrefix(file.Name(), "AUDIT_LOG_") {
+			continue
+		}
+
+		paths = append(paths, fmt.Sprintf("%s/%s", a.directory(day), file.Name()))
+	}
+
+	return paths, nil
+}
+
+// Return the last entry of an audit log file by reading the file backwards
+// from its end until the start of the last line is found.
+func (a *AuditLog) lastEntry(path string) (AuditLogEntry, bool, error) {
+	file, err := a.tieredFS.Open(path)
+
+	if err != nil {
+		return AuditLogEntry{}, false, err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+
+	if err != nil {
+		return AuditLogEntry{}, false, err
+	}
+
+	size := info.Size()
+
+	for block := int64(4096); ; block *= 2 {
+		offset := max(size-block, 0)
+		data := make([]byte, size-offset)
+
+		n, err := file.ReadAt(data, offset)
+
+		if err != nil && err != io.EOF {
+			return AuditLogEntry{}, false, err
+		}
+
+		data = bytes.TrimRight(data[:n], " \t\r\n")
+
+		// The start of the last line is only known once a line break before
+		// it was read, or the start of the file was reached.
+		i := bytes.LastIndexByte(data, '\n')
+
+		if i < 0 && offset > 0 {
+			continue
+		}
+
+		if len(data) == 0 {
+			return AuditLogEntry{}, false, nil
+		}
+
+		var entry AuditLogEntry
+
+		if err := json.Unmarshal(data[i+1:], &entry); err != nil {
+			return AuditLogEntry{}, false, fmt.Errorf("%w: %s contains an invalid entry", ErrAuditLogTampered, path)
+		}
+
+		return entry, true, nil
+	}
+}
+
+// Restore the sequence and hash of the last entry written by this node so that
+// new entries continue its chain.
+func (a *AuditLog) loadHead() error {
+	days, err := a.days()
+
+	if err != nil {
+		return err
+	}
+
+	for i := len(days) - 1; i >= 0; i-- {
+		last, ok, err := a.lastEntry(fmt.Sprintf("%s/AUDIT_LOG_%s", a.directory(days[i]), a.cluster.Node().ID))
+
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
+		a.sequence = last.Sequence
+		a.hash = last.Hash
+
+		break
+	}
+
+	a.loaded = true
+
+	return nil
+}
+
+// Return a page of the audit log entries that match the filter, ordered by
+// timestamp, and the offset of the next page, which is zero when there are no
+// more entries.
+func (a *AuditLog) Read(filter AuditLogFilter) ([]AuditLogEntry, int, error) {
+	if filter.End == 0 {
+		filter.End = time.Now().UTC().Unix()
+	}
+
+	if filter.Start == 0 {
+		filter.Start = filter.End - int64(AuditLogDefaultRange/time.Second)
+	}
+
+	if filter.Start > filter.End || filter.End-filter.Start > int64(AuditLogMaxRange/time.Second) {
+		return nil, 0, ErrAuditLogRange
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = AuditLogDefaultLimit
+	}
+
+	filter.Limit = min(filter.Limit, AuditLogMaxLimit)
+	filter.Offset = max(filter.Offset, 0)
+
+	if err := a.flush(); err != nil {
+		return nil, 0, err
+	}
+
+	days, err := a.days()
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Entries are stored in the file of the day they were appended on, so the
+	// days are read in order until the page and the entry after it, which
+	// tells if there is a next page, have been found.
+	matching := []AuditLogEntry{}
+
+	for _, day := range days {
+		if day+86400 <= filter.Start || day > filter.End {
+			continue
+		}
+
+		if len(matching) > filter.Offset+filter.Limit {
+			break
+		}
+
+		paths, err := a.dayFiles(day)
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		dayEntries := []AuditLogEntry{}
+
+		for _, path := range paths {
+			err := a.scanFile(path, func(entry AuditLogEntry) bool {
+				if entry.Timestamp >= filter.Start &&
+					entry.Timestamp <= filter.End &&
+					(filter.Action == "" || entry.Action == filter.Action) &&
+					(filter.Actor == "" || entry.Actor == filter.Actor) {
+					dayEntries = append(dayEntries, entry)
+				}
+
+				return true
+			})
+
+			if err != nil && !os.IsNotExist(err) {
+				return nil, 0, err
+			}
+		}
+
+		slices.SortStableFunc(dayEntries, func(a, b AuditLogEntry) int {
+			if a.Timestamp != b.Timestamp {
+				return cmp.Compare(a.Timestamp, b.Timestamp)
+			}
+
+			if a.NodeID != b.NodeID {
+				return strings.Compare(a.NodeID, b.NodeID)
+			}
+
+			return cmp.Compare(a.Sequence, b.Sequence)
+		})
+
+		matching = append(matching, dayEntries...)
+	}
+
+	if filter.Offset >= len(matching) {
+		return []AuditLogEntry{}, 0, nil
+	}
+
+	next := filter.Offset + filter.Limit
+
+	if next >= len(matching) {
+		return matching[filter.Offset:], 0, nil
+	}
+
+	return matching[filter.Offset:next], next, nil
+}
+
+// Stream the entries of an audit log file to fn, until fn returns false.
+func (a *AuditLog) scanFile(path string, fn func(entry AuditLogEntry) bool) error {
+	file, err := a.tieredFS.Open(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry AuditLogEntry
+
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("%w: %s contains an invalid entry", ErrAuditLogTampered, path)
+		}
+
+		if !fn(entry) {
+			return nil
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Read the signed heads of the chains of every node.
+func (a *AuditLog) readHeads() (map[string]auditLogHead, error) {
+	files, err := a.tieredFS.ReadDir("_audit/heads")
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]auditLogHead{}, nil
+		}
+
+		return nil, err
+	}
+
+	heads := make(map[string]auditLogHead, len(files))
+
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		data, err := a.tieredFS.ReadFile(fmt.Sprintf("_audit/heads/%s", file.Name()))
+
+		if err != nil {
+			return nil, err
+		}
+
+		var head auditLogHead
+
+		if err := json.Unmarshal(data, &head); err != nil || head.NodeID != file.Name() {
+			return nil, fmt.Errorf("%w: the head of node %s is invalid", ErrAuditLogTampered, file.Name())
+		}
+
+		heads[head.NodeID] = head
+	}
+
+	return heads, nil
+}
+
+// Write the buffered entries until the audit log is closed. Entries that are
+// buffered while a batch is written are written with the next batch, and
+// entries that could not be written are retried.
+func (a *AuditLog) run(done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-a.signal:
+			if err := a.flush(); err != nil {
+				slog.Error("Error writing audit log entries", "error", err)
+
+				select {
+				case <-done:
+					return
+				case <-time.After(AuditLogRetryInterval):
+				}
+
+				select {
+				case a.signal <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}
+}
+
+// Return the secret the hashes of entries are keyed with for the encryption
+// key with the hash. Entries written before the encryption key was rotated
+// are verified with the key they were written with.
+func (a *AuditLog) secret(keyHash string) ([]byte, error) {
+	if secret, ok := a.secrets.Load(keyHash); ok {
+		return secret.([]byte), nil
+	}
+
+	encryptionKey := a.cluster.Config.EncryptionKey
+
+	if auth.EncryptionKeyHash(encryptionKey) != keyHash {
+		encryptionKey = auth.FindKey(a.cluster.ObjectFS(), keyHash)
+	}
+
+	if encryptionKey == "" {
+		return nil, fmt.Errorf("%w: the encryption key of an entry is unknown", ErrAuditLogTampered)
+	}
+
+	sum := sha256.Sum256([]byte(encryptionKey + ":audit"))
+	a.secrets.Store(keyHash, sum[:])
+
+	return sum[:], nil
+}
+
+// Verify the chain of entries written by each node. An error wrapping
+// ErrAuditLogTampered is returned at the first entry that does not match its
+// hash or does not follow the entry before it, and when a chain does not
+// reach its signed head.
+func (a *AuditLog) Verify() ([]AuditLogChain, error) {
+	if err := a.flush(); err != nil {
+		return nil, err
+	}
+
+	heads, err := a.readHeads()
+
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := a.days()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The entries of each node are appended in order of their sequence, so
+	// the chains are verified while the files are read day by day.
+	nodes := map[string]*AuditLogChain{}
+	reachedHead := map[string]bool{}
+
+	for _, day := range days {
+		paths, err := a.dayFiles(day)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, path := range paths {
+			var verifyErr error
+
+			err := a.scanFile(path, func(entry AuditLogEntry) bool {
+				node, ok := nodes[entry.NodeID]
+
+				if !ok {
+					node = &AuditLogChain{NodeID: entry.NodeID}
+					nodes[entry.NodeID] = node
+				}
+
+				secret, err := a.secret(entry.KeyHash)
+
+				if err != nil {
+					verifyErr = fmt.Errorf("%w: node %s at sequence %d", err, entry.NodeID, entry.Sequence)
+					return false
+				}
+
+				if entry.Sequence != uint64(node.Entries+1) ||
+					entry.PreviousHash != node.Hash ||
+					entry.Hash != entry.ComputeHash(secret) {
+					verifyErr = fmt.Errorf("%w: node %s at sequence %d", ErrAuditLogTampered, entry.NodeID, entry.Sequence)
+					return false
+				}
+
+				node.Entries++
+				node.Hash = entry.Hash
+				node.Sequence = entry.Sequence
+
+				if head, ok := heads[entry.NodeID]; ok && head.Sequence == entry.Sequence && head.Hash == entry.Hash {
+					reachedHead[entry.NodeID] = true
+				}
+
+				return true
+			})
+
+			if err == nil {
+				err = verifyErr
+			}
+
+			if err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+		}
+	}
+
+	for nodeId := range heads {
+		if _, ok := nodes[nodeId]; !ok {
+			return nil, fmt.Errorf("%w: the entries of node %s have been removed", ErrAuditLogTampered, nodeId)
+		}
+	}
+
+	chains := make([]AuditLogChain, 0, len(nodes))
+
+	for nodeId, node := range nodes {
+		// The head is written after the entries, so the chain may end after
+		// its head but never before it.
+		head, ok := heads[nodeId]
+
+		if !ok {
+			return nil, fmt.Errorf("%w: the head of node %s is missing", ErrAuditLogTampered, nodeId)
+		}
+
+		secret, err := a.secret(head.KeyHash)
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: the head of node %s", err, nodeId)
+		}
+
+		if !hmac.Equal([]byte(head.Signature), []byte(head.sign(secret))) || !reachedHead[nodeId] {
+			return nil, fmt.Errorf("%w: the chain of node %s does not reach its head at sequence %d", ErrAuditLogTampered, nodeId, head.Sequence)
+		}
+
+		chains = append(chains, *node)
+	}
+
+	slices.SortFunc(chains, func(a, b AuditLogChain) int {
+		return strings.Compare(a.NodeID, b.NodeID)
+	})
+
+	return chains, nil
+}
+
+// Append an entry to the audit log, chaining it to the last entry written by
+// this node. The entry is synced to storage before returning.
+func (a *AuditLog) Write(entry AuditLogEntry) (AuditLogEntry, error) {
+	a.mutex.Lock()
+	entry, err := a.chain(entry)
+	a.mutex.Unlock()
+
+	if err != nil {
+		return AuditLogEntry{}, err
+	}
+
+	if err := a.flush(); err != nil {
+		return AuditLogEntry{}, err
+	}
+
+	return entry, nil
+}
+
+/*
+Write the buffered entries to storage and sync them, then update the signed
+head of the chain of this node. Entries that could not be written are
+buffered again, so they are written in order by the next flush. A flush waits
+for the flush in progress, so once it returns without an error every entry
+that was buffered before it has been written.
+*/
+func (a *AuditLog) flush() error {
+	a.writeMutex.Lock()
+	defer a.writeMutex.Unlock()
+
+	a.mutex.Lock()
+	entries := a.pending
+	a.pending = nil
+	a.mutex.Unlock()
+
+	written, err := a.writeEntries(entries)
+
+	if written < len(entries) {
+		a.mutex.Lock()
+		a.pending = append(entries[written:], a.pending...)
+		a.mutex.Unlock()
+	}
+
+	if written > 0 {
+		last := entries[written-1]
+
+		a.head = &auditLogHead{
+			Hash:     last.Hash,
+			KeyHash:  last.KeyHash,
+			NodeID:   last.NodeID,
+			Sequence: last.Sequence,
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if a.head == nil {
+		return nil
+	}
+
+	if err := a.writeHead(*a.head); err != nil {
+		return err
+	}
+
+	a.head = nil
+
+	return nil
+}
+
+// Write entries to the files of the days they were appended on and return
+// the number of entries that were written. The write mutex must be held by
+// the caller.
+func (a *AuditLog) writeEntries(entries []AuditLogEntry) (int, error) {
+	var (
+		data    []byte
+		written int
+	)
+
+	for i, entry := range entries {
+		line, err := json.Marshal(entry)
+
+		if err != nil {
+			return written, err
+		}
+
+		data = append(append(data, line...), '\n')
+
+		// Write the entries of a day once the next entry was appended on a
+		// later day, or all entries have been buffered.
+		if i < len(entries)-1 && auditLogDay(entries[i+1].Timestamp) == auditLogDay(entry.Timestamp) {
+			continue
+		}
+
+		if err := a.writeFile(auditLogDay(entry.Timestamp), data); err != nil {
+			return written, err
+		}
+
+		data = data[:0]
+		written = i + 1
+	}
+
+	return written, nil
+}
+
+// Sign and store the head of the chain of this node. The write mutex must be
+// held by the caller.
+func (a *AuditLog) writeHead(head auditLogHead) error {
+	secret, err := a.secret(head.KeyHash)
+
+	if err != nil {
+		return err
+	}
+
+	head.Signature = head.sign(secret)
+
+	headData, err := json.Marshal(head)
+
+	if err != nil {
+		return err
+	}
+
+	if err := a.tieredFS.MkdirAll("_audit/heads", 0750); err != nil {
+		return err
+	}
+
+	return a.tieredFS.WriteFile(fmt.Sprintf("_audit/heads/%s", head.NodeID), headData, 0600)
+}
+
+// Append data to the file of the day and sync it. The write mutex must be
+// held by the caller.
+func (a *AuditLog) writeFile(day int64, data []byte) error {
+	if a.file != nil && a.fileTimestamp != day {
+		a.file.Close()
+		a.file = nil
+	}
+
+	if a.file == nil {
+		path := fmt.Sprintf("%s/AUDIT_LOG_%s", a.directory(day), a.cluster.Node().ID)
+
+		if err := a.tieredFS.MkdirAll(a.directory(day), 0750); err != nil {
+			return err
+		}
+
+		file, err := a.tieredFS.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+
+		if err != nil {
+			return err
+		}
+
+		a.file = file
+		a.fileTimestamp = day
+	}
+
+	if _, err := a.file.Write(data); err != nil {
+		return err
+	}
+
+	return a.file.Sync()
+}
+
+// Return the timestamp of the start of the day of the timestamp.
+func auditLogDay(timestamp int64) int64 {
+	return timestamp - timestamp%86400
+}
@@ -0,0 +1,245 @@
+package logs_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/litebase/litebase/internal/test"
+	"github.com/litebase/litebase/pkg/logs"
+	"github.com/litebase/litebase/pkg/server"
+)
+
+func TestAuditLog(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		auditLog := logs.NewAuditLog(app.Cluster)
+
+		for _, action := range []string{
+			logs.AuditActionUserCreate,
+			logs.AuditActionDatabaseCreate,
+			logs.AuditActionUserDelete,
+		} {
+			_, err := auditLog.Write(logs.AuditLogEntry{
+				ActorType: logs.AuditActorUser,
+				Actor:     "root",
+				Action:    action,
+				Resource:  "test",
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		t.Run("Read", func(t *testing.T) {
+			entries, next, err := auditLog.Read(logs.AuditLogFilter{})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(entries) != 3 || next != 0 {
+				t.Fatalf("Expected 3 entries on a single page, got %d and next offset %d", len(entries), next)
+			}
+
+			for i, entry := range entries {
+				if entry.Sequence != uint64(i+1) {
+					t.Errorf("Expected sequence %d, got %d", i+1, entry.Sequence)
+				}
+
+				if i > 0 && entry.PreviousHash != entries[i-1].Hash {
+					t.Error("Expected the entry to be chained to the previous entry")
+				}
+			}
+
+			entries, _, err = auditLog.Read(logs.AuditLogFilter{Action: logs.AuditActionDatabaseCreate})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(entries) != 1 || entries[0].Action != logs.AuditActionDatabaseCreate {
+				t.Errorf("Expected only the matching entry, got %v", entries)
+			}
+
+			entries, _, err = auditLog.Read(logs.AuditLogFilter{End: time.Now().Add(-time.Hour).Unix()})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(entries) != 0 {
+				t.Errorf("Expected no entries before the range, got %d", len(entries))
+			}
+		})
+
+		t.Run("ReadPages", func(t *testing.T) {
+			entries, next, err := auditLog.Read(logs.AuditLogFilter{Limit: 2})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(entries) != 2 || next != 2 {
+				t.Fatalf("Expected a page of 2 entries and next offset 2, got %d and %d", len(entries), next)
+			}
+
+			entries, next, err = auditLog.Read(logs.AuditLogFilter{Limit: 2, Offset: next})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(entries) != 1 || next != 0 || entries[0].Sequence != 3 {
+				t.Errorf("Expected the last entry on the last page, got %v and next offset %d", entries, next)
+			}
+
+			_, _, err = auditLog.Read(logs.AuditLogFilter{Start: time.Now().Add(-logs.AuditLogMaxRange - time.Hour).Unix()})
+
+			if !errors.Is(err, logs.ErrAuditLogRange) {
+				t.Errorf("Expected %v, got %v", logs.ErrAuditLogRange, err)
+			}
+		})
+
+		t.Run("Append", func(t *testing.T) {
+			entry, err := auditLog.Append(logs.AuditLogEntry{
+				ActorType: logs.AuditActorAccessKey,
+				Actor:     "key",
+				Action:    logs.AuditActionDatabaseWrite,
+				Resource:  "database:test:branch:main",
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if entry.Sequence != 4 || entry.Hash == "" {
+				t.Fatalf("Expected the entry to be chained at sequence 4, got %v", entry)
+			}
+
+			// Reading the audit log writes the buffered entries first.
+			entries, _, err := auditLog.Read(logs.AuditLogFilter{Action: logs.AuditActionDatabaseWrite})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(entries) != 1 || entries[0].Hash != entry.Hash {
+				t.Errorf("Expected the appended entry to be written, got %v", entries)
+			}
+		})
+
+		t.Run("ContinuesChain", func(t *testing.T) {
+			entry, err := logs.NewAuditLog(app.Cluster).Write(logs.AuditLogEntry{
+				ActorType: logs.AuditActorUser,
+				Actor:     "root",
+				Action:    logs.AuditActionUserUpdate,
+				Resource:  "test",
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if entry.Sequence != 5 {
+				t.Errorf("Expected the chain to continue at sequence 5, got %d", entry.Sequence)
+			}
+
+			chains, err := auditLog.Verify()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(chains) != 1 || chains[0].Entries != 5 || chains[0].Hash != entry.Hash {
+				t.Errorf("Expected a single chain ending with the last entry, got %v", chains)
+			}
+		})
+
+		t.Run("ContinuesChainAfterLargeEntry", func(t *testing.T) {
+			// The last entry is read from the end of the file, in blocks that
+			// are smaller than this entry.
+			writer := logs.NewAuditLog(app.Cluster)
+			defer writer.Close()
+
+			large, err := writer.Write(logs.AuditLogEntry{
+				ActorType: logs.AuditActorUser,
+				Actor:     "root",
+				Action:    logs.AuditActionUserUpdate,
+				Resource:  "test",
+				Details:   map[string]string{"value": strings.Repeat("a", 10000)},
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			continued := logs.NewAuditLog(app.Cluster)
+			defer continued.Close()
+
+			entry, err := continued.Write(logs.AuditLogEntry{
+				ActorType: logs.AuditActorUser,
+				Actor:     "root",
+				Action:    logs.AuditActionUserUpdate,
+				Resource:  "test",
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if entry.Sequence != large.Sequence+1 || entry.PreviousHash != large.Hash {
+				t.Errorf("Expected the chain to continue after the large entry, got %v", entry)
+			}
+
+			if _, err := auditLog.Verify(); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		t.Run("DetectsTampering", func(t *testing.T) {
+			if err := auditLog.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			now := time.Now().UTC()
+			path := fmt.Sprintf(
+				"_audit/%d/AUDIT_LOG_%s",
+				time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Unix(),
+				app.Cluster.Node().ID,
+			)
+
+			data, err := app.Cluster.TieredFS().ReadFile(path)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tampered := bytes.Replace(data, []byte(logs.AuditActionDatabaseCreate), []byte(logs.AuditActionDatabaseDelete), 1)
+
+			if err := app.Cluster.TieredFS().WriteFile(path, tampered, 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := auditLog.Verify(); !errors.Is(err, logs.ErrAuditLogTampered) {
+				t.Errorf("Expected %v, got %v", logs.ErrAuditLogTampered, err)
+			}
+
+			// Removing the last entry leaves a valid chain that no longer
+			// reaches the signed head of the node.
+			lines := bytes.SplitAfter(bytes.TrimSpace(data), []byte("\n"))
+			truncated := bytes.Join(lines[:len(lines)-1], nil)
+
+			if err := app.Cluster.TieredFS().WriteFile(path, truncated, 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := auditLog.Verify(); !errors.Is(err, logs.ErrAuditLogTampered) {
+				t.Errorf("Expected the truncation to be detected, got %v", err)
+			}
+		})
+	})
+}
@@ -17,6 +17,7 @@ var QueryLogManagerTickInterval = time.Second * 1
 var QueryLogManagerFlushThreshold = time.Minute * 1
 
 type LogManager struct {
+	auditLog       *AuditLog
 	context        context.Context
 	deletingLogs   bool
 	queryLogBuffer sync.Pool
@@ -51,9 +52,44 @@ func (lm *LogManager) Close() error {
 
 	lm.queryLogs = make(map[string]*QueryLog)
 
+	if lm.auditLog != nil {
+		err := lm.auditLog.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// Append an entry to the audit log of the node.
+func (lm *LogManager) Audit(cluster *cluster.Cluster, entry AuditLogEntry) error {
+	_, err := lm.GetAuditLog(cluster).Write(entry)
+
+	return err
+}
+
+// Append an entry to the audit log of the node without waiting for it to be
+// synced to storage, for entries that are written on the hot path.
+func (lm *LogManager) AuditAsync(cluster *cluster.Cluster, entry AuditLogEntry) error {
+	_, err := lm.GetAuditLog(cluster).Append(entry)
+
+	return err
+}
+
+// Return the audit log of the node, creating it if needed.
+func (lm *LogManager) GetAuditLog(cluster *cluster.Cluster) *AuditLog {
+	lm.mutex.Lock()
+	defer lm.mutex.Unlock()
+
+	if lm.auditLog == nil {
+		lm.auditLog = NewAuditLog(cluster)
+	}
+
+	return lm.auditLog
+}
+
 func (lm *LogManager) GetQueryLog(cluster *cluster.Cluster, databaseHash, databaseId, branchId string) *QueryLog {
 	// Get the current time un UTC
 	t := time.Now().UTC()
@@ -0,0 +1,83 @@
+package router
+
+import (
+	"cmp"
+	"hash/fnv"
+	"slices"
+	"strconv"
+)
+
+// The number of points each target is placed at on the hash ring. More points
+// spread keys more evenly across targets.
+var HashRingReplicas = 128
+
+// A HashRing maps keys to targets using consistent hashing. When a target is
+// added or removed only the keys of that target move to other targets.
+type HashRing struct {
+	points []hashRingPoint
+}
+
+type hashRingPoint struct {
+	hash   uint64
+	target *Target
+}
+
+// Create a new hash ring for the targets.
+func NewHashRing(targets []*Target) *HashRing {
+	ring := &HashRing{
+		points: make([]hashRingPoint, 0, len(targets)*HashRingReplicas),
+	}
+
+	for _, target := range targets {
+		for i := range HashRingReplicas {
+			ring.points = append(ring.points, hashRingPoint{
+				hash:   hashRingKey(target.URL.Host + "#" + strconv.Itoa(i)),
+				target: target,
+			})
+		}
+	}
+
+	slices.SortFunc(ring.points, func(a, b hashRingPoint) int {
+		return cmp.Compare(a.hash, b.hash)
+	})
+
+	return ring
+}
+
+// Return the position of a key on the hash ring.
+func hashRingKey(key string) uint64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+
+	return hash.Sum64()
+}
+
+// Walk the distinct targets of the ring in order, starting from the position
+// of the key, until the function returns true.
+func (r *HashRing) Walk(key string, fn func(*Target) bool) {
+	if len(r.points) == 0 {
+		return
+	}
+
+	hash := hashRingKey(key)
+
+	start, _ := slices.BinarySearchFunc(r.points, hash, func(point hashRingPoint, hash uint64) int {
+		return cmp.Compare(point.hash, hash)
+	})
+
+	visited := map[*Target]struct{}{}
+
+	for i := range r.points {
+		target := r.points[(start+i)%len(r.points)].target
+
+		if _, ok := visited[target]; ok {
+			continue
+		}
+
+		visited[target] = struct{}{}
+
+		if fn(target) {
+			return
+		}
+	}
+}
@@ -1,92 +1,310 @@
 package router
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
-	"net"
 	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/litebase/litebase/pkg/config"
+	"github.com/litebase/litebase/pkg/https"
+)
+
+var (
+	ErrNoTargetsAvailable             = fmt.Errorf("no targets available")
+	ErrRouterBalancingStrategyInvalid = fmt.Errorf("the router balancing strategy must be one of 'affinity', 'latency' or 'least_connections'")
 )
 
+// Check that the balancing strategy is supported by the LoadBalancer.
+func ValidateBalancingStrategy(strategy string) error {
+	switch strategy {
+	case config.RouterBalancingAffinity, config.RouterBalancingLatency, config.RouterBalancingLeastConnections:
+		return nil
+	default:
+		return fmt.Errorf("%w, got '%s'", ErrRouterBalancingStrategyInvalid, strategy)
+	}
+}
+
+// The response body of the health check endpoint of a node.
+type healthCheckResponse struct {
+	Data struct {
+		ReplicationLag int64  `json:"replication_lag_ms"`
+		Role           string `json:"role"`
+	} `json:"data"`
+}
+
 type LoadBalancer struct {
+	client        *http.Client
+	config        *config.Config
 	currentTarget int64
+	mutex         *sync.RWMutex
+	ring          *HashRing
 	targets       []*Target
 }
 
-func NewLoadBalancer() *LoadBalancer {
-	lb := &LoadBalancer{
+func NewLoadBalancer(c *config.Config) *LoadBalancer {
+	transport := &http.Transport{
+		IdleConnTimeout: 30 * time.Second,
+	}
+
+	if c.RouterNodeScheme == "https" {
+		transport.TLSClientConfig = https.ClientConfig(c)
+	}
+
+	return &LoadBalancer{
+		client: &http.Client{
+			Timeout:   2 * time.Second,
+			Transport: transport,
+		},
+		config:  c,
+		mutex:   &sync.RWMutex{},
+		ring:    NewHashRing(nil),
 		targets: []*Target{},
 	}
+}
 
-	lb.setTargets()
+// Return the target for a read request of a database. Databases are mapped to
+// targets with consistent hashing so the caches and tiered files of each
+// database stay warm on a small set of nodes. A target only receives a
+// request while its load is within the configured bound of the average load,
+// otherwise the request spills over to the next target on the ring.
+func (lb *LoadBalancer) AffinityTarget(databaseName string) (*Target, error) {
+	lb.mutex.RLock()
+	ring := lb.ring
+	targets := lb.targets
+	lb.mutex.RUnlock()
+
+	var count, load int64
+
+	for _, target := range targets {
+		if lb.isEligible(target) {
+			count++
+			load += target.Connections()
+		}
+	}
 
-	go func() {
-		lb.getTargets()
-		lb.runHealthChecks()
-	}()
+	if count == 0 {
+		return nil, ErrNoTargetsAvailable
+	}
+
+	bound := lb.config.RouterAffinityLoadBoundPercent
+
+	if bound < 100 {
+		bound = 100
+	}
+
+	// The capacity includes the request that is being routed
+	capacity := max(1, ((load+1)*bound+count*100-1)/(count*100))
+
+	var selected *Target
+
+	ring.Walk(databaseName, func(target *Target) bool {
+		if !lb.isEligible(target) || target.Connections()+1 > capacity {
+			return false
+		}
 
-	return lb
+		selected = target
+
+		return true
+	})
+
+	if selected == nil {
+		return lb.ReadTarget()
+	}
+
+	return selected, nil
 }
 
-func (lb *LoadBalancer) getTargets() {
-	timer := time.NewTicker(3 * time.Second)
+// Check the health of a target using the health check endpoint of the node.
+// The role and replication lag reported by the node are recorded along with
+// the latency of the request.
+func (lb *LoadBalancer) checkTarget(target *Target) bool {
+	start := time.Now()
+
+	response, err := lb.client.Get(target.URL.JoinPath("/v1/health").String())
+
+	if err != nil {
+		log.Printf("%s unreachable: %v\n", target.URL, err)
+		return false
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		log.Printf("%s unhealthy: status %d\n", target.URL, response.StatusCode)
+		return false
+	}
 
-	for range timer.C {
-		lb.setTargets()
+	health := healthCheckResponse{}
+
+	if err := json.NewDecoder(response.Body).Decode(&health); err != nil {
+		log.Printf("%s returned an invalid health check response: %v\n", target.URL, err)
+		return false
 	}
+
+	target.SetHealth(
+		health.Data.Role == "primary",
+		time.Duration(health.Data.ReplicationLag)*time.Millisecond,
+		time.Since(start),
+	)
+
+	return true
 }
 
-// HealthCheck pings the backends and update the status
+// HealthCheck checks the health of every target concurrently.
 func (lb *LoadBalancer) HealthCheck() {
-	for _, target := range lb.targets {
-		status := "up"
-		alive := lb.checkTarget(target)
-		target.SetAlive(alive)
+	wg := sync.WaitGroup{}
 
-		if !alive {
-			status = "down"
-		}
+	for _, target := range lb.Targets() {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			target.SetAlive(lb.checkTarget(target))
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (lb *LoadBalancer) Handle(w http.ResponseWriter, r *http.Request) {
+	attempts := GetAttemptsFromContext(r)
+
+	if attempts > 3 {
+		log.Printf("%s(%s) Max attempts reached, terminating\n", r.RemoteAddr, r.URL.Path)
+		http.Error(w, "Service not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	var target *Target
+	var err error
+
+	if isWriteRequest(r) {
+		target, err = lb.PrimaryTarget()
+	} else if databaseName := databaseNameFromPath(r.URL.Path); databaseName != "" &&
+		lb.config.RouterBalancing == config.RouterBalancingAffinity {
+		target, err = lb.AffinityTarget(databaseName)
+	} else {
+		target, err = lb.ReadTarget()
+	}
+
+	if err != nil {
+		log.Printf("%s(%s) No targets available\n", r.RemoteAddr, r.URL.Path)
+		http.Error(w, "Service not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	target.ServeHTTP(w, r)
+}
+
+// Return true if a target can serve read requests. Replicas that lag behind
+// the primary by more than the configured maximum are not eligible.
+func (lb *LoadBalancer) isEligible(target *Target) bool {
+	if !target.IsAlive() {
+		return false
+	}
 
-		log.Printf("%s [%s]\n", target.URL, status)
+	maxLag := time.Duration(lb.config.ReplicaMaxLagMs) * time.Millisecond
+
+	return maxLag <= 0 || target.IsPrimary() || target.Lag() <= maxLag
+}
+
+// Return the target of the primary node. If the primary is unknown or
+// unavailable a read target is returned instead and the node forwards the
+// request to the primary.
+func (lb *LoadBalancer) PrimaryTarget() (*Target, error) {
+	for _, target := range lb.Targets() {
+		if target.IsAlive() && target.IsPrimary() {
+			return target, nil
+		}
 	}
+
+	return lb.ReadTarget()
 }
 
-func (lb *LoadBalancer) NextTarget() (*Target, error) {
-	if len(lb.targets) == 0 {
-		return nil, fmt.Errorf("no targets available")
+// Return the target to send a read request to. Targets that are not eligible
+// are skipped and the remaining targets are selected by the configured
+// balancing strategy, starting from a different target each time so that ties
+// are spread evenly.
+func (lb *LoadBalancer) ReadTarget() (*Target, error) {
+	targets := lb.Targets()
+
+	if len(targets) == 0 {
+		return nil, ErrNoTargetsAvailable
 	}
 
-	next := lb.NextIndex()
+	next := int(atomic.AddInt64(&lb.currentTarget, 1) % int64(len(targets)))
 
-	l := len(lb.targets) + next // start from next and move a full cycle
+	var selected *Target
 
-	for i := next; i < l; i++ {
-		index := i % len(lb.targets) // take an index by modding with length
+	for i := range targets {
+		target := targets[(next+i)%len(targets)]
 
-		// if we have an alive backend, use it and store if its not the original one
-		if lb.targets[index].IsAlive() {
-			if i != next {
-				atomic.StoreInt64(&lb.currentTarget, int64(index)) // mark the current one
-			}
+		if !lb.isEligible(target) {
+			continue
+		}
 
-			return lb.targets[index], nil
+		if selected == nil || lb.score(target) < lb.score(selected) {
+			selected = target
 		}
 	}
 
-	return nil, fmt.Errorf("no targets available")
+	if selected == nil {
+		return nil, ErrNoTargetsAvailable
+	}
+
+	return selected, nil
 }
 
-func (lb *LoadBalancer) NextIndex() int {
-	return int(atomic.AddInt64(&lb.currentTarget, int64(1)) % int64(len(lb.targets)))
+// Refresh the targets and check their health at the configured interval until
+// the context is done.
+func (lb *LoadBalancer) Run(ctx context.Context) {
+	lb.setTargets()
+	lb.HealthCheck()
+
+	interval := time.Duration(lb.config.RouterHealthIntervalMs) * time.Millisecond
+
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			lb.setTargets()
+			lb.HealthCheck()
+		}
+	}
 }
 
-func (lb *LoadBalancer) setTargets() {
-	targets := []*Target{}
+// Return the score of a target for the balancing strategy, lower is better.
+func (lb *LoadBalancer) score(target *Target) int64 {
+	switch lb.config.RouterBalancing {
+	case config.RouterBalancingLatency:
+		return int64(target.Latency()) * (target.Connections() + 1)
+	default:
+		return target.Connections()
+	}
+}
 
-	entries, err := os.ReadDir(fmt.Sprintf("%s/%s", os.Getenv("LITEBASE_DATA_PATH"), "/_nodes"))
+// Set the targets from the node addresses registered in network storage.
+// Existing targets are kept so their health and statistics are preserved.
+func (lb *LoadBalancer) setTargets() {
+	entries, err := os.ReadDir(filepath.Join(lb.config.NetworkStoragePath, "_nodes"))
 
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -98,61 +316,60 @@ func (lb *LoadBalancer) setTargets() {
 		return
 	}
 
+	addresses := []string{}
+
 	for _, entry := range entries {
-		parts := strings.Split(entry.Name(), ":")
+		// Node addresses are stored with the port separated by an underscore
+		index := strings.LastIndexAny(entry.Name(), "_:")
 
-		if len(parts) != 2 {
+		if index <= 0 || index == len(entry.Name())-1 {
 			continue
 		}
 
-		targets = append(targets, NewTarget(lb, parts[0], parts[1]))
+		addresses = append(addresses, entry.Name()[:index]+":"+entry.Name()[index+1:])
 	}
 
-	lb.targets = targets
+	slices.Sort(addresses)
+
+	lb.SetTargets(addresses)
 }
 
-func (lb *LoadBalancer) checkTarget(target *Target) bool {
-	timeout := 2 * time.Second
-	conn, err := net.DialTimeout("tcp", target.URL.Host, timeout)
+// Set the targets of the load balancer to the given node addresses. The hash
+// ring is rebuilt so databases are rehashed when the membership changes.
+func (lb *LoadBalancer) SetTargets(addresses []string) {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
 
-	if err != nil {
-		log.Println("Service unreachable, error: ", err)
-		return false
-	}
+	targets := make([]*Target, 0, len(addresses))
 
-	defer conn.Close()
+	for _, address := range addresses {
+		index := slices.IndexFunc(lb.targets, func(target *Target) bool {
+			return target.URL.Host == address
+		})
 
-	return true
-}
+		if index >= 0 {
+			targets = append(targets, lb.targets[index])
+			continue
+		}
 
-func (lb *LoadBalancer) Handle(w http.ResponseWriter, r *http.Request) {
-	attempts := GetAttemptsFromContext(r)
-	log.Println("Attempts: ", attempts)
-	if attempts > 3 {
-		log.Printf("%s(%s) Max attempts reached, terminating\n", r.RemoteAddr, r.URL.Path)
-		http.Error(w, "Service not available", http.StatusServiceUnavailable)
-		return
-	}
+		target, err := NewTarget(lb.config, address)
 
-	target, err := lb.NextTarget()
-	log.Println("Target: ", target)
-	if err != nil {
-		http.Error(w, "Service not available", http.StatusServiceUnavailable)
-		return
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		targets = append(targets, target)
 	}
 
-	target.ServeHTTP(w, r)
+	lb.ring = NewHashRing(targets)
+	lb.targets = targets
 }
 
-func (lb *LoadBalancer) runHealthChecks() {
-	t := time.NewTicker(time.Minute * 2)
+// Return the current targets of the load balancer.
+func (lb *LoadBalancer) Targets() []*Target {
+	lb.mutex.RLock()
+	defer lb.mutex.RUnlock()
 
-	for {
-		select {
-		case <-t.C:
-			log.Println("Starting health check...")
-			lb.HealthCheck()
-			log.Println("Health check completed")
-		}
-	}
+	return lb.targets
 }
@@ -0,0 +1,308 @@
+package router_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/litebase/litebase/pkg/config"
+	"github.com/litebase/litebase/pkg/router"
+)
+
+type testNode struct {
+	release  chan struct{}
+	requests int64
+	server   *httptest.Server
+}
+
+// Create a node that reports the given role and replication lag from its
+// health check endpoint and counts the other requests it serves. Requests
+// with the block parameter are held open until the node is released.
+func newTestNode(t *testing.T, role string, lag int64) *testNode {
+	node := &testNode{
+		release: make(chan struct{}),
+	}
+
+	node.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/health" {
+			fmt.Fprintf(w, `{"status":"success","data":{"role":%q,"replication_lag_ms":%d}}`, role, lag)
+			return
+		}
+
+		atomic.AddInt64(&node.requests, 1)
+
+		if r.URL.Query().Has("block") {
+			<-node.release
+		}
+	}))
+
+	t.Cleanup(node.server.Close)
+
+	return node
+}
+
+func newTestLoadBalancer(c *config.Config, nodes ...*testNode) *router.LoadBalancer {
+	c.RouterNodeScheme = "http"
+
+	loadBalancer := router.NewLoadBalancer(c)
+	addresses := []string{}
+
+	for _, node := range nodes {
+		addresses = append(addresses, strings.TrimPrefix(node.server.URL, "http://"))
+	}
+
+	loadBalancer.SetTargets(addresses)
+	loadBalancer.HealthCheck()
+
+	return loadBalancer
+}
+
+func sendQuery(loadBalancer *router.LoadBalancer, body string) {
+	request := httptest.NewRequest("POST", "/v1/databases/test/main/query", strings.NewReader(body))
+
+	loadBalancer.Handle(httptest.NewRecorder(), request)
+}
+
+func TestLoadBalancerRoutesWritesToPrimary(t *testing.T) {
+	replica := newTestNode(t, "replica", 0)
+	primary := newTestNode(t, "primary", 0)
+	loadBalancer := newTestLoadBalancer(&config.Config{}, replica, primary)
+
+	for _, body := range []string{
+		`{"queries":[{"id":"1","statement":"INSERT INTO users (name) VALUES ('a')"}]}`,
+		`{"queries":[{"id":"1","statement":"SELECT 1"},{"id":"2","statement":"DELETE FROM users"}]}`,
+		`{"queries":[{"id":"1","statement":"SELECT 1","transaction_id":"abc"}]}`,
+		`{"queries":[{"id":"1","statement":"WITH t AS (SELECT 1) DELETE FROM users"}]}`,
+		`not json`,
+	} {
+		sendQuery(loadBalancer, body)
+	}
+
+	loadBalancer.Handle(httptest.NewRecorder(), httptest.NewRequest("POST", "/v1/databases", nil))
+
+	if replica.requests != 0 {
+		t.Errorf("Expected no writes to be sent to the replica, got %d", replica.requests)
+	}
+
+	if primary.requests != 6 {
+		t.Errorf("Expected 6 writes to be sent to the primary, got %d", primary.requests)
+	}
+}
+
+func TestLoadBalancerSpreadsReads(t *testing.T) {
+	replica := newTestNode(t, "replica", 0)
+	primary := newTestNode(t, "primary", 0)
+	loadBalancer := newTestLoadBalancer(&config.Config{}, replica, primary)
+
+	for range 10 {
+		sendQuery(loadBalancer, `{"queries":[{"id":"1","statement":"  -- comment\n select * from users"}]}`)
+		loadBalancer.Handle(httptest.NewRecorder(), httptest.NewRequest("GET", "/v1/databases", nil))
+	}
+
+	if replica.requests == 0 || primary.requests == 0 {
+		t.Errorf("Expected reads to be spread across nodes, got %d and %d", replica.requests, primary.requests)
+	}
+
+	if replica.requests+primary.requests != 20 {
+		t.Errorf("Expected 20 reads, got %d", replica.requests+primary.requests)
+	}
+}
+
+func TestLoadBalancerSkipsLaggingReplicas(t *testing.T) {
+	replica := newTestNode(t, "replica", 5000)
+	primary := newTestNode(t, "primary", 0)
+	loadBalancer := newTestLoadBalancer(&config.Config{ReplicaMaxLagMs: 1000}, replica, primary)
+
+	for range 5 {
+		sendQuery(loadBalancer, `{"queries":[{"id":"1","statement":"SELECT 1"}]}`)
+	}
+
+	if replica.requests != 0 {
+		t.Errorf("Expected no reads to be sent to the lagging replica, got %d", replica.requests)
+	}
+
+	if primary.requests != 5 {
+		t.Errorf("Expected 5 reads to be sent to the primary, got %d", primary.requests)
+	}
+}
+
+func TestLoadBalancerSkipsUnhealthyTargets(t *testing.T) {
+	replica := newTestNode(t, "replica", 0)
+	primary := newTestNode(t, "primary", 0)
+	primary.server.Close()
+
+	loadBalancer := newTestLoadBalancer(&config.Config{}, replica, primary)
+
+	// Without a healthy primary writes are sent to a replica, which forwards
+	// them to the primary.
+	sendQuery(loadBalancer, `{"queries":[{"id":"1","statement":"INSERT INTO users (name) VALUES ('a')"}]}`)
+	sendQuery(loadBalancer, `{"queries":[{"id":"1","statement":"SELECT 1"}]}`)
+
+	if replica.requests != 2 {
+		t.Errorf("Expected 2 requests to be sent to the replica, got %d", replica.requests)
+	}
+
+	target, err := loadBalancer.PrimaryTarget()
+
+	if err != nil || target.IsPrimary() {
+		t.Errorf("Expected the primary to be skipped, got %v, %v", target, err)
+	}
+}
+
+func TestLoadBalancerDatabaseAffinity(t *testing.T) {
+	nodes := []*testNode{
+		newTestNode(t, "primary", 0),
+		newTestNode(t, "replica", 0),
+		newTestNode(t, "replica", 0),
+	}
+
+	loadBalancer := newTestLoadBalancer(&config.Config{
+		RouterAffinityLoadBoundPercent: 125,
+		RouterBalancing:                config.RouterBalancingAffinity,
+	}, nodes...)
+
+	// Sequential reads of the same database are always sent to the same node
+	for range 10 {
+		loadBalancer.Handle(httptest.NewRecorder(), httptest.NewRequest("GET", "/v1/databases/test/main/backups", nil))
+		sendQuery(loadBalancer, `{"queries":[{"id":"1","statement":"SELECT 1"}]}`)
+	}
+
+	served := 0
+
+	for _, node := range nodes {
+		if node.requests > 0 {
+			served++
+
+			if node.requests != 20 {
+				t.Errorf("Expected all 20 reads on one node, got %d", node.requests)
+			}
+		}
+	}
+
+	if served != 1 {
+		t.Errorf("Expected the database to be served by 1 node, got %d", served)
+	}
+}
+
+func TestLoadBalancerDatabaseAffinityRehash(t *testing.T) {
+	c := &config.Config{
+		RouterAffinityLoadBoundPercent: 125,
+		RouterBalancing:                config.RouterBalancingAffinity,
+	}
+
+	nodes := []*testNode{
+		newTestNode(t, "primary", 0),
+		newTestNode(t, "replica", 0),
+		newTestNode(t, "replica", 0),
+	}
+
+	loadBalancer := newTestLoadBalancer(c, nodes...)
+	before := map[string]string{}
+
+	for i := range 100 {
+		database := fmt.Sprintf("database-%d", i)
+		target, err := loadBalancer.AffinityTarget(database)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		before[database] = target.URL.Host
+	}
+
+	// Removing a node only moves the databases of that node
+	removed := strings.TrimPrefix(nodes[2].server.URL, "http://")
+
+	loadBalancer.SetTargets([]string{
+		strings.TrimPrefix(nodes[0].server.URL, "http://"),
+		strings.TrimPrefix(nodes[1].server.URL, "http://"),
+	})
+
+	for database, host := range before {
+		target, err := loadBalancer.AffinityTarget(database)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if host != removed && target.URL.Host != host {
+			t.Errorf("Expected %s to stay on %s, moved to %s", database, host, target.URL.Host)
+		}
+
+		if target.URL.Host == removed {
+			t.Errorf("Expected %s to move off the removed node", database)
+		}
+	}
+}
+
+func TestLoadBalancerDatabaseAffinityBoundedLoad(t *testing.T) {
+	nodes := []*testNode{newTestNode(t, "primary", 0), newTestNode(t, "replica", 0)}
+
+	loadBalancer := newTestLoadBalancer(&config.Config{
+		RouterAffinityLoadBoundPercent: 100,
+		RouterBalancing:                config.RouterBalancingAffinity,
+	}, nodes...)
+
+	preferred, err := loadBalancer.AffinityTarget("test")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Hold a request open on the preferred node so it reaches its load bound
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		preferred.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/?block=true", nil))
+	}()
+
+	for preferred.Connections() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	target, err := loadBalancer.AffinityTarget("test")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if target == preferred {
+		t.Error("Expected the request to spill over to the next node on the ring")
+	}
+
+	for _, node := range nodes {
+		close(node.release)
+	}
+
+	<-done
+
+	target, err = loadBalancer.AffinityTarget("test")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if target != preferred {
+		t.Error("Expected the database to return to the preferred node")
+	}
+}
+
+func TestValidateBalancingStrategy(t *testing.T) {
+	for _, strategy := range []string{config.RouterBalancingAffinity, config.RouterBalancingLatency, config.RouterBalancingLeastConnections} {
+		if err := router.ValidateBalancingStrategy(strategy); err != nil {
+			t.Errorf("Expected %s to be valid, got %v", strategy, err)
+		}
+	}
+
+	for _, strategy := range []string{"", "Latency", "round_robin"} {
+		if err := router.ValidateBalancingStrategy(strategy); !errors.Is(err, router.ErrRouterBalancingStrategyInvalid) {
+			t.Errorf("Expected %q to be invalid, got %v", strategy, err)
+		}
+	}
+}
@@ -9,33 +9,72 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+
+	"github.com/litebase/litebase/pkg/config"
+	"github.com/litebase/litebase/pkg/https"
 )
 
 type Router struct {
-	Cancel     context.CancelFunc
-	Context    context.Context
-	HttpServer *http.Server
+	Cancel         context.CancelFunc
+	config         *config.Config
+	Context        context.Context
+	HttpServer     *http.Server
+	redirectServer *http.Server
 }
 
-func NewRouter() *Router {
-	return &Router{}
+func NewRouter(c *config.Config) *Router {
+	return &Router{
+		config: c,
+	}
 }
 
 func (router *Router) Start() {
-	port := os.Getenv("LITEBASE_ROUTER_NODE_PORT")
+	if err := ValidateBalancingStrategy(router.config.RouterBalancing); err != nil {
+		log.Fatalf("Invalid router configuration: %v", err)
+	}
+
+	port := router.config.RouterNodePort
 	ctx := context.Background()
 	router.Context, router.Cancel = context.WithCancel(ctx)
 
 	router.HttpServer = &http.Server{
 		Addr:              fmt.Sprintf(":%s", port),
-		Handler:           RouterHandler(),
+		Handler:           RouterHandler(router.Context, router.config),
 		ReadHeaderTimeout: 2 * time.Second,
 	}
 
+	certificate, err := https.NewCertificateReloaderFromConfig(router.config)
+
+	if err != nil {
+		log.Fatalf("Failed to load the TLS certificate: %v", err)
+	}
+
+	if certificate != nil {
+		https.Configure(router.HttpServer, router.config, https.ServerConfig(router.config, certificate))
+
+		if router.config.TLSRedirectPort != "" {
+			router.redirectServer = https.NewRedirectServer(router.config, port)
+
+			go func() {
+				if err := router.redirectServer.ListenAndServe(); err != http.ErrServerClosed {
+					log.Fatalf("ListenAndServe(): %v", err)
+				}
+			}()
+		}
+	}
+
 	log.Println("Litebase Router running on port", port)
 
 	go func() {
-		if err := router.HttpServer.ListenAndServe(); err != http.ErrServerClosed {
+		var err error
+
+		if router.HttpServer.TLSConfig != nil {
+			err = router.HttpServer.ListenAndServeTLS("", "")
+		} else {
+			err = router.HttpServer.ListenAndServe()
+		}
+
+		if err != http.ErrServerClosed {
 			log.Fatalf("ListenAndServe(): %v", err)
 		}
 	}()
@@ -58,6 +97,12 @@ func (router *Router) Start() {
 func (router *Router) Shutdown() {
 	fmt.Println("")
 
+	if router.redirectServer != nil {
+		if err := router.redirectServer.Shutdown(router.Context); err != nil {
+			log.Printf("HTTP redirect server Shutdown: %v", err)
+		}
+	}
+
 	if err := router.HttpServer.Shutdown(router.Context); err != nil {
 		log.Printf("HTTP server Shutdown: %v", err)
 	}
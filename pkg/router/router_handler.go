@@ -1,32 +1,52 @@
 package router
 
 import (
-	"log"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
 	"net/http"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/litebase/litebase/pkg/config"
 )
 
-func RouterHandler() http.HandlerFunc {
-	loadBalancer := NewLoadBalancer()
+// The maximum size of a query request body that is inspected to determine if
+// the request can be served by a replica. Larger requests are sent to the
+// primary.
+const maxInspectedBodySize = 1 << 20
 
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// attempts := GetAttemptsFromContext(r)
+var queryPathPattern = regexp.MustCompile(`^/v1/databases/[^/]+/[^/]+/query$`)
 
-		// if attempts > 3 {
-		// 	log.Printf("%s(%s) Max attempts reached, terminating\n", r.RemoteAddr, r.URL.Path)
-		// 	http.Error(w, "Service not available", http.StatusServiceUnavailable)
-		// 	return
-		// }
+type queryRequest struct {
+	Queries []struct {
+		Statement     string `json:"statement"`
+		TransactionID string `json:"transaction_id"`
+	} `json:"queries"`
+}
 
-		target, err := loadBalancer.NextTarget()
+func RouterHandler(ctx context.Context, c *config.Config) http.HandlerFunc {
+	loadBalancer := NewLoadBalancer(c)
 
-		if err != nil {
-			log.Printf("%s(%s) No targets available\n", r.RemoteAddr, r.URL.Path)
-			http.Error(w, "Service not available", http.StatusServiceUnavailable)
-			return
-		}
+	go loadBalancer.Run(ctx)
+
+	return http.HandlerFunc(loadBalancer.Handle)
+}
+
+// Return the name of the database a request is for, or an empty string if the
+// request is not for a database.
+func databaseNameFromPath(path string) string {
+	path, ok := strings.CutPrefix(path, "/v1/databases/")
+
+	if !ok {
+		return ""
+	}
+
+	databaseName, _, _ := strings.Cut(path, "/")
 
-		target.ServeHTTP(w, r)
-	})
+	return databaseName
 }
 
 func GetAttemptsFromContext(r *http.Request) int {
@@ -45,3 +65,75 @@ func GetRetryFromContext(r *http.Request) int {
 	}
 	return 0
 }
+
+// Return true if the request must be served by the primary. Reads of the API
+// and queries that only contain read statements outside of a transaction can
+// be served by any node, every other request is a write.
+func isWriteRequest(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+
+	if r.Method != http.MethodPost || !queryPathPattern.MatchString(r.URL.Path) || r.Body == nil {
+		return true
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxInspectedBodySize+1))
+
+	// Restore the body so it can be proxied to the node
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+
+	if err != nil || len(body) > maxInspectedBodySize {
+		return true
+	}
+
+	input := queryRequest{}
+
+	if err := json.Unmarshal(body, &input); err != nil || len(input.Queries) == 0 {
+		return true
+	}
+
+	for _, query := range input.Queries {
+		if query.TransactionID != "" || !isReadStatement(query.Statement) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Return true if the statement only reads data. Statements that can not be
+// classified with certainty, such as common table expressions that may
+// contain writes, are treated as writes.
+func isReadStatement(statement string) bool {
+	for {
+		statement = strings.TrimLeft(statement, " \t\r\n(")
+
+		if strings.HasPrefix(statement, "--") {
+			_, statement, _ = strings.Cut(statement, "\n")
+		} else if strings.HasPrefix(statement, "/*") {
+			_, statement, _ = strings.Cut(statement, "*/")
+		} else {
+			break
+		}
+	}
+
+	keyword := statement
+
+	if index := strings.IndexFunc(statement, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}); index >= 0 {
+		keyword = statement[:index]
+	}
+
+	switch strings.ToUpper(keyword) {
+	case "EXPLAIN", "SELECT", "VALUES":
+		return true
+	}
+
+	return false
+}
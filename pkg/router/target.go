@@ -9,6 +9,9 @@ import (
 	"net/url"
 	"sync/atomic"
 	"time"
+
+	"github.com/litebase/litebase/pkg/config"
+	"github.com/litebase/litebase/pkg/https"
 )
 
 type Attempts struct{}
@@ -16,17 +19,21 @@ type Retry struct{}
 
 type Target struct {
 	alive          int32
+	connections    int64
+	lag            int64
+	latency        int64
+	primary        int32
 	RevereseProxy  *httputil.ReverseProxy
 	transportIndex int64
 	transports     []*customTransport
 	URL            *url.URL
 }
 
-func NewTarget(lb *LoadBalancer, host, port string) *Target {
-	url, err := url.Parse(fmt.Sprintf("http://%s:%s", host, port))
+func NewTarget(c *config.Config, address string) (*Target, error) {
+	url, err := url.Parse(fmt.Sprintf("%s://%s", c.RouterNodeScheme, address))
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	target := &Target{
@@ -63,26 +70,50 @@ func NewTarget(lb *LoadBalancer, host, port string) *Target {
 			MaxConnsPerHost: 0,
 			IdleConnTimeout: 30 * time.Second,
 		}}
+
+		if c.RouterNodeScheme == "https" {
+			transport.transport.TLSClientConfig = https.ClientConfig(c)
+		}
+
 		target.transports = append(target.transports, transport)
 	}
 
-	// proxy.Transport = &customTransport{http.Transport{
-	// 	// DialContext: (&net.Dialer{
-	// 	// 	Timeout:   30 * time.Second,
-	// 	// 	KeepAlive: 30 * time.Second,
-	// 	// }).DialContext,
-	// 	MaxIdleConns:    100000,
-	// 	MaxConnsPerHost: 1000,
-	// 	IdleConnTimeout: 1 * time.Second,
-	// }}
-
-	return target
+	// The target rotates through its transports for each request
+	proxy.Transport = target
+
+	return target, nil
+}
+
+// Return the number of requests that are currently being proxied to the target.
+func (target *Target) Connections() int64 {
+	return atomic.LoadInt64(&target.connections)
 }
 
 func (target *Target) IsAlive() bool {
 	return atomic.LoadInt32(&target.alive) == 1
 }
 
+// Return true if the target was the primary at the last health check.
+func (target *Target) IsPrimary() bool {
+	return atomic.LoadInt32(&target.primary) == 1
+}
+
+// Return the replication lag reported by the target at the last health check.
+func (target *Target) Lag() time.Duration {
+	return time.Duration(atomic.LoadInt64(&target.lag))
+}
+
+// Return the moving average of the latency of the health checks of the target.
+func (target *Target) Latency() time.Duration {
+	return time.Duration(atomic.LoadInt64(&target.latency))
+}
+
+func (target *Target) RoundTrip(request *http.Request) (*http.Response, error) {
+	index := atomic.AddInt64(&target.transportIndex, 1) % int64(len(target.transports))
+
+	return target.transports[index].RoundTrip(request)
+}
+
 func (target *Target) SetAlive(alive bool) {
 	var i int32
 
@@ -93,8 +124,31 @@ func (target *Target) SetAlive(alive bool) {
 	atomic.StoreInt32(&target.alive, i)
 }
 
+// Record the result of a health check of the target. The latency is smoothed
+// with an exponentially weighted moving average so a single slow check does
+// not move traffic away from the target.
+func (target *Target) SetHealth(primary bool, lag, latency time.Duration) {
+	var i int32
+
+	if primary {
+		i = 1
+	}
+
+	atomic.StoreInt32(&target.primary, i)
+	atomic.StoreInt64(&target.lag, int64(lag))
+
+	previous := atomic.LoadInt64(&target.latency)
+
+	if previous == 0 {
+		atomic.StoreInt64(&target.latency, int64(latency))
+	} else {
+		atomic.StoreInt64(&target.latency, (previous*4+int64(latency))/5)
+	}
+}
+
 func (target *Target) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	target.transportIndex = (target.transportIndex + 1) % int64(len(target.transports))
-	target.RevereseProxy.Transport = target.transports[target.transportIndex]
+	atomic.AddInt64(&target.connections, 1)
+	defer atomic.AddInt64(&target.connections, -1)
+
 	target.RevereseProxy.ServeHTTP(w, r)
 }
@@ -89,10 +89,16 @@ func NewApp(configInstance *config.Config, serveMux *netHttp.ServeMux) *App {
 		database.ResponsePool(),
 		database.NewDatabaseWALSynchronizer(app.DatabaseManager),
 	)
+	app.Cluster.Node().SetStatusReporter(app.DatabaseManager.Status)
+	app.Cluster.Node().SetWriteDrainer(app.DatabaseManager.DrainWrites)
 	app.Cluster.EventsManager().Init()
 	app.Auth.Broadcaster(app.Cluster.EventsManager().Hook())
 
 	go app.DatabaseManager.WriteQueueManager.Run()
+
+	if app.Cluster.Node().IsRemoteReplica() {
+		go database.NewReplicationLogTailer(app.DatabaseManager).Run(app.Cluster.Node().Context())
+	}
 	go app.LogManager.Run()
 
 	app.initialized = true
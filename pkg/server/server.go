@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
 	"net/http"
@@ -10,7 +11,9 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/litebase/litebase/pkg/cluster"
 	"github.com/litebase/litebase/pkg/config"
+	"github.com/litebase/litebase/pkg/https"
 	"github.com/litebase/litebase/pkg/storage"
 )
 
@@ -20,6 +23,8 @@ type Server struct {
 	context    context.Context
 	HttpServer *http.Server
 	ServeMux   *http.ServeMux
+
+	redirectServer *http.Server
 }
 
 func NewServer(c *config.Config) *Server {
@@ -35,12 +40,7 @@ func NewServer(c *config.Config) *Server {
 }
 
 func (s *Server) Start(startHook func(*http.ServeMux), shutdownHook func()) {
-	// TODO: Add TLS support using autocert or certmagic if this is a query node
-	// TODO: Wait until a primary node is elected before starting the server with TLS
-	// TODO: Ensure only the primary can renew the TLS certificate
 	port := s.config.Port
-	tlsCertPath := os.Getenv("LITEBASE_TLS_CERT_PATH")
-	tlsKeyPath := os.Getenv("LITEBASE_TLS_KEY_PATH")
 
 	s.ServeMux = http.NewServeMux()
 
@@ -50,6 +50,43 @@ func (s *Server) Start(startHook func(*http.ServeMux), shutdownHook func()) {
 		ReadHeaderTimeout: 2 * time.Second,
 	}
 
+	publicCertificate, err := https.NewCertificateReloaderFromConfig(s.config)
+
+	if err != nil {
+		log.Fatalf("Failed to load the TLS certificate: %v", err)
+	}
+
+	clusterTLS, err := cluster.ClusterTLSFromConfig(s.config)
+
+	if err != nil {
+		log.Fatalf("Failed to load the cluster certificates: %v", err)
+	}
+
+	// With mTLS the server is always served over TLS. Other nodes are served
+	// the node certificate, clients are served the public certificate if
+	// one has been configured.
+	if clusterTLS != nil {
+		var getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+
+		if publicCertificate != nil {
+			getCertificate = publicCertificate.GetCertificate
+		}
+
+		https.Configure(s.HttpServer, s.config, clusterTLS.ServerConfig(getCertificate, https.NextProtos(s.config)))
+	} else if publicCertificate != nil {
+		https.Configure(s.HttpServer, s.config, https.ServerConfig(s.config, publicCertificate))
+	}
+
+	if s.HttpServer.TLSConfig != nil && s.config.TLSRedirectPort != "" {
+		s.redirectServer = https.NewRedirectServer(s.config, port)
+
+		go func() {
+			if err := s.redirectServer.ListenAndServe(); err != http.ErrServerClosed {
+				log.Fatalf("ListenAndServe(): %v", err)
+			}
+		}()
+	}
+
 	if startHook != nil {
 		startHook(s.ServeMux)
 	}
@@ -61,8 +98,8 @@ func (s *Server) Start(startHook func(*http.ServeMux), shutdownHook func()) {
 		defer close(serverDone)
 		var err error
 
-		if tlsCertPath != "" && tlsKeyPath != "" {
-			err = s.HttpServer.ListenAndServeTLS(tlsCertPath, tlsKeyPath)
+		if s.HttpServer.TLSConfig != nil {
+			err = s.HttpServer.ListenAndServeTLS("", "")
 		} else {
 			err = s.HttpServer.ListenAndServe()
 		}
@@ -105,6 +142,12 @@ func (s *Server) Shutdown(ctx context.Context) {
 
 	defer cancel()
 
+	if s.redirectServer != nil {
+		if err := s.redirectServer.Shutdown(ctx); err != nil {
+			log.Printf("HTTP redirect server Shutdown: %v", err)
+		}
+	}
+
 	if err := s.HttpServer.Shutdown(ctx); err != nil {
 		log.Printf("HTTP server Shutdown: %v", err)
 	}
@@ -0,0 +1,42 @@
+#include "function.h"
+#include <stddef.h>
+
+extern void go_scalar_function(sqlite3_context *ctx, int argc, sqlite3_value **argv);
+extern void go_scalar_function_destroy(void *handle);
+
+int function_create(sqlite3 *db, const char *name, int nArg, uintptr_t handle)
+{
+	return sqlite3_create_function_v2(
+		db,
+		name,
+		nArg,
+		SQLITE_UTF8 | SQLITE_DETERMINISTIC,
+		(void *)handle,
+		go_scalar_function,
+		NULL,
+		NULL,
+		go_scalar_function_destroy);
+}
+
+sqlite3_value *function_value(sqlite3_value **argv, int i)
+{
+	return argv[i];
+}
+
+// Values are copied by SQLite, and an empty value must not be NULL since
+// SQLite would return NULL instead.
+void function_result_blob(sqlite3_context *ctx, const void *value, int n)
+{
+	if (n == 0)
+		value = "";
+
+	sqlite3_result_blob(ctx, value, n, SQLITE_TRANSIENT);
+}
+
+void function_result_text(sqlite3_context *ctx, const char *value, int n)
+{
+	if (n == 0)
+		value = "";
+
+	sqlite3_result_text(ctx, value, n, SQLITE_TRANSIENT);
+}
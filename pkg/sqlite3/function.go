@@ -0,0 +1,113 @@
+package sqlite3
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+#include "./function.h"
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"runtime/cgo"
+	"unsafe"
+
+	"github.com/litebase/litebase/internal/utils"
+)
+
+// A ScalarFunction is a Go function that can be called from SQL. NULL
+// arguments are passed as columns of type ColumnTypeNull, and a nil column
+// is returned to SQLite as NULL.
+type ScalarFunction func(args []*Column) (*Column, error)
+
+// Register a deterministic scalar function on the connection.
+// https://www.sqlite.org/c3ref/create_function.html
+func (c *Connection) CreateFunction(name string, nArg int, function ScalarFunction) error {
+	cName, err := utils.SafeCString(name)
+
+	if err != nil {
+		return err
+	}
+
+	defer C.free(unsafe.Pointer(cName))
+
+	handle := cgo.NewHandle(function)
+
+	// SQLite calls the destructor, which deletes the handle, when the
+	// function could not be registered.
+	if rc := C.function_create(c.sqlite3, (*C.char)(cName), C.int(nArg), C.uintptr_t(handle)); rc != SQLITE_OK {
+		return errors.New(C.GoString(C.sqlite3_errstr(rc)))
+	}
+
+	return nil
+}
+
+//export go_scalar_function
+func go_scalar_function(ctx *C.sqlite3_context, argc C.int, argv **C.sqlite3_value) {
+	function := cgo.Handle(uintptr(C.sqlite3_user_data(ctx))).Value().(ScalarFunction)
+
+	args := make([]*Column, int(argc))
+
+	for i := range args {
+		value := C.function_value(argv, C.int(i))
+
+		switch C.sqlite3_value_type(value) {
+		case C.SQLITE_INTEGER:
+			args[i] = NewColumn(ColumnTypeInteger, binary.LittleEndian.AppendUint64(nil, uint64(C.sqlite3_value_int64(value))))
+		case C.SQLITE_FLOAT:
+			args[i] = NewColumn(ColumnTypeFloat, binary.LittleEndian.AppendUint64(nil, math.Float64bits(float64(C.sqlite3_value_double(value)))))
+		case C.SQLITE_TEXT:
+			args[i] = NewColumn(ColumnTypeText, C.GoBytes(unsafe.Pointer(C.sqlite3_value_text(value)), C.sqlite3_value_bytes(value)))
+		case C.SQLITE_BLOB:
+			args[i] = NewColumn(ColumnTypeBlob, C.GoBytes(C.sqlite3_value_blob(value), C.sqlite3_value_bytes(value)))
+		default:
+			args[i] = NewColumn(ColumnTypeNull, nil)
+		}
+	}
+
+	result, err := function(args)
+
+	if err != nil {
+		cMessage := C.CString(err.Error())
+		defer C.free(unsafe.Pointer(cMessage))
+
+		C.sqlite3_result_error(ctx, cMessage, -1)
+
+		return
+	}
+
+	if result == nil {
+		C.sqlite3_result_null(ctx)
+
+		return
+	}
+
+	switch result.ColumnType {
+	case ColumnTypeInteger:
+		C.sqlite3_result_int64(ctx, C.sqlite3_int64(result.Int64()))
+	case ColumnTypeFloat:
+		C.sqlite3_result_double(ctx, C.double(result.Float64()))
+	case ColumnTypeText:
+		C.function_result_text(ctx, (*C.char)(bytesPointer(result.ColumnValue)), C.int(len(result.ColumnValue)))
+	case ColumnTypeBlob:
+		C.function_result_blob(ctx, bytesPointer(result.ColumnValue), C.int(len(result.ColumnValue)))
+	default:
+		C.sqlite3_result_null(ctx)
+	}
+}
+
+// Return a pointer to the first byte of the value, or nil if it is empty.
+func bytesPointer(value []byte) unsafe.Pointer {
+	if len(value) == 0 {
+		return nil
+	}
+
+	return unsafe.Pointer(&value[0])
+}
+
+//export go_scalar_function_destroy
+func go_scalar_function_destroy(handle unsafe.Pointer) {
+	cgo.Handle(uintptr(handle)).Delete()
+}
@@ -0,0 +1,12 @@
+#include "sqlite3.h"
+#include <stdint.h>
+
+#ifndef FUNCTION_H
+#define FUNCTION_H
+
+int function_create(sqlite3 *db, const char *name, int nArg, uintptr_t handle);
+sqlite3_value *function_value(sqlite3_value **argv, int i);
+void function_result_blob(sqlite3_context *ctx, const void *value, int n);
+void function_result_text(sqlite3_context *ctx, const char *value, int n);
+
+#endif
@@ -0,0 +1,190 @@
+package sqlite3
+
+import (
+	"strings"
+)
+
+type TokenType int
+
+const (
+	TokenIllegal TokenType = iota
+	TokenComment
+	TokenIdentifier
+	TokenNumber
+	TokenParameter
+	TokenPunctuation
+	TokenQuotedIdentifier
+	TokenString
+	TokenWhitespace
+)
+
+// A Token is a lexical unit of an SQL statement.
+type Token struct {
+	Start int
+	Text  string
+	Type  TokenType
+}
+
+// Determine if the token is a bare or quoted identifier, keywords included.
+func (t Token) IsIdentifier() bool {
+	return t.Type == TokenIdentifier || t.Type == TokenQuotedIdentifier
+}
+
+// Determine if the token is the given keyword, ignoring case.
+func (t Token) IsKeyword(keyword string) bool {
+	return t.Type == TokenIdentifier && strings.EqualFold(t.Text, keyword)
+}
+
+// Determine if the token is the given punctuation.
+func (t Token) IsPunctuation(punctuation string) bool {
+	return t.Type == TokenPunctuation && t.Text == punctuation
+}
+
+// Determine if the token has no meaning to the statement.
+func (t Token) IsSpace() bool {
+	return t.Type == TokenWhitespace || t.Type == TokenComment
+}
+
+// Return the name of an identifier with the quotes removed.
+func (t Token) Value() string {
+	if t.Type != TokenQuotedIdentifier || len(t.Text) < 2 {
+		return t.Text
+	}
+
+	inner := t.Text[1 : len(t.Text)-1]
+
+	switch t.Text[0] {
+	case '"':
+		return strings.ReplaceAll(inner, `""`, `"`)
+	case '`':
+		return strings.ReplaceAll(inner, "``", "`")
+	}
+
+	return inner
+}
+
+// Split an SQL statement into tokens following the lexical rules of SQLite.
+// Unterminated strings and quoted identifiers are returned as a single
+// illegal token that runs to the end of the statement.
+func Tokenize(sql string) []Token {
+	tokens := []Token{}
+
+	for i := 0; i < len(sql); {
+		start := i
+		tokenType := TokenPunctuation
+		c := sql[i]
+
+		switch {
+		case isSpace(c):
+			for i < len(sql) && isSpace(sql[i]) {
+				i++
+			}
+
+			tokenType = TokenWhitespace
+		case c == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			if end := strings.IndexByte(sql[i:], '\n'); end >= 0 {
+				i += end + 1
+			} else {
+				i = len(sql)
+			}
+
+			tokenType = TokenComment
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			if end := strings.Index(sql[i+2:], "*/"); end >= 0 {
+				i += end + 4
+			} else {
+				i = len(sql)
+			}
+
+			tokenType = TokenComment
+		case (c == 'x' || c == 'X') && i+1 < len(sql) && sql[i+1] == '\'':
+			i, tokenType = scanQuoted(sql, i+1, '\'', TokenString)
+		case c == '\'':
+			i, tokenType = scanQuoted(sql, i, '\'', TokenString)
+		case c == '"' || c == '`':
+			i, tokenType = scanQuoted(sql, i, c, TokenQuotedIdentifier)
+		case c == '[':
+			if end := strings.IndexByte(sql[i:], ']'); end >= 0 {
+				i += end + 1
+				tokenType = TokenQuotedIdentifier
+			} else {
+				i = len(sql)
+				tokenType = TokenIllegal
+			}
+		case isDigit(c) || (c == '.' && i+1 < len(sql) && isDigit(sql[i+1])):
+			for i < len(sql) && (isIdentifierChar(sql[i]) || sql[i] == '.' ||
+				((sql[i] == '+' || sql[i] == '-') && (sql[i-1] == 'e' || sql[i-1] == 'E'))) {
+				i++
+			}
+
+			tokenType = TokenNumber
+		case c == '?':
+			i++
+
+			for i < len(sql) && isDigit(sql[i]) {
+				i++
+			}
+
+			tokenType = TokenParameter
+		case (c == ':' || c == '@' || c == '$') && i+1 < len(sql) && isIdentifierChar(sql[i+1]):
+			i++
+
+			for i < len(sql) && isIdentifierChar(sql[i]) {
+				i++
+			}
+
+			tokenType = TokenParameter
+		case isIdentifierStart(c):
+			for i < len(sql) && isIdentifierChar(sql[i]) {
+				i++
+			}
+
+			tokenType = TokenIdentifier
+		default:
+			i++
+		}
+
+		tokens = append(tokens, Token{
+			Start: start,
+			Text:  sql[start:i],
+			Type:  tokenType,
+		})
+	}
+
+	return tokens
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifierChar(c byte) bool {
+	return isIdentifierStart(c) || isDigit(c) || c == '$'
+}
+
+func isIdentifierStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' || c >= 0x80
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
+
+// Scan a quoted token where the quote is escaped by repeating it, returning
+// the position after the closing quote.
+func scanQuoted(sql string, i int, quote byte, tokenType TokenType) (int, TokenType) {
+	for i++; i < len(sql); i++ {
+		if sql[i] != quote {
+			continue
+		}
+
+		if i+1 < len(sql) && sql[i+1] == quote {
+			i++
+			continue
+		}
+
+		return i + 1, tokenType
+	}
+
+	return len(sql), TokenIllegal
+}
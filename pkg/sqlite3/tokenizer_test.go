@@ -0,0 +1,69 @@
+package sqlite3_test
+
+import (
+	"testing"
+
+	"github.com/litebase/litebase/pkg/sqlite3"
+)
+
+func TestTokenize(t *testing.T) {
+	tc := []struct {
+		sql    string
+		texts  []string
+		types  []sqlite3.TokenType
+		values []string
+	}{
+		{
+			sql:   "SELECT * FROM t",
+			texts: []string{"SELECT", " ", "*", " ", "FROM", " ", "t"},
+			types: []sqlite3.TokenType{
+				sqlite3.TokenIdentifier, sqlite3.TokenWhitespace, sqlite3.TokenPunctuation, sqlite3.TokenWhitespace,
+				sqlite3.TokenIdentifier, sqlite3.TokenWhitespace, sqlite3.TokenIdentifier,
+			},
+		},
+		{
+			sql:    `"a""b" [c d] ` + "`e`",
+			texts:  []string{`"a""b"`, " ", "[c d]", " ", "`e`"},
+			values: []string{`a"b`, " ", "c d", " ", "e"},
+		},
+		{
+			sql:   "'it''s' x'00' 1.5e-3 -- done",
+			texts: []string{"'it''s'", " ", "x'00'", " ", "1.5e-3", " ", "-- done"},
+			types: []sqlite3.TokenType{
+				sqlite3.TokenString, sqlite3.TokenWhitespace, sqlite3.TokenString, sqlite3.TokenWhitespace,
+				sqlite3.TokenNumber, sqlite3.TokenWhitespace, sqlite3.TokenComment,
+			},
+		},
+		{
+			sql:   "? ?1 :name @p $v :claim.tenant",
+			texts: []string{"?", " ", "?1", " ", ":name", " ", "@p", " ", "$v", " ", ":claim", ".", "tenant"},
+		},
+		{
+			sql:   "'unterminated",
+			texts: []string{"'unterminated"},
+			types: []sqlite3.TokenType{sqlite3.TokenIllegal},
+		},
+	}
+
+	for _, testCase := range tc {
+		tokens := sqlite3.Tokenize(testCase.sql)
+
+		if len(tokens) != len(testCase.texts) {
+			t.Fatalf("Expected %d tokens for %q, got %d", len(testCase.texts), testCase.sql, len(tokens))
+		}
+
+		for i, token := range tokens {
+			if token.Text != testCase.texts[i] {
+				t.Errorf("Expected token %d of %q to be %q, got %q", i, testCase.sql, testCase.texts[i], token.Text)
+			}
+
+			if testCase.types != nil && token.Type != testCase.types[i] {
+				t.Errorf("Expected token %d of %q to have type %d, got %d", i, testCase.sql, testCase.types[i], token.Type)
+			}
+
+			if testCase.values != nil && token.Value() != testCase.values[i] {
+				t.Errorf("Expected token %d of %q to have value %q, got %q", i, testCase.sql, testCase.values[i], token.Value())
+			}
+		}
+	}
+}
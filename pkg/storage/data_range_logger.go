@@ -57,9 +57,14 @@ func (drl *DataRangeLogger) All() ([]DataRangeLogEntry, error) {
 		return nil, err
 	}
 
+	return parseDataRangeLogEntries(f)
+}
+
+// Parse the entries of a data range log, skipping lines that are malformed.
+func parseDataRangeLogEntries(r io.Reader) ([]DataRangeLogEntry, error) {
 	var entries []DataRangeLogEntry
 
-	scanner := bufio.NewScanner(f)
+	scanner := bufio.NewScanner(r)
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -2,12 +2,17 @@ package storage
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"log"
 	"log/slog"
 	"maps"
+	"os"
 	"slices"
 	"sync"
+
+	"github.com/litebase/litebase/internal/utils"
+	"github.com/litebase/litebase/pkg/file"
 )
 
 type DataRangeManager struct {
@@ -17,6 +22,7 @@ type DataRangeManager struct {
 	mutex      *sync.RWMutex
 	ranges     map[int64]map[int64]*Range
 	rangeUsage map[int64]int64
+	readRanges *sync.Map
 
 	lastRangeMap map[int64]int64
 }
@@ -28,6 +34,7 @@ func NewDataRangeManager(dfs *DurableDatabaseFileSystem) *DataRangeManager {
 		mutex:        &sync.RWMutex{},
 		ranges:       make(map[int64]map[int64]*Range),
 		rangeUsage:   make(map[int64]int64),
+		readRanges:   &sync.Map{},
 		lastRangeMap: make(map[int64]int64),
 	}
 
@@ -63,6 +70,7 @@ func (drm *DataRangeManager) Close() error {
 
 	drm.ranges = make(map[int64]map[int64]*Range)
 	drm.rangeUsage = make(map[int64]int64)
+	drm.readRanges.Clear()
 
 	return nil
 }
@@ -340,6 +348,57 @@ func (drm *DataRangeManager) RangeUsage() map[int64]int64 {
 	return usageCopy
 }
 
+// ReadReferencedRanges returns the file names of every range version of a
+// branch that is referenced by its data range index or is waiting in its range
+// log to be garbage collected. The files are read directly from storage so the
+// file system of the branch does not have to be opened. An error satisfying
+// os.IsNotExist is returned when the branch has no range index.
+func ReadReferencedRanges(fileSystem *FileSystem, databaseId, branchId string) (map[string]struct{}, error) {
+	directory := file.GetDatabaseFileDir(databaseId, branchId)
+
+	index, err := fileSystem.ReadFile(directory + "_RANGE_INDEX")
+
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := make(map[string]struct{})
+
+	for offset := 0; offset+8 <= len(index); offset += 8 {
+		version, err := utils.SafeUint64ToInt64(binary.LittleEndian.Uint64(index[offset : offset+8]))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if version == 0 {
+			continue
+		}
+
+		entry := DataRangeIndexEntry{Number: int64(offset/8) + 1, Version: version}
+
+		referenced[entry.Name()] = struct{}{}
+	}
+
+	rangeLog, err := fileSystem.ReadFile(directory + "_RANGE_LOG")
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	logEntries, err := parseDataRangeLogEntries(bytes.NewReader(rangeLog))
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range logEntries {
+		referenced[entry.ID] = struct{}{}
+	}
+
+	return referenced, nil
+}
+
 // Release marks a range as no longer being used at the specified timestamp.
 func (drm *DataRangeManager) Release(timestamp int64) {
 	drm.mutex.Lock()
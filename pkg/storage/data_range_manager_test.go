@@ -2,7 +2,9 @@ package storage_test
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
+	"slices"
 	"testing"
 	"time"
 
@@ -178,6 +180,140 @@ func TestDataRangeManager_GetOldestTimestamp(t *testing.T) {
 	})
 }
 
+func TestDataRangeManager_Prefetch(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		mock := test.MockDatabase(app)
+
+		dfs := app.DatabaseManager.Resources(mock.DatabaseID, mock.DatabaseBranchID).FileSystem()
+
+		drm := storage.NewDataRangeManager(dfs)
+
+		for rangeNumber := int64(1); rangeNumber <= 3; rangeNumber++ {
+			_, err := drm.Get(rangeNumber, time.Now().UTC().UnixNano())
+
+			if err != nil {
+				t.Fatalf("Expected Get to succeed, got error: %v", err)
+			}
+		}
+
+		err := drm.Close()
+
+		if err != nil {
+			t.Fatalf("Expected Close to succeed, got error: %v", err)
+		}
+
+		drm = storage.NewDataRangeManager(dfs)
+
+		if len(drm.OpenRanges()) != 0 {
+			t.Fatalf("Expected no open ranges, got %d", len(drm.OpenRanges()))
+		}
+
+		err = drm.Prefetch(context.Background(), []int64{1, 3, 10}, 2)
+
+		if err != nil {
+			t.Fatalf("Expected Prefetch to succeed, got error: %v", err)
+		}
+
+		if !slices.Equal(drm.OpenRanges(), []int64{1, 3}) {
+			t.Errorf("Expected ranges 1 and 3 to be open, got %v", drm.OpenRanges())
+		}
+
+		err = drm.Prefetch(context.Background(), nil, 2)
+
+		if err != nil {
+			t.Fatalf("Expected Prefetch to succeed, got error: %v", err)
+		}
+
+		if !slices.Equal(drm.OpenRanges(), []int64{1, 2, 3}) {
+			t.Errorf("Expected all ranges to be open, got %v", drm.OpenRanges())
+		}
+	})
+}
+
+func TestDataRangeManager_RecentlyUsedRanges(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		mock := test.MockDatabase(app)
+
+		drm := storage.NewDataRangeManager(
+			app.DatabaseManager.Resources(mock.DatabaseID, mock.DatabaseBranchID).FileSystem(),
+		)
+
+		rangeNumbers, err := drm.RecentlyUsedRanges()
+
+		if err != nil {
+			t.Fatalf("Expected RecentlyUsedRanges to succeed, got error: %v", err)
+		}
+
+		if len(rangeNumbers) != 0 {
+			t.Fatalf("Expected no recently used ranges, got %v", rangeNumbers)
+		}
+
+		for _, rangeNumber := range []int64{4, 3, 2} {
+			_, err := drm.Get(rangeNumber, time.Now().UTC().UnixNano())
+
+			if err != nil {
+				t.Fatalf("Expected Get to succeed, got error: %v", err)
+			}
+		}
+
+		// Ranges that are open but were never read from are not recently used
+		drm.RecordRead(4)
+		drm.RecordRead(2)
+		drm.RecordRead(4)
+
+		err = drm.SaveRecentlyUsedRanges()
+
+		if err != nil {
+			t.Fatalf("Expected SaveRecentlyUsedRanges to succeed, got error: %v", err)
+		}
+
+		rangeNumbers, err = drm.RecentlyUsedRanges()
+
+		if err != nil {
+			t.Fatalf("Expected RecentlyUsedRanges to succeed, got error: %v", err)
+		}
+
+		if !slices.Equal(rangeNumbers, []int64{2, 4}) {
+			t.Errorf("Expected recently used ranges to be [2 4], got %v", rangeNumbers)
+		}
+
+		// Prefetching the recently used ranges does not make them recently used
+		err = drm.Close()
+
+		if err != nil {
+			t.Fatalf("Expected Close to succeed, got error: %v", err)
+		}
+
+		drm = storage.NewDataRangeManager(
+			app.DatabaseManager.Resources(mock.DatabaseID, mock.DatabaseBranchID).FileSystem(),
+		)
+
+		err = drm.Prefetch(context.Background(), nil, 2)
+
+		if err != nil {
+			t.Fatalf("Expected Prefetch to succeed, got error: %v", err)
+		}
+
+		drm.RecordRead(3)
+
+		err = drm.SaveRecentlyUsedRanges()
+
+		if err != nil {
+			t.Fatalf("Expected SaveRecentlyUsedRanges to succeed, got error: %v", err)
+		}
+
+		rangeNumbers, err = drm.RecentlyUsedRanges()
+
+		if err != nil {
+			t.Fatalf("Expected RecentlyUsedRanges to succeed, got error: %v", err)
+		}
+
+		if !slices.Equal(rangeNumbers, []int64{3}) {
+			t.Errorf("Expected recently used ranges to be [3], got %v", rangeNumbers)
+		}
+	})
+}
+
 func TestDataRangeManager_RangeUsage(t *testing.T) {
 	drm := storage.NewDataRangeManager(nil)
 
@@ -0,0 +1,204 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/litebase/litebase/pkg/file"
+)
+
+// Return the numbers of the ranges that are currently open, in ascending order.
+func (drm *DataRangeManager) OpenRanges() []int64 {
+	drm.mutex.RLock()
+	defer drm.mutex.RUnlock()
+
+	rangeNumbers := make([]int64, 0, len(drm.ranges))
+
+	for rangeNumber, rangeVersions := range drm.ranges {
+		if len(rangeVersions) > 0 {
+			rangeNumbers = append(rangeNumbers, rangeNumber)
+		}
+	}
+
+	slices.Sort(rangeNumbers)
+
+	return rangeNumbers
+}
+
+// Prefetch opens the latest version of each of the given ranges so that their
+// files are loaded on the high tier of the file system ahead of the first
+// read. When no range numbers are given, every range in the index is loaded.
+// At most concurrency ranges are opened at the same time. Ranges that are not
+// in the index or are already open are skipped.
+func (drm *DataRangeManager) Prefetch(ctx context.Context, rangeNumbers []int64, concurrency int) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	// The index file is shared with readers, so hold the lock while reading it
+	drm.mutex.Lock()
+	entries, err := drm.Index.All()
+	drm.mutex.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	if len(rangeNumbers) == 0 {
+		for rangeNumber := range entries {
+			rangeNumbers = append(rangeNumbers, rangeNumber)
+		}
+
+		slices.Sort(rangeNumbers)
+	}
+
+	semaphore := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+
+	for _, rangeNumber := range rangeNumbers {
+		entry, ok := entries[rangeNumber]
+
+		if !ok || entry.Version == 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		case semaphore <- struct{}{}:
+		}
+
+		wg.Add(1)
+
+		go func(entry DataRangeIndexEntry) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			err := drm.prefetchRange(entry)
+
+			if err != nil {
+				slog.Debug("Error prefetching range", "range", entry.Name(), "error", err)
+			}
+		}(entry)
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+// Open a single range and add it to the open ranges. The range file is opened
+// without holding the lock so multiple ranges can be loaded concurrently.
+func (drm *DataRangeManager) prefetchRange(entry DataRangeIndexEntry) error {
+	drm.mutex.RLock()
+	_, ok := drm.ranges[entry.Number][entry.Version]
+	drm.mutex.RUnlock()
+
+	if ok {
+		return nil
+	}
+
+	r, err := NewRange(
+		drm.dfs.databaseId,
+		drm.dfs.branchId,
+		drm.dfs.tieredFS,
+		entry.Number,
+		drm.dfs.pageSize,
+		entry.Version,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	drm.mutex.Lock()
+	defer drm.mutex.Unlock()
+
+	if _, ok := drm.ranges[entry.Number][entry.Version]; ok {
+		return r.Close()
+	}
+
+	if _, ok := drm.ranges[entry.Number]; !ok {
+		drm.ranges[entry.Number] = make(map[int64]*Range)
+	}
+
+	drm.ranges[entry.Number][entry.Version] = r
+
+	return nil
+}
+
+// Record that a page was read from a range file. Only ranges that pages were
+// read from are saved as recently used, so ranges that were only opened by
+// the prefetcher are not prefetched again.
+func (drm *DataRangeManager) RecordRead(rangeNumber int64) {
+	if _, ok := drm.readRanges.Load(rangeNumber); !ok {
+		drm.readRanges.Store(rangeNumber, struct{}{})
+	}
+}
+
+// Return the numbers of the ranges that pages were read from, in ascending
+// order.
+func (drm *DataRangeManager) ReadRanges() []int64 {
+	rangeNumbers := []int64{}
+
+	drm.readRanges.Range(func(key, _ any) bool {
+		rangeNumbers = append(rangeNumbers, key.(int64))
+		return true
+	})
+
+	slices.Sort(rangeNumbers)
+
+	return rangeNumbers
+}
+
+// Return the range numbers that pages were read from before the recently
+// used ranges were last saved.
+func (drm *DataRangeManager) RecentlyUsedRanges() ([]int64, error) {
+	data, err := drm.dfs.tieredFS.ReadFile(drm.recentlyUsedPath())
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []int64{}, nil
+		}
+
+		return nil, err
+	}
+
+	rangeNumbers := make([]int64, 0, len(data)/8)
+
+	for i := 0; i+8 <= len(data); i += 8 {
+		rangeNumbers = append(rangeNumbers, int64(binary.LittleEndian.Uint64(data[i:i+8])))
+	}
+
+	return rangeNumbers, nil
+}
+
+// Return the path of the file that stores the recently used ranges.
+func (drm *DataRangeManager) recentlyUsedPath() string {
+	return fmt.Sprintf("%s_RANGE_RECENT", file.GetDatabaseFileDir(drm.dfs.databaseId, drm.dfs.branchId))
+}
+
+// Save the numbers of the ranges that pages were read from so they can be
+// prefetched the next time the database is opened. The previously saved
+// ranges are kept when no pages were read.
+func (drm *DataRangeManager) SaveRecentlyUsedRanges() error {
+	rangeNumbers := drm.ReadRanges()
+
+	if len(rangeNumbers) == 0 {
+		return nil
+	}
+
+	data := make([]byte, 8*len(rangeNumbers))
+
+	for i, rangeNumber := range rangeNumbers {
+		binary.LittleEndian.PutUint64(data[i*8:], uint64(rangeNumber))
+	}
+
+	return drm.dfs.tieredFS.WriteFile(drm.recentlyUsedPath(), data, 0600)
+}
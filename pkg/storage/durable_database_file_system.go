@@ -2,9 +2,11 @@ package storage
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"log"
 	"log/slog"
+	"os"
 	"sync"
 	"time"
 
@@ -25,6 +27,7 @@ type DurableDatabaseFileSystem struct {
 	mutex        *sync.RWMutex
 	path         string
 	PageLogger   *PageLogger
+	pageHook     func(pageNumber, timestamp int64, data []byte)
 	pageSize     int64
 	writeHook    func(offset int64, data []byte)
 }
@@ -136,6 +139,12 @@ func (dfs *DurableDatabaseFileSystem) ForceCompact() error {
 	return dfs.PageLogger.ForceCompact(dfs)
 }
 
+// Prefetch the given ranges, or every range when none are given, so their
+// files are loaded on the high tier of the file system before they are read.
+func (dfs *DurableDatabaseFileSystem) Prefetch(ctx context.Context, rangeNumbers []int64, concurrency int) error {
+	return dfs.RangeManager.Prefetch(ctx, rangeNumbers, concurrency)
+}
+
 // GetRangeFile returns the range file for the given range number.
 func (dfs *DurableDatabaseFileSystem) GetRangeFile(rangeNumber int64) (*Range, error) {
 	r, err := dfs.RangeManager.Get(rangeNumber, time.Now().UTC().UnixNano())
@@ -234,17 +243,18 @@ func (dfs *DurableDatabaseFileSystem) ReadAt(walTimestamp, transactionalTimestam
 		return len(data), nil
 	}
 
+	rangeNumber := file.PageRange(pageNumber, RangeMaxPages)
+
 	// Get the range file for the page using the range manager
-	rangeFile, err := dfs.RangeManager.Get(
-		file.PageRange(pageNumber, RangeMaxPages),
-		transactionalTimestamp,
-	)
+	rangeFile, err := dfs.RangeManager.Get(rangeNumber, transactionalTimestamp)
 
 	if err != nil {
 		log.Println("Error getting range file", err)
 		return 0, err
 	}
 
+	dfs.RangeManager.RecordRead(rangeNumber)
+
 	n, err := rangeFile.ReadAt(pageNumber, data)
 
 	if err != nil {
@@ -262,6 +272,14 @@ func (dfs *DurableDatabaseFileSystem) Release(timestamp int64) {
 	dfs.RangeManager.Release(timestamp)
 }
 
+// Set a hook that is called after a page version has been written to the
+// page logs.
+func (dfs *DurableDatabaseFileSystem) SetPageHook(hook func(pageNumber, timestamp int64, data []byte)) *DurableDatabaseFileSystem {
+	dfs.pageHook = hook
+
+	return dfs
+}
+
 func (dfs *DurableDatabaseFileSystem) SetWriteHook(hook func(offset int64, data []byte)) *DurableDatabaseFileSystem {
 	dfs.writeHook = hook
 
@@ -276,7 +294,13 @@ func (dfs *DurableDatabaseFileSystem) Shutdown() error {
 	dfs.mutex.Lock()
 	defer dfs.mutex.Unlock()
 
-	err := dfs.RangeManager.Close()
+	err := dfs.RangeManager.SaveRecentlyUsedRanges()
+
+	if err != nil && !os.IsNotExist(err) {
+		slog.Warn("Error saving recently used ranges", "error", err)
+	}
+
+	err = dfs.RangeManager.Close()
 
 	if err != nil {
 		slog.Error("Error closing range manager", "error", err)
@@ -443,6 +467,10 @@ func (dfs *DurableDatabaseFileSystem) WriteAt(walTimestamp, transactionalTimesta
 		return 0, err
 	}
 
+	if dfs.pageHook != nil {
+		dfs.pageHook(pageNumber, walTimestamp, data)
+	}
+
 	// Get the range file for the page
 	// rangeFile, err := dfs.GetRangeFile(file.PageRange(pageNumber, RangeMaxPages))
 
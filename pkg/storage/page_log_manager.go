@@ -20,6 +20,7 @@ type PageLogManagerConfig func(*PageLogManager)
 // compaction tasks for page logs. There should only be one PageLogManager per
 // to avoid duplicate processing.
 type PageLogManager struct {
+	appendFn           func(databaseId, branchId string, size int64)
 	compacting         bool
 	compactionFn       func()
 	CompactionInterval time.Duration
@@ -89,6 +90,7 @@ func (plm *PageLogManager) Get(
 		return nil
 	}
 
+	logger.appendFn = plm.appendFn
 	plm.loggers[key] = logger
 
 	return plm.loggers[key]
@@ -153,6 +155,17 @@ func (plm *PageLogManager) run() {
 	}
 }
 
+// Set a function to be called with the number of bytes appended to the page
+// logs of a database branch.
+func (plm *PageLogManager) SetAppendFn(
+	fn func(databaseId, branchId string, size int64),
+) {
+	plm.mutex.Lock()
+	defer plm.mutex.Unlock()
+
+	plm.appendFn = fn
+}
+
 // Set a function to be called for compaction tasks.
 func (plm *PageLogManager) SetCompactionFn(
 	fn func(),
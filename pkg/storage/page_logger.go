@@ -29,6 +29,7 @@ type PageNumber int64
 type PageVersion int64
 
 type PageLogger struct {
+	appendFn        func(databaseId, branchId string, size int64)
 	BranchID        string
 	CompactedAt     time.Time
 	compactionMutex *sync.Mutex
@@ -265,6 +266,37 @@ func (pl *PageLogger) ForceCompact(
 	})
 }
 
+// ReadIndexedPageLogs returns the file names of the page logs of a branch that
+// are referenced by its page logger index. The index is read directly from
+// storage so the page logger of the branch does not have to be opened. An
+// error satisfying os.IsNotExist is returned when the branch has no index.
+func ReadIndexedPageLogs(networkFS *FileSystem, databaseId, branchId string) (map[string]struct{}, error) {
+	path := fmt.Sprintf("%slogs/page/PAGE_LOGGER_INDEX", file.GetDatabaseFileBaseDir(databaseId, branchId))
+
+	// Opening the index creates it, so make sure it exists first.
+	if _, err := networkFS.Stat(path); err != nil {
+		return nil, err
+	}
+
+	pli, err := NewPageLoggerIndex(networkFS, path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer pli.Close()
+
+	indexed := make(map[string]struct{})
+
+	for pageGroup, pageGroupVersions := range pli.pageGroups {
+		for pageGroupVersion := range pageGroupVersions {
+			indexed[fmt.Sprintf("PAGE_LOG_%d_%d", pageGroup, pageGroupVersion)] = struct{}{}
+		}
+	}
+
+	return indexed, nil
+}
+
 // Get the log group number for a given page number. This is used to determine
 // which page log a page belongs to. Page logs are sharded by groups of pages to
 // limit the number of open file handles and improve performance.
@@ -611,6 +643,10 @@ func (pl *PageLogger) Write(
 		return 0, err
 	}
 
+	if pl.appendFn != nil {
+		pl.appendFn(pl.DatabaseID, pl.BranchID, int64(len(data)))
+	}
+
 	pl.writtenAt = time.Now().UTC()
 
 	return len(data), nil
@@ -6,8 +6,10 @@ import (
 	"io"
 	"io/fs"
 	"log/slog"
+	"math"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	internalStorage "github.com/litebase/litebase/internal/storage"
@@ -18,6 +20,17 @@ import (
 // stored on a shared file system and eventually stored durably to
 // another file system, typically object storage.
 type TieredFile struct {
+	// AccessCount is the number of times the File has been accessed while it
+	// has been loaded on the high tier.
+	AccessCount uint64
+
+	// The time of the last access and the access frequency at that time. The
+	// frequency decays over time so that files that were frequently accessed
+	// long ago are eventually released by the LFU eviction policy. These are
+	// guarded by the mutex of the TieredFileSystemDriver, like the file order.
+	accessedAt      time.Time
+	accessFrequency float64
+
 	// Closed is a boolean value that determines if the File has been Closed
 	// by local storage. If the File has been Closed, the File will be marked
 	// for release, which means the File will be removed from local storage
@@ -67,6 +80,9 @@ type TieredFile struct {
 	// Descriptors can still be "open" but will need to reopen the file when accessed.
 	Released bool
 
+	// The size of the File on the high tier file system in bytes.
+	size atomic.Int64
+
 	// Mutex that needs to be checked when flushing the file to durable storage
 	// to prevent multiple goroutines from flushing the file at the same time.
 	syncMutex *sync.Mutex
@@ -223,6 +239,13 @@ func (f *TieredFile) HasOpenDescriptors() bool {
 	return len(f.descriptors) > 0
 }
 
+// Grow the tracked size of the File if a write extended past its current end.
+func (f *TieredFile) growSize(end int64) {
+	if end > f.size.Load() {
+		f.setSize(end)
+	}
+}
+
 // Indicate that the file has been updated so that they TieredFileSystemDriver
 // knows to write the file to durable storage.
 func (f *TieredFile) MarkUpdated() {
@@ -259,7 +282,7 @@ func (f *TieredFile) Read(b []byte) (n int, err error) {
 		}
 	}
 
-	f.TieredFileSystemDriver.FileOrder.MoveToBack(f.Element)
+	f.touch()
 
 	n, err = f.File.Read(b)
 
@@ -284,7 +307,7 @@ func (f *TieredFile) ReadAt(p []byte, off int64) (n int, err error) {
 			}
 		}
 
-		f.TieredFileSystemDriver.FileOrder.MoveToBack(f.Element)
+		f.touch()
 
 		n, err = f.File.ReadAt(p, off)
 
@@ -335,6 +358,17 @@ func (f *TieredFile) shouldBeWrittenToDurableStorage() bool {
 		(time.Since(f.WrittenAt) >= f.TieredFileSystemDriver.WriteInterval)
 }
 
+// Set the tracked size of the File and update the high tier usage of the
+// TieredFileSystemDriver by the difference.
+func (f *TieredFile) setSize(size int64) {
+	f.TieredFileSystemDriver.highTierBytes.Add(size - f.size.Swap(size))
+}
+
+// Size returns the size of the File on the high tier file system in bytes.
+func (f *TieredFile) Size() int64 {
+	return f.size.Load()
+}
+
 // Stat returns the FileInfo structure describing the File. If the File is
 // nil, the File will be opened and the FileInfo structure will be returned.
 func (f *TieredFile) Stat() (fs.FileInfo, error) {
@@ -349,7 +383,7 @@ func (f *TieredFile) Stat() (fs.FileInfo, error) {
 		}
 	}
 
-	f.TieredFileSystemDriver.FileOrder.MoveToBack(f.Element)
+	f.touch()
 
 	return f.File.Stat()
 }
@@ -385,7 +419,7 @@ func (f *TieredFile) Sync() error {
 		}
 	}
 
-	f.TieredFileSystemDriver.FileOrder.MoveToBack(f.Element)
+	f.touch()
 
 	f.UpdatedAt = time.Now().UTC()
 
@@ -414,6 +448,8 @@ func (f *TieredFile) Truncate(size int64) error {
 			return err
 		}
 
+		f.setSize(size)
+
 		return nil
 	})
 
@@ -466,6 +502,7 @@ func (f *TieredFile) Write(p []byte) (n int, err error) {
 	if err == nil {
 		f.MarkUpdated()
 		f.position += int64(n)
+		f.growSize(f.position)
 	}
 
 	return n, err
@@ -490,6 +527,7 @@ func (f *TieredFile) WriteAt(p []byte, off int64) (n int, err error) {
 
 		if err == nil {
 			f.MarkUpdated()
+			f.growSize(off + int64(n))
 
 			if off+int64(n) > f.position {
 				f.position = off + int64(n)
@@ -502,6 +540,34 @@ func (f *TieredFile) WriteAt(p []byte, off int64) (n int, err error) {
 	return n, err
 }
 
+// AccessFrequency returns the access frequency of the File at the given time.
+// Each access adds one to the frequency, which is halved every frequency half
+// life of the TieredFileSystemDriver. The mutex of the TieredFileSystemDriver
+// must be held.
+func (f *TieredFile) AccessFrequency(now time.Time) float64 {
+	halfLife := f.TieredFileSystemDriver.FrequencyHalfLife
+
+	if halfLife <= 0 || f.accessedAt.IsZero() {
+		return f.accessFrequency
+	}
+
+	return f.accessFrequency * math.Exp2(-float64(now.Sub(f.accessedAt))/float64(halfLife))
+}
+
+// Record an access to the File by moving it to the back of the file order
+// list and incrementing its access count and frequency.
+func (f *TieredFile) touch() {
+	f.TieredFileSystemDriver.mutex.Lock()
+	defer f.TieredFileSystemDriver.mutex.Unlock()
+
+	now := time.Now()
+
+	f.AccessCount++
+	f.accessFrequency = f.AccessFrequency(now) + 1
+	f.accessedAt = now
+	f.TieredFileSystemDriver.FileOrder.MoveToBack(f.Element)
+}
+
 // WriteTo writes the contents of the File to w. It returns the number of
 // bytes written and any error encountered.
 func (f *TieredFile) WriteTo(w io.Writer) (n int64, err error) {
@@ -516,7 +582,7 @@ func (f *TieredFile) WriteTo(w io.Writer) (n int64, err error) {
 		}
 	}
 
-	defer f.TieredFileSystemDriver.FileOrder.MoveToBack(f.Element)
+	defer f.touch()
 
 	n, err = f.File.WriteTo(w)
 
@@ -547,6 +613,7 @@ func (f *TieredFile) WriteString(s string) (n int, err error) {
 		if err == nil {
 			f.MarkUpdated()
 			f.position += int64(n)
+			f.growSize(f.position)
 		}
 
 		return err
@@ -599,6 +666,10 @@ func (f *TieredFile) reopenFile() error {
 	f.File = file
 	f.Closed = false
 
+	if info, err := file.Stat(); err == nil {
+		f.setSize(info.Size())
+	}
+
 	// Restore the file position
 	if f.position > 0 {
 		actualPos, err := f.File.Seek(f.position, io.SeekStart)
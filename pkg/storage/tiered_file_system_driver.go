@@ -2,17 +2,20 @@ package storage
 
 import (
 	"bytes"
+	"cmp"
 	"container/list"
 	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"io/fs"
 	"log"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -31,13 +34,30 @@ const (
 	DefaultWriteInterval         = 10 * time.Second
 	TieredFileTTL                = 1 * time.Hour
 	TieredFileSystemMaxOpenFiles = 10000
+
+	TieredFileSystemEvictionPolicyLFU = "lfu"
+	TieredFileSystemEvictionPolicyLRU = "lru"
+
+	// The time after which the access frequency of a file used by the LFU
+	// eviction policy is halved.
+	TieredFileFrequencyHalfLife = 10 * time.Minute
 )
 
 var (
-	ErrTieredFileCannotBeReleased = errors.New("tiered file cannot be released")
-	ErrNoTieredFilesToRemove      = errors.New("no tiered files to remove")
+	ErrTieredFileCannotBeReleased            = errors.New("tiered file cannot be released")
+	ErrNoTieredFilesToRemove                 = errors.New("no tiered files to remove")
+	ErrTieredFileSystemEvictionPolicyInvalid = errors.New("the tiered storage eviction policy must be either 'lru' or 'lfu'")
 )
 
+// Check that the eviction policy is supported by the TieredFileSystemDriver.
+func ValidateTieredFileSystemEvictionPolicy(policy string) error {
+	if policy != TieredFileSystemEvictionPolicyLRU && policy != TieredFileSystemEvictionPolicyLFU {
+		return fmt.Errorf("%w, got '%s'", ErrTieredFileSystemEvictionPolicyInvalid, policy)
+	}
+
+	return nil
+}
+
 // Data in this driver is stored on disk in a high tier then eventually pushed
 // up down to a low tier. The high tier is typically a local file system that
 // transiently stores files while the low tier durably stores files with
@@ -47,13 +67,18 @@ type TieredFileSystemDriver struct {
 	buffers                  sync.Pool
 	CanSyncDirtyFiles        func() bool // Function to check if dirty files can be synced
 	context                  context.Context
+	EvictionPolicy           string
 	logger                   *TieredFileSystemLogger
 	lowTierFileSystemDriver  FileSystemDriver
 	FileOrder                *list.List
 	Files                    map[string]*TieredFile
+	FrequencyHalfLife        time.Duration
+	highTierBytes            atomic.Int64
 	highTierFileSystemDriver FileSystemDriver
+	MaxBytes                 int64
 	MaxFilesOpened           int
 	mutex                    *sync.Mutex
+	pinnedPrefixes           map[string]struct{}
 	releasingOldestFile      atomic.Bool
 	shuttingDown             bool
 	WriteInterval            time.Duration
@@ -85,13 +110,16 @@ func NewTieredFileSystemDriver(
 			},
 		},
 		context:                  context,
+		EvictionPolicy:           TieredFileSystemEvictionPolicyLRU,
 		FileOrder:                list.New(),
 		Files:                    map[string]*TieredFile{},
+		FrequencyHalfLife:        TieredFileFrequencyHalfLife,
 		highTierFileSystemDriver: highTierFileSystemDriver,
 		lowTierFileSystemDriver:  lowTierFileSystemDriver,
 		logger:                   nil,
 		MaxFilesOpened:           TieredFileSystemMaxOpenFiles,
 		mutex:                    &sync.Mutex{},
+		pinnedPrefixes:           map[string]struct{}{},
 		WriteInterval:            DefaultWriteInterval,
 	}
 
@@ -145,6 +173,26 @@ func (fsd *TieredFileSystemDriver) addFile(path string, file internalStorage.Fil
 		}
 	}
 
+	var size int64
+
+	if info, err := file.Stat(); err == nil {
+		size = info.Size()
+	}
+
+	// Release files until the new file fits within the high tier quota
+	if fsd.MaxBytes > 0 && fsd.highTierBytes.Load()+size > fsd.MaxBytes {
+		err := fsd.releaseFilesInternal(fsd.highTierBytes.Load() + size - fsd.MaxBytes)
+
+		if err != nil {
+			slog.Warn("Unable to release files to satisfy the high tier quota", "error", err, "used_bytes", fsd.highTierBytes.Load(), "max_bytes", fsd.MaxBytes)
+		}
+	}
+
+	// Stop accounting for a file that is being replaced
+	if existing, ok := fsd.Files[path]; ok {
+		existing.setSize(0)
+	}
+
 	fsd.Files[path] = NewTieredFile(
 		fsd,
 		path,
@@ -152,6 +200,8 @@ func (fsd *TieredFileSystemDriver) addFile(path string, file internalStorage.Fil
 		flag,
 	)
 
+	fsd.Files[path].setSize(size)
+
 	element := fsd.FileOrder.PushBack(fsd.Files[path])
 	fsd.Files[path].Element = element
 
@@ -694,6 +744,7 @@ func (fsd *TieredFileSystemDriver) releaseFile(file *TieredFile) error {
 			}
 
 			file.File = nil
+			file.setSize(0)
 
 			return nil
 		})
@@ -751,6 +802,7 @@ func (fsd *TieredFileSystemDriver) RemoveAll(path string) error {
 	for key, file := range fsd.Files {
 		if key == path || (len(key) > len(path) && key[:len(path)] == path) {
 			filesToClose = append(filesToClose, file)
+			file.setSize(0)
 			delete(fsd.Files, key)
 		}
 	}
@@ -791,33 +843,85 @@ func (fsd *TieredFileSystemDriver) ReleaseOldestFile() error {
 // release the oldest file without blocking other operations.
 func (fsd *TieredFileSystemDriver) releaseOldestFileInternal() error {
 	// Assumes lock is already held
-	element := fsd.FileOrder.Front()
+	return fsd.releaseFilesInternal(0)
+}
+
+// releaseFilesInternal releases files from the high tier until at least the
+// given number of bytes has been freed, or a single file if the size is zero.
+// The files are selected with a single pass over the file order list. Assumes
+// the lock is already held.
+func (fsd *TieredFileSystemDriver) releaseFilesInternal(size int64) error {
+	elements := fsd.evictionCandidates(size)
 
-	if element == nil {
+	if len(elements) == 0 {
 		return ErrNoTieredFilesToRemove
 	}
 
-	file := element.Value.(*TieredFile)
+	for _, element := range elements {
+		file := element.Value.(*TieredFile)
+
+		fsd.FileOrder.Remove(element)
+
+		err := fsd.releaseFile(file)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Select the next files to be released from the high tier according to the
+// eviction policy, until their size covers the given number of bytes. Files
+// that belong to a pinned path or that need to be written to durable storage
+// are never selected. Files with open descriptors can be released if they're
+// already flushed.
+func (fsd *TieredFileSystemDriver) evictionCandidates(size int64) []*list.Element {
+	var (
+		candidates  []*list.Element
+		frequencies = make(map[*list.Element]float64)
+		selected    int64
+	)
+
+	now := time.Now()
 
-	// Skip files that need to be written to durable storage
-	// Files with open descriptors can be released if they're already flushed
-	for file.shouldBeWrittenToDurableStorage() {
-		element = element.Next()
+	for element := fsd.FileOrder.Front(); element != nil; element = element.Next() {
+		file := element.Value.(*TieredFile)
 
-		if element == nil {
-			return ErrNoTieredFilesToRemove
+		if file.shouldBeWrittenToDurableStorage() || fsd.isPinned(file.Key) {
+			continue
 		}
 
-		file = element.Value.(*TieredFile)
+		candidates = append(candidates, element)
+
+		// The file order list is kept in least recently used order
+		if fsd.EvictionPolicy != TieredFileSystemEvictionPolicyLFU {
+			if selected += file.Size(); selected >= size {
+				return candidates
+			}
+
+			continue
+		}
+
+		frequencies[element] = file.AccessFrequency(now)
 	}
 
-	if element == nil || file == nil {
-		return ErrNoTieredFilesToRemove
+	if fsd.EvictionPolicy != TieredFileSystemEvictionPolicyLFU {
+		return candidates
 	}
 
-	fsd.FileOrder.Remove(element)
+	slices.SortStableFunc(candidates, func(a, b *list.Element) int {
+		return cmp.Compare(frequencies[a], frequencies[b])
+	})
 
-	return fsd.releaseFile(file)
+	for i, element := range candidates {
+		if selected += element.Value.(*TieredFile).Size(); selected >= size {
+			return candidates[:i+1]
+		}
+	}
+
+	return candidates
 }
 
 func (fsd *TieredFileSystemDriver) releaseOldestFileWithLock() error {
@@ -891,6 +995,12 @@ func (fsd *TieredFileSystemDriver) Stat(path string) (internalStorage.FileInfo,
 	return info, nil
 }
 
+// exceedsQuota returns true when the files on the high tier use more bytes
+// than allowed by the quota of the driver.
+func (fsd *TieredFileSystemDriver) exceedsQuota() bool {
+	return fsd.MaxBytes > 0 && fsd.highTierBytes.Load() > fsd.MaxBytes
+}
+
 // HasDirtyLogs checks if there are any dirty logs
 func (fsd *TieredFileSystemDriver) HasDirtyLogs() bool {
 	return fsd.logger.HasDirtyLogs()
@@ -1064,7 +1174,7 @@ func (fsd *TieredFileSystemDriver) watchForFileChanges() {
 
 			// Attempt to remove files to ensure we do not exceed the max
 			// number of files opened
-			for fsd.FileCount() > fsd.MaxFilesOpened {
+			for fsd.FileCount() > fsd.MaxFilesOpened || fsd.exceedsQuota() {
 				err := fsd.ReleaseOldestFile()
 
 				if err != nil {
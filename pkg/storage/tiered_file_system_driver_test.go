@@ -2,10 +2,12 @@ package storage_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -2430,3 +2432,321 @@ func TestTieredFileSystemDriver_FlushAndRestartScenario(t *testing.T) {
 		}
 	})
 }
+
+func TestTieredFileSystemDriverOnlyKeepsMaxBytes(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		fs1 := storage.NewFileSystem(
+			storage.NewLocalFileSystemDriver(app.Config.DataPath + "/local"),
+		)
+
+		fs2 := storage.NewFileSystem(
+			storage.NewLocalFileSystemDriver(app.Config.DataPath + "/object"),
+		)
+
+		tieredFileSystemDriver := storage.NewTieredFileSystemDriver(
+			context.Background(),
+			fs1,
+			fs2,
+			func(context context.Context, tieredFileSystemDriver *storage.TieredFileSystemDriver) {
+				tieredFileSystemDriver.MaxBytes = 1024
+			},
+			func(ctx context.Context, fsd *storage.TieredFileSystemDriver) {
+				fsd.CanSyncDirtyFiles = func() bool {
+					return true
+				}
+			},
+		)
+
+		data := make([]byte, 512)
+
+		for i := range 4 {
+			err := fs2.WriteFile(fmt.Sprintf("test%d.txt", i), data, 0600)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for i := range 4 {
+			_, err := tieredFileSystemDriver.ReadFile(fmt.Sprintf("test%d.txt", i))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tieredFileSystemDriver.UsedBytes() > 1024 {
+				t.Fatalf("Expected used bytes to be at most 1024, got %d", tieredFileSystemDriver.UsedBytes())
+			}
+		}
+
+		if tieredFileSystemDriver.FileCount() != 2 {
+			t.Fatalf("Expected 2 files to be loaded, got %d", tieredFileSystemDriver.FileCount())
+		}
+
+		// The least recently used files should have been released
+		if _, ok := tieredFileSystemDriver.GetTieredFile("test0.txt"); ok {
+			t.Error("Expected test0.txt to be released")
+		}
+
+		if _, ok := tieredFileSystemDriver.GetTieredFile("test3.txt"); !ok {
+			t.Error("Expected test3.txt to be loaded")
+		}
+	})
+}
+
+func TestTieredFileSystemDriverLFUEvictionPolicy(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		fs1 := storage.NewFileSystem(
+			storage.NewLocalFileSystemDriver(app.Config.DataPath + "/local"),
+		)
+
+		fs2 := storage.NewFileSystem(
+			storage.NewLocalFileSystemDriver(app.Config.DataPath + "/object"),
+		)
+
+		tieredFileSystemDriver := storage.NewTieredFileSystemDriver(
+			context.Background(),
+			fs1,
+			fs2,
+			func(context context.Context, tieredFileSystemDriver *storage.TieredFileSystemDriver) {
+				tieredFileSystemDriver.EvictionPolicy = storage.TieredFileSystemEvictionPolicyLFU
+				tieredFileSystemDriver.MaxFilesOpened = 2
+			},
+			func(ctx context.Context, fsd *storage.TieredFileSystemDriver) {
+				fsd.CanSyncDirtyFiles = func() bool {
+					return true
+				}
+			},
+		)
+
+		for i := range 3 {
+			err := fs2.WriteFile(fmt.Sprintf("test%d.txt", i), []byte("test"), 0600)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		first, err := tieredFileSystemDriver.Open("test0.txt")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer first.Close()
+
+		second, err := tieredFileSystemDriver.Open("test1.txt")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer second.Close()
+
+		// Access the first file more frequently than the second
+		for range 3 {
+			_, err = first.ReadAt(make([]byte, 4), 0)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		_, err = second.ReadAt(make([]byte, 4), 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = tieredFileSystemDriver.ReadFile("test2.txt")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if file, ok := tieredFileSystemDriver.GetTieredFile("test1.txt"); ok && !file.Released {
+			t.Error("Expected the least frequently used file to be released")
+		}
+
+		if file, ok := tieredFileSystemDriver.GetTieredFile("test0.txt"); !ok || file.Released {
+			t.Error("Expected the most frequently used file to remain loaded")
+		}
+	})
+}
+
+func TestTieredFileSystemDriverLFUEvictionPolicyAgesAccessCounts(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		fs1 := storage.NewFileSystem(
+			storage.NewLocalFileSystemDriver(app.Config.DataPath + "/local"),
+		)
+
+		fs2 := storage.NewFileSystem(
+			storage.NewLocalFileSystemDriver(app.Config.DataPath + "/object"),
+		)
+
+		tieredFileSystemDriver := storage.NewTieredFileSystemDriver(
+			context.Background(),
+			fs1,
+			fs2,
+			func(context context.Context, tieredFileSystemDriver *storage.TieredFileSystemDriver) {
+				tieredFileSystemDriver.EvictionPolicy = storage.TieredFileSystemEvictionPolicyLFU
+				tieredFileSystemDriver.FrequencyHalfLife = time.Millisecond
+				tieredFileSystemDriver.MaxFilesOpened = 2
+			},
+			func(ctx context.Context, fsd *storage.TieredFileSystemDriver) {
+				fsd.CanSyncDirtyFiles = func() bool {
+					return true
+				}
+			},
+		)
+
+		for i := range 3 {
+			err := fs2.WriteFile(fmt.Sprintf("test%d.txt", i), []byte("test"), 0600)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		first, err := tieredFileSystemDriver.Open("test0.txt")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer first.Close()
+
+		// The first file was accessed frequently, but long ago
+		for range 100 {
+			_, err = first.ReadAt(make([]byte, 4), 0)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		file, _ := tieredFileSystemDriver.GetTieredFile("test0.txt")
+		accessedAt := time.Now()
+
+		if frequency := file.AccessFrequency(accessedAt.Add(20 * time.Millisecond)); frequency >= 1 {
+			t.Fatalf("Expected the access frequency to decay, got %f", frequency)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+
+		second, err := tieredFileSystemDriver.Open("test1.txt")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer second.Close()
+
+		_, err = second.ReadAt(make([]byte, 4), 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = tieredFileSystemDriver.ReadFile("test2.txt")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if file, ok := tieredFileSystemDriver.GetTieredFile("test0.txt"); ok && !file.Released {
+			t.Error("Expected the file that was hot long ago to be released")
+		}
+
+		if file, ok := tieredFileSystemDriver.GetTieredFile("test1.txt"); !ok || file.Released {
+			t.Error("Expected the recently used file to remain loaded")
+		}
+	})
+}
+
+func TestValidateTieredFileSystemEvictionPolicy(t *testing.T) {
+	for _, policy := range []string{storage.TieredFileSystemEvictionPolicyLRU, storage.TieredFileSystemEvictionPolicyLFU} {
+		if err := storage.ValidateTieredFileSystemEvictionPolicy(policy); err != nil {
+			t.Errorf("Expected %s to be valid, got %v", policy, err)
+		}
+	}
+
+	for _, policy := range []string{"", "LRU", "fifo"} {
+		if err := storage.ValidateTieredFileSystemEvictionPolicy(policy); !errors.Is(err, storage.ErrTieredFileSystemEvictionPolicyInvalid) {
+			t.Errorf("Expected %q to be invalid, got %v", policy, err)
+		}
+	}
+}
+
+func TestTieredFileSystemDriverPinDatabase(t *testing.T) {
+	test.RunWithApp(t, func(app *server.App) {
+		fs1 := storage.NewFileSystem(
+			storage.NewLocalFileSystemDriver(app.Config.DataPath + "/local"),
+		)
+
+		fs2 := storage.NewFileSystem(
+			storage.NewLocalFileSystemDriver(app.Config.DataPath + "/object"),
+		)
+
+		tieredFileSystemDriver := storage.NewTieredFileSystemDriver(
+			context.Background(),
+			fs1,
+			fs2,
+			func(context context.Context, tieredFileSystemDriver *storage.TieredFileSystemDriver) {
+				tieredFileSystemDriver.MaxFilesOpened = 1
+			},
+			func(ctx context.Context, fsd *storage.TieredFileSystemDriver) {
+				fsd.CanSyncDirtyFiles = func() bool {
+					return true
+				}
+			},
+		)
+
+		tieredFileSystemDriver.PinDatabase("pinned")
+
+		paths := []string{
+			"_databases/pinned/main/0000000001",
+			"_databases/other/main/0000000001",
+		}
+
+		for _, path := range paths {
+			err := fs2.MkdirAll(filepath.Dir(path), 0750)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = fs2.WriteFile(path, []byte("test"), 0600)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = tieredFileSystemDriver.ReadFile(path)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if _, ok := tieredFileSystemDriver.GetTieredFile(paths[0]); !ok {
+			t.Error("Expected the pinned file to remain loaded")
+		}
+
+		usage := tieredFileSystemDriver.Usage()
+
+		if usage.Databases["pinned"] == nil || !usage.Databases["pinned"].Pinned {
+			t.Fatal("Expected the pinned database to be reported as pinned")
+		}
+
+		if usage.Databases["pinned"].Bytes != 4 {
+			t.Errorf("Expected the pinned database to use 4 bytes, got %d", usage.Databases["pinned"].Bytes)
+		}
+
+		tieredFileSystemDriver.UnpinDatabase("pinned")
+
+		if _, ok := tieredFileSystemDriver.Usage().Databases["pinned"]; ok && tieredFileSystemDriver.Usage().Databases["pinned"].Pinned {
+			t.Error("Expected the database to be unpinned")
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"strings"
+
+	"github.com/litebase/litebase/pkg/file"
+)
+
+// TieredFileSystemUsage describes how much of the high tier is being used by
+// the files that are currently loaded by a TieredFileSystemDriver.
+type TieredFileSystemUsage struct {
+	Databases      map[string]*TieredFileSystemDatabaseUsage `json:"databases"`
+	EvictionPolicy string                                    `json:"eviction_policy"`
+	FileCount      int                                       `json:"file_count"`
+	MaxBytes       int64                                     `json:"max_bytes"`
+	MaxFiles       int                                       `json:"max_files"`
+	UsedBytes      int64                                     `json:"used_bytes"`
+}
+
+// TieredFileSystemDatabaseUsage describes the high tier usage of a single
+// database, including all of its branches.
+type TieredFileSystemDatabaseUsage struct {
+	Bytes  int64 `json:"bytes"`
+	Files  int   `json:"files"`
+	Pinned bool  `json:"pinned"`
+}
+
+// Return the ID of the database a file belongs to, or an empty string if the
+// file does not belong to a database.
+func tieredFileDatabaseId(key string) string {
+	key = strings.TrimPrefix(key, "/")
+
+	if !strings.HasPrefix(key, file.DatabaseDirectory()) {
+		return ""
+	}
+
+	databaseId, _, found := strings.Cut(strings.TrimPrefix(key, file.DatabaseDirectory()), "/")
+
+	if !found {
+		return ""
+	}
+
+	return databaseId
+}
+
+// Check if the file with the given key belongs to a pinned path. Assumes the
+// lock is already held.
+func (fsd *TieredFileSystemDriver) isPinned(key string) bool {
+	if len(fsd.pinnedPrefixes) == 0 {
+		return false
+	}
+
+	key = strings.TrimPrefix(key, "/")
+
+	for prefix := range fsd.pinnedPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Pin the files of a database to the high tier. Pinned files will not be
+// released to satisfy the file or byte limits of the driver, but they are
+// still released when they expire or are closed.
+func (fsd *TieredFileSystemDriver) PinDatabase(databaseId string) {
+	fsd.mutex.Lock()
+	defer fsd.mutex.Unlock()
+
+	fsd.pinnedPrefixes[file.GetDatabaseRootDir(databaseId)] = struct{}{}
+}
+
+// Remove a database pin so its files may be released from the high tier.
+func (fsd *TieredFileSystemDriver) UnpinDatabase(databaseId string) {
+	fsd.mutex.Lock()
+	defer fsd.mutex.Unlock()
+
+	delete(fsd.pinnedPrefixes, file.GetDatabaseRootDir(databaseId))
+}
+
+// Return the number of bytes used on the high tier by loaded files.
+func (fsd *TieredFileSystemDriver) UsedBytes() int64 {
+	return fsd.highTierBytes.Load()
+}
+
+// Usage returns a breakdown of the high tier usage of the driver per database.
+func (fsd *TieredFileSystemDriver) Usage() TieredFileSystemUsage {
+	fsd.mutex.Lock()
+	defer fsd.mutex.Unlock()
+
+	usage := TieredFileSystemUsage{
+		Databases:      map[string]*TieredFileSystemDatabaseUsage{},
+		EvictionPolicy: fsd.EvictionPolicy,
+		FileCount:      fsd.fileCountInternal(),
+		MaxBytes:       fsd.MaxBytes,
+		MaxFiles:       fsd.MaxFilesOpened,
+		UsedBytes:      fsd.highTierBytes.Load(),
+	}
+
+	for prefix := range fsd.pinnedPrefixes {
+		if databaseId := tieredFileDatabaseId(prefix); databaseId != "" {
+			usage.Databases[databaseId] = &TieredFileSystemDatabaseUsage{Pinned: true}
+		}
+	}
+
+	for key, file := range fsd.Files {
+		databaseId := tieredFileDatabaseId(key)
+
+		if databaseId == "" || file.Released {
+			continue
+		}
+
+		if _, ok := usage.Databases[databaseId]; !ok {
+			usage.Databases[databaseId] = &TieredFileSystemDatabaseUsage{}
+		}
+
+		usage.Databases[databaseId].Bytes += file.Size()
+		usage.Databases[databaseId].Files++
+	}
+
+	return usage
+}